@@ -87,9 +87,14 @@ func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
 	}
 
 	// Create engine for the selected database
+	nullStyle := loadResult.Plan.NullStyle
+	if opts.EmitPointersForNull {
+		nullStyle = config.NullStylePointer
+	}
 	eng, err := engine.New(database, engine.Options{
-		EmitPointersForNull: loadResult.Plan.EmitPointersForNull,
-		CustomTypes:         loadResult.Plan.CustomTypes,
+		NullStyle:   nullStyle,
+		NullOption:  loadResult.Plan.NullOption,
+		CustomTypes: loadResult.Plan.CustomTypes,
 	})
 	if err != nil {
 		_, _ = fmt.Fprintf(stderr, "Error creating engine: %v\n", err)
@@ -2,65 +2,9 @@ package complexdb
 
 import "database/sql"
 
-type CreateAuthorRow struct {
-	Id        int64
-	Name      string
-	Email     string
-	Bio       sql.NullString
-	CreatedAt int64
-}
-
-func scanCreateAuthorRow(rows *sql.Rows) (CreateAuthorRow, error) {
-	var item CreateAuthorRow
-	if err := rows.Scan(&item.Id, &item.Name, &item.Email, &item.Bio, &item.CreatedAt); err != nil {
-		return item, err
-	}
-	return item, nil
-}
-
-type CreatePostRow struct {
-	Id        int64
-	AuthorId  int64
-	Title     string
-	Content   string
-	Published int64
-	ViewCount int64
-	CreatedAt int64
-	UpdatedAt sql.NullInt64
-}
-
-func scanCreatePostRow(rows *sql.Rows) (CreatePostRow, error) {
-	var item CreatePostRow
-	if err := rows.Scan(&item.Id, &item.AuthorId, &item.Title, &item.Content, &item.Published, &item.ViewCount, &item.CreatedAt, &item.UpdatedAt); err != nil {
-		return item, err
-	}
-	return item, nil
-}
-
-type CreateTagRow struct {
-	Id          int64
-	Name        string
-	Description sql.NullString
-}
-
-func scanCreateTagRow(rows *sql.Rows) (CreateTagRow, error) {
-	var item CreateTagRow
-	if err := rows.Scan(&item.Id, &item.Name, &item.Description); err != nil {
-		return item, err
-	}
-	return item, nil
-}
-
-type GetAuthorRow struct {
-	Id        int64
-	Name      string
-	Email     string
-	Bio       sql.NullString
-	CreatedAt int64
-}
-
-func scanGetAuthorRow(rows *sql.Rows) (GetAuthorRow, error) {
-	var item GetAuthorRow
+// scanAuthors scans a row into the Authors model.
+func scanAuthors(rows *sql.Rows) (Authors, error) {
+	var item Authors
 	if err := rows.Scan(&item.Id, &item.Name, &item.Email, &item.Bio, &item.CreatedAt); err != nil {
 		return item, err
 	}
@@ -70,8 +14,8 @@ func scanGetAuthorRow(rows *sql.Rows) (GetAuthorRow, error) {
 type GetAuthorStatsRow struct {
 	Id         int64
 	Name       string
-	TotalPosts any
-	TotalViews any
+	TotalPosts int64
+	TotalViews sql.NullInt64
 }
 
 func scanGetAuthorStatsRow(rows *sql.Rows) (GetAuthorStatsRow, error) {
@@ -87,7 +31,7 @@ type GetAuthorWithPostCountRow struct {
 	Name       string
 	Email      string
 	Bio        sql.NullString
-	TotalPosts any
+	TotalPosts int64
 }
 
 func scanGetAuthorWithPostCountRow(rows *sql.Rows) (GetAuthorWithPostCountRow, error) {
@@ -102,7 +46,7 @@ type GetPopularTagsRow struct {
 	Id          int64
 	Name        string
 	Description sql.NullString
-	PostCount   any
+	PostCount   int64
 }
 
 func scanGetPopularTagsRow(rows *sql.Rows) (GetPopularTagsRow, error) {
@@ -113,25 +57,6 @@ func scanGetPopularTagsRow(rows *sql.Rows) (GetPopularTagsRow, error) {
 	return item, nil
 }
 
-type GetPostRow struct {
-	Id        int64
-	AuthorId  int64
-	Title     string
-	Content   string
-	Published int64
-	ViewCount int64
-	CreatedAt int64
-	UpdatedAt sql.NullInt64
-}
-
-func scanGetPostRow(rows *sql.Rows) (GetPostRow, error) {
-	var item GetPostRow
-	if err := rows.Scan(&item.Id, &item.AuthorId, &item.Title, &item.Content, &item.Published, &item.ViewCount, &item.CreatedAt, &item.UpdatedAt); err != nil {
-		return item, err
-	}
-	return item, nil
-}
-
 type GetPostTagsRow struct {
 	Id          int64
 	Name        string
@@ -146,152 +71,20 @@ func scanGetPostTagsRow(rows *sql.Rows) (GetPostTagsRow, error) {
 	return item, nil
 }
 
-type GetPostsByTagRow struct {
-	Id        int64
-	AuthorId  int64
-	Title     string
-	Content   string
-	Published int64
-	ViewCount int64
-	CreatedAt int64
-	UpdatedAt sql.NullInt64
-}
-
-func scanGetPostsByTagRow(rows *sql.Rows) (GetPostsByTagRow, error) {
-	var item GetPostsByTagRow
-	if err := rows.Scan(&item.Id, &item.AuthorId, &item.Title, &item.Content, &item.Published, &item.ViewCount, &item.CreatedAt, &item.UpdatedAt); err != nil {
-		return item, err
-	}
-	return item, nil
-}
-
-type GetTagByNameRow struct {
-	Id          int64
-	Name        string
-	Description sql.NullString
-}
-
-func scanGetTagByNameRow(rows *sql.Rows) (GetTagByNameRow, error) {
-	var item GetTagByNameRow
-	if err := rows.Scan(&item.Id, &item.Name, &item.Description); err != nil {
-		return item, err
-	}
-	return item, nil
-}
-
-type GetTagRow struct {
-	Id          int64
-	Name        string
-	Description sql.NullString
-}
-
-func scanGetTagRow(rows *sql.Rows) (GetTagRow, error) {
-	var item GetTagRow
-	if err := rows.Scan(&item.Id, &item.Name, &item.Description); err != nil {
-		return item, err
-	}
-	return item, nil
-}
-
-type ListAuthorsRow struct {
-	Id        int64
-	Name      string
-	Email     string
-	Bio       sql.NullString
-	CreatedAt int64
-}
-
-func scanListAuthorsRow(rows *sql.Rows) (ListAuthorsRow, error) {
-	var item ListAuthorsRow
-	if err := rows.Scan(&item.Id, &item.Name, &item.Email, &item.Bio, &item.CreatedAt); err != nil {
-		return item, err
-	}
-	return item, nil
-}
-
-type ListPostsRow struct {
-	Id        int64
-	AuthorId  int64
-	Title     string
-	Content   string
-	Published int64
-	ViewCount int64
-	CreatedAt int64
-	UpdatedAt sql.NullInt64
-}
-
-func scanListPostsRow(rows *sql.Rows) (ListPostsRow, error) {
-	var item ListPostsRow
+// scanPosts scans a row into the Posts model.
+func scanPosts(rows *sql.Rows) (Posts, error) {
+	var item Posts
 	if err := rows.Scan(&item.Id, &item.AuthorId, &item.Title, &item.Content, &item.Published, &item.ViewCount, &item.CreatedAt, &item.UpdatedAt); err != nil {
 		return item, err
 	}
 	return item, nil
 }
 
-type ListTagsRow struct {
-	Id          int64
-	Name        string
-	Description sql.NullString
-}
-
-func scanListTagsRow(rows *sql.Rows) (ListTagsRow, error) {
-	var item ListTagsRow
+// scanTags scans a row into the Tags model.
+func scanTags(rows *sql.Rows) (Tags, error) {
+	var item Tags
 	if err := rows.Scan(&item.Id, &item.Name, &item.Description); err != nil {
 		return item, err
 	}
 	return item, nil
 }
-
-type ListUnpublishedPostsRow struct {
-	Id        int64
-	AuthorId  int64
-	Title     string
-	Content   string
-	Published int64
-	ViewCount int64
-	CreatedAt int64
-	UpdatedAt sql.NullInt64
-}
-
-func scanListUnpublishedPostsRow(rows *sql.Rows) (ListUnpublishedPostsRow, error) {
-	var item ListUnpublishedPostsRow
-	if err := rows.Scan(&item.Id, &item.AuthorId, &item.Title, &item.Content, &item.Published, &item.ViewCount, &item.CreatedAt, &item.UpdatedAt); err != nil {
-		return item, err
-	}
-	return item, nil
-}
-
-type SearchPostsRow struct {
-	Id        int64
-	AuthorId  int64
-	Title     string
-	Content   string
-	Published int64
-	ViewCount int64
-	CreatedAt int64
-	UpdatedAt sql.NullInt64
-}
-
-func scanSearchPostsRow(rows *sql.Rows) (SearchPostsRow, error) {
-	var item SearchPostsRow
-	if err := rows.Scan(&item.Id, &item.AuthorId, &item.Title, &item.Content, &item.Published, &item.ViewCount, &item.CreatedAt, &item.UpdatedAt); err != nil {
-		return item, err
-	}
-	return item, nil
-}
-
-type UpdateAuthorRow struct {
-	Id        int64
-	Name      string
-	Email     string
-	Bio       sql.NullString
-	CreatedAt int64
-}
-
-func scanUpdateAuthorRow(rows *sql.Rows) (UpdateAuthorRow, error) {
-	var item UpdateAuthorRow
-	if err := rows.Scan(&item.Id, &item.Name, &item.Email, &item.Bio, &item.CreatedAt); err != nil {
-		return item, err
-	}
-	return item, nil
-}
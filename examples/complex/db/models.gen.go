@@ -3,24 +3,24 @@ package complexdb
 import "database/sql"
 
 type Authors struct {
-	Id        int32          `json:"id"`
+	Id        int64          `json:"id"`
 	Name      string         `json:"name"`
 	Email     string         `json:"email"`
 	Bio       sql.NullString `json:"bio"`
-	CreatedAt int32          `json:"created_at"`
+	CreatedAt int64          `json:"created_at"`
 }
 type Posts struct {
-	Id        int32  `json:"id"`
-	AuthorId  int32  `json:"author_id"`
-	Title     string `json:"title"`
-	Content   string `json:"content"`
-	Published int32  `json:"published"`
-	ViewCount int32  `json:"view_count"`
-	CreatedAt int32  `json:"created_at"`
-	UpdatedAt *int32 `json:"updated_at"`
+	Id        int64         `json:"id"`
+	AuthorId  int64         `json:"author_id"`
+	Title     string        `json:"title"`
+	Content   string        `json:"content"`
+	Published int64         `json:"published"`
+	ViewCount int64         `json:"view_count"`
+	CreatedAt int64         `json:"created_at"`
+	UpdatedAt sql.NullInt64 `json:"updated_at"`
 }
 type Tags struct {
-	Id          int32          `json:"id"`
+	Id          int64          `json:"id"`
 	Name        string         `json:"name"`
 	Description sql.NullString `json:"description"`
 }
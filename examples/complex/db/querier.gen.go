@@ -7,27 +7,27 @@ import (
 
 type Querier interface {
 	AddTagToPost(ctx context.Context, arg AddTagToPostParams) error
-	CreateAuthor(ctx context.Context, arg CreateAuthorParams) (CreateAuthorRow, error)
-	CreatePost(ctx context.Context, arg CreatePostParams) (CreatePostRow, error)
-	CreateTag(ctx context.Context, arg CreateTagParams) (CreateTagRow, error)
+	CreateAuthor(ctx context.Context, arg CreateAuthorParams) (Authors, error)
+	CreatePost(ctx context.Context, arg CreatePostParams) (Posts, error)
+	CreateTag(ctx context.Context, arg CreateTagParams) (Tags, error)
 	DeleteAuthor(ctx context.Context, id int64) error
 	DeleteTag(ctx context.Context, id int64) error
-	GetAuthor(ctx context.Context, id int64) (GetAuthorRow, error)
+	GetAuthor(ctx context.Context, id int64) (Authors, error)
 	GetAuthorStats(ctx context.Context, id int64) (GetAuthorStatsRow, error)
 	GetAuthorWithPostCount(ctx context.Context, id int64) (GetAuthorWithPostCountRow, error)
-	GetPopularTags(ctx context.Context, limit any) ([]GetPopularTagsRow, error)
-	GetPost(ctx context.Context, id int64) (GetPostRow, error)
+	GetPopularTags(ctx context.Context, limit int64) ([]GetPopularTagsRow, error)
+	GetPost(ctx context.Context, id int64) (Posts, error)
 	GetPostTags(ctx context.Context, postId int64) ([]GetPostTagsRow, error)
-	GetPostsByTag(ctx context.Context, name string) ([]GetPostsByTagRow, error)
-	GetTag(ctx context.Context, id int64) (GetTagRow, error)
-	GetTagByName(ctx context.Context, name string) (GetTagByNameRow, error)
+	GetPostsByTag(ctx context.Context, name string) ([]Posts, error)
+	GetTag(ctx context.Context, id int64) (Tags, error)
+	GetTagByName(ctx context.Context, name string) (Tags, error)
 	IncrementViewCount(ctx context.Context, id int64) error
-	ListAuthors(ctx context.Context) ([]ListAuthorsRow, error)
-	ListPosts(ctx context.Context) ([]ListPostsRow, error)
-	ListTags(ctx context.Context) ([]ListTagsRow, error)
-	ListUnpublishedPosts(ctx context.Context) ([]ListUnpublishedPostsRow, error)
-	SearchPosts(ctx context.Context, arg SearchPostsParams) ([]SearchPostsRow, error)
-	UpdateAuthor(ctx context.Context, arg UpdateAuthorParams) (UpdateAuthorRow, error)
+	ListAuthors(ctx context.Context) ([]Authors, error)
+	ListPosts(ctx context.Context) ([]Posts, error)
+	ListTags(ctx context.Context) ([]Tags, error)
+	ListUnpublishedPosts(ctx context.Context) ([]Posts, error)
+	SearchPosts(ctx context.Context, arg SearchPostsParams) ([]Posts, error)
+	UpdateAuthor(ctx context.Context, arg UpdateAuthorParams) (Authors, error)
 }
 type DBTX interface {
 	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
@@ -15,24 +15,15 @@ const queryCreateAuthor string = `INSERT INTO authors (name, email, bio)
 VALUES (?, ?, ?)
 RETURNING *;`
 
-func (q *Queries) CreateAuthor(ctx context.Context, arg CreateAuthorParams) (CreateAuthorRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryCreateAuthor, arg.Name, arg.Email, arg.Bio)
-	if err != nil {
-		return CreateAuthorRow{}, err
-	}
-	defer rows.Close()
-	if !rows.Next() {
-		if err := rows.Err(); err != nil {
-			return CreateAuthorRow{}, err
-		}
-		return CreateAuthorRow{}, sql.ErrNoRows
+func (q *Queries) CreateAuthor(ctx context.Context, arg CreateAuthorParams) (Authors, error) {
+	row := q.db.QueryRowContext(ctx, queryCreateAuthor, arg.Name, arg.Email, arg.Bio)
+	if err := row.Err(); err != nil {
+		return Authors{}, err
 	}
-	item, err := scanCreateAuthorRow(rows)
+	var item Authors
+	err := row.Scan(&item.Id, &item.Name, &item.Email, &item.Bio, &item.CreatedAt)
 	if err != nil {
-		return item, err
-	}
-	if err := rows.Err(); err != nil {
-		return item, err
+		return Authors{}, err
 	}
 	return item, nil
 }
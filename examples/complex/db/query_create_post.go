@@ -1,9 +1,6 @@
 package complexdb
 
-import (
-	"context"
-	"database/sql"
-)
+import "context"
 
 type CreatePostParams struct {
 	AuthorId  int64
@@ -16,24 +13,15 @@ const queryCreatePost string = `INSERT INTO posts (author_id, title, content, pu
 VALUES (?, ?, ?, ?)
 RETURNING id, author_id, title, content, published, view_count, created_at, updated_at;`
 
-func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (CreatePostRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryCreatePost, arg.AuthorId, arg.Title, arg.Content, arg.Published)
-	if err != nil {
-		return CreatePostRow{}, err
-	}
-	defer rows.Close()
-	if !rows.Next() {
-		if err := rows.Err(); err != nil {
-			return CreatePostRow{}, err
-		}
-		return CreatePostRow{}, sql.ErrNoRows
+func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Posts, error) {
+	row := q.db.QueryRowContext(ctx, queryCreatePost, arg.AuthorId, arg.Title, arg.Content, arg.Published)
+	if err := row.Err(); err != nil {
+		return Posts{}, err
 	}
-	item, err := scanCreatePostRow(rows)
+	var item Posts
+	err := row.Scan(&item.Id, &item.AuthorId, &item.Title, &item.Content, &item.Published, &item.ViewCount, &item.CreatedAt, &item.UpdatedAt)
 	if err != nil {
-		return item, err
-	}
-	if err := rows.Err(); err != nil {
-		return item, err
+		return Posts{}, err
 	}
 	return item, nil
 }
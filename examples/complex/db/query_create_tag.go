@@ -14,24 +14,15 @@ const queryCreateTag string = `INSERT INTO tags (name, description)
 VALUES (?, ?)
 RETURNING *;`
 
-func (q *Queries) CreateTag(ctx context.Context, arg CreateTagParams) (CreateTagRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryCreateTag, arg.Name, arg.Description)
-	if err != nil {
-		return CreateTagRow{}, err
-	}
-	defer rows.Close()
-	if !rows.Next() {
-		if err := rows.Err(); err != nil {
-			return CreateTagRow{}, err
-		}
-		return CreateTagRow{}, sql.ErrNoRows
+func (q *Queries) CreateTag(ctx context.Context, arg CreateTagParams) (Tags, error) {
+	row := q.db.QueryRowContext(ctx, queryCreateTag, arg.Name, arg.Description)
+	if err := row.Err(); err != nil {
+		return Tags{}, err
 	}
-	item, err := scanCreateTagRow(rows)
+	var item Tags
+	err := row.Scan(&item.Id, &item.Name, &item.Description)
 	if err != nil {
-		return item, err
-	}
-	if err := rows.Err(); err != nil {
-		return item, err
+		return Tags{}, err
 	}
 	return item, nil
 }
@@ -1,30 +1,18 @@
 package complexdb
 
-import (
-	"context"
-	"database/sql"
-)
+import "context"
 
 const queryGetAuthor string = `SELECT * FROM authors WHERE id = ?;`
 
-func (q *Queries) GetAuthor(ctx context.Context, id int64) (GetAuthorRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryGetAuthor, id)
-	if err != nil {
-		return GetAuthorRow{}, err
-	}
-	defer rows.Close()
-	if !rows.Next() {
-		if err := rows.Err(); err != nil {
-			return GetAuthorRow{}, err
-		}
-		return GetAuthorRow{}, sql.ErrNoRows
+func (q *Queries) GetAuthor(ctx context.Context, id int64) (Authors, error) {
+	row := q.db.QueryRowContext(ctx, queryGetAuthor, id)
+	if err := row.Err(); err != nil {
+		return Authors{}, err
 	}
-	item, err := scanGetAuthorRow(rows)
+	var item Authors
+	err := row.Scan(&item.Id, &item.Name, &item.Email, &item.Bio, &item.CreatedAt)
 	if err != nil {
-		return item, err
-	}
-	if err := rows.Err(); err != nil {
-		return item, err
+		return Authors{}, err
 	}
 	return item, nil
 }
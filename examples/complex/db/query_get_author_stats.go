@@ -1,9 +1,6 @@
 package complexdb
 
-import (
-	"context"
-	"database/sql"
-)
+import "context"
 
 const queryGetAuthorStats string = `SELECT 
     a.id,
@@ -14,23 +11,14 @@ FROM authors a
 WHERE a.id = ?;`
 
 func (q *Queries) GetAuthorStats(ctx context.Context, id int64) (GetAuthorStatsRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryGetAuthorStats, id)
-	if err != nil {
+	row := q.db.QueryRowContext(ctx, queryGetAuthorStats, id)
+	if err := row.Err(); err != nil {
 		return GetAuthorStatsRow{}, err
 	}
-	defer rows.Close()
-	if !rows.Next() {
-		if err := rows.Err(); err != nil {
-			return GetAuthorStatsRow{}, err
-		}
-		return GetAuthorStatsRow{}, sql.ErrNoRows
-	}
-	item, err := scanGetAuthorStatsRow(rows)
+	var item GetAuthorStatsRow
+	err := row.Scan(&item.Id, &item.Name, &item.TotalPosts, &item.TotalViews)
 	if err != nil {
-		return item, err
-	}
-	if err := rows.Err(); err != nil {
-		return item, err
+		return GetAuthorStatsRow{}, err
 	}
 	return item, nil
 }
@@ -1,9 +1,6 @@
 package complexdb
 
-import (
-	"context"
-	"database/sql"
-)
+import "context"
 
 const queryGetAuthorWithPostCount string = `SELECT 
     a.id,
@@ -15,23 +12,14 @@ FROM authors a
 WHERE a.id = ?;`
 
 func (q *Queries) GetAuthorWithPostCount(ctx context.Context, id int64) (GetAuthorWithPostCountRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryGetAuthorWithPostCount, id)
-	if err != nil {
+	row := q.db.QueryRowContext(ctx, queryGetAuthorWithPostCount, id)
+	if err := row.Err(); err != nil {
 		return GetAuthorWithPostCountRow{}, err
 	}
-	defer rows.Close()
-	if !rows.Next() {
-		if err := rows.Err(); err != nil {
-			return GetAuthorWithPostCountRow{}, err
-		}
-		return GetAuthorWithPostCountRow{}, sql.ErrNoRows
-	}
-	item, err := scanGetAuthorWithPostCountRow(rows)
+	var item GetAuthorWithPostCountRow
+	err := row.Scan(&item.Id, &item.Name, &item.Email, &item.Bio, &item.TotalPosts)
 	if err != nil {
-		return item, err
-	}
-	if err := rows.Err(); err != nil {
-		return item, err
+		return GetAuthorWithPostCountRow{}, err
 	}
 	return item, nil
 }
@@ -1,30 +1,18 @@
 package complexdb
 
-import (
-	"context"
-	"database/sql"
-)
+import "context"
 
 const queryGetPost string = `SELECT * FROM posts WHERE id = ?;`
 
-func (q *Queries) GetPost(ctx context.Context, id int64) (GetPostRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryGetPost, id)
-	if err != nil {
-		return GetPostRow{}, err
-	}
-	defer rows.Close()
-	if !rows.Next() {
-		if err := rows.Err(); err != nil {
-			return GetPostRow{}, err
-		}
-		return GetPostRow{}, sql.ErrNoRows
+func (q *Queries) GetPost(ctx context.Context, id int64) (Posts, error) {
+	row := q.db.QueryRowContext(ctx, queryGetPost, id)
+	if err := row.Err(); err != nil {
+		return Posts{}, err
 	}
-	item, err := scanGetPostRow(rows)
+	var item Posts
+	err := row.Scan(&item.Id, &item.AuthorId, &item.Title, &item.Content, &item.Published, &item.ViewCount, &item.CreatedAt, &item.UpdatedAt)
 	if err != nil {
-		return item, err
-	}
-	if err := rows.Err(); err != nil {
-		return item, err
+		return Posts{}, err
 	}
 	return item, nil
 }
@@ -12,15 +12,15 @@ WHERE p.id IN (
 AND p.published = 1
 ORDER BY p.created_at DESC;`
 
-func (q *Queries) GetPostsByTag(ctx context.Context, name string) ([]GetPostsByTagRow, error) {
+func (q *Queries) GetPostsByTag(ctx context.Context, name string) ([]Posts, error) {
 	rows, err := q.db.QueryContext(ctx, queryGetPostsByTag, name)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetPostsByTagRow
+	var items []Posts
 	for rows.Next() {
-		item, err := scanGetPostsByTagRow(rows)
+		item, err := scanPosts(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -1,30 +1,18 @@
 package complexdb
 
-import (
-	"context"
-	"database/sql"
-)
+import "context"
 
 const queryGetTag string = `SELECT * FROM tags WHERE id = ?;`
 
-func (q *Queries) GetTag(ctx context.Context, id int64) (GetTagRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryGetTag, id)
-	if err != nil {
-		return GetTagRow{}, err
-	}
-	defer rows.Close()
-	if !rows.Next() {
-		if err := rows.Err(); err != nil {
-			return GetTagRow{}, err
-		}
-		return GetTagRow{}, sql.ErrNoRows
+func (q *Queries) GetTag(ctx context.Context, id int64) (Tags, error) {
+	row := q.db.QueryRowContext(ctx, queryGetTag, id)
+	if err := row.Err(); err != nil {
+		return Tags{}, err
 	}
-	item, err := scanGetTagRow(rows)
+	var item Tags
+	err := row.Scan(&item.Id, &item.Name, &item.Description)
 	if err != nil {
-		return item, err
-	}
-	if err := rows.Err(); err != nil {
-		return item, err
+		return Tags{}, err
 	}
 	return item, nil
 }
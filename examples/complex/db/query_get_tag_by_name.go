@@ -1,30 +1,18 @@
 package complexdb
 
-import (
-	"context"
-	"database/sql"
-)
+import "context"
 
 const queryGetTagByName string = `SELECT * FROM tags WHERE name = ?;`
 
-func (q *Queries) GetTagByName(ctx context.Context, name string) (GetTagByNameRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryGetTagByName, name)
-	if err != nil {
-		return GetTagByNameRow{}, err
-	}
-	defer rows.Close()
-	if !rows.Next() {
-		if err := rows.Err(); err != nil {
-			return GetTagByNameRow{}, err
-		}
-		return GetTagByNameRow{}, sql.ErrNoRows
+func (q *Queries) GetTagByName(ctx context.Context, name string) (Tags, error) {
+	row := q.db.QueryRowContext(ctx, queryGetTagByName, name)
+	if err := row.Err(); err != nil {
+		return Tags{}, err
 	}
-	item, err := scanGetTagByNameRow(rows)
+	var item Tags
+	err := row.Scan(&item.Id, &item.Name, &item.Description)
 	if err != nil {
-		return item, err
-	}
-	if err := rows.Err(); err != nil {
-		return item, err
+		return Tags{}, err
 	}
 	return item, nil
 }
@@ -4,15 +4,15 @@ import "context"
 
 const queryListAuthors string = `SELECT * FROM authors ORDER BY name;`
 
-func (q *Queries) ListAuthors(ctx context.Context) ([]ListAuthorsRow, error) {
+func (q *Queries) ListAuthors(ctx context.Context) ([]Authors, error) {
 	rows, err := q.db.QueryContext(ctx, queryListAuthors)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListAuthorsRow
+	var items []Authors
 	for rows.Next() {
-		item, err := scanListAuthorsRow(rows)
+		item, err := scanAuthors(rows)
 		if err != nil {
 			return nil, err
 		}
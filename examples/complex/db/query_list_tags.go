@@ -4,15 +4,15 @@ import "context"
 
 const queryListTags string = `SELECT * FROM tags ORDER BY name;`
 
-func (q *Queries) ListTags(ctx context.Context) ([]ListTagsRow, error) {
+func (q *Queries) ListTags(ctx context.Context) ([]Tags, error) {
 	rows, err := q.db.QueryContext(ctx, queryListTags)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListTagsRow
+	var items []Tags
 	for rows.Next() {
-		item, err := scanListTagsRow(rows)
+		item, err := scanTags(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -4,15 +4,15 @@ import "context"
 
 const queryListUnpublishedPosts string = `SELECT * FROM posts WHERE published = 0 ORDER BY created_at DESC;`
 
-func (q *Queries) ListUnpublishedPosts(ctx context.Context) ([]ListUnpublishedPostsRow, error) {
+func (q *Queries) ListUnpublishedPosts(ctx context.Context) ([]Posts, error) {
 	rows, err := q.db.QueryContext(ctx, queryListUnpublishedPosts)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListUnpublishedPostsRow
+	var items []Posts
 	for rows.Next() {
-		item, err := scanListUnpublishedPostsRow(rows)
+		item, err := scanPosts(rows)
 		if err != nil {
 			return nil, err
 		}
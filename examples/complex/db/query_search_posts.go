@@ -3,10 +3,10 @@ package complexdb
 import "context"
 
 type SearchPostsParams struct {
-	Title   any
-	Content any
-	Limit   any
-	Offset  any
+	Title   string
+	Content string
+	Limit   int64
+	Offset  int64
 }
 
 const querySearchPosts string = `SELECT *
@@ -16,15 +16,15 @@ WHERE published = 1
 ORDER BY view_count DESC
 LIMIT ? OFFSET ?;`
 
-func (q *Queries) SearchPosts(ctx context.Context, arg SearchPostsParams) ([]SearchPostsRow, error) {
+func (q *Queries) SearchPosts(ctx context.Context, arg SearchPostsParams) ([]Posts, error) {
 	rows, err := q.db.QueryContext(ctx, querySearchPosts, arg.Title, arg.Content, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []SearchPostsRow
+	var items []Posts
 	for rows.Next() {
-		item, err := scanSearchPostsRow(rows)
+		item, err := scanPosts(rows)
 		if err != nil {
 			return nil, err
 		}
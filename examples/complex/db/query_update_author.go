@@ -17,24 +17,15 @@ SET name = ?, email = ?, bio = ?
 WHERE id = ?
 RETURNING *;`
 
-func (q *Queries) UpdateAuthor(ctx context.Context, arg UpdateAuthorParams) (UpdateAuthorRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryUpdateAuthor, arg.Name, arg.Email, arg.Bio, arg.Id)
-	if err != nil {
-		return UpdateAuthorRow{}, err
-	}
-	defer rows.Close()
-	if !rows.Next() {
-		if err := rows.Err(); err != nil {
-			return UpdateAuthorRow{}, err
-		}
-		return UpdateAuthorRow{}, sql.ErrNoRows
+func (q *Queries) UpdateAuthor(ctx context.Context, arg UpdateAuthorParams) (Authors, error) {
+	row := q.db.QueryRowContext(ctx, queryUpdateAuthor, arg.Name, arg.Email, arg.Bio, arg.Id)
+	if err := row.Err(); err != nil {
+		return Authors{}, err
 	}
-	item, err := scanUpdateAuthorRow(rows)
+	var item Authors
+	err := row.Scan(&item.Id, &item.Name, &item.Email, &item.Bio, &item.CreatedAt)
 	if err != nil {
-		return item, err
-	}
-	if err := rows.Err(); err != nil {
-		return item, err
+		return Authors{}, err
 	}
 	return item, nil
 }
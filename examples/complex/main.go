@@ -186,8 +186,7 @@ func main() {
 	fmt.Println()
 
 	fmt.Println("\n--- Popular Tags ---")
-	limit := any(int64(5)) //nolint:mnd // Example query limit
-	popularTags, err := queries.GetPopularTags(ctx, &limit)
+	popularTags, err := queries.GetPopularTags(ctx, 5) //nolint:mnd // Example query limit
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -212,7 +211,11 @@ func main() {
 	}
 
 	fmt.Println("\n--- Search Posts ---")
-	searchResults, err := queries.SearchPosts(ctx, complexdb.SearchPostsParams{})
+	searchResults, err := queries.SearchPosts(ctx, complexdb.SearchPostsParams{
+		Title:   "SQLite",
+		Content: "SQLite",
+		Limit:   10, //nolint:mnd // Example page size
+	})
 	if err != nil {
 		log.Fatal(err)
 	}
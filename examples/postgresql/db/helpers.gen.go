@@ -1,193 +1,31 @@
 package postgresqldb
 
 import (
-	"database/sql"
-	"time"
+	"encoding/json"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/shopspring/decimal"
 )
 
-type CreateCommentRow struct {
-	Id          uuid.UUID
-	PostId      *uuid.UUID
-	UserId      *uuid.UUID
-	Commentbody pgtype.Text
-	Likes       pgtype.Int4
-	CreatedAt   *time.Time
-}
-
-func scanCreateCommentRow(rows *sql.Rows) (CreateCommentRow, error) {
-	var item CreateCommentRow
-	if err := rows.Scan(&item.Id, &item.PostId, &item.UserId, &item.Commentbody, &item.Likes, &item.CreatedAt); err != nil {
-		return item, err
-	}
-	return item, nil
-}
-
-type CreatePostRow struct {
-	Id          uuid.UUID
-	UserId      *uuid.UUID
-	Title       pgtype.Text
-	Postbody    pgtype.Text
-	Categories  pgtype.Text
-	ViewCount   pgtype.Int4
-	Rating      *decimal.Decimal
-	IsPublished pgtype.Bool
-	PublishedAt *time.Time
-	CreatedAt   *time.Time
-	UpdatedAt   *time.Time
-}
-
-func scanCreatePostRow(rows *sql.Rows) (CreatePostRow, error) {
-	var item CreatePostRow
-	if err := rows.Scan(&item.Id, &item.UserId, &item.Title, &item.Postbody, &item.Categories, &item.ViewCount, &item.Rating, &item.IsPublished, &item.PublishedAt, &item.CreatedAt, &item.UpdatedAt); err != nil {
-		return item, err
-	}
-	return item, nil
-}
-
-type CreateTagRow struct {
-	Id             uuid.UUID
-	Tagname        pgtype.Text
-	Tagdescription pgtype.Text
-	CreatedAt      *time.Time
-}
-
-func scanCreateTagRow(rows *sql.Rows) (CreateTagRow, error) {
-	var item CreateTagRow
-	if err := rows.Scan(&item.Id, &item.Tagname, &item.Tagdescription, &item.CreatedAt); err != nil {
-		return item, err
-	}
-	return item, nil
-}
-
-type CreateUserRow struct {
-	Id        uuid.UUID
-	Username  pgtype.Text
-	Useremail pgtype.Text
-	Metadata  *[]byte
-	Tags      pgtype.Text
-	IsActive  pgtype.Bool
-	CreatedAt *time.Time
-	UpdatedAt *time.Time
-}
-
-func scanCreateUserRow(rows *sql.Rows) (CreateUserRow, error) {
-	var item CreateUserRow
-	if err := rows.Scan(&item.Id, &item.Username, &item.Useremail, &item.Metadata, &item.Tags, &item.IsActive, &item.CreatedAt, &item.UpdatedAt); err != nil {
-		return item, err
-	}
-	return item, nil
-}
-
-type GetCommentByIdRow struct {
-	Id          uuid.UUID
-	PostId      *uuid.UUID
-	UserId      *uuid.UUID
-	Commentbody pgtype.Text
-	Likes       pgtype.Int4
-	CreatedAt   *time.Time
-}
-
-func scanGetCommentByIdRow(rows *sql.Rows) (GetCommentByIdRow, error) {
-	var item GetCommentByIdRow
+// scanComments scans a row into the Comments model.
+func scanComments(rows pgx.Rows) (Comments, error) {
+	var item Comments
 	if err := rows.Scan(&item.Id, &item.PostId, &item.UserId, &item.Commentbody, &item.Likes, &item.CreatedAt); err != nil {
 		return item, err
 	}
 	return item, nil
 }
 
-type GetPopularPostsRow struct {
-	Id          uuid.UUID
-	UserId      *uuid.UUID
-	Title       pgtype.Text
-	Postbody    pgtype.Text
-	Categories  pgtype.Text
-	ViewCount   pgtype.Int4
-	Rating      *decimal.Decimal
-	IsPublished pgtype.Bool
-	PublishedAt *time.Time
-	CreatedAt   *time.Time
-	UpdatedAt   *time.Time
-}
-
-func scanGetPopularPostsRow(rows *sql.Rows) (GetPopularPostsRow, error) {
-	var item GetPopularPostsRow
-	if err := rows.Scan(&item.Id, &item.UserId, &item.Title, &item.Postbody, &item.Categories, &item.ViewCount, &item.Rating, &item.IsPublished, &item.PublishedAt, &item.CreatedAt, &item.UpdatedAt); err != nil {
-		return item, err
-	}
-	return item, nil
-}
-
-type GetPostByIdRow struct {
-	Id          uuid.UUID
-	UserId      *uuid.UUID
-	Title       pgtype.Text
-	Postbody    pgtype.Text
-	Categories  pgtype.Text
-	ViewCount   pgtype.Int4
-	Rating      *decimal.Decimal
-	IsPublished pgtype.Bool
-	PublishedAt *time.Time
-	CreatedAt   *time.Time
-	UpdatedAt   *time.Time
-}
-
-func scanGetPostByIdRow(rows *sql.Rows) (GetPostByIdRow, error) {
-	var item GetPostByIdRow
-	if err := rows.Scan(&item.Id, &item.UserId, &item.Title, &item.Postbody, &item.Categories, &item.ViewCount, &item.Rating, &item.IsPublished, &item.PublishedAt, &item.CreatedAt, &item.UpdatedAt); err != nil {
-		return item, err
-	}
-	return item, nil
-}
-
-type GetPostsForTagRow struct {
-	Id          uuid.UUID
-	UserId      *uuid.UUID
-	Title       pgtype.Text
-	Postbody    pgtype.Text
-	Categories  pgtype.Text
-	ViewCount   pgtype.Int4
-	Rating      *decimal.Decimal
-	IsPublished pgtype.Bool
-	PublishedAt *time.Time
-	CreatedAt   *time.Time
-	UpdatedAt   *time.Time
-}
-
-func scanGetPostsForTagRow(rows *sql.Rows) (GetPostsForTagRow, error) {
-	var item GetPostsForTagRow
-	if err := rows.Scan(&item.Id, &item.UserId, &item.Title, &item.Postbody, &item.Categories, &item.ViewCount, &item.Rating, &item.IsPublished, &item.PublishedAt, &item.CreatedAt, &item.UpdatedAt); err != nil {
-		return item, err
-	}
-	return item, nil
-}
-
-type GetTagByIdRow struct {
-	Id             uuid.UUID
-	Tagname        pgtype.Text
-	Tagdescription pgtype.Text
-	CreatedAt      *time.Time
-}
-
-func scanGetTagByIdRow(rows *sql.Rows) (GetTagByIdRow, error) {
-	var item GetTagByIdRow
-	if err := rows.Scan(&item.Id, &item.Tagname, &item.Tagdescription, &item.CreatedAt); err != nil {
-		return item, err
-	}
-	return item, nil
-}
-
 type GetTagByNameRow struct {
 	Id             uuid.UUID
-	Tagname        pgtype.Text
+	Tagname        string
 	Tagdescription pgtype.Text
-	CreatedAt      *time.Time
+	CreatedAt      pgtype.Timestamptz
 }
 
-func scanGetTagByNameRow(rows *sql.Rows) (GetTagByNameRow, error) {
+func scanGetTagByNameRow(rows pgx.Rows) (GetTagByNameRow, error) {
 	var item GetTagByNameRow
 	if err := rows.Scan(&item.Id, &item.Tagname, &item.Tagdescription, &item.CreatedAt); err != nil {
 		return item, err
@@ -195,33 +33,18 @@ func scanGetTagByNameRow(rows *sql.Rows) (GetTagByNameRow, error) {
 	return item, nil
 }
 
-type GetTagsForPostRow struct {
-	Id             uuid.UUID
-	Tagname        pgtype.Text
-	Tagdescription pgtype.Text
-	CreatedAt      *time.Time
-}
-
-func scanGetTagsForPostRow(rows *sql.Rows) (GetTagsForPostRow, error) {
-	var item GetTagsForPostRow
-	if err := rows.Scan(&item.Id, &item.Tagname, &item.Tagdescription, &item.CreatedAt); err != nil {
-		return item, err
-	}
-	return item, nil
-}
-
 type GetUserByEmailRow struct {
 	Id        uuid.UUID
 	Username  pgtype.Text
-	Useremail pgtype.Text
-	Metadata  *[]byte
+	Useremail string
+	Metadata  *json.RawMessage
 	Tags      pgtype.Text
 	IsActive  pgtype.Bool
-	CreatedAt *time.Time
-	UpdatedAt *time.Time
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
 }
 
-func scanGetUserByEmailRow(rows *sql.Rows) (GetUserByEmailRow, error) {
+func scanGetUserByEmailRow(rows pgx.Rows) (GetUserByEmailRow, error) {
 	var item GetUserByEmailRow
 	if err := rows.Scan(&item.Id, &item.Username, &item.Useremail, &item.Metadata, &item.Tags, &item.IsActive, &item.CreatedAt, &item.UpdatedAt); err != nil {
 		return item, err
@@ -229,34 +52,15 @@ func scanGetUserByEmailRow(rows *sql.Rows) (GetUserByEmailRow, error) {
 	return item, nil
 }
 
-type GetUserByIdRow struct {
-	Id        uuid.UUID
-	Username  pgtype.Text
-	Useremail pgtype.Text
-	Metadata  *[]byte
-	Tags      pgtype.Text
-	IsActive  pgtype.Bool
-	CreatedAt *time.Time
-	UpdatedAt *time.Time
-}
-
-func scanGetUserByIdRow(rows *sql.Rows) (GetUserByIdRow, error) {
-	var item GetUserByIdRow
-	if err := rows.Scan(&item.Id, &item.Username, &item.Useremail, &item.Metadata, &item.Tags, &item.IsActive, &item.CreatedAt, &item.UpdatedAt); err != nil {
-		return item, err
-	}
-	return item, nil
-}
-
 type GetUserStatsRow struct {
 	Id           uuid.UUID
 	Username     pgtype.Text
 	PostCount    int64
 	CommentCount int64
-	TotalViews   any
+	TotalViews   int64
 }
 
-func scanGetUserStatsRow(rows *sql.Rows) (GetUserStatsRow, error) {
+func scanGetUserStatsRow(rows pgx.Rows) (GetUserStatsRow, error) {
 	var item GetUserStatsRow
 	if err := rows.Scan(&item.Id, &item.Username, &item.PostCount, &item.CommentCount, &item.TotalViews); err != nil {
 		return item, err
@@ -268,14 +72,14 @@ type ListActiveUsersRow struct {
 	Id        uuid.UUID
 	Username  pgtype.Text
 	Useremail pgtype.Text
-	Metadata  *[]byte
+	Metadata  *json.RawMessage
 	Tags      pgtype.Text
-	IsActive  pgtype.Bool
-	CreatedAt *time.Time
-	UpdatedAt *time.Time
+	IsActive  bool
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
 }
 
-func scanListActiveUsersRow(rows *sql.Rows) (ListActiveUsersRow, error) {
+func scanListActiveUsersRow(rows pgx.Rows) (ListActiveUsersRow, error) {
 	var item ListActiveUsersRow
 	if err := rows.Scan(&item.Id, &item.Username, &item.Useremail, &item.Metadata, &item.Tags, &item.IsActive, &item.CreatedAt, &item.UpdatedAt); err != nil {
 		return item, err
@@ -285,14 +89,14 @@ func scanListActiveUsersRow(rows *sql.Rows) (ListActiveUsersRow, error) {
 
 type ListCommentsByPostRow struct {
 	Id          uuid.UUID
-	PostId      *uuid.UUID
+	PostId      uuid.UUID
 	UserId      *uuid.UUID
 	Commentbody pgtype.Text
 	Likes       pgtype.Int4
-	CreatedAt   *time.Time
+	CreatedAt   pgtype.Timestamptz
 }
 
-func scanListCommentsByPostRow(rows *sql.Rows) (ListCommentsByPostRow, error) {
+func scanListCommentsByPostRow(rows pgx.Rows) (ListCommentsByPostRow, error) {
 	var item ListCommentsByPostRow
 	if err := rows.Scan(&item.Id, &item.PostId, &item.UserId, &item.Commentbody, &item.Likes, &item.CreatedAt); err != nil {
 		return item, err
@@ -303,13 +107,13 @@ func scanListCommentsByPostRow(rows *sql.Rows) (ListCommentsByPostRow, error) {
 type ListCommentsByUserRow struct {
 	Id          uuid.UUID
 	PostId      *uuid.UUID
-	UserId      *uuid.UUID
+	UserId      uuid.UUID
 	Commentbody pgtype.Text
 	Likes       pgtype.Int4
-	CreatedAt   *time.Time
+	CreatedAt   pgtype.Timestamptz
 }
 
-func scanListCommentsByUserRow(rows *sql.Rows) (ListCommentsByUserRow, error) {
+func scanListCommentsByUserRow(rows pgx.Rows) (ListCommentsByUserRow, error) {
 	var item ListCommentsByUserRow
 	if err := rows.Scan(&item.Id, &item.PostId, &item.UserId, &item.Commentbody, &item.Likes, &item.CreatedAt); err != nil {
 		return item, err
@@ -319,19 +123,19 @@ func scanListCommentsByUserRow(rows *sql.Rows) (ListCommentsByUserRow, error) {
 
 type ListPostsByUserRow struct {
 	Id          uuid.UUID
-	UserId      *uuid.UUID
+	UserId      uuid.UUID
 	Title       pgtype.Text
 	Postbody    pgtype.Text
 	Categories  pgtype.Text
 	ViewCount   pgtype.Int4
 	Rating      *decimal.Decimal
 	IsPublished pgtype.Bool
-	PublishedAt *time.Time
-	CreatedAt   *time.Time
-	UpdatedAt   *time.Time
+	PublishedAt pgtype.Timestamptz
+	CreatedAt   pgtype.Timestamptz
+	UpdatedAt   pgtype.Timestamptz
 }
 
-func scanListPostsByUserRow(rows *sql.Rows) (ListPostsByUserRow, error) {
+func scanListPostsByUserRow(rows pgx.Rows) (ListPostsByUserRow, error) {
 	var item ListPostsByUserRow
 	if err := rows.Scan(&item.Id, &item.UserId, &item.Title, &item.Postbody, &item.Categories, &item.ViewCount, &item.Rating, &item.IsPublished, &item.PublishedAt, &item.CreatedAt, &item.UpdatedAt); err != nil {
 		return item, err
@@ -339,162 +143,16 @@ func scanListPostsByUserRow(rows *sql.Rows) (ListPostsByUserRow, error) {
 	return item, nil
 }
 
-type ListPostsRow struct {
-	Id          uuid.UUID
-	UserId      *uuid.UUID
-	Title       pgtype.Text
-	Postbody    pgtype.Text
-	Categories  pgtype.Text
-	ViewCount   pgtype.Int4
-	Rating      *decimal.Decimal
-	IsPublished pgtype.Bool
-	PublishedAt *time.Time
-	CreatedAt   *time.Time
-	UpdatedAt   *time.Time
-}
-
-func scanListPostsRow(rows *sql.Rows) (ListPostsRow, error) {
-	var item ListPostsRow
-	if err := rows.Scan(&item.Id, &item.UserId, &item.Title, &item.Postbody, &item.Categories, &item.ViewCount, &item.Rating, &item.IsPublished, &item.PublishedAt, &item.CreatedAt, &item.UpdatedAt); err != nil {
-		return item, err
-	}
-	return item, nil
-}
-
-type ListPublishedPostsRow struct {
-	Id          uuid.UUID
-	UserId      *uuid.UUID
-	Title       pgtype.Text
-	Postbody    pgtype.Text
-	Categories  pgtype.Text
-	ViewCount   pgtype.Int4
-	Rating      *decimal.Decimal
-	IsPublished pgtype.Bool
-	PublishedAt *time.Time
-	CreatedAt   *time.Time
-	UpdatedAt   *time.Time
-}
-
-func scanListPublishedPostsRow(rows *sql.Rows) (ListPublishedPostsRow, error) {
-	var item ListPublishedPostsRow
-	if err := rows.Scan(&item.Id, &item.UserId, &item.Title, &item.Postbody, &item.Categories, &item.ViewCount, &item.Rating, &item.IsPublished, &item.PublishedAt, &item.CreatedAt, &item.UpdatedAt); err != nil {
-		return item, err
-	}
-	return item, nil
-}
-
-type ListTagsRow struct {
-	Id             uuid.UUID
-	Tagname        pgtype.Text
-	Tagdescription pgtype.Text
-	CreatedAt      *time.Time
-}
-
-func scanListTagsRow(rows *sql.Rows) (ListTagsRow, error) {
-	var item ListTagsRow
-	if err := rows.Scan(&item.Id, &item.Tagname, &item.Tagdescription, &item.CreatedAt); err != nil {
-		return item, err
-	}
-	return item, nil
-}
-
-type ListUsersRow struct {
-	Id        uuid.UUID
-	Username  pgtype.Text
-	Useremail pgtype.Text
-	Metadata  *[]byte
-	Tags      pgtype.Text
-	IsActive  pgtype.Bool
-	CreatedAt *time.Time
-	UpdatedAt *time.Time
-}
-
-func scanListUsersRow(rows *sql.Rows) (ListUsersRow, error) {
-	var item ListUsersRow
-	if err := rows.Scan(&item.Id, &item.Username, &item.Useremail, &item.Metadata, &item.Tags, &item.IsActive, &item.CreatedAt, &item.UpdatedAt); err != nil {
-		return item, err
-	}
-	return item, nil
-}
-
-type SearchPostsByCategoryRow struct {
-	Id          uuid.UUID
-	UserId      *uuid.UUID
-	Title       pgtype.Text
-	Postbody    pgtype.Text
-	Categories  pgtype.Text
-	ViewCount   pgtype.Int4
-	Rating      *decimal.Decimal
-	IsPublished pgtype.Bool
-	PublishedAt *time.Time
-	CreatedAt   *time.Time
-	UpdatedAt   *time.Time
-}
-
-func scanSearchPostsByCategoryRow(rows *sql.Rows) (SearchPostsByCategoryRow, error) {
-	var item SearchPostsByCategoryRow
+// scanPosts scans a row into the Posts model.
+func scanPosts(rows pgx.Rows) (Posts, error) {
+	var item Posts
 	if err := rows.Scan(&item.Id, &item.UserId, &item.Title, &item.Postbody, &item.Categories, &item.ViewCount, &item.Rating, &item.IsPublished, &item.PublishedAt, &item.CreatedAt, &item.UpdatedAt); err != nil {
 		return item, err
 	}
 	return item, nil
 }
 
-type SearchUsersByMetadataRow struct {
-	Id        uuid.UUID
-	Username  pgtype.Text
-	Useremail pgtype.Text
-	Metadata  *[]byte
-	Tags      pgtype.Text
-	IsActive  pgtype.Bool
-	CreatedAt *time.Time
-	UpdatedAt *time.Time
-}
-
-func scanSearchUsersByMetadataRow(rows *sql.Rows) (SearchUsersByMetadataRow, error) {
-	var item SearchUsersByMetadataRow
-	if err := rows.Scan(&item.Id, &item.Username, &item.Useremail, &item.Metadata, &item.Tags, &item.IsActive, &item.CreatedAt, &item.UpdatedAt); err != nil {
-		return item, err
-	}
-	return item, nil
-}
-
-type SearchUsersByTagRow struct {
-	Id        uuid.UUID
-	Username  pgtype.Text
-	Useremail pgtype.Text
-	Metadata  *[]byte
-	Tags      pgtype.Text
-	IsActive  pgtype.Bool
-	CreatedAt *time.Time
-	UpdatedAt *time.Time
-}
-
-func scanSearchUsersByTagRow(rows *sql.Rows) (SearchUsersByTagRow, error) {
-	var item SearchUsersByTagRow
-	if err := rows.Scan(&item.Id, &item.Username, &item.Useremail, &item.Metadata, &item.Tags, &item.IsActive, &item.CreatedAt, &item.UpdatedAt); err != nil {
-		return item, err
-	}
-	return item, nil
-}
-
-type UpdateCommentRow struct {
-	Id          uuid.UUID
-	PostId      *uuid.UUID
-	UserId      *uuid.UUID
-	Commentbody pgtype.Text
-	Likes       pgtype.Int4
-	CreatedAt   *time.Time
-}
-
-func scanUpdateCommentRow(rows *sql.Rows) (UpdateCommentRow, error) {
-	var item UpdateCommentRow
-	if err := rows.Scan(&item.Id, &item.PostId, &item.UserId, &item.Commentbody, &item.Likes, &item.CreatedAt); err != nil {
-		return item, err
-	}
-	return item, nil
-}
-
-type UpdatePostRow struct {
+type PostsRow struct {
 	Id          uuid.UUID
 	UserId      *uuid.UUID
 	Title       pgtype.Text
@@ -502,48 +160,32 @@ type UpdatePostRow struct {
 	Categories  pgtype.Text
 	ViewCount   pgtype.Int4
 	Rating      *decimal.Decimal
-	IsPublished pgtype.Bool
-	PublishedAt *time.Time
-	CreatedAt   *time.Time
-	UpdatedAt   *time.Time
+	IsPublished bool
+	PublishedAt pgtype.Timestamptz
+	CreatedAt   pgtype.Timestamptz
+	UpdatedAt   pgtype.Timestamptz
 }
 
-func scanUpdatePostRow(rows *sql.Rows) (UpdatePostRow, error) {
-	var item UpdatePostRow
+func scanPostsRow(rows pgx.Rows) (PostsRow, error) {
+	var item PostsRow
 	if err := rows.Scan(&item.Id, &item.UserId, &item.Title, &item.Postbody, &item.Categories, &item.ViewCount, &item.Rating, &item.IsPublished, &item.PublishedAt, &item.CreatedAt, &item.UpdatedAt); err != nil {
 		return item, err
 	}
 	return item, nil
 }
 
-type UpdateTagRow struct {
-	Id             uuid.UUID
-	Tagname        pgtype.Text
-	Tagdescription pgtype.Text
-	CreatedAt      *time.Time
-}
-
-func scanUpdateTagRow(rows *sql.Rows) (UpdateTagRow, error) {
-	var item UpdateTagRow
+// scanTags scans a row into the Tags model.
+func scanTags(rows pgx.Rows) (Tags, error) {
+	var item Tags
 	if err := rows.Scan(&item.Id, &item.Tagname, &item.Tagdescription, &item.CreatedAt); err != nil {
 		return item, err
 	}
 	return item, nil
 }
 
-type UpdateUserRow struct {
-	Id        uuid.UUID
-	Username  pgtype.Text
-	Useremail pgtype.Text
-	Metadata  *[]byte
-	Tags      pgtype.Text
-	IsActive  pgtype.Bool
-	CreatedAt *time.Time
-	UpdatedAt *time.Time
-}
-
-func scanUpdateUserRow(rows *sql.Rows) (UpdateUserRow, error) {
-	var item UpdateUserRow
+// scanUsers scans a row into the Users model.
+func scanUsers(rows pgx.Rows) (Users, error) {
+	var item Users
 	if err := rows.Scan(&item.Id, &item.Username, &item.Useremail, &item.Metadata, &item.Tags, &item.IsActive, &item.CreatedAt, &item.UpdatedAt); err != nil {
 		return item, err
 	}
@@ -1,6 +1,8 @@
 package postgresqldb
 
 import (
+	"encoding/json"
+
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/shopspring/decimal"
@@ -37,7 +39,7 @@ type Users struct {
 	Id        uuid.UUID
 	Username  pgtype.Text
 	Useremail pgtype.Text
-	Metadata  *[]byte
+	Metadata  *json.RawMessage
 	Tags      pgtype.Text
 	IsActive  pgtype.Bool
 	CreatedAt pgtype.Timestamptz
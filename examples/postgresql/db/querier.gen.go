@@ -2,55 +2,56 @@ package postgresqldb
 
 import (
 	"context"
-	"database/sql"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/pgtype"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
 	AddTagToPost(ctx context.Context, arg AddTagToPostParams) error
-	CreateComment(ctx context.Context, arg CreateCommentParams) (CreateCommentRow, error)
-	CreatePost(ctx context.Context, arg CreatePostParams) (CreatePostRow, error)
-	CreateTag(ctx context.Context, arg CreateTagParams) (CreateTagRow, error)
-	CreateUser(ctx context.Context, arg CreateUserParams) (CreateUserRow, error)
+	CreateComment(ctx context.Context, arg CreateCommentParams) (Comments, error)
+	CreatePost(ctx context.Context, arg CreatePostParams) (Posts, error)
+	CreateTag(ctx context.Context, arg CreateTagParams) (Tags, error)
+	CreateUser(ctx context.Context, arg CreateUserParams) (Users, error)
 	DeleteComment(ctx context.Context, id uuid.UUID) error
 	DeletePost(ctx context.Context, id uuid.UUID) error
 	DeleteTag(ctx context.Context, id uuid.UUID) error
 	DeleteUser(ctx context.Context, id uuid.UUID) error
-	GetCommentById(ctx context.Context, id uuid.UUID) (GetCommentByIdRow, error)
-	GetPopularPosts(ctx context.Context, limit any) ([]GetPopularPostsRow, error)
-	GetPostById(ctx context.Context, id uuid.UUID) (GetPostByIdRow, error)
-	GetPostsForTag(ctx context.Context, tagId *uuid.UUID) ([]GetPostsForTagRow, error)
-	GetTagById(ctx context.Context, id uuid.UUID) (GetTagByIdRow, error)
+	GetCommentById(ctx context.Context, id uuid.UUID) (Comments, error)
+	GetPopularPosts(ctx context.Context, limit int64) ([]PostsRow, error)
+	GetPostById(ctx context.Context, id uuid.UUID) (Posts, error)
+	GetPostsForTag(ctx context.Context, tagId *uuid.UUID) ([]PostsRow, error)
+	GetTagById(ctx context.Context, id uuid.UUID) (Tags, error)
 	GetTagByName(ctx context.Context, tagname pgtype.Text) (GetTagByNameRow, error)
-	GetTagsForPost(ctx context.Context, postId *uuid.UUID) ([]GetTagsForPostRow, error)
+	GetTagsForPost(ctx context.Context, postId *uuid.UUID) ([]Tags, error)
 	GetUserByEmail(ctx context.Context, useremail pgtype.Text) (GetUserByEmailRow, error)
-	GetUserById(ctx context.Context, id uuid.UUID) (GetUserByIdRow, error)
+	GetUserById(ctx context.Context, id uuid.UUID) (Users, error)
 	GetUserStats(ctx context.Context, id uuid.UUID) (GetUserStatsRow, error)
 	IncrementPostViews(ctx context.Context, id uuid.UUID) error
 	LikeComment(ctx context.Context, id uuid.UUID) error
 	ListActiveUsers(ctx context.Context) ([]ListActiveUsersRow, error)
 	ListCommentsByPost(ctx context.Context, postId *uuid.UUID) ([]ListCommentsByPostRow, error)
 	ListCommentsByUser(ctx context.Context, userId *uuid.UUID) ([]ListCommentsByUserRow, error)
-	ListPosts(ctx context.Context) ([]ListPostsRow, error)
+	ListPosts(ctx context.Context) ([]Posts, error)
 	ListPostsByUser(ctx context.Context, userId *uuid.UUID) ([]ListPostsByUserRow, error)
-	ListPublishedPosts(ctx context.Context) ([]ListPublishedPostsRow, error)
-	ListTags(ctx context.Context) ([]ListTagsRow, error)
-	ListUsers(ctx context.Context) ([]ListUsersRow, error)
+	ListPublishedPosts(ctx context.Context) ([]PostsRow, error)
+	ListTags(ctx context.Context) ([]Tags, error)
+	ListUsers(ctx context.Context) ([]Users, error)
 	RemoveTagFromPost(ctx context.Context, arg RemoveTagFromPostParams) error
-	SearchPostsByCategory(ctx context.Context, any any) ([]SearchPostsByCategoryRow, error)
-	SearchUsersByMetadata(ctx context.Context, p any) ([]SearchUsersByMetadataRow, error)
-	SearchUsersByTag(ctx context.Context, any any) ([]SearchUsersByTagRow, error)
-	UpdateComment(ctx context.Context, arg UpdateCommentParams) (UpdateCommentRow, error)
-	UpdatePost(ctx context.Context, arg UpdatePostParams) (UpdatePostRow, error)
-	UpdateTag(ctx context.Context, arg UpdateTagParams) (UpdateTagRow, error)
-	UpdateUser(ctx context.Context, arg UpdateUserParams) (UpdateUserRow, error)
+	SearchPostsByCategory(ctx context.Context, any any) ([]PostsRow, error)
+	SearchUsersByMetadata(ctx context.Context, p any) ([]Users, error)
+	SearchUsersByTag(ctx context.Context, any any) ([]Users, error)
+	UpdateComment(ctx context.Context, arg UpdateCommentParams) (Comments, error)
+	UpdatePost(ctx context.Context, arg UpdatePostParams) (Posts, error)
+	UpdateTag(ctx context.Context, arg UpdateTagParams) (Tags, error)
+	UpdateUser(ctx context.Context, arg UpdateUserParams) (Users, error)
 }
 type DBTX interface {
-	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
-	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
-	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	Exec(ctx context.Context, query string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, query string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, query string, args ...any) pgx.Row
 }
 type Queries struct {
 	db DBTX
@@ -7,13 +7,13 @@ import (
 )
 
 type AddTagToPostParams struct {
-	PostId  *uuid.UUID
-	PostId2 *uuid.UUID
+	PostId *uuid.UUID
+	TagId  *uuid.UUID
 }
 
 const queryAddTagToPost string = `INSERT INTO post_tags (post_id, tag_id) VALUES ($1, $2);`
 
 func (q *Queries) AddTagToPost(ctx context.Context, arg AddTagToPostParams) error {
-	_, err := q.db.ExecContext(ctx, queryAddTagToPost, arg.PostId, arg.PostId2)
+	_, err := q.db.Exec(ctx, queryAddTagToPost, arg.PostId, arg.TagId)
 	return err
 }
@@ -2,40 +2,28 @@ package postgresqldb
 
 import (
 	"context"
-	"database/sql"
+	"encoding/json"
 
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type CreateUserParams struct {
 	Username  pgtype.Text
-	Username2 pgtype.Text
-	Username3 *[]byte
-	Username4 pgtype.Text
+	Useremail pgtype.Text
+	Metadata  *json.RawMessage
+	Tags      pgtype.Text
 }
 
 const queryCreateUser string = `INSERT INTO users (username, useremail, metadata, tags)
 VALUES ($1, $2, $3, $4)
 RETURNING *;`
 
-func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (CreateUserRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryCreateUser, arg.Username, arg.Username2, arg.Username3, arg.Username4)
+func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (Users, error) {
+	row := q.db.QueryRow(ctx, queryCreateUser, arg.Username, arg.Useremail, arg.Metadata, arg.Tags)
+	var item Users
+	err := row.Scan(&item.Id, &item.Username, &item.Useremail, &item.Metadata, &item.Tags, &item.IsActive, &item.CreatedAt, &item.UpdatedAt)
 	if err != nil {
-		return CreateUserRow{}, err
-	}
-	defer rows.Close()
-	if !rows.Next() {
-		if err := rows.Err(); err != nil {
-			return CreateUserRow{}, err
-		}
-		return CreateUserRow{}, sql.ErrNoRows
-	}
-	item, err := scanCreateUserRow(rows)
-	if err != nil {
-		return item, err
-	}
-	if err := rows.Err(); err != nil {
-		return item, err
+		return Users{}, err
 	}
 	return item, nil
 }
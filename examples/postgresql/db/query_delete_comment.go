@@ -9,6 +9,6 @@ import (
 const queryDeleteComment string = `DELETE FROM comments WHERE id = $1;`
 
 func (q *Queries) DeleteComment(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, queryDeleteComment, id)
+	_, err := q.db.Exec(ctx, queryDeleteComment, id)
 	return err
 }
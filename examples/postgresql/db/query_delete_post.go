@@ -9,6 +9,6 @@ import (
 const queryDeletePost string = `DELETE FROM posts WHERE id = $1;`
 
 func (q *Queries) DeletePost(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, queryDeletePost, id)
+	_, err := q.db.Exec(ctx, queryDeletePost, id)
 	return err
 }
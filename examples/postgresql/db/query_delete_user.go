@@ -9,6 +9,6 @@ import (
 const queryDeleteUser string = `DELETE FROM users WHERE id = $1;`
 
 func (q *Queries) DeleteUser(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, queryDeleteUser, id)
+	_, err := q.db.Exec(ctx, queryDeleteUser, id)
 	return err
 }
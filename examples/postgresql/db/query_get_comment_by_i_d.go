@@ -2,31 +2,18 @@ package postgresqldb
 
 import (
 	"context"
-	"database/sql"
 
 	"github.com/google/uuid"
 )
 
 const queryGetCommentById string = `SELECT * FROM comments WHERE id = $1;`
 
-func (q *Queries) GetCommentById(ctx context.Context, id uuid.UUID) (GetCommentByIdRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryGetCommentById, id)
+func (q *Queries) GetCommentById(ctx context.Context, id uuid.UUID) (Comments, error) {
+	row := q.db.QueryRow(ctx, queryGetCommentById, id)
+	var item Comments
+	err := row.Scan(&item.Id, &item.PostId, &item.UserId, &item.Commentbody, &item.Likes, &item.CreatedAt)
 	if err != nil {
-		return GetCommentByIdRow{}, err
-	}
-	defer rows.Close()
-	if !rows.Next() {
-		if err := rows.Err(); err != nil {
-			return GetCommentByIdRow{}, err
-		}
-		return GetCommentByIdRow{}, sql.ErrNoRows
-	}
-	item, err := scanGetCommentByIdRow(rows)
-	if err != nil {
-		return item, err
-	}
-	if err := rows.Err(); err != nil {
-		return item, err
+		return Comments{}, err
 	}
 	return item, nil
 }
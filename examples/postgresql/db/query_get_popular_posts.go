@@ -7,15 +7,15 @@ WHERE is_published = true
 ORDER BY view_count DESC, published_at DESC
 LIMIT $1;`
 
-func (q *Queries) GetPopularPosts(ctx context.Context, limit any) ([]GetPopularPostsRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryGetPopularPosts, limit)
+func (q *Queries) GetPopularPosts(ctx context.Context, limit int64) ([]PostsRow, error) {
+	rows, err := q.db.Query(ctx, queryGetPopularPosts, limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetPopularPostsRow
+	var items []PostsRow
 	for rows.Next() {
-		item, err := scanGetPopularPostsRow(rows)
+		item, err := scanPostsRow(rows)
 		if err != nil {
 			return nil, err
 		}
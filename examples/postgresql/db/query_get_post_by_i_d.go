@@ -2,31 +2,18 @@ package postgresqldb
 
 import (
 	"context"
-	"database/sql"
 
 	"github.com/google/uuid"
 )
 
 const queryGetPostById string = `SELECT * FROM posts WHERE id = $1;`
 
-func (q *Queries) GetPostById(ctx context.Context, id uuid.UUID) (GetPostByIdRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryGetPostById, id)
+func (q *Queries) GetPostById(ctx context.Context, id uuid.UUID) (Posts, error) {
+	row := q.db.QueryRow(ctx, queryGetPostById, id)
+	var item Posts
+	err := row.Scan(&item.Id, &item.UserId, &item.Title, &item.Postbody, &item.Categories, &item.ViewCount, &item.Rating, &item.IsPublished, &item.PublishedAt, &item.CreatedAt, &item.UpdatedAt)
 	if err != nil {
-		return GetPostByIdRow{}, err
-	}
-	defer rows.Close()
-	if !rows.Next() {
-		if err := rows.Err(); err != nil {
-			return GetPostByIdRow{}, err
-		}
-		return GetPostByIdRow{}, sql.ErrNoRows
-	}
-	item, err := scanGetPostByIdRow(rows)
-	if err != nil {
-		return item, err
-	}
-	if err := rows.Err(); err != nil {
-		return item, err
+		return Posts{}, err
 	}
 	return item, nil
 }
@@ -12,15 +12,15 @@ WHERE pt.tag_id = $1 AND p.is_published = true;
 
 -- Complex queries`
 
-func (q *Queries) GetPostsForTag(ctx context.Context, tagId *uuid.UUID) ([]GetPostsForTagRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryGetPostsForTag, tagId)
+func (q *Queries) GetPostsForTag(ctx context.Context, tagId *uuid.UUID) ([]PostsRow, error) {
+	rows, err := q.db.Query(ctx, queryGetPostsForTag, tagId)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetPostsForTagRow
+	var items []PostsRow
 	for rows.Next() {
-		item, err := scanGetPostsForTagRow(rows)
+		item, err := scanPostsRow(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -2,7 +2,6 @@ package postgresqldb
 
 import (
 	"context"
-	"database/sql"
 
 	"github.com/jackc/pgx/v5/pgtype"
 )
@@ -10,23 +9,11 @@ import (
 const queryGetTagByName string = `SELECT * FROM tags WHERE tagname = $1;`
 
 func (q *Queries) GetTagByName(ctx context.Context, tagname pgtype.Text) (GetTagByNameRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryGetTagByName, tagname)
+	row := q.db.QueryRow(ctx, queryGetTagByName, tagname)
+	var item GetTagByNameRow
+	err := row.Scan(&item.Id, &item.Tagname, &item.Tagdescription, &item.CreatedAt)
 	if err != nil {
 		return GetTagByNameRow{}, err
 	}
-	defer rows.Close()
-	if !rows.Next() {
-		if err := rows.Err(); err != nil {
-			return GetTagByNameRow{}, err
-		}
-		return GetTagByNameRow{}, sql.ErrNoRows
-	}
-	item, err := scanGetTagByNameRow(rows)
-	if err != nil {
-		return item, err
-	}
-	if err := rows.Err(); err != nil {
-		return item, err
-	}
 	return item, nil
 }
@@ -10,15 +10,15 @@ const queryGetTagsForPost string = `SELECT t.* FROM tags t
 JOIN post_tags pt ON t.id = pt.tag_id
 WHERE pt.post_id = $1;`
 
-func (q *Queries) GetTagsForPost(ctx context.Context, postId *uuid.UUID) ([]GetTagsForPostRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryGetTagsForPost, postId)
+func (q *Queries) GetTagsForPost(ctx context.Context, postId *uuid.UUID) ([]Tags, error) {
+	rows, err := q.db.Query(ctx, queryGetTagsForPost, postId)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetTagsForPostRow
+	var items []Tags
 	for rows.Next() {
-		item, err := scanGetTagsForPostRow(rows)
+		item, err := scanTags(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -2,31 +2,18 @@ package postgresqldb
 
 import (
 	"context"
-	"database/sql"
 
 	"github.com/google/uuid"
 )
 
 const queryGetUserById string = `SELECT * FROM users WHERE id = $1;`
 
-func (q *Queries) GetUserById(ctx context.Context, id uuid.UUID) (GetUserByIdRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryGetUserById, id)
+func (q *Queries) GetUserById(ctx context.Context, id uuid.UUID) (Users, error) {
+	row := q.db.QueryRow(ctx, queryGetUserById, id)
+	var item Users
+	err := row.Scan(&item.Id, &item.Username, &item.Useremail, &item.Metadata, &item.Tags, &item.IsActive, &item.CreatedAt, &item.UpdatedAt)
 	if err != nil {
-		return GetUserByIdRow{}, err
-	}
-	defer rows.Close()
-	if !rows.Next() {
-		if err := rows.Err(); err != nil {
-			return GetUserByIdRow{}, err
-		}
-		return GetUserByIdRow{}, sql.ErrNoRows
-	}
-	item, err := scanGetUserByIdRow(rows)
-	if err != nil {
-		return item, err
-	}
-	if err := rows.Err(); err != nil {
-		return item, err
+		return Users{}, err
 	}
 	return item, nil
 }
@@ -2,7 +2,6 @@ package postgresqldb
 
 import (
 	"context"
-	"database/sql"
 
 	"github.com/google/uuid"
 )
@@ -20,23 +19,11 @@ WHERE u.id = $1
 GROUP BY u.id, u.username;`
 
 func (q *Queries) GetUserStats(ctx context.Context, id uuid.UUID) (GetUserStatsRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryGetUserStats, id)
+	row := q.db.QueryRow(ctx, queryGetUserStats, id)
+	var item GetUserStatsRow
+	err := row.Scan(&item.Id, &item.Username, &item.PostCount, &item.CommentCount, &item.TotalViews)
 	if err != nil {
 		return GetUserStatsRow{}, err
 	}
-	defer rows.Close()
-	if !rows.Next() {
-		if err := rows.Err(); err != nil {
-			return GetUserStatsRow{}, err
-		}
-		return GetUserStatsRow{}, sql.ErrNoRows
-	}
-	item, err := scanGetUserStatsRow(rows)
-	if err != nil {
-		return item, err
-	}
-	if err := rows.Err(); err != nil {
-		return item, err
-	}
 	return item, nil
 }
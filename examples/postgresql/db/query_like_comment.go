@@ -11,6 +11,6 @@ const queryLikeComment string = `UPDATE comments SET likes = likes + 1 WHERE id
 -- Tag queries`
 
 func (q *Queries) LikeComment(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, queryLikeComment, id)
+	_, err := q.db.Exec(ctx, queryLikeComment, id)
 	return err
 }
@@ -5,7 +5,7 @@ import "context"
 const queryListActiveUsers string = `SELECT * FROM users WHERE is_active = true ORDER BY created_at DESC;`
 
 func (q *Queries) ListActiveUsers(ctx context.Context) ([]ListActiveUsersRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryListActiveUsers)
+	rows, err := q.db.Query(ctx, queryListActiveUsers)
 	if err != nil {
 		return nil, err
 	}
@@ -9,7 +9,7 @@ import (
 const queryListCommentsByPost string = `SELECT * FROM comments WHERE post_id = $1 ORDER BY created_at DESC;`
 
 func (q *Queries) ListCommentsByPost(ctx context.Context, postId *uuid.UUID) ([]ListCommentsByPostRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryListCommentsByPost, postId)
+	rows, err := q.db.Query(ctx, queryListCommentsByPost, postId)
 	if err != nil {
 		return nil, err
 	}
@@ -9,7 +9,7 @@ import (
 const queryListCommentsByUser string = `SELECT * FROM comments WHERE user_id = $1 ORDER BY created_at DESC;`
 
 func (q *Queries) ListCommentsByUser(ctx context.Context, userId *uuid.UUID) ([]ListCommentsByUserRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryListCommentsByUser, userId)
+	rows, err := q.db.Query(ctx, queryListCommentsByUser, userId)
 	if err != nil {
 		return nil, err
 	}
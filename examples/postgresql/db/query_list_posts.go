@@ -4,15 +4,15 @@ import "context"
 
 const queryListPosts string = `SELECT * FROM posts ORDER BY created_at DESC;`
 
-func (q *Queries) ListPosts(ctx context.Context) ([]ListPostsRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryListPosts)
+func (q *Queries) ListPosts(ctx context.Context) ([]Posts, error) {
+	rows, err := q.db.Query(ctx, queryListPosts)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListPostsRow
+	var items []Posts
 	for rows.Next() {
-		item, err := scanListPostsRow(rows)
+		item, err := scanPosts(rows)
 		if err != nil {
 			return nil, err
 		}
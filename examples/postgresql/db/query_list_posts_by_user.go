@@ -9,7 +9,7 @@ import (
 const queryListPostsByUser string = `SELECT * FROM posts WHERE user_id = $1 ORDER BY created_at DESC;`
 
 func (q *Queries) ListPostsByUser(ctx context.Context, userId *uuid.UUID) ([]ListPostsByUserRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryListPostsByUser, userId)
+	rows, err := q.db.Query(ctx, queryListPostsByUser, userId)
 	if err != nil {
 		return nil, err
 	}
@@ -4,15 +4,15 @@ import "context"
 
 const queryListPublishedPosts string = `SELECT * FROM posts WHERE is_published = true ORDER BY published_at DESC;`
 
-func (q *Queries) ListPublishedPosts(ctx context.Context) ([]ListPublishedPostsRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryListPublishedPosts)
+func (q *Queries) ListPublishedPosts(ctx context.Context) ([]PostsRow, error) {
+	rows, err := q.db.Query(ctx, queryListPublishedPosts)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListPublishedPostsRow
+	var items []PostsRow
 	for rows.Next() {
-		item, err := scanListPublishedPostsRow(rows)
+		item, err := scanPostsRow(rows)
 		if err != nil {
 			return nil, err
 		}
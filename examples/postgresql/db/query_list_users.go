@@ -4,15 +4,15 @@ import "context"
 
 const queryListUsers string = `SELECT * FROM users ORDER BY created_at DESC;`
 
-func (q *Queries) ListUsers(ctx context.Context) ([]ListUsersRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryListUsers)
+func (q *Queries) ListUsers(ctx context.Context) ([]Users, error) {
+	rows, err := q.db.Query(ctx, queryListUsers)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListUsersRow
+	var items []Users
 	for rows.Next() {
-		item, err := scanListUsersRow(rows)
+		item, err := scanUsers(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -14,6 +14,6 @@ type RemoveTagFromPostParams struct {
 const queryRemoveTagFromPost string = `DELETE FROM post_tags WHERE post_id = $1 AND tag_id = $2;`
 
 func (q *Queries) RemoveTagFromPost(ctx context.Context, arg RemoveTagFromPostParams) error {
-	_, err := q.db.ExecContext(ctx, queryRemoveTagFromPost, arg.PostId, arg.TagId)
+	_, err := q.db.Exec(ctx, queryRemoveTagFromPost, arg.PostId, arg.TagId)
 	return err
 }
@@ -6,15 +6,15 @@ const querySearchPostsByCategory string = `SELECT * FROM posts WHERE $1 = ANY(ca
 
 -- Comment queries`
 
-func (q *Queries) SearchPostsByCategory(ctx context.Context, any any) ([]SearchPostsByCategoryRow, error) {
-	rows, err := q.db.QueryContext(ctx, querySearchPostsByCategory, any)
+func (q *Queries) SearchPostsByCategory(ctx context.Context, any any) ([]PostsRow, error) {
+	rows, err := q.db.Query(ctx, querySearchPostsByCategory, any)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []SearchPostsByCategoryRow
+	var items []PostsRow
 	for rows.Next() {
-		item, err := scanSearchPostsByCategoryRow(rows)
+		item, err := scanPostsRow(rows)
 		if err != nil {
 			return nil, err
 		}
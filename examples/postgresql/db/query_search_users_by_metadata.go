@@ -6,15 +6,15 @@ const querySearchUsersByMetadata string = `SELECT * FROM users WHERE metadata @>
 
 -- Post queries`
 
-func (q *Queries) SearchUsersByMetadata(ctx context.Context, p any) ([]SearchUsersByMetadataRow, error) {
-	rows, err := q.db.QueryContext(ctx, querySearchUsersByMetadata, p)
+func (q *Queries) SearchUsersByMetadata(ctx context.Context, p any) ([]Users, error) {
+	rows, err := q.db.Query(ctx, querySearchUsersByMetadata, p)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []SearchUsersByMetadataRow
+	var items []Users
 	for rows.Next() {
-		item, err := scanSearchUsersByMetadataRow(rows)
+		item, err := scanUsers(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -4,15 +4,15 @@ import "context"
 
 const querySearchUsersByTag string = `SELECT * FROM users WHERE $1 = ANY(tags);`
 
-func (q *Queries) SearchUsersByTag(ctx context.Context, any any) ([]SearchUsersByTagRow, error) {
-	rows, err := q.db.QueryContext(ctx, querySearchUsersByTag, any)
+func (q *Queries) SearchUsersByTag(ctx context.Context, any any) ([]Users, error) {
+	rows, err := q.db.Query(ctx, querySearchUsersByTag, any)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []SearchUsersByTagRow
+	var items []Users
 	for rows.Next() {
-		item, err := scanSearchUsersByTagRow(rows)
+		item, err := scanUsers(rows)
 		if err != nil {
 			return nil, err
 		}
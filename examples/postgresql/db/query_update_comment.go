@@ -2,7 +2,6 @@ package postgresqldb
 
 import (
 	"context"
-	"database/sql"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -15,24 +14,12 @@ type UpdateCommentParams struct {
 
 const queryUpdateComment string = `UPDATE comments SET commentbody = $2 WHERE id = $1 RETURNING *;`
 
-func (q *Queries) UpdateComment(ctx context.Context, arg UpdateCommentParams) (UpdateCommentRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryUpdateComment, arg.Commentbody, arg.Id)
+func (q *Queries) UpdateComment(ctx context.Context, arg UpdateCommentParams) (Comments, error) {
+	row := q.db.QueryRow(ctx, queryUpdateComment, arg.Commentbody, arg.Id)
+	var item Comments
+	err := row.Scan(&item.Id, &item.PostId, &item.UserId, &item.Commentbody, &item.Likes, &item.CreatedAt)
 	if err != nil {
-		return UpdateCommentRow{}, err
-	}
-	defer rows.Close()
-	if !rows.Next() {
-		if err := rows.Err(); err != nil {
-			return UpdateCommentRow{}, err
-		}
-		return UpdateCommentRow{}, sql.ErrNoRows
-	}
-	item, err := scanUpdateCommentRow(rows)
-	if err != nil {
-		return item, err
-	}
-	if err := rows.Err(); err != nil {
-		return item, err
+		return Comments{}, err
 	}
 	return item, nil
 }
@@ -2,7 +2,6 @@ package postgresqldb
 
 import (
 	"context"
-	"database/sql"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -24,24 +23,12 @@ SET title = $2, postbody = $3, categories = $4, is_published = $5,
 WHERE id = $1
 RETURNING *;`
 
-func (q *Queries) UpdatePost(ctx context.Context, arg UpdatePostParams) (UpdatePostRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryUpdatePost, arg.Title, arg.Postbody, arg.Categories, arg.IsPublished, arg.PublishedAt, arg.Id)
+func (q *Queries) UpdatePost(ctx context.Context, arg UpdatePostParams) (Posts, error) {
+	row := q.db.QueryRow(ctx, queryUpdatePost, arg.Title, arg.Postbody, arg.Categories, arg.IsPublished, arg.PublishedAt, arg.Id)
+	var item Posts
+	err := row.Scan(&item.Id, &item.UserId, &item.Title, &item.Postbody, &item.Categories, &item.ViewCount, &item.Rating, &item.IsPublished, &item.PublishedAt, &item.CreatedAt, &item.UpdatedAt)
 	if err != nil {
-		return UpdatePostRow{}, err
-	}
-	defer rows.Close()
-	if !rows.Next() {
-		if err := rows.Err(); err != nil {
-			return UpdatePostRow{}, err
-		}
-		return UpdatePostRow{}, sql.ErrNoRows
-	}
-	item, err := scanUpdatePostRow(rows)
-	if err != nil {
-		return item, err
-	}
-	if err := rows.Err(); err != nil {
-		return item, err
+		return Posts{}, err
 	}
 	return item, nil
 }
@@ -2,7 +2,6 @@ package postgresqldb
 
 import (
 	"context"
-	"database/sql"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -16,24 +15,12 @@ type UpdateTagParams struct {
 
 const queryUpdateTag string = `UPDATE tags SET tagname = $2, tagdescription = $3 WHERE id = $1 RETURNING *;`
 
-func (q *Queries) UpdateTag(ctx context.Context, arg UpdateTagParams) (UpdateTagRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryUpdateTag, arg.Tagname, arg.Tagdescription, arg.Id)
+func (q *Queries) UpdateTag(ctx context.Context, arg UpdateTagParams) (Tags, error) {
+	row := q.db.QueryRow(ctx, queryUpdateTag, arg.Tagname, arg.Tagdescription, arg.Id)
+	var item Tags
+	err := row.Scan(&item.Id, &item.Tagname, &item.Tagdescription, &item.CreatedAt)
 	if err != nil {
-		return UpdateTagRow{}, err
-	}
-	defer rows.Close()
-	if !rows.Next() {
-		if err := rows.Err(); err != nil {
-			return UpdateTagRow{}, err
-		}
-		return UpdateTagRow{}, sql.ErrNoRows
-	}
-	item, err := scanUpdateTagRow(rows)
-	if err != nil {
-		return item, err
-	}
-	if err := rows.Err(); err != nil {
-		return item, err
+		return Tags{}, err
 	}
 	return item, nil
 }
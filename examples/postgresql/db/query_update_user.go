@@ -2,7 +2,7 @@ package postgresqldb
 
 import (
 	"context"
-	"database/sql"
+	"encoding/json"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -11,7 +11,7 @@ import (
 type UpdateUserParams struct {
 	Username  pgtype.Text
 	Useremail pgtype.Text
-	Metadata  *[]byte
+	Metadata  *json.RawMessage
 	Tags      pgtype.Text
 	Id        uuid.UUID
 }
@@ -21,24 +21,12 @@ SET username = $2, useremail = $3, metadata = $4, tags = $5, updated_at = NOW()
 WHERE id = $1
 RETURNING *;`
 
-func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (UpdateUserRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryUpdateUser, arg.Username, arg.Useremail, arg.Metadata, arg.Tags, arg.Id)
+func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (Users, error) {
+	row := q.db.QueryRow(ctx, queryUpdateUser, arg.Username, arg.Useremail, arg.Metadata, arg.Tags, arg.Id)
+	var item Users
+	err := row.Scan(&item.Id, &item.Username, &item.Useremail, &item.Metadata, &item.Tags, &item.IsActive, &item.CreatedAt, &item.UpdatedAt)
 	if err != nil {
-		return UpdateUserRow{}, err
-	}
-	defer rows.Close()
-	if !rows.Next() {
-		if err := rows.Err(); err != nil {
-			return UpdateUserRow{}, err
-		}
-		return UpdateUserRow{}, sql.ErrNoRows
-	}
-	item, err := scanUpdateUserRow(rows)
-	if err != nil {
-		return item, err
-	}
-	if err := rows.Err(); err != nil {
-		return item, err
+		return Users{}, err
 	}
 	return item, nil
 }
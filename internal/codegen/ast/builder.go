@@ -11,6 +11,7 @@ import (
 	"go/parser"
 	"go/token"
 	"maps"
+	"path"
 	"slices"
 	"strconv"
 	"strings"
@@ -22,6 +23,7 @@ import (
 	"github.com/electwix/db-catalyst/internal/query/analyzer"
 	"github.com/electwix/db-catalyst/internal/query/block"
 	"github.com/electwix/db-catalyst/internal/schema/model"
+	"github.com/electwix/db-catalyst/internal/types"
 )
 
 // PreparedOptions captures prepared-query generation toggles.
@@ -33,13 +35,26 @@ type PreparedOptions struct {
 
 // Options configures the AST builder.
 type Options struct {
-	Package             string
-	EmitJSONTags        bool
-	EmitEmptySlices     bool
-	EmitPointersForNull bool
-	Prepared            PreparedOptions
-	TypeResolver        *TypeResolver
-	ColumnOverrides     []config.ColumnOverride
+	Package         string
+	EmitJSONTags    bool
+	EmitEmptySlices bool
+	// NullStyle represents nullable values; when empty the TypeResolver's
+	// style is used.
+	NullStyle config.NullStyle
+	// NullOption is the generic type of config.NullStyleOption.
+	NullOption      config.GoTypeDetails
+	Prepared        PreparedOptions
+	TypeResolver    *TypeResolver
+	ColumnOverrides []config.ColumnOverride
+	Database        config.Database
+	SQLPackage      config.SQLPackage
+	SQLiteTypes     config.SQLiteTypes
+	SQLiteDriver    config.Driver
+	// DriverHelpers emits Open and the error helpers of the SQLiteDriver.
+	DriverHelpers bool
+	// ConstraintErrors emits ClassifyError and the errors of the catalog's
+	// constraints.
+	ConstraintErrors config.ConstraintErrors
 }
 
 // File represents an AST file ready for rendering.
@@ -52,6 +67,35 @@ type File struct {
 // Builder constructs Go AST files for code generation outputs.
 type Builder struct {
 	opts Options
+	// models holds the table models of the current build by table name,
+	// for results that embed or reuse them.
+	models map[string]*tableModel
+	// helpers holds the result helpers built so far by the shape of their
+	// fields, so that queries with identical results share one Row type.
+	helpers map[string]*helperSpec
+	// sharedRows names the Row types that several queries share, by shape.
+	sharedRows map[string]string
+	// named holds the @returns and @params types built so far by name.
+	named map[string]namedType
+	// catalog is the schema of the current build.
+	catalog *model.Catalog
+	// enums holds the Go types generated for the schema enums.
+	enums map[*model.Enum]*enumSpec
+	// domains holds the Go types generated for the schema domains, nil for
+	// domains that use their base type.
+	domains map[*model.Domain]*domainSpec
+	// sqliteTime and sqliteJSON record whether the generated code calls the
+	// [sqlite_types] adapters, which are emitted only when used.
+	sqliteTime bool
+	sqliteJSON bool
+}
+
+// namedType is a result or params type named by a query annotation, with the
+// query that declared it first.
+type namedType struct {
+	query  string
+	helper *helperSpec
+	params *paramStructSpec
 }
 
 // New returns a builder configured with the provided options.
@@ -59,6 +103,36 @@ func New(options Options) *Builder {
 	return &Builder{opts: options}
 }
 
+// Import paths of the pgx packages generated code uses. They are declared
+// explicitly since goimports may resolve pgx to an older major version.
+const (
+	pgxImport    = "github.com/jackc/pgx/v5"
+	pgconnImport = "github.com/jackc/pgx/v5/pgconn"
+)
+
+// usesPgx reports whether generated code targets pgx/v5 directly instead of
+// database/sql.
+func (b *Builder) usesPgx() bool {
+	return b.opts.SQLPackage == config.SQLPackagePgxV5
+}
+
+// dbMethod returns the DBTX method for the database/sql method name without
+// its Context suffix; the pgx methods take a context under the plain name.
+func (b *Builder) dbMethod(name string) string {
+	if b.usesPgx() {
+		return name
+	}
+	return name + "Context"
+}
+
+// noRowsErr returns the error a QueryRow scan reports when no row matches.
+func (b *Builder) noRowsErr() string {
+	if b.usesPgx() {
+		return "pgx.ErrNoRows"
+	}
+	return "sql.ErrNoRows"
+}
+
 // Build produces the Go AST files for the provided catalog and analyses.
 func (b *Builder) Build(ctx context.Context, catalog *model.Catalog, analyses []analyzer.Result) ([]File, error) {
 	if err := ctx.Err(); err != nil {
@@ -69,11 +143,32 @@ func (b *Builder) Build(ctx context.Context, catalog *model.Catalog, analyses []
 	if packageName == "" {
 		packageName = "db"
 	}
+	if b.usesPgx() && b.opts.Prepared.Enabled {
+		return nil, fmt.Errorf("prepared queries are not supported with sql_package %q", b.opts.SQLPackage)
+	}
+
+	b.catalog = catalog
+	b.enums = nil
+	b.domains = nil
+	b.sqliteTime = false
+	b.sqliteJSON = false
+	if catalog != nil {
+		for _, enum := range catalog.Enums {
+			b.enumSpecFor(enum)
+		}
+		for _, domain := range catalog.Domains {
+			b.domainSpecFor(domain)
+		}
+	}
 
 	tableModels, err := b.collectTableModels(catalog, analyses)
 	if err != nil {
 		return nil, err
 	}
+	b.models = make(map[string]*tableModel, len(tableModels))
+	for _, mdl := range tableModels {
+		b.models[mdl.tableName] = mdl
+	}
 	queries, err := b.buildQueries(analyses)
 	if err != nil {
 		return nil, err
@@ -81,7 +176,7 @@ func (b *Builder) Build(ctx context.Context, catalog *model.Catalog, analyses []
 
 	helperPtrs := make([]*helperSpec, 0, len(queries))
 	for i := range queries {
-		if queries[i].helper != nil {
+		if queries[i].helper != nil && !slices.Contains(helperPtrs, queries[i].helper) {
 			helperPtrs = append(helperPtrs, queries[i].helper)
 		}
 	}
@@ -99,6 +194,22 @@ func (b *Builder) Build(ctx context.Context, catalog *model.Catalog, analyses []
 		files = append(files, File{Path: "models.gen.go", Node: node})
 	}
 
+	if len(b.enums) > 0 {
+		enumsFile, err := b.buildEnumsFile(packageName)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, enumsFile)
+	}
+
+	if specs := b.domainTypes(); len(specs) > 0 {
+		domainsFile, err := b.buildDomainsFile(packageName, specs)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, domainsFile)
+	}
+
 	querierNode, err := b.buildQuerierFile(packageName, queries)
 	if err != nil {
 		return nil, err
@@ -119,6 +230,38 @@ func (b *Builder) Build(ctx context.Context, catalog *model.Catalog, analyses []
 	}
 	files = append(files, queryFiles...)
 
+	usesCopyFrom := slices.ContainsFunc(queries, func(q queryInfo) bool { return q.copyFrom != nil })
+	usesBatch := slices.ContainsFunc(queries, func(q queryInfo) bool { return q.command.IsBatch() })
+	if usesCopyFrom {
+		copyFromFile, err := b.buildCopyFromFile(packageName)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, copyFromFile)
+	}
+	if usesBatch {
+		batchFile, err := b.buildBatchFile(packageName)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, batchFile)
+	}
+	if b.opts.Database == config.DatabasePostgreSQL && !b.usesPgx() && (usesCopyFrom || usesBatch) {
+		pgxFile, err := b.buildPgxFile(packageName)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, pgxFile)
+	}
+
+	if b.usesJSONOverrides() {
+		jsonFile, err := b.buildJSONFile(packageName)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, jsonFile)
+	}
+
 	if b.opts.Prepared.Enabled {
 		preparedFile, err := b.buildPreparedFile(packageName, queries)
 		if err != nil {
@@ -127,6 +270,30 @@ func (b *Builder) Build(ctx context.Context, catalog *model.Catalog, analyses []
 		files = append(files, preparedFile)
 	}
 
+	if b.opts.DriverHelpers && b.opts.Database == config.DatabaseSQLite {
+		driverFile, err := b.buildDriverFile(packageName)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, driverFile)
+	}
+
+	if b.opts.ConstraintErrors.Enabled {
+		errorsFile, err := b.buildErrorsFile(packageName)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, errorsFile)
+	}
+
+	if b.sqliteTime || b.sqliteJSON {
+		sqliteFile, err := b.buildSQLiteTypesFile(packageName)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, sqliteFile)
+	}
+
 	slices.SortFunc(files, func(a, b File) int {
 		if a.Path == b.Path {
 			return 0
@@ -154,6 +321,8 @@ type modelField struct {
 	jsonTag     string
 	importPath  string
 	packageName string
+	// storage is set for [sqlite_types] columns the driver cannot convert
+	storage config.SQLiteStorage
 }
 
 type queryInfo struct {
@@ -175,12 +344,40 @@ type queryInfo struct {
 	cache      *cacheSpec
 	// paramStruct is set when there are 2+ params to group them into a struct
 	paramStruct *paramStructSpec
+	// copyFrom is set for :copyfrom queries
+	copyFrom *analyzer.CopyFromTarget
+	// scalarStorage is set for a scalar result of a [sqlite_types] column
+	// the driver cannot scan by itself
+	scalarStorage config.SQLiteStorage
+}
+
+// typeImports returns the imports of the @returns and @params types of q
+// that are declared in other packages.
+func (q queryInfo) typeImports() []string {
+	var paths []string
+	if q.helper != nil && q.helper.importPath != "" {
+		paths = append(paths, q.helper.importPath)
+	}
+	if q.paramStruct != nil && q.paramStruct.importPath != "" {
+		paths = append(paths, q.paramStruct.importPath)
+	}
+	return paths
+}
+
+// returnsError reports whether the generated method returns an error next to
+// its result. Batches and iterators report errors per item instead.
+func (q queryInfo) returnsError() bool {
+	return !q.command.IsBatch() && q.command != block.CommandIter
 }
 
 // paramStructSpec represents a parameter struct like CreateCounterParams
 type paramStructSpec struct {
 	typeName string
 	fields   []paramStructField
+	// owner is the method whose query file declares the struct
+	owner string
+	// importPath is set for a @params type declared in another package
+	importPath string
 }
 
 type paramStructField struct {
@@ -206,12 +403,25 @@ type paramSpec struct {
 	isDynamicSlice bool
 	marker         string
 	importPath     string
+	// storage is set for values of [sqlite_types] columns the driver
+	// cannot bind by itself
+	storage config.SQLiteStorage
 }
 
 type helperSpec struct {
 	rowTypeName string
 	funcName    string
 	fields      []helperField
+	// isModel is set when rowTypeName is a table model, so no Row struct is
+	// emitted for it
+	isModel bool
+	// importPath is set for a @returns type declared in another package
+	importPath string
+}
+
+// emitsStruct reports whether helpers.gen.go declares the Row struct.
+func (h *helperSpec) emitsStruct() bool {
+	return !h.isModel && h.importPath == ""
 }
 
 type helperField struct {
@@ -219,6 +429,26 @@ type helperField struct {
 	goType      string
 	importPath  string
 	packageName string
+	// storage is set for values of [sqlite_types] columns the driver
+	// cannot scan by itself
+	storage config.SQLiteStorage
+	// embed is set for a table model nested with sqlc.embed
+	embed *helperEmbed
+}
+
+// helperEmbed describes a nested table model filled from several columns.
+type helperEmbed struct {
+	model    *tableModel
+	nullable bool
+}
+
+// scanPlan holds the code that scans a result row into item: declarations
+// that precede the scan, the scan destinations, and assignments that follow
+// a successful scan.
+type scanPlan struct {
+	decls   []string
+	args    string
+	assigns []string
 }
 
 func (b *Builder) collectTableModels(catalog *model.Catalog, analyses []analyzer.Result) ([]*tableModel, error) {
@@ -272,27 +502,117 @@ func (b *Builder) lookupColumnOverride(table, column string) *config.ColumnOverr
 	return nil
 }
 
-// resolveColumnType determines the Go type for a column, checking overrides first
-func (b *Builder) resolveColumnType(tbl *model.Table, col *model.Column) TypeInfo {
+// resolveColumnType determines the Go type for a column, checking overrides
+// first, and how a [sqlite_types] column stores it.
+func (b *Builder) resolveColumnType(tbl *model.Table, col *model.Column) (TypeInfo, config.SQLiteStorage) {
+	var style config.NullStyle
 	// Check for column-specific override first
-	if override := b.lookupColumnOverride(tbl.Name, col.Name); override != nil {
+	if override := b.lookupColumnOverride(tbl.Name, col.Name); override != nil && override.GoType.Type == "" {
+		style = override.NullStyle
+	} else if override != nil {
 		goType := override.GoType.Type
 		if col.NotNull && override.GoType.Pointer {
 			goType = "*" + goType
 		}
+		if override.JSON {
+			goType = "JSON[" + goType + "]"
+		}
 		return TypeInfo{
 			GoType:      goType,
 			Import:      override.GoType.Import,
 			Package:     override.GoType.Package,
 			UsesSQLNull: false,
+		}, ""
+	}
+
+	// Custom type mappings take precedence over schema enums
+	if b.opts.TypeResolver != nil {
+		if info, ok := b.opts.TypeResolver.resolveCustomTypeWithStyle(col.Type, !col.NotNull, b.nullStyle(style)); ok {
+			return info, ""
 		}
 	}
+	if enum := b.catalog.ColumnEnum(col); enum != nil {
+		return b.enumTypeInfo(enum, "string", !col.NotNull, style), ""
+	}
+	if domain := b.catalog.ColumnDomain(col); domain != nil {
+		return b.domainTypeInfo(domain, analyzer.SQLiteTypeToGo(domain.BaseType), !col.NotNull && !domain.NotNull(), style), ""
+	}
+	if goType, storage, ok := analyzer.SQLiteSemanticType(col, b.opts.SQLiteTypes); ok {
+		return b.resolveValueType(goType, !col.NotNull, nil, nil, style), storage
+	}
 
 	// Fall back to type resolver
 	if b.opts.TypeResolver != nil {
-		return b.opts.TypeResolver.ResolveType(col.Type, !col.NotNull)
+		return b.opts.TypeResolver.ResolveTypeWithStyle(col.Type, !col.NotNull, b.nullStyle(style)), ""
+	}
+	return b.resolveFallbackType(analyzer.SQLiteTypeToGo(col.Type), !col.NotNull, style), ""
+}
+
+// resolveValueType determines the Go type of a query column or parameter,
+// using the generated enum or domain type for values of a schema enum or
+// domain. Nullable values are represented in style, or in the generation
+// null style when style is empty.
+func (b *Builder) resolveValueType(goType string, nullable bool, enum *model.Enum, domain *model.Domain, style config.NullStyle) TypeInfo {
+	if enum != nil {
+		return b.enumTypeInfo(enum, goType, nullable, style)
+	}
+	if domain != nil && !strings.HasPrefix(goType, "[]") {
+		return b.domainTypeInfo(domain, goType, nullable, style)
+	}
+	if b.opts.TypeResolver != nil {
+		return b.opts.TypeResolver.ResolveTypeWithStyle(goType, nullable, b.nullStyle(style))
+	}
+	return b.resolveFallbackType(goType, nullable, style)
+}
+
+// resolveFallbackType resolves goType without a TypeResolver, as a SQLite
+// resolver with the builder's null style would.
+func (b *Builder) resolveFallbackType(goType string, nullable bool, style config.NullStyle) TypeInfo {
+	resolver := NewTypeResolver(nil)
+	resolver.nullOption = b.opts.NullOption
+	return resolver.resolveStandardTypeWithStyle(goType, nullable, b.nullStyle(style))
+}
+
+// nullStyle returns style, or the style of nullable values the builder is
+// configured with when style is empty.
+func (b *Builder) nullStyle(style config.NullStyle) config.NullStyle {
+	switch {
+	case style != "":
+		return style
+	case b.opts.NullStyle != "":
+		return b.opts.NullStyle
+	case b.opts.TypeResolver != nil:
+		return b.opts.TypeResolver.NullStyle()
+	case b.opts.Database == config.DatabasePostgreSQL:
+		return config.NullStylePgtype
+	default:
+		return config.NullStyleSQLNull
+	}
+}
+
+// nullOption returns the generic type of config.NullStyleOption.
+func (b *Builder) nullOption() config.GoTypeDetails {
+	if b.opts.NullOption.Type == "" && b.opts.TypeResolver != nil {
+		return b.opts.TypeResolver.nullOption
+	}
+	return b.opts.NullOption
+}
+
+// nullImports returns the import of the option type when a nullable value
+// may be represented with it. The type can wrap types from other packages,
+// so files that may hold one import it and leave unused imports to goimports.
+func (b *Builder) nullImports() []string {
+	option := b.nullOption()
+	if option.Import == "" {
+		return nil
 	}
-	return resolveType(analyzer.SQLiteTypeToGo(col.Type), !col.NotNull)
+	usesOption := b.nullStyle("") == config.NullStyleOption || slices.ContainsFunc(b.opts.ColumnOverrides, func(o config.ColumnOverride) bool {
+		return o.NullStyle == config.NullStyleOption
+	})
+	if !usesOption {
+		return nil
+	}
+	return []string{option.Import}
 }
 
 func (b *Builder) buildTableModel(tbl *model.Table) (*tableModel, error) {
@@ -313,7 +633,7 @@ func (b *Builder) buildTableModel(tbl *model.Table) (*tableModel, error) {
 		} else {
 			used[goName] = 1
 		}
-		typeInfo := b.resolveColumnType(tbl, col)
+		typeInfo, storage := b.resolveColumnType(tbl, col)
 		if typeInfo.UsesSQLNull {
 			needsSQL = true
 		}
@@ -323,6 +643,7 @@ func (b *Builder) buildTableModel(tbl *model.Table) (*tableModel, error) {
 			goType:      typeInfo.GoType,
 			importPath:  typeInfo.Import,
 			packageName: typeInfo.Package,
+			storage:     storage,
 		}
 		if b.opts.EmitJSONTags {
 			field.jsonTag = fmt.Sprintf("`json:\"%s\"`", col.Name)
@@ -343,6 +664,13 @@ func (b *Builder) buildTableModel(tbl *model.Table) (*tableModel, error) {
 
 func (b *Builder) buildQueries(analyses []analyzer.Result) ([]queryInfo, error) {
 	queries := make([]queryInfo, 0, len(analyses))
+	b.helpers = make(map[string]*helperSpec)
+	b.named = make(map[string]namedType)
+	sharedRows, err := b.sharedRowNames(analyses)
+	if err != nil {
+		return nil, err
+	}
+	b.sharedRows = sharedRows
 	for _, res := range analyses {
 		methodName := ExportedIdentifier(res.Query.Block.Name)
 		if methodName == "" {
@@ -358,22 +686,33 @@ func (b *Builder) buildQueries(analyses []analyzer.Result) ([]queryInfo, error)
 		}
 
 		sqlLiteral := res.Query.Block.SQL
-		// Replace dynamic slice macros with markers in the SQL string
-		// We do this by iterating params in reverse offset order to avoid offset shifting
-		// 3. Apply replacements to SQL.
-		// 4. Update paramSpec with the used marker.
-
-		// We need unique markers.
-		for i := len(params) - 1; i >= 0; i-- {
+		// Replace dynamic slice macros with markers and sqlc.embed(name)
+		// with name.* in the SQL string.
+		// We do this in reverse offset order to avoid offset shifting.
+		type sqlEdit struct {
+			start, end int
+			text       string
+		}
+		var edits []sqlEdit
+		for i := range params {
 			if params[i].isDynamicSlice {
 				pp := res.Query.Params[i]
+				// We need unique markers.
 				marker := fmt.Sprintf("/*SLICE:%s*/", params[i].name)
 				params[i].marker = marker
-				// Replace in sqlLiteral
-				// Ensure offsets are valid
-				if pp.StartOffset >= 0 && pp.EndOffset <= len(sqlLiteral) && pp.StartOffset <= pp.EndOffset {
-					sqlLiteral = sqlLiteral[:pp.StartOffset] + marker + sqlLiteral[pp.EndOffset:]
-				}
+				edits = append(edits, sqlEdit{pp.StartOffset, pp.EndOffset, marker})
+			}
+		}
+		for _, col := range res.Query.Columns {
+			if col.Embed != "" {
+				edits = append(edits, sqlEdit{col.StartOffset, col.EndOffset, col.Embed + ".*"})
+			}
+		}
+		slices.SortFunc(edits, func(a, b sqlEdit) int { return b.start - a.start })
+		for _, e := range edits {
+			// Ensure offsets are valid
+			if e.start >= 0 && e.end <= len(sqlLiteral) && e.start <= e.end {
+				sqlLiteral = sqlLiteral[:e.start] + e.text + sqlLiteral[e.end:]
 			}
 		}
 
@@ -381,16 +720,18 @@ func (b *Builder) buildQueries(analyses []analyzer.Result) ([]queryInfo, error)
 		args := make([]string, 0, len(params))
 		var paramStruct *paramStructSpec
 
-		// If there are 2+ params, group them into a struct like SQLC does
-		if len(params) >= 2 {
-			paramStruct = buildParamStruct(methodName, params)
-			args = append(args, "arg."+ExportedIdentifier(params[0].name))
-			for i := 1; i < len(params); i++ {
-				args = append(args, "arg."+ExportedIdentifier(params[i].name))
+		// If there are 2+ params or a @params type, group them into a struct like SQLC does
+		if len(params) >= 2 || res.Query.Block.ParamsType != "" {
+			paramStruct, err = b.buildNamedParamStruct(methodName, res.Query.Block.ParamsType, params)
+			if err != nil {
+				return nil, err
+			}
+			for _, p := range params {
+				args = append(args, b.bindArg("arg."+ExportedIdentifier(p.name), p))
 			}
 		} else {
 			for _, p := range params {
-				args = append(args, p.argExpr)
+				args = append(args, b.bindArg(p.argExpr, p))
 			}
 		}
 
@@ -421,22 +762,20 @@ func (b *Builder) buildQueries(analyses []analyzer.Result) ([]queryInfo, error)
 			}
 		}
 
+		// A @returns type is a struct even for a single column
+		scalar := isScalarResult(res.Columns) && res.Query.Block.ResultType == ""
 		switch res.Query.Block.Command {
 		case block.CommandOne:
 			// For single-column results, return the scalar type directly
-			if len(res.Columns) == 1 {
+			if scalar {
 				col := res.Columns[0]
-				var typeInfo TypeInfo
-				if b.opts.TypeResolver != nil {
-					typeInfo = b.opts.TypeResolver.ResolveType(col.GoType, col.Nullable)
-				} else {
-					typeInfo = resolveType(col.GoType, col.Nullable)
-				}
+				typeInfo := b.resolveValueType(col.GoType, col.Nullable, col.Enum, col.Domain, col.NullStyle)
 				info.returnType = typeInfo.GoType
 				info.returnZero = b.zeroValueForType(typeInfo.GoType)
 				info.helper = nil // No helper needed for scalar
+				info.scalarStorage = col.Storage
 			} else {
-				helper, err := b.buildHelper(methodName, res.Columns)
+				helper, err := b.resultHelper(methodName, res)
 				if err != nil {
 					return nil, err
 				}
@@ -445,8 +784,25 @@ func (b *Builder) buildQueries(analyses []analyzer.Result) ([]queryInfo, error)
 				info.returnType = helper.rowTypeName
 				info.returnZero = helper.rowTypeName + "{}"
 			}
+		case block.CommandOpt:
+			// Like :one, but a missing row is reported as a nil pointer
+			if scalar {
+				col := res.Columns[0]
+				typeInfo := b.resolveValueType(col.GoType, col.Nullable, col.Enum, col.Domain, col.NullStyle)
+				info.rowType = typeInfo.GoType
+				info.scalarStorage = col.Storage
+			} else {
+				helper, err := b.resultHelper(methodName, res)
+				if err != nil {
+					return nil, err
+				}
+				info.helper = helper
+				info.rowType = helper.rowTypeName
+			}
+			info.returnType = "*" + info.rowType
+			info.returnZero = "nil"
 		case block.CommandMany:
-			helper, err := b.buildHelper(methodName, res.Columns)
+			helper, err := b.resultHelper(methodName, res)
 			if err != nil {
 				return nil, err
 			}
@@ -454,6 +810,16 @@ func (b *Builder) buildQueries(analyses []analyzer.Result) ([]queryInfo, error)
 			info.rowType = helper.rowTypeName
 			info.returnType = "[]" + helper.rowTypeName
 			info.returnZero = "nil"
+		case block.CommandIter:
+			helper, err := b.resultHelper(methodName, res)
+			if err != nil {
+				return nil, err
+			}
+			info.cache = nil
+			info.helper = helper
+			info.rowType = helper.rowTypeName
+			info.returnType = "iter.Seq2[" + helper.rowTypeName + ", error]"
+			info.returnZero = "nil"
 		case block.CommandExec:
 			// :exec returns just error, not sql.Result
 			info.returnType = ""
@@ -467,9 +833,54 @@ func (b *Builder) buildQueries(analyses []analyzer.Result) ([]queryInfo, error)
 		case block.CommandExecLastID:
 			info.returnType = "int64"
 			info.returnZero = "0"
+		case block.CommandBatchExec:
+			info.cache = nil
+			info.returnType = "*" + methodName + "BatchResults"
+			info.returnZero = "nil"
+		case block.CommandBatchOne:
+			info.cache = nil
+			info.returnType = "*" + methodName + "BatchResults"
+			info.returnZero = "nil"
+			if scalar {
+				col := res.Columns[0]
+				typeInfo := b.resolveValueType(col.GoType, col.Nullable, col.Enum, col.Domain, col.NullStyle)
+				info.rowType = typeInfo.GoType
+				info.scalarStorage = col.Storage
+			} else {
+				helper, err := b.resultHelper(methodName, res)
+				if err != nil {
+					return nil, err
+				}
+				info.helper = helper
+				info.rowType = helper.rowTypeName
+			}
+		case block.CommandBatchMany:
+			helper, err := b.resultHelper(methodName, res)
+			if err != nil {
+				return nil, err
+			}
+			info.cache = nil
+			info.helper = helper
+			info.rowType = helper.rowTypeName
+			info.returnType = "*" + methodName + "BatchResults"
+			info.returnZero = "nil"
+		case block.CommandCopyFrom:
+			if res.CopyFrom == nil {
+				return nil, fmt.Errorf("query %s: :copyfrom target could not be resolved", res.Query.Block.Name)
+			}
+			info.copyFrom = res.CopyFrom
+			info.cache = nil
+			info.returnType = "int64"
+			info.returnZero = "0"
 		default:
 			// CommandUnknown or other unhandled commands - skip
 		}
+		if info.command == block.CommandExecLastID && b.usesPgx() {
+			return nil, fmt.Errorf("query %s: :execlastid is not supported with sql_package %q; use RETURNING with :one", res.Query.Block.Name, b.opts.SQLPackage)
+		}
+		if res.Query.Block.ResultType != "" && info.helper == nil {
+			return nil, fmt.Errorf("query %s: @returns requires a command that returns rows", res.Query.Block.Name)
+		}
 
 		queries = append(queries, info)
 	}
@@ -477,10 +888,53 @@ func (b *Builder) buildQueries(analyses []analyzer.Result) ([]queryInfo, error)
 	slices.SortFunc(queries, func(a, b queryInfo) int {
 		return strings.Compare(a.methodName, b.methodName)
 	})
+	if err := b.checkNamedTypes(queries); err != nil {
+		return nil, err
+	}
 
 	return queries, nil
 }
 
+// checkNamedTypes rejects @returns and @params types named like another type
+// of the package: a table model, enum, domain, or the Row or Params struct of
+// a query. A @returns type may name the table model whose fields it matches.
+func (b *Builder) checkNamedTypes(queries []queryInfo) error {
+	declared := make(map[string]string)
+	for _, mdl := range b.models {
+		declared[mdl.typeName] = fmt.Sprintf("the %s table model", mdl.tableName)
+	}
+	for _, spec := range b.enums {
+		declared[spec.typeName] = fmt.Sprintf("the %s enum", spec.name)
+	}
+	for _, spec := range b.domains {
+		if spec != nil {
+			declared[spec.typeName] = fmt.Sprintf("the %s domain", spec.name)
+		}
+	}
+	for _, q := range queries {
+		if h := q.helper; h != nil && !h.isModel && b.named[h.rowTypeName].helper != h {
+			declared[h.rowTypeName] = "the result of query " + q.methodName
+		}
+		if ps := q.paramStruct; ps != nil && b.named[ps.typeName].params != ps {
+			declared[ps.typeName] = "the params of query " + q.methodName
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(b.named)) {
+		named := b.named[name]
+		decl, ok := declared[name]
+		if !ok || (named.helper != nil && named.helper.isModel) {
+			continue
+		}
+		annotation := "@returns"
+		if named.params != nil {
+			annotation = "@params"
+		}
+		return fmt.Errorf("query %s: %s %s is already declared as %s", named.query, annotation, name, decl)
+	}
+	return nil
+}
+
 // buildParamStruct creates a parameter struct spec for grouping multiple parameters.
 // This matches SQLC's behavior of generating structs like CreateCounterParams.
 func buildParamStruct(methodName string, params []paramSpec) *paramStructSpec {
@@ -502,7 +956,70 @@ func buildParamStruct(methodName string, params []paramSpec) *paramStructSpec {
 	return &paramStructSpec{
 		typeName: typeName,
 		fields:   fields,
+		owner:    methodName,
+	}
+}
+
+// buildNamedParamStruct builds the param struct of a query, shared with the
+// other queries naming the same @params type. Queries sharing a type must
+// agree on its fields.
+func (b *Builder) buildNamedParamStruct(methodName, name string, params []paramSpec) (*paramStructSpec, error) {
+	spec := buildParamStruct(methodName, params)
+	if name == "" {
+		return spec, nil
+	}
+	if len(params) == 0 {
+		return nil, fmt.Errorf("query %s: @params %s names a type for a query without parameters", methodName, name)
 	}
+	typeName, importPath, err := namedGoType(name)
+	if err != nil {
+		return nil, fmt.Errorf("query %s: @params: %w", methodName, err)
+	}
+	spec.typeName = typeName
+	spec.importPath = importPath
+	if prev, ok := b.named[typeName]; ok {
+		if prev.params == nil {
+			return nil, fmt.Errorf("query %s: @params %s is the @returns type of query %s", methodName, name, prev.query)
+		}
+		if paramStructShape(prev.params.fields) != paramStructShape(spec.fields) {
+			return nil, fmt.Errorf("query %s: @params %s fields differ from those of query %s", methodName, name, prev.query)
+		}
+		return prev.params, nil
+	}
+	if b.named != nil {
+		b.named[typeName] = namedType{query: methodName, params: spec}
+	}
+	return spec, nil
+}
+
+func paramStructShape(fields []paramStructField) string {
+	var sb strings.Builder
+	for _, fld := range fields {
+		fmt.Fprintf(&sb, "%s %s;", fld.name, fld.goType)
+	}
+	return sb.String()
+}
+
+// namedGoType resolves a type named by a @returns or @params annotation. A
+// bare identifier is a type generated in the output package; a name qualified
+// with an import path, like github.com/acme/api/dto.User, is an existing type
+// of that package.
+func namedGoType(name string) (goType, importPath string, err error) {
+	typeName := name
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		importPath, typeName = name[:idx], name[idx+1:]
+	}
+	if !token.IsIdentifier(typeName) || !token.IsExported(typeName) || (importPath == "" && name != typeName) {
+		return "", "", fmt.Errorf("%q is not an exported Go type name", name)
+	}
+	if importPath == "" {
+		return typeName, "", nil
+	}
+	pkg := path.Base(importPath)
+	if !token.IsIdentifier(pkg) {
+		return "", "", fmt.Errorf("package of %q must be named after its import path", name)
+	}
+	return pkg + "." + typeName, importPath, nil
 }
 
 func (b *Builder) buildParams(params []analyzer.ResultParam) ([]paramSpec, error) {
@@ -521,10 +1038,8 @@ func (b *Builder) buildParams(params []analyzer.ResultParam) ([]paramSpec, error
 				Package:     p.Package,
 				UsesSQLNull: false,
 			}
-		} else if b.opts.TypeResolver != nil {
-			typeInfo = b.opts.TypeResolver.ResolveType(p.GoType, p.Nullable)
 		} else {
-			typeInfo = resolveType(p.GoType, p.Nullable)
+			typeInfo = b.resolveValueType(p.GoType, p.Nullable, p.Enum, p.Domain, p.NullStyle)
 		}
 
 		isDynamicSlice := p.IsVariadic && p.VariadicCount == 0
@@ -537,6 +1052,7 @@ func (b *Builder) buildParams(params []analyzer.ResultParam) ([]paramSpec, error
 			argExpr:        name,
 			isDynamicSlice: isDynamicSlice,
 			importPath:     typeInfo.Import,
+			storage:        p.Storage,
 		}
 
 		if isDynamicSlice {
@@ -588,11 +1104,134 @@ func (b *Builder) resolveSliceName(baseName string, used map[string]int) (string
 }
 
 func (b *Builder) buildHelper(methodName string, columns []analyzer.ResultColumn) (*helperSpec, error) {
-	rowTypeName := methodName + "Row"
-	funcName := "scan" + methodName + "Row"
+	fields, err := b.buildHelperFields(columns)
+	if err != nil {
+		return nil, err
+	}
+
+	shape := helperShape(fields)
+	if shared, ok := b.helpers[shape]; ok {
+		return shared, nil
+	}
+	helper := &helperSpec{rowTypeName: methodName + "Row", funcName: "scan" + methodName + "Row", fields: fields}
+	if mdl := b.matchingModel(columns, fields); mdl != nil {
+		helper.rowTypeName = mdl.typeName
+		helper.funcName = "scan" + mdl.typeName
+		helper.isModel = true
+	} else if name, ok := b.sharedRows[shape]; ok {
+		helper.rowTypeName = name
+		helper.funcName = "scan" + name
+	} else {
+		return helper, nil
+	}
+	if b.helpers != nil {
+		b.helpers[shape] = helper
+	}
+	return helper, nil
+}
+
+// sharedRowNames returns the names of the Row types shared by several
+// queries, keyed by the shape of their fields. A shared Row is named after
+// the models of the tables its columns come from, such as TagsRow or
+// PostsUsersRow, so its name does not depend on which queries use it. Other
+// shared Rows, such as those with expression columns, are named after the
+// first of their queries by name.
+func (b *Builder) sharedRowNames(analyses []analyzer.Result) (map[string]string, error) {
+	users := make(map[string][]string)
+	tables := make(map[string]string)
+	taken := make(map[string]bool)
+	for _, res := range analyses {
+		methodName := ExportedIdentifier(res.Query.Block.Name)
+		taken[methodName+"Row"] = true
+		if !usesRowHelper(res) {
+			continue
+		}
+		fields, err := b.buildHelperFields(res.Columns)
+		if err != nil {
+			return nil, err
+		}
+		if b.matchingModel(res.Columns, fields) != nil {
+			continue
+		}
+		shape := helperShape(fields)
+		users[shape] = append(users[shape], methodName)
+		tables[shape] = b.rowModels(res.Columns)
+	}
+
+	candidates := make(map[string][]string)
+	for shape, methods := range users {
+		if name := tables[shape] + "Row"; len(methods) > 1 && tables[shape] != "" && !taken[name] {
+			candidates[name] = append(candidates[name], shape)
+		}
+	}
+	names := make(map[string]string)
+	for name, shapes := range candidates {
+		// Several shapes of the same tables would share the name, so none does.
+		if len(shapes) == 1 {
+			names[shapes[0]] = name
+		}
+	}
+	for shape, methods := range users {
+		if _, ok := names[shape]; !ok && len(methods) > 1 {
+			names[shape] = slices.Min(methods) + "Row"
+		}
+	}
+	return names, nil
+}
+
+// usesRowHelper reports whether the result of res is scanned into a Row type
+// named after its query.
+func usesRowHelper(res analyzer.Result) bool {
+	if res.Query.Block.ResultType != "" || len(res.Columns) == 0 {
+		return false
+	}
+	switch res.Query.Block.Command {
+	case block.CommandOne, block.CommandOpt, block.CommandBatchOne:
+		return !isScalarResult(res.Columns)
+	case block.CommandMany, block.CommandIter, block.CommandBatchMany:
+		return true
+	default:
+		return false
+	}
+}
+
+// rowModels returns the type names of the models of the tables the columns
+// come from, joined in order of first use, or "" when a column is an
+// expression or its table has no model.
+func (b *Builder) rowModels(columns []analyzer.ResultColumn) string {
+	var names []string
+	for _, col := range columns {
+		mdl := b.models[col.Table]
+		if col.Embed != nil || mdl == nil {
+			return ""
+		}
+		if !slices.Contains(names, mdl.typeName) {
+			names = append(names, mdl.typeName)
+		}
+	}
+	return strings.Join(names, "")
+}
+
+// buildHelperFields builds the Row struct fields of a result.
+func (b *Builder) buildHelperFields(columns []analyzer.ResultColumn) ([]helperField, error) {
 	fields := make([]helperField, 0, len(columns))
 	used := make(map[string]int)
-	for idx, col := range columns {
+	for idx := 0; idx < len(columns); idx++ {
+		col := columns[idx]
+		if col.Embed != nil {
+			// The columns of an embed are consecutive and share the same *Embed
+			end := idx + 1
+			for end < len(columns) && columns[end].Embed == col.Embed {
+				end++
+			}
+			field, err := b.buildEmbedField(col.Embed, end-idx, used)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, field)
+			idx = end - 1
+			continue
+		}
 		baseName := col.Name
 		if baseName == "" {
 			baseName = fmt.Sprintf("column_%d", idx+1)
@@ -621,28 +1260,203 @@ func (b *Builder) buildHelper(methodName string, columns []analyzer.ResultColumn
 				Package:     col.Package,
 				UsesSQLNull: false,
 			}
-		} else if b.opts.TypeResolver != nil {
-			typeInfo = b.opts.TypeResolver.ResolveType(col.GoType, col.Nullable)
 		} else {
-			typeInfo = resolveType(col.GoType, col.Nullable)
+			typeInfo = b.resolveValueType(col.GoType, col.Nullable, col.Enum, col.Domain, col.NullStyle)
 		}
 		fields = append(fields, helperField{
 			name:        fieldName,
 			goType:      typeInfo.GoType,
 			importPath:  typeInfo.Import,
 			packageName: typeInfo.Package,
+			storage:     col.Storage,
 		})
 	}
-	return &helperSpec{rowTypeName: rowTypeName, funcName: funcName, fields: fields}, nil
+	return fields, nil
 }
 
-func (b *Builder) buildModelsFile(pkg string, models []*tableModel) (*goast.File, error) {
-	file := &goast.File{Name: goast.NewIdent(pkg)}
+// resultHelper builds the result helper of a query, shared with the other
+// queries naming the same @returns type. Queries sharing a type must agree on
+// its fields.
+func (b *Builder) resultHelper(methodName string, res analyzer.Result) (*helperSpec, error) {
+	name := res.Query.Block.ResultType
+	if name == "" {
+		return b.buildHelper(methodName, res.Columns)
+	}
+	typeName, importPath, err := namedGoType(name)
+	if err != nil {
+		return nil, fmt.Errorf("query %s: @returns: %w", methodName, err)
+	}
+	fields, err := b.buildHelperFields(res.Columns)
+	if err != nil {
+		return nil, err
+	}
+	if prev, ok := b.named[typeName]; ok {
+		if prev.helper == nil {
+			return nil, fmt.Errorf("query %s: @returns %s is the @params type of query %s", methodName, name, prev.query)
+		}
+		if helperShape(prev.helper.fields) != helperShape(fields) {
+			return nil, fmt.Errorf("query %s: @returns %s fields differ from those of query %s", methodName, name, prev.query)
+		}
+		return prev.helper, nil
+	}
 
-	// Collect imports needed for custom types and PostgreSQL types
-	importSet := make(map[string]struct{})
+	helper := &helperSpec{
+		rowTypeName: typeName,
+		funcName:    "scan" + ExportedIdentifier(strings.ReplaceAll(typeName, ".", "_")),
+		fields:      fields,
+		importPath:  importPath,
+	}
+	if mdl := b.modelNamed(typeName); mdl != nil {
+		if b.matchingModel(res.Columns, fields) != mdl {
+			return nil, fmt.Errorf("query %s: @returns %s fields differ from the %s table model", methodName, name, mdl.tableName)
+		}
+		helper.isModel = true
+	}
+	if b.named != nil {
+		b.named[typeName] = namedType{query: methodName, helper: helper}
+	}
+	return helper, nil
+}
 
-	// Collect from TypeResolver if available
+// modelNamed returns the table model generated as typeName, or nil.
+func (b *Builder) modelNamed(typeName string) *tableModel {
+	for _, mdl := range b.models {
+		if mdl.typeName == typeName {
+			return mdl
+		}
+	}
+	return nil
+}
+
+// helperShape identifies the Row struct that fields describe; helpers with
+// the same shape are interchangeable.
+func helperShape(fields []helperField) string {
+	var sb strings.Builder
+	for _, fld := range fields {
+		fmt.Fprintf(&sb, "%s %s %s;", fld.name, fld.goType, fld.storage)
+	}
+	return sb.String()
+}
+
+// matchingModel returns the model of the table that columns select in full,
+// in schema order and with the model's field types, or nil. Such results are
+// returned as the model instead of a Row struct.
+func (b *Builder) matchingModel(columns []analyzer.ResultColumn, fields []helperField) *tableModel {
+	if len(columns) == 0 || len(columns) != len(fields) {
+		return nil
+	}
+	mdl := b.models[columns[0].Table]
+	if mdl == nil || len(mdl.fields) != len(fields) {
+		return nil
+	}
+	for i, col := range columns {
+		mf := mdl.fields[i]
+		if col.Table != mdl.tableName || col.Embed != nil || col.Name != mf.columnName ||
+			fields[i].name != mf.fieldName || fields[i].goType != mf.goType || fields[i].storage != mf.storage {
+			return nil
+		}
+	}
+	return mdl
+}
+
+// buildEmbedField returns the Row field holding the table model that
+// sqlc.embed nests into a result. Nullable embeds are pointers.
+func (b *Builder) buildEmbedField(embed *analyzer.Embed, columns int, used map[string]int) (helperField, error) {
+	mdl := b.models[embed.Table]
+	if mdl == nil {
+		return helperField{}, fmt.Errorf("sqlc.embed(%s): no model for table %s", embed.Table, embed.Table)
+	}
+	if len(mdl.fields) != columns {
+		return helperField{}, fmt.Errorf("sqlc.embed(%s): %d columns for a model with %d fields", embed.Table, columns, len(mdl.fields))
+	}
+	fieldName := mdl.typeName
+	if _, exists := used[fieldName]; exists {
+		var err error
+		fieldName, err = UniqueName(fieldName, used)
+		if err != nil {
+			return helperField{}, err
+		}
+	} else {
+		used[fieldName] = 1
+	}
+	goType := mdl.typeName
+	if embed.Nullable {
+		goType = "*" + goType
+	}
+	return helperField{
+		name:   fieldName,
+		goType: goType,
+		embed:  &helperEmbed{model: mdl, nullable: embed.Nullable},
+	}, nil
+}
+
+// isScalarResult reports whether a result with these columns is returned as
+// the bare column value rather than a Row struct.
+func isScalarResult(columns []analyzer.ResultColumn) bool {
+	return len(columns) == 1 && columns[0].Embed == nil
+}
+
+// buildScanPlan returns the code that scans a row into item. The fields of an
+// embedded model are scanned in place; a nullable embed is scanned into
+// sql.Null temporaries and only set when one of its columns is not NULL.
+// scalar is the storage of a result without helper.
+func (b *Builder) buildScanPlan(helper *helperSpec, scalar config.SQLiteStorage) scanPlan {
+	if helper == nil || len(helper.fields) == 0 {
+		return scanPlan{args: b.scanDest("&item", scalar)}
+	}
+	var plan scanPlan
+	args := make([]string, 0, len(helper.fields))
+	for _, fld := range helper.fields {
+		switch {
+		case fld.embed == nil:
+			args = append(args, b.scanDest("&item."+fld.name, fld.storage))
+		case !fld.embed.nullable:
+			for _, mf := range fld.embed.model.fields {
+				args = append(args, b.scanDest("&item."+fld.name+"."+mf.fieldName, mf.storage))
+			}
+		default:
+			prefix := strings.ToLower(fld.name[:1]) + fld.name[1:]
+			valid := make([]string, 0, len(fld.embed.model.fields))
+			values := make([]string, 0, len(fld.embed.model.fields))
+			for _, mf := range fld.embed.model.fields {
+				tmp := prefix + mf.fieldName
+				plan.decls = append(plan.decls, fmt.Sprintf("var %s sql.Null[%s]", tmp, mf.goType))
+				args = append(args, b.scanDest("&"+tmp, mf.storage))
+				valid = append(valid, tmp+".Valid")
+				values = append(values, fmt.Sprintf("%s: %s.V", mf.fieldName, tmp))
+			}
+			plan.assigns = append(plan.assigns, fmt.Sprintf("if %s {\nitem.%s = &%s{%s}\n}",
+				strings.Join(valid, " || "), fld.name, fld.embed.model.typeName, strings.Join(values, ", ")))
+		}
+	}
+	plan.args = strings.Join(args, ", ")
+	return plan
+}
+
+// importPaths returns the imports the type of fld needs, including those of
+// the fields of an embedded model.
+func (fld helperField) importPaths() []string {
+	var paths []string
+	if fld.importPath != "" {
+		paths = append(paths, fld.importPath)
+	}
+	if fld.embed != nil {
+		for _, mf := range fld.embed.model.fields {
+			if mf.importPath != "" {
+				paths = append(paths, mf.importPath)
+			}
+		}
+	}
+	return paths
+}
+
+func (b *Builder) buildModelsFile(pkg string, models []*tableModel) (*goast.File, error) {
+	file := &goast.File{Name: goast.NewIdent(pkg)}
+
+	// Collect imports needed for custom types and PostgreSQL types
+	importSet := make(map[string]struct{})
+
+	// Collect from TypeResolver if available
 	if b.opts.TypeResolver != nil {
 		if b.opts.TypeResolver.transformer != nil {
 			for _, mapping := range b.opts.TypeResolver.transformer.GetCustomTypes() {
@@ -660,6 +1474,9 @@ func (b *Builder) buildModelsFile(pkg string, models []*tableModel) (*goast.File
 			}
 		}
 	}
+	for _, path := range b.nullImports() {
+		importSet[path] = struct{}{}
+	}
 
 	// Add imports if needed
 	if len(importSet) > 0 {
@@ -707,12 +1524,19 @@ func (b *Builder) buildQuerierFile(pkg string, queries []queryInfo) (*goast.File
 		params := []*goast.Field{{Names: []*goast.Ident{goast.NewIdent("ctx")}, Type: selector("context", "Context")}}
 
 		// If we have a param struct (2+ params), use it instead of individual params
-		if q.paramStruct != nil {
+		switch {
+		case q.copyFrom != nil || q.command.IsBatch():
+			field, err := sliceArgField(q)
+			if err != nil {
+				return nil, err
+			}
+			params = append(params, field)
+		case q.paramStruct != nil:
 			params = append(params, &goast.Field{
 				Names: []*goast.Ident{goast.NewIdent("arg")},
 				Type:  goast.NewIdent(q.paramStruct.typeName),
 			})
-		} else {
+		default:
 			for _, p := range q.params {
 				expr, err := parser.ParseExpr(p.goType)
 				if err != nil {
@@ -734,8 +1558,10 @@ func (b *Builder) buildQuerierFile(pkg string, queries []queryInfo) (*goast.File
 			}
 			results = append(results, &goast.Field{Type: expr})
 		}
-		errorType, _ := parser.ParseExpr("error")
-		results = append(results, &goast.Field{Type: errorType})
+		if q.returnsError() {
+			errorType, _ := parser.ParseExpr("error")
+			results = append(results, &goast.Field{Type: errorType})
+		}
 
 		interfaceFields = append(interfaceFields, &goast.Field{
 			Names: []*goast.Ident{goast.NewIdent(q.methodName)},
@@ -746,26 +1572,48 @@ func (b *Builder) buildQuerierFile(pkg string, queries []queryInfo) (*goast.File
 		})
 	}
 
+	// Types of other packages named by @returns and @params, parameters and
+	// results; goimports adds the standard library imports and drops unused
+	// ones
+	importSet := make(map[string]struct{})
+	for _, q := range queries {
+		for _, path := range q.typeImports() {
+			importSet[path] = struct{}{}
+		}
+		for _, p := range q.params {
+			if p.importPath != "" {
+				importSet[p.importPath] = struct{}{}
+			}
+		}
+		if q.helper != nil {
+			for _, fld := range q.helper.fields {
+				for _, path := range fld.importPaths() {
+					importSet[path] = struct{}{}
+				}
+			}
+		}
+	}
+	for _, path := range b.nullImports() {
+		importSet[path] = struct{}{}
+	}
+	if b.usesPgx() {
+		importSet[pgxImport] = struct{}{}
+		importSet[pgconnImport] = struct{}{}
+	}
+	if len(importSet) > 0 {
+		importDecls := make([]goast.Spec, 0, len(importSet))
+		for _, importPath := range slices.Sorted(maps.Keys(importSet)) {
+			importDecls = append(importDecls, &goast.ImportSpec{
+				Path: &goast.BasicLit{Kind: token.STRING, Value: strconv.Quote(importPath)},
+			})
+		}
+		file.Decls = append(file.Decls, &goast.GenDecl{Tok: token.IMPORT, Specs: importDecls})
+	}
+
 	querierType := &goast.TypeSpec{Name: goast.NewIdent("Querier"), Type: &goast.InterfaceType{Methods: &goast.FieldList{List: interfaceFields}}}
 	querierDecl := &goast.GenDecl{Tok: token.TYPE, Specs: []goast.Spec{querierType}}
 
-	dbtxMethods := []*goast.Field{
-		{Names: []*goast.Ident{goast.NewIdent("ExecContext")}, Type: funcType([]*goast.Field{
-			{Names: []*goast.Ident{goast.NewIdent("ctx")}, Type: selector("context", "Context")},
-			{Names: []*goast.Ident{goast.NewIdent("query")}, Type: goast.NewIdent("string")},
-			{Names: []*goast.Ident{goast.NewIdent("args")}, Type: &goast.Ellipsis{Elt: goast.NewIdent("any")}},
-		}, []*goast.Field{{Type: selector("sql", "Result")}, {Type: goast.NewIdent("error")}})},
-		{Names: []*goast.Ident{goast.NewIdent("QueryContext")}, Type: funcType([]*goast.Field{
-			{Names: []*goast.Ident{goast.NewIdent("ctx")}, Type: selector("context", "Context")},
-			{Names: []*goast.Ident{goast.NewIdent("query")}, Type: goast.NewIdent("string")},
-			{Names: []*goast.Ident{goast.NewIdent("args")}, Type: &goast.Ellipsis{Elt: goast.NewIdent("any")}},
-		}, []*goast.Field{{Type: &goast.StarExpr{X: selector("sql", "Rows")}}, {Type: goast.NewIdent("error")}})},
-		{Names: []*goast.Ident{goast.NewIdent("QueryRowContext")}, Type: funcType([]*goast.Field{
-			{Names: []*goast.Ident{goast.NewIdent("ctx")}, Type: selector("context", "Context")},
-			{Names: []*goast.Ident{goast.NewIdent("query")}, Type: goast.NewIdent("string")},
-			{Names: []*goast.Ident{goast.NewIdent("args")}, Type: &goast.Ellipsis{Elt: goast.NewIdent("any")}},
-		}, []*goast.Field{{Type: &goast.StarExpr{X: selector("sql", "Row")}}})},
-	}
+	dbtxMethods := b.dbtxMethods(queries)
 	dbtxType := &goast.TypeSpec{Name: goast.NewIdent("DBTX"), Type: &goast.InterfaceType{Methods: &goast.FieldList{List: dbtxMethods}}}
 	dbtxDecl := &goast.GenDecl{Tok: token.TYPE, Specs: []goast.Spec{dbtxType}}
 
@@ -817,18 +1665,56 @@ func (b *Builder) buildQuerierFile(pkg string, queries []queryInfo) (*goast.File
 	}}}}
 	resultDecl := &goast.GenDecl{Tok: token.TYPE, Specs: []goast.Spec{resultStruct}}
 
-	file.Decls = []goast.Decl{querierDecl, dbtxDecl, queriesDecl, newFunc, withTxFunc, resultDecl}
-
 	// Add Cache interface if any queries use caching
 	if hasCache {
 		cacheDecl := buildCacheInterface()
 		// Insert cache interface before queriesDecl: [querier, dbtx, cache, queries, newFunc, withTxFunc, resultDecl]
-		file.Decls = []goast.Decl{querierDecl, dbtxDecl, cacheDecl, queriesDecl, newFunc, withTxFunc, resultDecl}
+		file.Decls = append(file.Decls, querierDecl, dbtxDecl, cacheDecl, queriesDecl, newFunc, withTxFunc, resultDecl)
+	} else {
+		file.Decls = append(file.Decls, querierDecl, dbtxDecl, queriesDecl, newFunc, withTxFunc, resultDecl)
 	}
 
 	return file, nil
 }
 
+// dbtxMethods returns the methods of the DBTX interface: those of *sql.DB
+// and *sql.Tx, or with pgx those of pgx.Tx and *pgxpool.Pool that the
+// queries use.
+func (b *Builder) dbtxMethods(queries []queryInfo) []*goast.Field {
+	ctxParams := func(params ...*goast.Field) []*goast.Field {
+		return append([]*goast.Field{{Names: []*goast.Ident{goast.NewIdent("ctx")}, Type: selector("context", "Context")}}, params...)
+	}
+	queryParams := ctxParams(
+		&goast.Field{Names: []*goast.Ident{goast.NewIdent("query")}, Type: goast.NewIdent("string")},
+		&goast.Field{Names: []*goast.Ident{goast.NewIdent("args")}, Type: &goast.Ellipsis{Elt: goast.NewIdent("any")}},
+	)
+	if !b.usesPgx() {
+		return []*goast.Field{
+			{Names: []*goast.Ident{goast.NewIdent("ExecContext")}, Type: funcType(queryParams, []*goast.Field{{Type: selector("sql", "Result")}, {Type: goast.NewIdent("error")}})},
+			{Names: []*goast.Ident{goast.NewIdent("QueryContext")}, Type: funcType(queryParams, []*goast.Field{{Type: &goast.StarExpr{X: selector("sql", "Rows")}}, {Type: goast.NewIdent("error")}})},
+			{Names: []*goast.Ident{goast.NewIdent("QueryRowContext")}, Type: funcType(queryParams, []*goast.Field{{Type: &goast.StarExpr{X: selector("sql", "Row")}}})},
+		}
+	}
+	methods := []*goast.Field{
+		{Names: []*goast.Ident{goast.NewIdent("Exec")}, Type: funcType(queryParams, []*goast.Field{{Type: selector("pgconn", "CommandTag")}, {Type: goast.NewIdent("error")}})},
+		{Names: []*goast.Ident{goast.NewIdent("Query")}, Type: funcType(queryParams, []*goast.Field{{Type: selector("pgx", "Rows")}, {Type: goast.NewIdent("error")}})},
+		{Names: []*goast.Ident{goast.NewIdent("QueryRow")}, Type: funcType(queryParams, []*goast.Field{{Type: selector("pgx", "Row")}})},
+	}
+	if slices.ContainsFunc(queries, func(q queryInfo) bool { return q.copyFrom != nil }) {
+		methods = append(methods, &goast.Field{Names: []*goast.Ident{goast.NewIdent("CopyFrom")}, Type: funcType(ctxParams(
+			&goast.Field{Names: []*goast.Ident{goast.NewIdent("tableName")}, Type: selector("pgx", "Identifier")},
+			&goast.Field{Names: []*goast.Ident{goast.NewIdent("columnNames")}, Type: &goast.ArrayType{Elt: goast.NewIdent("string")}},
+			&goast.Field{Names: []*goast.Ident{goast.NewIdent("rowSrc")}, Type: selector("pgx", "CopyFromSource")},
+		), []*goast.Field{{Type: goast.NewIdent("int64")}, {Type: goast.NewIdent("error")}})})
+	}
+	if slices.ContainsFunc(queries, func(q queryInfo) bool { return q.command.IsBatch() }) {
+		methods = append(methods, &goast.Field{Names: []*goast.Ident{goast.NewIdent("SendBatch")}, Type: funcType(ctxParams(
+			&goast.Field{Names: []*goast.Ident{goast.NewIdent("b")}, Type: &goast.StarExpr{X: selector("pgx", "Batch")}},
+		), []*goast.Field{{Type: selector("pgx", "BatchResults")}})})
+	}
+	return methods
+}
+
 func buildCacheInterface() goast.Decl {
 	methods := []*goast.Field{
 		{Names: []*goast.Ident{goast.NewIdent("Get")}, Type: funcType([]*goast.Field{
@@ -859,10 +1745,19 @@ func (b *Builder) buildHelpersFile(pkg string, helpers []*helperSpec) (*goast.Fi
 
 	// Collect imports needed for helper fields
 	importSet := make(map[string]struct{})
+	if b.usesPgx() {
+		importSet[pgxImport] = struct{}{}
+	}
+	for _, path := range b.nullImports() {
+		importSet[path] = struct{}{}
+	}
 	for _, helper := range helpers {
+		if helper.importPath != "" {
+			importSet[helper.importPath] = struct{}{}
+		}
 		for _, fld := range helper.fields {
-			if fld.importPath != "" {
-				importSet[fld.importPath] = struct{}{}
+			for _, path := range fld.importPaths() {
+				importSet[path] = struct{}{}
 			}
 		}
 	}
@@ -881,48 +1776,54 @@ func (b *Builder) buildHelpersFile(pkg string, helpers []*helperSpec) (*goast.Fi
 
 	decls := make([]goast.Decl, 0, len(helpers)*2) //nolint:mnd // capacity for type and function declarations
 	for _, helper := range helpers {
-		fields := make([]*goast.Field, 0, len(helper.fields))
-		for _, fld := range helper.fields {
-			expr, err := parser.ParseExpr(fld.goType)
+		if helper.emitsStruct() {
+			fields := make([]*goast.Field, 0, len(helper.fields))
+			for _, fld := range helper.fields {
+				expr, err := parser.ParseExpr(fld.goType)
+				if err != nil {
+					return nil, err
+				}
+				fields = append(fields, &goast.Field{Names: []*goast.Ident{goast.NewIdent(fld.name)}, Type: expr})
+			}
+			rowType := &goast.StructType{Fields: &goast.FieldList{List: fields}}
+			rowSpec := &goast.TypeSpec{Name: goast.NewIdent(helper.rowTypeName), Type: rowType}
+			decls = append(decls, &goast.GenDecl{Tok: token.TYPE, Specs: []goast.Spec{rowSpec}})
+		}
+
+		plan := b.buildScanPlan(helper, "")
+		code := make([]string, 0, 3+len(plan.decls)+len(plan.assigns)) //nolint:mnd // capacity for the declaration, scan and return statements
+		code = append(code, "var item "+helper.rowTypeName)
+		code = append(code, plan.decls...)
+		code = append(code, fmt.Sprintf("if err := rows.Scan(%s); err != nil {\nreturn item, err\n}", plan.args))
+		code = append(code, plan.assigns...)
+		code = append(code, "return item, nil")
+		stmts := make([]goast.Stmt, 0, len(code))
+		for _, c := range code {
+			stmt, err := parseStmt(c)
 			if err != nil {
 				return nil, err
 			}
-			fields = append(fields, &goast.Field{Names: []*goast.Ident{goast.NewIdent(fld.name)}, Type: expr})
+			stmts = append(stmts, stmt)
 		}
-		rowType := &goast.StructType{Fields: &goast.FieldList{List: fields}}
-		rowSpec := &goast.TypeSpec{Name: goast.NewIdent(helper.rowTypeName), Type: rowType}
-		decls = append(decls, &goast.GenDecl{Tok: token.TYPE, Specs: []goast.Spec{rowSpec}})
 
-		stmts := make([]goast.Stmt, 0, 3) //nolint:mnd // capacity for up to 3 statements
-		stmt, err := parseStmt("var item " + helper.rowTypeName)
-		if err != nil {
-			return nil, err
-		}
-		stmts = append(stmts, stmt)
-		scanArgs := make([]string, 0, len(helper.fields))
-		for _, fld := range helper.fields {
-			scanArgs = append(scanArgs, "&item."+fld.name)
+		var rowsType goast.Expr = &goast.StarExpr{X: selector("sql", "Rows")}
+		if b.usesPgx() {
+			rowsType = selector("pgx", "Rows")
 		}
-		scanStmt := fmt.Sprintf("if err := rows.Scan(%s); err != nil {\nreturn item, err\n}", strings.Join(scanArgs, ", "))
-		stmt, err = parseStmt(scanStmt)
-		if err != nil {
-			return nil, err
-		}
-		stmts = append(stmts, stmt)
-		stmt, err = parseStmt("return item, nil")
-		if err != nil {
-			return nil, err
-		}
-		stmts = append(stmts, stmt)
-
 		funcDecl := &goast.FuncDecl{
 			Name: goast.NewIdent(helper.funcName),
 			Type: &goast.FuncType{
-				Params:  &goast.FieldList{List: []*goast.Field{{Names: []*goast.Ident{goast.NewIdent("rows")}, Type: &goast.StarExpr{X: selector("sql", "Rows")}}}},
+				Params:  &goast.FieldList{List: []*goast.Field{{Names: []*goast.Ident{goast.NewIdent("rows")}, Type: rowsType}}},
 				Results: &goast.FieldList{List: []*goast.Field{{Type: goast.NewIdent(helper.rowTypeName)}, {Type: goast.NewIdent("error")}}},
 			},
 			Body: &goast.BlockStmt{List: stmts},
 		}
+		switch {
+		case helper.isModel:
+			funcDecl.Doc = b.buildDocComment(fmt.Sprintf("%s scans a row into the %s model.", helper.funcName, helper.rowTypeName))
+		case helper.importPath != "":
+			funcDecl.Doc = b.buildDocComment(fmt.Sprintf("%s scans a row into a %s.", helper.funcName, helper.rowTypeName))
+		}
 		decls = append(decls, funcDecl)
 	}
 	file.Decls = append(file.Decls, decls...)
@@ -941,11 +1842,17 @@ func (b *Builder) buildQueryFiles(pkg string, queries []queryInfo) ([]File, erro
 				importSet[p.importPath] = struct{}{}
 			}
 		}
+		for _, path := range slices.Concat(q.typeImports(), b.nullImports()) {
+			importSet[path] = struct{}{}
+		}
+		if b.usesPgx() {
+			importSet[pgxImport] = struct{}{}
+		}
 		// Collect imports from helper fields (result columns with custom types)
 		if q.helper != nil {
 			for _, fld := range q.helper.fields {
-				if fld.importPath != "" {
-					importSet[fld.importPath] = struct{}{}
+				for _, path := range fld.importPaths() {
+					importSet[path] = struct{}{}
 				}
 			}
 		}
@@ -962,8 +1869,9 @@ func (b *Builder) buildQueryFiles(pkg string, queries []queryInfo) ([]File, erro
 			file.Decls = append(file.Decls, importDecl)
 		}
 
-		// Generate param struct type if needed (for 2+ params)
-		if q.paramStruct != nil {
+		// Generate param struct type if needed (for 2+ params); a shared
+		// @params struct is declared once, with the query that named it first
+		if q.paramStruct != nil && q.paramStruct.importPath == "" && q.paramStruct.owner == q.methodName {
 			structFields := make([]*goast.Field, 0, len(q.paramStruct.fields))
 			for _, f := range q.paramStruct.fields {
 				expr, err := parser.ParseExpr(f.goType)
@@ -996,12 +1904,30 @@ func (b *Builder) buildQueryFiles(pkg string, queries []queryInfo) ([]File, erro
 		}
 
 		file.Decls = append(file.Decls, constDecl, funcDecl)
+		if q.command.IsBatch() {
+			batchDecls, err := b.buildBatchResultsDecls(q)
+			if err != nil {
+				return nil, err
+			}
+			file.Decls = append(file.Decls, batchDecls...)
+		}
 		files = append(files, File{Path: q.fileName, Node: file})
 	}
 	return files, nil
 }
 
 func (b *Builder) buildPreparedFile(pkg string, queries []queryInfo) (File, error) {
+	// Bulk inserts and batches build or prepare their statements per call,
+	// so PreparedQueries delegates them to Queries.
+	var delegated []queryInfo
+	queries = slices.DeleteFunc(slices.Clone(queries), func(q queryInfo) bool {
+		if q.copyFrom != nil || q.command.IsBatch() {
+			delegated = append(delegated, q)
+			return true
+		}
+		return false
+	})
+
 	importSet := map[string]struct{}{
 		"context":      {},
 		"database/sql": {},
@@ -1012,6 +1938,17 @@ func (b *Builder) buildPreparedFile(pkg string, queries []queryInfo) (File, erro
 	if b.opts.Prepared.EmitMetrics {
 		importSet["time"] = struct{}{}
 	}
+	if slices.ContainsFunc(queries, func(q queryInfo) bool { return q.command == block.CommandIter }) {
+		importSet["iter"] = struct{}{}
+	}
+	for _, q := range slices.Concat(queries, delegated) {
+		for _, path := range q.typeImports() {
+			importSet[path] = struct{}{}
+		}
+	}
+	for _, path := range b.nullImports() {
+		importSet[path] = struct{}{}
+	}
 
 	// Use maps.Keys for cleaner extraction
 	keys := slices.Collect(maps.Keys(importSet))
@@ -1153,6 +2090,10 @@ func (b *Builder) buildPreparedFile(pkg string, queries []queryInfo) (File, erro
 	}
 
 	for _, q := range queries {
+		if q.command == block.CommandIter {
+			b.writePreparedIter(&buf, q)
+			continue
+		}
 		if q.docComment != "" {
 			fmt.Fprintf(&buf, "// %s\n", q.docComment)
 		}
@@ -1277,7 +2218,7 @@ func (b *Builder) buildPreparedFile(pkg string, queries []queryInfo) (File, erro
 			fmt.Fprintf(&buf, "\t\treturn 0, err\n")
 			fmt.Fprintf(&buf, "\t}\n")
 			fmt.Fprintf(&buf, "\treturn res.LastInsertId()\n")
-		case block.CommandOne:
+		case block.CommandOne, block.CommandOpt:
 			fmt.Fprintf(&buf, "\trows, err := stmt.QueryContext(ctx")
 			for _, arg := range q.args {
 				fmt.Fprintf(&buf, ", %s", arg)
@@ -1296,25 +2237,42 @@ func (b *Builder) buildPreparedFile(pkg string, queries []queryInfo) (File, erro
 			fmt.Fprintf(&buf, "\t\tif err := rows.Err(); err != nil {\n")
 			fmt.Fprintf(&buf, "\t\t\treturn %s, err\n", q.returnZero)
 			fmt.Fprintf(&buf, "\t\t}\n")
-			fmt.Fprintf(&buf, "\t\treturn %s, sql.ErrNoRows\n", q.returnZero)
+			if q.command == block.CommandOpt {
+				fmt.Fprintf(&buf, "\t\treturn nil, nil\n")
+			} else {
+				fmt.Fprintf(&buf, "\t\treturn %s, sql.ErrNoRows\n", q.returnZero)
+			}
 			fmt.Fprintf(&buf, "\t}\n")
+			// :one returns the scanned item even on error; :opt returns nil
+			failed := "item"
+			if q.command == block.CommandOpt {
+				failed = q.returnZero
+			}
 			// For scalar single-column returns, scan directly; otherwise use helper
 			if q.helper == nil {
-				fmt.Fprintf(&buf, "\tvar item %s\n", q.returnType)
-				fmt.Fprintf(&buf, "\terr = rows.Scan(&item)\n")
+				if q.command == block.CommandOpt {
+					fmt.Fprintf(&buf, "\tvar item %s\n", q.rowType)
+				} else {
+					fmt.Fprintf(&buf, "\tvar item %s\n", q.returnType)
+				}
+				fmt.Fprintf(&buf, "\terr = rows.Scan(%s)\n", b.scanDest("&item", q.scalarStorage))
 				fmt.Fprintf(&buf, "\tif err != nil {\n")
 				fmt.Fprintf(&buf, "\t\treturn %s, err\n", q.returnZero)
 				fmt.Fprintf(&buf, "\t}\n")
 			} else {
 				fmt.Fprintf(&buf, "\titem, err := %s(rows)\n", q.helper.funcName)
 				fmt.Fprintf(&buf, "\tif err != nil {\n")
-				fmt.Fprintf(&buf, "\t\treturn item, err\n")
+				fmt.Fprintf(&buf, "\t\treturn %s, err\n", failed)
 				fmt.Fprintf(&buf, "\t}\n")
 			}
 			fmt.Fprintf(&buf, "\tif err := rows.Err(); err != nil {\n")
-			fmt.Fprintf(&buf, "\t\treturn item, err\n")
+			fmt.Fprintf(&buf, "\t\treturn %s, err\n", failed)
 			fmt.Fprintf(&buf, "\t}\n")
-			fmt.Fprintf(&buf, "\treturn item, nil\n")
+			if q.command == block.CommandOpt {
+				fmt.Fprintf(&buf, "\treturn &item, nil\n")
+			} else {
+				fmt.Fprintf(&buf, "\treturn item, nil\n")
+			}
 		case block.CommandMany:
 			fmt.Fprintf(&buf, "\trows, err := stmt.QueryContext(ctx")
 			for _, arg := range q.args {
@@ -1350,11 +2308,33 @@ func (b *Builder) buildPreparedFile(pkg string, queries []queryInfo) (File, erro
 		fmt.Fprintf(&buf, "}\n\n")
 	}
 
+	for _, q := range delegated {
+		if q.docComment != "" {
+			fmt.Fprintf(&buf, "// %s\n", q.docComment)
+		}
+		results := "(int64, error)"
+		if q.command.IsBatch() {
+			results = q.returnType
+		}
+		fmt.Fprintf(&buf, "func (p *PreparedQueries) %s(ctx context.Context, arg []%s) %s {\n", q.methodName, sliceArgType(q), results)
+		fmt.Fprintf(&buf, "\treturn p.queries.%s(ctx, arg)\n", q.methodName)
+		fmt.Fprintf(&buf, "}\n\n")
+	}
+
 	source := buf.String()
 	formatted, err := imports.Process("", []byte(source), nil)
 	if err != nil {
 		return File{}, err
 	}
+	if b.opts.ConstraintErrors.Wrap {
+		methods := make(map[string]struct{}, len(queries))
+		for _, q := range queries {
+			methods[q.methodName] = struct{}{}
+		}
+		if formatted, err = classifyPreparedErrs(formatted, methods); err != nil {
+			return File{}, err
+		}
+	}
 	fset := token.NewFileSet()
 	node, err := parser.ParseFile(fset, "", formatted, parser.ParseComments)
 	if err != nil {
@@ -1363,16 +2343,958 @@ func (b *Builder) buildPreparedFile(pkg string, queries []queryInfo) (File, erro
 	return File{Path: "prepared.gen.go", Node: node, Raw: formatted}, nil
 }
 
+// writePreparedIter writes the PreparedQueries method of an :iter query.
+// Preparing the statement and running it both happen once iteration starts,
+// so their errors are yielded like row errors.
+func (b *Builder) writePreparedIter(buf *strings.Builder, q queryInfo) {
+	if q.docComment != "" {
+		fmt.Fprintf(buf, "// %s\n", q.docComment)
+	}
+	fmt.Fprintf(buf, "func (p *PreparedQueries) %s(ctx context.Context", q.methodName)
+	if q.paramStruct != nil {
+		fmt.Fprintf(buf, ", arg %s", q.paramStruct.typeName)
+	} else {
+		for _, param := range q.params {
+			if param.variadic {
+				fmt.Fprintf(buf, ", %s ...%s", param.name, param.goType)
+				continue
+			}
+			fmt.Fprintf(buf, ", %s %s", param.name, param.goType)
+		}
+	}
+	fmt.Fprintf(buf, ") %s {\n", q.returnType)
+	fmt.Fprintf(buf, "\treturn func(yield func(%s, error) bool) {\n", q.rowType)
+	if b.opts.Prepared.ThreadSafe {
+		fmt.Fprintf(buf, "\t\tstmt, err := p.%s(ctx)\n", q.prepareFn)
+		fmt.Fprintf(buf, "\t\tif err != nil {\n")
+		fmt.Fprintf(buf, "\t\t\tyield(%s{}, err)\n", q.rowType)
+		fmt.Fprintf(buf, "\t\t\treturn\n")
+		fmt.Fprintf(buf, "\t\t}\n")
+	} else {
+		fmt.Fprintf(buf, "\t\tstmt := p.%s\n", q.stmtField)
+	}
+	if b.opts.Prepared.EmitMetrics {
+		fmt.Fprintf(buf, "\t\trecorder := p.metrics\n")
+		fmt.Fprintf(buf, "\t\tvar start time.Time\n")
+		fmt.Fprintf(buf, "\t\tif recorder != nil {\n")
+		fmt.Fprintf(buf, "\t\t\tstart = time.Now()\n")
+		fmt.Fprintf(buf, "\t\t}\n")
+	}
+	for _, param := range q.params {
+		if !param.variadic {
+			continue
+		}
+		fmt.Fprintf(buf, "\t\t%[1]s := make([]any, len(%[2]s))\n", param.sliceName, param.name)
+		fmt.Fprintf(buf, "\t\tfor i := range %[1]s {\n", param.name)
+		fmt.Fprintf(buf, "\t\t\t%[1]s[i] = %[2]s[i]\n", param.sliceName, param.name)
+		fmt.Fprintf(buf, "\t\t}\n")
+	}
+	fmt.Fprintf(buf, "\t\trows, err := stmt.QueryContext(ctx")
+	for _, arg := range q.args {
+		fmt.Fprintf(buf, ", %s", arg)
+	}
+	fmt.Fprintf(buf, ")\n")
+	if b.opts.Prepared.EmitMetrics {
+		fmt.Fprintf(buf, "\t\tif recorder != nil {\n")
+		fmt.Fprintf(buf, "\t\t\trecorder.ObservePreparedQuery(ctx, %q, time.Since(start), err)\n", q.metricsKey)
+		fmt.Fprintf(buf, "\t\t}\n")
+	}
+	fmt.Fprintf(buf, "\t\tif err != nil {\n")
+	fmt.Fprintf(buf, "\t\t\tyield(%s{}, err)\n", q.rowType)
+	fmt.Fprintf(buf, "\t\t\treturn\n")
+	fmt.Fprintf(buf, "\t\t}\n")
+	fmt.Fprintf(buf, "\t\tdefer rows.Close()\n")
+	fmt.Fprintf(buf, "\t\tfor rows.Next() {\n")
+	fmt.Fprintf(buf, "\t\t\titem, err := %s(rows)\n", q.helper.funcName)
+	fmt.Fprintf(buf, "\t\t\tif err != nil {\n")
+	fmt.Fprintf(buf, "\t\t\t\tyield(item, err)\n")
+	fmt.Fprintf(buf, "\t\t\t\treturn\n")
+	fmt.Fprintf(buf, "\t\t\t}\n")
+	fmt.Fprintf(buf, "\t\t\tif !yield(item, nil) {\n")
+	fmt.Fprintf(buf, "\t\t\t\treturn\n")
+	fmt.Fprintf(buf, "\t\t\t}\n")
+	fmt.Fprintf(buf, "\t\t}\n")
+	fmt.Fprintf(buf, "\t\tif err := rows.Err(); err != nil {\n")
+	fmt.Fprintf(buf, "\t\t\tyield(%s{}, err)\n", q.rowType)
+	fmt.Fprintf(buf, "\t\t}\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "}\n\n")
+}
+
+// usesJSONOverrides reports whether any column override decodes JSON.
+func (b *Builder) usesJSONOverrides() bool {
+	return slices.ContainsFunc(b.opts.ColumnOverrides, func(o config.ColumnOverride) bool {
+		return o.JSON
+	})
+}
+
+// buildJSONFile emits the JSON wrapper used by JSON column overrides. It
+// stores a value as JSON and decodes it on scan, leaving the zero value for
+// NULL.
+func (b *Builder) buildJSONFile(pkg string) (File, error) {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	fmt.Fprintf(&buf, "import (\n")
+	fmt.Fprintf(&buf, "\t\"database/sql/driver\"\n")
+	fmt.Fprintf(&buf, "\t\"encoding/json\"\n")
+	fmt.Fprintf(&buf, "\t\"fmt\"\n")
+	fmt.Fprintf(&buf, ")\n\n")
+
+	fmt.Fprintf(&buf, "// JSON stores V in a JSON column.\n")
+	fmt.Fprintf(&buf, "type JSON[T any] struct {\n")
+	fmt.Fprintf(&buf, "\tV T\n")
+	fmt.Fprintf(&buf, "}\n\n")
+
+	fmt.Fprintf(&buf, "// Scan implements sql.Scanner.\n")
+	fmt.Fprintf(&buf, "func (j *JSON[T]) Scan(src any) error {\n")
+	fmt.Fprintf(&buf, "\tvar zero T\n")
+	fmt.Fprintf(&buf, "\tj.V = zero\n")
+	fmt.Fprintf(&buf, "\tswitch v := src.(type) {\n")
+	fmt.Fprintf(&buf, "\tcase nil:\n")
+	fmt.Fprintf(&buf, "\t\treturn nil\n")
+	fmt.Fprintf(&buf, "\tcase []byte:\n")
+	fmt.Fprintf(&buf, "\t\treturn json.Unmarshal(v, &j.V)\n")
+	fmt.Fprintf(&buf, "\tcase string:\n")
+	fmt.Fprintf(&buf, "\t\treturn json.Unmarshal([]byte(v), &j.V)\n")
+	fmt.Fprintf(&buf, "\tdefault:\n")
+	fmt.Fprintf(&buf, "\t\treturn fmt.Errorf(\"cannot scan %%T into JSON\", src)\n")
+	fmt.Fprintf(&buf, "\t}\n")
+	fmt.Fprintf(&buf, "}\n\n")
+
+	fmt.Fprintf(&buf, "// Value implements driver.Valuer.\n")
+	fmt.Fprintf(&buf, "func (j JSON[T]) Value() (driver.Value, error) {\n")
+	fmt.Fprintf(&buf, "\treturn json.Marshal(j.V)\n")
+	fmt.Fprintf(&buf, "}\n")
+
+	formatted, err := imports.Process("", []byte(buf.String()), nil)
+	if err != nil {
+		return File{}, err
+	}
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, "", formatted, parser.ParseComments)
+	if err != nil {
+		return File{}, err
+	}
+	return File{Path: "json.gen.go", Node: node, Raw: formatted}, nil
+}
+
+// enumSpec is the Go type generated for a schema enum.
+type enumSpec struct {
+	name     string
+	typeName string
+	values   []enumValue
+}
+
+type enumValue struct {
+	constName string
+	value     string
+}
+
+// enumSpecFor returns the Go type of enum, building it on first use.
+func (b *Builder) enumSpecFor(enum *model.Enum) *enumSpec {
+	if spec, ok := b.enums[enum]; ok {
+		return spec
+	}
+	typeName := ExportedIdentifier(enum.Name)
+	spec := &enumSpec{name: enum.Name, typeName: typeName}
+	used := make(map[string]int)
+	for _, value := range enum.Values {
+		// UniqueName cannot fail with a non-nil map
+		constName, _ := UniqueName(typeName+ExportedIdentifier(value), used)
+		spec.values = append(spec.values, enumValue{constName: constName, value: value})
+	}
+	if b.enums == nil {
+		b.enums = make(map[*model.Enum]*enumSpec)
+	}
+	b.enums[enum] = spec
+	return spec
+}
+
+// enumTypeInfo returns the generated type holding values of enum. Nullable
+// values use the Null wrapper in the sql.Null* and pgtype styles and the
+// style's type otherwise; slices of values become slices of the enum type.
+func (b *Builder) enumTypeInfo(enum *model.Enum, goType string, nullable bool, style config.NullStyle) TypeInfo {
+	spec := b.enumSpecFor(enum)
+	switch {
+	case strings.HasPrefix(goType, "[]"):
+		return TypeInfo{GoType: "[]" + spec.typeName}
+	case !nullable:
+		return TypeInfo{GoType: spec.typeName}
+	}
+	switch style = b.nullStyle(style); style {
+	case config.NullStyleSQLNull, config.NullStylePgtype:
+		return TypeInfo{GoType: "Null" + spec.typeName}
+	default:
+		return b.nullableTypeInfo(spec.typeName, style)
+	}
+}
+
+// nullableTypeInfo returns the type of a nullable value of the generated
+// type typeName in style.
+func (b *Builder) nullableTypeInfo(typeName string, style config.NullStyle) TypeInfo {
+	nt := types.Nullable(typeName, style, b.nullOption())
+	return TypeInfo{GoType: nt.GoType, UsesSQLNull: nt.UsesSQLNull, Import: nt.Import, Package: nt.Package}
+}
+
+// buildEnumsFile emits a string type per schema enum with a constant per
+// value. Scanning accepts any value the database returns, while Value
+// rejects values outside the enum before they reach the database.
+func (b *Builder) buildEnumsFile(pkg string) (File, error) {
+	specs := slices.SortedFunc(maps.Values(b.enums), func(a, b *enumSpec) int {
+		return strings.Compare(a.typeName, b.typeName)
+	})
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	fmt.Fprintf(&buf, "import (\n")
+	fmt.Fprintf(&buf, "\t\"database/sql/driver\"\n")
+	fmt.Fprintf(&buf, "\t\"fmt\"\n")
+	fmt.Fprintf(&buf, ")\n")
+
+	for _, spec := range specs {
+		typ := spec.typeName
+		fmt.Fprintf(&buf, "\n// %s is a value of the %s enum.\n", typ, spec.name)
+		fmt.Fprintf(&buf, "type %s string\n\n", typ)
+
+		if len(spec.values) > 0 {
+			fmt.Fprintf(&buf, "const (\n")
+			for _, v := range spec.values {
+				fmt.Fprintf(&buf, "\t%s %s = %s\n", v.constName, typ, strconv.Quote(v.value))
+			}
+			fmt.Fprintf(&buf, ")\n\n")
+		}
+
+		fmt.Fprintf(&buf, "// Values returns the values of the %s enum in schema order.\n", spec.name)
+		fmt.Fprintf(&buf, "func (%s) Values() []%s {\n", typ, typ)
+		fmt.Fprintf(&buf, "\treturn []%s{\n", typ)
+		for _, v := range spec.values {
+			fmt.Fprintf(&buf, "\t\t%s,\n", v.constName)
+		}
+		fmt.Fprintf(&buf, "\t}\n")
+		fmt.Fprintf(&buf, "}\n\n")
+
+		fmt.Fprintf(&buf, "// Valid reports whether e is a value of the %s enum.\n", spec.name)
+		fmt.Fprintf(&buf, "func (e %s) Valid() bool {\n", typ)
+		if len(spec.values) > 0 {
+			names := make([]string, 0, len(spec.values))
+			for _, v := range spec.values {
+				names = append(names, v.constName)
+			}
+			fmt.Fprintf(&buf, "\tswitch e {\n")
+			fmt.Fprintf(&buf, "\tcase %s:\n", strings.Join(names, ", "))
+			fmt.Fprintf(&buf, "\t\treturn true\n")
+			fmt.Fprintf(&buf, "\t}\n")
+		}
+		fmt.Fprintf(&buf, "\treturn false\n")
+		fmt.Fprintf(&buf, "}\n\n")
+
+		fmt.Fprintf(&buf, "// Scan implements sql.Scanner.\n")
+		fmt.Fprintf(&buf, "func (e *%s) Scan(src any) error {\n", typ)
+		fmt.Fprintf(&buf, "\tswitch v := src.(type) {\n")
+		fmt.Fprintf(&buf, "\tcase string:\n")
+		fmt.Fprintf(&buf, "\t\t*e = %s(v)\n", typ)
+		fmt.Fprintf(&buf, "\tcase []byte:\n")
+		fmt.Fprintf(&buf, "\t\t*e = %s(v)\n", typ)
+		fmt.Fprintf(&buf, "\tdefault:\n")
+		fmt.Fprintf(&buf, "\t\treturn fmt.Errorf(\"cannot scan %%T into %s\", src)\n", typ)
+		fmt.Fprintf(&buf, "\t}\n")
+		fmt.Fprintf(&buf, "\treturn nil\n")
+		fmt.Fprintf(&buf, "}\n\n")
+
+		fmt.Fprintf(&buf, "// Value implements driver.Valuer.\n")
+		fmt.Fprintf(&buf, "func (e %s) Value() (driver.Value, error) {\n", typ)
+		fmt.Fprintf(&buf, "\tif !e.Valid() {\n")
+		fmt.Fprintf(&buf, "\t\treturn nil, fmt.Errorf(\"invalid %s value %%q\", string(e))\n", spec.name)
+		fmt.Fprintf(&buf, "\t}\n")
+		fmt.Fprintf(&buf, "\treturn string(e), nil\n")
+		fmt.Fprintf(&buf, "}\n\n")
+
+		fmt.Fprintf(&buf, "// Null%s is a nullable %s.\n", typ, typ)
+		fmt.Fprintf(&buf, "type Null%s struct {\n", typ)
+		fmt.Fprintf(&buf, "\t%s %s\n", typ, typ)
+		fmt.Fprintf(&buf, "\tValid bool // Valid is true if %s is not NULL\n", typ)
+		fmt.Fprintf(&buf, "}\n\n")
+
+		fmt.Fprintf(&buf, "// Scan implements sql.Scanner.\n")
+		fmt.Fprintf(&buf, "func (n *Null%s) Scan(src any) error {\n", typ)
+		fmt.Fprintf(&buf, "\tif src == nil {\n")
+		fmt.Fprintf(&buf, "\t\tn.%s, n.Valid = \"\", false\n", typ)
+		fmt.Fprintf(&buf, "\t\treturn nil\n")
+		fmt.Fprintf(&buf, "\t}\n")
+		fmt.Fprintf(&buf, "\tn.Valid = true\n")
+		fmt.Fprintf(&buf, "\treturn n.%s.Scan(src)\n", typ)
+		fmt.Fprintf(&buf, "}\n\n")
+
+		fmt.Fprintf(&buf, "// Value implements driver.Valuer.\n")
+		fmt.Fprintf(&buf, "func (n Null%s) Value() (driver.Value, error) {\n", typ)
+		fmt.Fprintf(&buf, "\tif !n.Valid {\n")
+		fmt.Fprintf(&buf, "\t\treturn nil, nil\n")
+		fmt.Fprintf(&buf, "\t}\n")
+		fmt.Fprintf(&buf, "\treturn n.%s.Value()\n", typ)
+		fmt.Fprintf(&buf, "}\n")
+	}
+
+	formatted, err := imports.Process("", []byte(buf.String()), nil)
+	if err != nil {
+		return File{}, err
+	}
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, "", formatted, parser.ParseComments)
+	if err != nil {
+		return File{}, err
+	}
+	return File{Path: "enums.gen.go", Node: node, Raw: formatted}, nil
+}
+
+// sliceArgType returns the element type of the items a :copyfrom or batch
+// method takes: the param struct, or the type of the only parameter.
+func sliceArgType(q queryInfo) string {
+	if q.paramStruct != nil {
+		return q.paramStruct.typeName
+	}
+	if len(q.params) == 1 {
+		return q.params[0].goType
+	}
+	return "struct{}"
+}
+
+func sliceArgField(q queryInfo) (*goast.Field, error) {
+	expr, err := parser.ParseExpr("[]" + sliceArgType(q))
+	if err != nil {
+		return nil, err
+	}
+	return &goast.Field{Names: []*goast.Ident{goast.NewIdent("arg")}, Type: expr}, nil
+}
+
+// sliceArgValues returns the argument list of one item, named row, of a
+// :copyfrom or batch method.
+func sliceArgValues(q queryInfo) string {
+	if q.paramStruct == nil {
+		if len(q.params) == 1 {
+			return "row"
+		}
+		return ""
+	}
+	values := make([]string, 0, len(q.paramStruct.fields))
+	for _, f := range q.paramStruct.fields {
+		values = append(values, "row."+f.name)
+	}
+	return strings.Join(values, ", ")
+}
+
+// buildCopyFromBody converts the rows of a :copyfrom method into value lists
+// and hands them to the copyFrom helper.
+func (b *Builder) buildCopyFromBody(q queryInfo) []goast.Stmt {
+	columns := make([]string, 0, len(q.copyFrom.Columns))
+	for _, col := range q.copyFrom.Columns {
+		columns = append(columns, strconv.Quote(col))
+	}
+	call := fmt.Sprintf("copyFrom(ctx, q.db, %q, []string{%s}, rows)", q.copyFrom.Table, strings.Join(columns, ", "))
+	body := []goast.Stmt{
+		mustParseStmt("rows := make([][]any, len(arg))"),
+		mustParseStmt(fmt.Sprintf("for i, row := range arg {\nrows[i] = []any{%s}\n}", sliceArgValues(q))),
+	}
+	if b.opts.ConstraintErrors.Wrap {
+		// Name the error so that classifyErrs can route it through ClassifyError.
+		return append(body, mustParseStmt("n, err := "+call), mustParseStmt("return n, err"))
+	}
+	return append(body, mustParseStmt("return "+call))
+}
+
+// buildCopyFromFile emits the copyFrom helper behind :copyfrom methods. It
+// inserts rows with chunked multi-row INSERT statements in one transaction,
+// keeping each statement within the dialect's parameter limit. On PostgreSQL
+// it uses the COPY protocol when the connection is backed by pgx, and always
+// with pgx as the SQL package.
+func (b *Builder) buildCopyFromFile(pkg string) (File, error) {
+	if b.usesPgx() {
+		return b.buildPgxCopyFromFile(pkg)
+	}
+	postgres := b.opts.Database == config.DatabasePostgreSQL
+	maxParams := 32766
+	quote := `"`
+	switch b.opts.Database {
+	case config.DatabasePostgreSQL:
+		maxParams = 65535
+	case config.DatabaseMySQL:
+		maxParams = 65535
+		quote = "`"
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	fmt.Fprintf(&buf, "import (\n")
+	fmt.Fprintf(&buf, "\t\"context\"\n")
+	fmt.Fprintf(&buf, "\t\"database/sql\"\n")
+	if postgres {
+		fmt.Fprintf(&buf, "\t\"strconv\"\n")
+	}
+	fmt.Fprintf(&buf, "\t\"strings\"\n")
+	if postgres {
+		fmt.Fprintf(&buf, "\n\t\"github.com/jackc/pgx/v5\"\n")
+	}
+	fmt.Fprintf(&buf, ")\n\n")
+
+	fmt.Fprintf(&buf, "// copyFromMaxParams is the number of bind parameters a single statement may use.\n")
+	fmt.Fprintf(&buf, "const copyFromMaxParams = %d\n\n", maxParams)
+
+	if postgres {
+		fmt.Fprintf(&buf, "// copyFrom inserts rows into table with the COPY protocol when the\n")
+		fmt.Fprintf(&buf, "// connection is backed by pgx and with multi-row INSERT statements otherwise.\n")
+		fmt.Fprintf(&buf, "func copyFrom(ctx context.Context, db DBTX, table string, columns []string, rows [][]any) (int64, error) {\n")
+		fmt.Fprintf(&buf, "\tif len(rows) == 0 {\n")
+		fmt.Fprintf(&buf, "\t\treturn 0, nil\n")
+		fmt.Fprintf(&buf, "\t}\n")
+		fmt.Fprintf(&buf, "\tvar n int64\n")
+		fmt.Fprintf(&buf, "\tcopied, err := withPgxConn(ctx, db, func(conn *pgx.Conn) error {\n")
+		fmt.Fprintf(&buf, "\t\tvar err error\n")
+		fmt.Fprintf(&buf, "\t\tn, err = conn.CopyFrom(ctx, pgx.Identifier(strings.Split(table, \".\")), columns, pgx.CopyFromRows(rows))\n")
+		fmt.Fprintf(&buf, "\t\treturn err\n")
+		fmt.Fprintf(&buf, "\t})\n")
+		fmt.Fprintf(&buf, "\tif err != nil || copied {\n")
+		fmt.Fprintf(&buf, "\t\treturn n, err\n")
+		fmt.Fprintf(&buf, "\t}\n")
+		fmt.Fprintf(&buf, "\treturn insertRows(ctx, db, table, columns, rows)\n")
+		fmt.Fprintf(&buf, "}\n\n")
+	} else {
+		fmt.Fprintf(&buf, "// copyFrom inserts rows into table with multi-row INSERT statements.\n")
+		fmt.Fprintf(&buf, "func copyFrom(ctx context.Context, db DBTX, table string, columns []string, rows [][]any) (int64, error) {\n")
+		fmt.Fprintf(&buf, "\treturn insertRows(ctx, db, table, columns, rows)\n")
+		fmt.Fprintf(&buf, "}\n\n")
+	}
+
+	fmt.Fprintf(&buf, "// insertRows inserts rows in chunks that fit copyFromMaxParams. When db can\n")
+	fmt.Fprintf(&buf, "// begin a transaction all chunks are inserted in one; otherwise db is assumed\n")
+	fmt.Fprintf(&buf, "// to be a transaction already.\n")
+	fmt.Fprintf(&buf, "func insertRows(ctx context.Context, db DBTX, table string, columns []string, rows [][]any) (int64, error) {\n")
+	fmt.Fprintf(&buf, "\tif len(rows) == 0 {\n")
+	fmt.Fprintf(&buf, "\t\treturn 0, nil\n")
+	fmt.Fprintf(&buf, "\t}\n")
+	fmt.Fprintf(&buf, "\tbeginner, ok := db.(interface {\n")
+	fmt.Fprintf(&buf, "\t\tBeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)\n")
+	fmt.Fprintf(&buf, "\t})\n")
+	fmt.Fprintf(&buf, "\tif !ok {\n")
+	fmt.Fprintf(&buf, "\t\treturn insertRowChunks(ctx, db, table, columns, rows)\n")
+	fmt.Fprintf(&buf, "\t}\n")
+	fmt.Fprintf(&buf, "\ttx, err := beginner.BeginTx(ctx, nil)\n")
+	fmt.Fprintf(&buf, "\tif err != nil {\n")
+	fmt.Fprintf(&buf, "\t\treturn 0, err\n")
+	fmt.Fprintf(&buf, "\t}\n")
+	fmt.Fprintf(&buf, "\tn, err := insertRowChunks(ctx, tx, table, columns, rows)\n")
+	fmt.Fprintf(&buf, "\tif err != nil {\n")
+	fmt.Fprintf(&buf, "\t\t_ = tx.Rollback()\n")
+	fmt.Fprintf(&buf, "\t\treturn 0, err\n")
+	fmt.Fprintf(&buf, "\t}\n")
+	fmt.Fprintf(&buf, "\tif err := tx.Commit(); err != nil {\n")
+	fmt.Fprintf(&buf, "\t\treturn 0, err\n")
+	fmt.Fprintf(&buf, "\t}\n")
+	fmt.Fprintf(&buf, "\treturn n, nil\n")
+	fmt.Fprintf(&buf, "}\n\n")
+
+	fmt.Fprintf(&buf, "func insertRowChunks(ctx context.Context, db DBTX, table string, columns []string, rows [][]any) (int64, error) {\n")
+	fmt.Fprintf(&buf, "\tquoted := make([]string, len(columns))\n")
+	fmt.Fprintf(&buf, "\tfor i, col := range columns {\n")
+	fmt.Fprintf(&buf, "\t\tquoted[i] = quoteIdent(col)\n")
+	fmt.Fprintf(&buf, "\t}\n")
+	fmt.Fprintf(&buf, "\tparts := strings.Split(table, \".\")\n")
+	fmt.Fprintf(&buf, "\tfor i, part := range parts {\n")
+	fmt.Fprintf(&buf, "\t\tparts[i] = quoteIdent(part)\n")
+	fmt.Fprintf(&buf, "\t}\n")
+	fmt.Fprintf(&buf, "\tprefix := \"INSERT INTO \" + strings.Join(parts, \".\") + \" (\" + strings.Join(quoted, \", \") + \") VALUES \"\n")
+	fmt.Fprintf(&buf, "\tchunk := max(copyFromMaxParams/max(len(columns), 1), 1)\n")
+	fmt.Fprintf(&buf, "\tvar total int64\n")
+	fmt.Fprintf(&buf, "\tfor start := 0; start < len(rows); start += chunk {\n")
+	fmt.Fprintf(&buf, "\t\tend := min(start+chunk, len(rows))\n")
+	fmt.Fprintf(&buf, "\t\tvar query strings.Builder\n")
+	fmt.Fprintf(&buf, "\t\tquery.WriteString(prefix)\n")
+	fmt.Fprintf(&buf, "\t\targs := make([]any, 0, (end-start)*len(columns))\n")
+	fmt.Fprintf(&buf, "\t\tfor i, row := range rows[start:end] {\n")
+	fmt.Fprintf(&buf, "\t\t\tif i > 0 {\n")
+	fmt.Fprintf(&buf, "\t\t\t\tquery.WriteString(\", \")\n")
+	fmt.Fprintf(&buf, "\t\t\t}\n")
+	fmt.Fprintf(&buf, "\t\t\tquery.WriteString(\"(\")\n")
+	fmt.Fprintf(&buf, "\t\t\tfor j, v := range row {\n")
+	fmt.Fprintf(&buf, "\t\t\t\tif j > 0 {\n")
+	fmt.Fprintf(&buf, "\t\t\t\t\tquery.WriteString(\", \")\n")
+	fmt.Fprintf(&buf, "\t\t\t\t}\n")
+	fmt.Fprintf(&buf, "\t\t\t\targs = append(args, v)\n")
+	if postgres {
+		fmt.Fprintf(&buf, "\t\t\t\tquery.WriteString(\"$\" + strconv.Itoa(len(args)))\n")
+	} else {
+		fmt.Fprintf(&buf, "\t\t\t\tquery.WriteString(\"?\")\n")
+	}
+	fmt.Fprintf(&buf, "\t\t\t}\n")
+	fmt.Fprintf(&buf, "\t\t\tquery.WriteString(\")\")\n")
+	fmt.Fprintf(&buf, "\t\t}\n")
+	fmt.Fprintf(&buf, "\t\tres, err := db.ExecContext(ctx, query.String(), args...)\n")
+	fmt.Fprintf(&buf, "\t\tif err != nil {\n")
+	fmt.Fprintf(&buf, "\t\t\treturn 0, err\n")
+	fmt.Fprintf(&buf, "\t\t}\n")
+	fmt.Fprintf(&buf, "\t\tn, err := res.RowsAffected()\n")
+	fmt.Fprintf(&buf, "\t\tif err != nil {\n")
+	fmt.Fprintf(&buf, "\t\t\treturn 0, err\n")
+	fmt.Fprintf(&buf, "\t\t}\n")
+	fmt.Fprintf(&buf, "\t\ttotal += n\n")
+	fmt.Fprintf(&buf, "\t}\n")
+	fmt.Fprintf(&buf, "\treturn total, nil\n")
+	fmt.Fprintf(&buf, "}\n\n")
+
+	fmt.Fprintf(&buf, "func quoteIdent(name string) string {\n")
+	fmt.Fprintf(&buf, "\treturn %[1]q + strings.ReplaceAll(name, %[1]q, %[2]q) + %[1]q\n", quote, quote+quote)
+	fmt.Fprintf(&buf, "}\n")
+
+	formatted, err := imports.Process("", []byte(buf.String()), nil)
+	if err != nil {
+		return File{}, err
+	}
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, "", formatted, parser.ParseComments)
+	if err != nil {
+		return File{}, err
+	}
+	return File{Path: "copyfrom.gen.go", Node: node, Raw: formatted}, nil
+}
+
+// buildPgxCopyFromFile emits the copyFrom helper for pgx as the SQL package,
+// which copies through DBTX.CopyFrom.
+func (b *Builder) buildPgxCopyFromFile(pkg string) (File, error) {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	fmt.Fprintf(&buf, "import (\n")
+	fmt.Fprintf(&buf, "\t\"context\"\n")
+	fmt.Fprintf(&buf, "\t\"strings\"\n\n")
+	fmt.Fprintf(&buf, "\t\"github.com/jackc/pgx/v5\"\n")
+	fmt.Fprintf(&buf, ")\n\n")
+
+	fmt.Fprintf(&buf, "// copyFrom inserts rows into table with the COPY protocol.\n")
+	fmt.Fprintf(&buf, "func copyFrom(ctx context.Context, db DBTX, table string, columns []string, rows [][]any) (int64, error) {\n")
+	fmt.Fprintf(&buf, "\tif len(rows) == 0 {\n")
+	fmt.Fprintf(&buf, "\t\treturn 0, nil\n")
+	fmt.Fprintf(&buf, "\t}\n")
+	fmt.Fprintf(&buf, "\treturn db.CopyFrom(ctx, pgx.Identifier(strings.Split(table, \".\")), columns, pgx.CopyFromRows(rows))\n")
+	fmt.Fprintf(&buf, "}\n")
+
+	formatted, err := imports.Process("", []byte(buf.String()), nil)
+	if err != nil {
+		return File{}, err
+	}
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, "", formatted, parser.ParseComments)
+	if err != nil {
+		return File{}, err
+	}
+	return File{Path: "copyfrom.gen.go", Node: node, Raw: formatted}, nil
+}
+
+// buildPgxFile emits withPgxConn, which gives the PostgreSQL-only paths of
+// :copyfrom and batch methods access to the pgx connection behind DBTX.
+func (b *Builder) buildPgxFile(pkg string) (File, error) {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	fmt.Fprintf(&buf, "import (\n")
+	fmt.Fprintf(&buf, "\t\"context\"\n")
+	fmt.Fprintf(&buf, "\t\"database/sql\"\n\n")
+	fmt.Fprintf(&buf, "\t\"github.com/jackc/pgx/v5\"\n")
+	fmt.Fprintf(&buf, ")\n\n")
+
+	fmt.Fprintf(&buf, "// withPgxConn calls f with the pgx connection behind db. It reports false\n")
+	fmt.Fprintf(&buf, "// without calling f unless db is a *sql.DB or *sql.Conn of the pgx driver.\n")
+	fmt.Fprintf(&buf, "func withPgxConn(ctx context.Context, db DBTX, f func(conn *pgx.Conn) error) (bool, error) {\n")
+	fmt.Fprintf(&buf, "\tvar conn *sql.Conn\n")
+	fmt.Fprintf(&buf, "\tswitch v := db.(type) {\n")
+	fmt.Fprintf(&buf, "\tcase *sql.DB:\n")
+	fmt.Fprintf(&buf, "\t\tc, err := v.Conn(ctx)\n")
+	fmt.Fprintf(&buf, "\t\tif err != nil {\n")
+	fmt.Fprintf(&buf, "\t\t\treturn false, err\n")
+	fmt.Fprintf(&buf, "\t\t}\n")
+	fmt.Fprintf(&buf, "\t\tdefer c.Close()\n")
+	fmt.Fprintf(&buf, "\t\tconn = c\n")
+	fmt.Fprintf(&buf, "\tcase *sql.Conn:\n")
+	fmt.Fprintf(&buf, "\t\tconn = v\n")
+	fmt.Fprintf(&buf, "\tdefault:\n")
+	fmt.Fprintf(&buf, "\t\treturn false, nil\n")
+	fmt.Fprintf(&buf, "\t}\n")
+	fmt.Fprintf(&buf, "\tused := false\n")
+	fmt.Fprintf(&buf, "\terr := conn.Raw(func(driverConn any) error {\n")
+	fmt.Fprintf(&buf, "\t\tpgxConn, ok := driverConn.(interface{ Conn() *pgx.Conn })\n")
+	fmt.Fprintf(&buf, "\t\tif !ok {\n")
+	fmt.Fprintf(&buf, "\t\t\treturn nil\n")
+	fmt.Fprintf(&buf, "\t\t}\n")
+	fmt.Fprintf(&buf, "\t\tused = true\n")
+	fmt.Fprintf(&buf, "\t\treturn f(pgxConn.Conn())\n")
+	fmt.Fprintf(&buf, "\t})\n")
+	fmt.Fprintf(&buf, "\treturn used, err\n")
+	fmt.Fprintf(&buf, "}\n")
+
+	formatted, err := imports.Process("", []byte(buf.String()), nil)
+	if err != nil {
+		return File{}, err
+	}
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, "", formatted, parser.ParseComments)
+	if err != nil {
+		return File{}, err
+	}
+	return File{Path: "pgx.gen.go", Node: node, Raw: formatted}, nil
+}
+
+// buildBatchFile emits the batch runner behind batch methods. A batch runs
+// when its results are first read or it is closed. On PostgreSQL it is sent
+// as one pgx Batch when the connection is backed by pgx; otherwise the items
+// run in one transaction through a single prepared statement. With pgx as
+// the SQL package it is always sent through DBTX.SendBatch. A failing item
+// does not stop the others.
+func (b *Builder) buildBatchFile(pkg string) (File, error) {
+	postgres := b.opts.Database == config.DatabasePostgreSQL
+	native := b.usesPgx()
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	fmt.Fprintf(&buf, "import (\n")
+	fmt.Fprintf(&buf, "\t\"context\"\n")
+	if !native {
+		fmt.Fprintf(&buf, "\t\"database/sql\"\n")
+	}
+	fmt.Fprintf(&buf, "\t\"errors\"\n")
+	if postgres {
+		fmt.Fprintf(&buf, "\n\t\"github.com/jackc/pgx/v5\"\n")
+	}
+	fmt.Fprintf(&buf, ")\n\n")
+
+	fmt.Fprintf(&buf, "// ErrBatchAlreadyClosed is reported for every item of a batch whose results\n")
+	fmt.Fprintf(&buf, "// were already read.\n")
+	fmt.Fprintf(&buf, "var ErrBatchAlreadyClosed = errors.New(\"batch already closed\")\n\n")
+
+	fmt.Fprintf(&buf, "// batchRows is the result set of one item of a batch.\n")
+	fmt.Fprintf(&buf, "type batchRows interface {\n")
+	fmt.Fprintf(&buf, "\tNext() bool\n")
+	fmt.Fprintf(&buf, "\tScan(dest ...any) error\n")
+	fmt.Fprintf(&buf, "\tErr() error\n")
+	fmt.Fprintf(&buf, "}\n\n")
+
+	fmt.Fprintf(&buf, "// batch queues one execution of query for each argument list in args.\n")
+	fmt.Fprintf(&buf, "type batch struct {\n")
+	fmt.Fprintf(&buf, "\tctx   context.Context\n")
+	fmt.Fprintf(&buf, "\tdb    DBTX\n")
+	fmt.Fprintf(&buf, "\tquery string\n")
+	fmt.Fprintf(&buf, "\targs  [][]any\n")
+	fmt.Fprintf(&buf, "\tran   bool\n")
+	fmt.Fprintf(&buf, "\terr   error\n")
+	fmt.Fprintf(&buf, "}\n\n")
+
+	fmt.Fprintf(&buf, "func newBatch(ctx context.Context, db DBTX, query string, args [][]any) *batch {\n")
+	fmt.Fprintf(&buf, "\treturn &batch{ctx: ctx, db: db, query: query, args: args}\n")
+	fmt.Fprintf(&buf, "}\n\n")
+
+	fmt.Fprintf(&buf, "// exec runs the batch and calls f with the error of each item.\n")
+	fmt.Fprintf(&buf, "func (b *batch) exec(f func(int, error)) {\n")
+	fmt.Fprintf(&buf, "\tb.run(false, func(i int, _ batchRows, err error) {\n")
+	fmt.Fprintf(&buf, "\t\tif f != nil {\n")
+	if b.opts.ConstraintErrors.Wrap {
+		fmt.Fprintf(&buf, "\t\t\tf(i, ClassifyError(err))\n")
+	} else {
+		fmt.Fprintf(&buf, "\t\t\tf(i, err)\n")
+	}
+	fmt.Fprintf(&buf, "\t\t}\n")
+	fmt.Fprintf(&buf, "\t})\n")
+	fmt.Fprintf(&buf, "}\n\n")
+
+	fmt.Fprintf(&buf, "// rows runs the batch and calls f with the rows or the error of each item.\n")
+	fmt.Fprintf(&buf, "// The rows are closed when f returns.\n")
+	fmt.Fprintf(&buf, "func (b *batch) rows(f func(int, batchRows, error)) {\n")
+	fmt.Fprintf(&buf, "\tb.run(true, f)\n")
+	fmt.Fprintf(&buf, "}\n\n")
+
+	fmt.Fprintf(&buf, "// close runs the batch unless its results were read and returns the error\n")
+	fmt.Fprintf(&buf, "// that ended it, such as a failed commit.\n")
+	fmt.Fprintf(&buf, "func (b *batch) close() error {\n")
+	fmt.Fprintf(&buf, "\tif !b.ran {\n")
+	fmt.Fprintf(&buf, "\t\tb.exec(nil)\n")
+	fmt.Fprintf(&buf, "\t}\n")
+	if b.opts.ConstraintErrors.Wrap {
+		// A deferred constraint fails at commit
+		fmt.Fprintf(&buf, "\treturn ClassifyError(b.err)\n")
+	} else {
+		fmt.Fprintf(&buf, "\treturn b.err\n")
+	}
+	fmt.Fprintf(&buf, "}\n\n")
+
+	fmt.Fprintf(&buf, "func (b *batch) run(withRows bool, f func(int, batchRows, error)) {\n")
+	fmt.Fprintf(&buf, "\tif b.ran {\n")
+	fmt.Fprintf(&buf, "\t\tfor i := range b.args {\n")
+	fmt.Fprintf(&buf, "\t\t\tf(i, nil, ErrBatchAlreadyClosed)\n")
+	fmt.Fprintf(&buf, "\t\t}\n")
+	fmt.Fprintf(&buf, "\t\treturn\n")
+	fmt.Fprintf(&buf, "\t}\n")
+	fmt.Fprintf(&buf, "\tb.ran = true\n")
+	fmt.Fprintf(&buf, "\tif len(b.args) == 0 {\n")
+	fmt.Fprintf(&buf, "\t\treturn\n")
+	fmt.Fprintf(&buf, "\t}\n")
+	if native {
+		fmt.Fprintf(&buf, "\tvar pgxBatch pgx.Batch\n")
+		fmt.Fprintf(&buf, "\tfor _, args := range b.args {\n")
+		fmt.Fprintf(&buf, "\t\tpgxBatch.Queue(b.query, args...)\n")
+		fmt.Fprintf(&buf, "\t}\n")
+		fmt.Fprintf(&buf, "\tresults := b.db.SendBatch(b.ctx, &pgxBatch)\n")
+		fmt.Fprintf(&buf, "\tfor i := range b.args {\n")
+		fmt.Fprintf(&buf, "\t\tif !withRows {\n")
+		fmt.Fprintf(&buf, "\t\t\t_, err := results.Exec()\n")
+		fmt.Fprintf(&buf, "\t\t\tf(i, nil, err)\n")
+		fmt.Fprintf(&buf, "\t\t\tcontinue\n")
+		fmt.Fprintf(&buf, "\t\t}\n")
+		fmt.Fprintf(&buf, "\t\trows, err := results.Query()\n")
+		fmt.Fprintf(&buf, "\t\tif err != nil {\n")
+		fmt.Fprintf(&buf, "\t\t\tf(i, nil, err)\n")
+		fmt.Fprintf(&buf, "\t\t\tcontinue\n")
+		fmt.Fprintf(&buf, "\t\t}\n")
+		fmt.Fprintf(&buf, "\t\tf(i, rows, nil)\n")
+		fmt.Fprintf(&buf, "\t\trows.Close()\n")
+		fmt.Fprintf(&buf, "\t}\n")
+		fmt.Fprintf(&buf, "\tb.err = results.Close()\n")
+		fmt.Fprintf(&buf, "}\n\n")
+	} else if postgres {
+		fmt.Fprintf(&buf, "\tsent, err := withPgxConn(b.ctx, b.db, func(conn *pgx.Conn) error {\n")
+		fmt.Fprintf(&buf, "\t\tvar pgxBatch pgx.Batch\n")
+		fmt.Fprintf(&buf, "\t\tfor _, args := range b.args {\n")
+		fmt.Fprintf(&buf, "\t\t\tpgxBatch.Queue(b.query, args...)\n")
+		fmt.Fprintf(&buf, "\t\t}\n")
+		fmt.Fprintf(&buf, "\t\tresults := conn.SendBatch(b.ctx, &pgxBatch)\n")
+		fmt.Fprintf(&buf, "\t\tfor i := range b.args {\n")
+		fmt.Fprintf(&buf, "\t\t\tif !withRows {\n")
+		fmt.Fprintf(&buf, "\t\t\t\t_, err := results.Exec()\n")
+		fmt.Fprintf(&buf, "\t\t\t\tf(i, nil, err)\n")
+		fmt.Fprintf(&buf, "\t\t\t\tcontinue\n")
+		fmt.Fprintf(&buf, "\t\t\t}\n")
+		fmt.Fprintf(&buf, "\t\t\trows, err := results.Query()\n")
+		fmt.Fprintf(&buf, "\t\t\tif err != nil {\n")
+		fmt.Fprintf(&buf, "\t\t\t\tf(i, nil, err)\n")
+		fmt.Fprintf(&buf, "\t\t\t\tcontinue\n")
+		fmt.Fprintf(&buf, "\t\t\t}\n")
+		fmt.Fprintf(&buf, "\t\t\tf(i, rows, nil)\n")
+		fmt.Fprintf(&buf, "\t\t\trows.Close()\n")
+		fmt.Fprintf(&buf, "\t\t}\n")
+		fmt.Fprintf(&buf, "\t\treturn results.Close()\n")
+		fmt.Fprintf(&buf, "\t})\n")
+		fmt.Fprintf(&buf, "\tif err != nil && !sent {\n")
+		fmt.Fprintf(&buf, "\t\tfor i := range b.args {\n")
+		fmt.Fprintf(&buf, "\t\t\tf(i, nil, err)\n")
+		fmt.Fprintf(&buf, "\t\t}\n")
+		fmt.Fprintf(&buf, "\t}\n")
+		fmt.Fprintf(&buf, "\tif err != nil || sent {\n")
+		fmt.Fprintf(&buf, "\t\tb.err = err\n")
+		fmt.Fprintf(&buf, "\t\treturn\n")
+		fmt.Fprintf(&buf, "\t}\n")
+	}
+	if !native {
+		fmt.Fprintf(&buf, "\tb.err = b.runStmt(withRows, f)\n")
+		fmt.Fprintf(&buf, "}\n\n")
+		b.writeBatchRunStmt(&buf)
+	}
+
+	fmt.Fprintf(&buf, "// scanBatchRow scans the first row of rows into dest.\n")
+	fmt.Fprintf(&buf, "func scanBatchRow(rows batchRows, dest ...any) error {\n")
+	fmt.Fprintf(&buf, "\tif !rows.Next() {\n")
+	fmt.Fprintf(&buf, "\t\tif err := rows.Err(); err != nil {\n")
+	fmt.Fprintf(&buf, "\t\t\treturn err\n")
+	fmt.Fprintf(&buf, "\t\t}\n")
+	fmt.Fprintf(&buf, "\t\treturn %s\n", b.noRowsErr())
+	fmt.Fprintf(&buf, "\t}\n")
+	fmt.Fprintf(&buf, "\treturn rows.Scan(dest...)\n")
+	fmt.Fprintf(&buf, "}\n")
+
+	formatted, err := imports.Process("", []byte(buf.String()), nil)
+	if err != nil {
+		return File{}, err
+	}
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, "", formatted, parser.ParseComments)
+	if err != nil {
+		return File{}, err
+	}
+	return File{Path: "batch.gen.go", Node: node, Raw: formatted}, nil
+}
+
+// writeBatchRunStmt writes the database/sql path of the batch helper.
+func (b *Builder) writeBatchRunStmt(buf *strings.Builder) {
+	fmt.Fprintf(buf, "// runStmt runs the items through one prepared statement, in a transaction\n")
+	fmt.Fprintf(buf, "// when db can begin one; otherwise db is assumed to be a transaction already.\n")
+	fmt.Fprintf(buf, "func (b *batch) runStmt(withRows bool, f func(int, batchRows, error)) error {\n")
+	fmt.Fprintf(buf, "\tfail := func(err error) error {\n")
+	fmt.Fprintf(buf, "\t\tfor i := range b.args {\n")
+	fmt.Fprintf(buf, "\t\t\tf(i, nil, err)\n")
+	fmt.Fprintf(buf, "\t\t}\n")
+	fmt.Fprintf(buf, "\t\treturn err\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "\tdb := b.db\n")
+	fmt.Fprintf(buf, "\tvar tx *sql.Tx\n")
+	fmt.Fprintf(buf, "\tif beginner, ok := db.(interface {\n")
+	fmt.Fprintf(buf, "\t\tBeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)\n")
+	fmt.Fprintf(buf, "\t}); ok {\n")
+	fmt.Fprintf(buf, "\t\tvar err error\n")
+	fmt.Fprintf(buf, "\t\tif tx, err = beginner.BeginTx(b.ctx, nil); err != nil {\n")
+	fmt.Fprintf(buf, "\t\t\treturn fail(err)\n")
+	fmt.Fprintf(buf, "\t\t}\n")
+	fmt.Fprintf(buf, "\t\tdb = tx\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "\tvar stmt *sql.Stmt\n")
+	fmt.Fprintf(buf, "\tif preparer, ok := db.(interface {\n")
+	fmt.Fprintf(buf, "\t\tPrepareContext(ctx context.Context, query string) (*sql.Stmt, error)\n")
+	fmt.Fprintf(buf, "\t}); ok {\n")
+	fmt.Fprintf(buf, "\t\tvar err error\n")
+	fmt.Fprintf(buf, "\t\tif stmt, err = preparer.PrepareContext(b.ctx, b.query); err != nil {\n")
+	fmt.Fprintf(buf, "\t\t\tif tx != nil {\n")
+	fmt.Fprintf(buf, "\t\t\t\t_ = tx.Rollback()\n")
+	fmt.Fprintf(buf, "\t\t\t}\n")
+	fmt.Fprintf(buf, "\t\t\treturn fail(err)\n")
+	fmt.Fprintf(buf, "\t\t}\n")
+	fmt.Fprintf(buf, "\t\tdefer stmt.Close()\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "\tfor i, args := range b.args {\n")
+	fmt.Fprintf(buf, "\t\tif !withRows {\n")
+	fmt.Fprintf(buf, "\t\t\tvar err error\n")
+	fmt.Fprintf(buf, "\t\t\tif stmt != nil {\n")
+	fmt.Fprintf(buf, "\t\t\t\t_, err = stmt.ExecContext(b.ctx, args...)\n")
+	fmt.Fprintf(buf, "\t\t\t} else {\n")
+	fmt.Fprintf(buf, "\t\t\t\t_, err = db.ExecContext(b.ctx, b.query, args...)\n")
+	fmt.Fprintf(buf, "\t\t\t}\n")
+	fmt.Fprintf(buf, "\t\t\tf(i, nil, err)\n")
+	fmt.Fprintf(buf, "\t\t\tcontinue\n")
+	fmt.Fprintf(buf, "\t\t}\n")
+	fmt.Fprintf(buf, "\t\tvar rows *sql.Rows\n")
+	fmt.Fprintf(buf, "\t\tvar err error\n")
+	fmt.Fprintf(buf, "\t\tif stmt != nil {\n")
+	fmt.Fprintf(buf, "\t\t\trows, err = stmt.QueryContext(b.ctx, args...)\n")
+	fmt.Fprintf(buf, "\t\t} else {\n")
+	fmt.Fprintf(buf, "\t\t\trows, err = db.QueryContext(b.ctx, b.query, args...)\n")
+	fmt.Fprintf(buf, "\t\t}\n")
+	fmt.Fprintf(buf, "\t\tif err != nil {\n")
+	fmt.Fprintf(buf, "\t\t\tf(i, nil, err)\n")
+	fmt.Fprintf(buf, "\t\t\tcontinue\n")
+	fmt.Fprintf(buf, "\t\t}\n")
+	fmt.Fprintf(buf, "\t\tf(i, rows, nil)\n")
+	fmt.Fprintf(buf, "\t\t_ = rows.Close()\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "\tif tx != nil {\n")
+	fmt.Fprintf(buf, "\t\treturn tx.Commit()\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "\treturn nil\n")
+	fmt.Fprintf(buf, "}\n\n")
+}
+
+// buildBatchResultsDecls builds the results type of a batch method and the
+// methods that read it.
+func (b *Builder) buildBatchResultsDecls(q queryInfo) ([]goast.Decl, error) {
+	typeName := q.methodName + "BatchResults"
+	plan := b.buildScanPlan(q.helper, q.scalarStorage)
+	var scanDecls, scanAssigns string
+	for _, decl := range plan.decls {
+		scanDecls += decl + "\n"
+	}
+	for _, assign := range plan.assigns {
+		scanAssigns += assign + "\n"
+	}
+
+	typeSpec := &goast.TypeSpec{
+		Name: goast.NewIdent(typeName),
+		Type: &goast.StructType{Fields: &goast.FieldList{List: []*goast.Field{
+			{Names: []*goast.Ident{goast.NewIdent("batch")}, Type: &goast.StarExpr{X: goast.NewIdent("batch")}},
+		}}},
+	}
+	decls := []goast.Decl{&goast.GenDecl{
+		Doc:   b.buildDocComment(fmt.Sprintf("%s holds the queued executions of %s.", typeName, q.methodName)),
+		Tok:   token.TYPE,
+		Specs: []goast.Spec{typeSpec},
+	}}
+
+	recv := &goast.FieldList{List: []*goast.Field{{
+		Names: []*goast.Ident{goast.NewIdent("b")},
+		Type:  &goast.StarExpr{X: goast.NewIdent(typeName)},
+	}}}
+
+	var name, callback, result string
+	var body []goast.Stmt
+	switch q.command {
+	case block.CommandBatchExec:
+		name, callback, result = "Exec", "func(int, error)", "error"
+		body = []goast.Stmt{mustParseStmt("b.batch.exec(f)")}
+	case block.CommandBatchOne:
+		name, callback, result = "QueryRow", fmt.Sprintf("func(int, %s, error)", q.rowType), "row or the error"
+		if scanAssigns != "" {
+			scanAssigns = "if err == nil {\n" + scanAssigns + "}\n"
+		}
+		body = []goast.Stmt{mustParseStmt(fmt.Sprintf(`b.batch.rows(func(i int, rows batchRows, err error) {
+var item %s
+%sif err == nil {
+err = scanBatchRow(rows, %s)
+}
+%sif f != nil {
+f(i, item, err)
+}
+})`, q.rowType, scanDecls, plan.args, scanAssigns))}
+	case block.CommandBatchMany:
+		name, callback, result = "Query", fmt.Sprintf("func(int, []%s, error)", q.rowType), "rows or the error"
+		items := fmt.Sprintf("var items []%s", q.rowType)
+		if b.opts.EmitEmptySlices {
+			items = fmt.Sprintf("items := make([]%s, 0)", q.rowType)
+		}
+		body = []goast.Stmt{mustParseStmt(fmt.Sprintf(`b.batch.rows(func(i int, rows batchRows, err error) {
+%s
+for err == nil && rows.Next() {
+var item %s
+%sif err = rows.Scan(%s); err == nil {
+%sitems = append(items, item)
+}
+}
+if err == nil {
+err = rows.Err()
+}
+if f != nil {
+f(i, items, err)
+}
+})`, items, q.rowType, scanDecls, plan.args, scanAssigns))}
+	}
+	callbackType, err := parser.ParseExpr(callback)
+	if err != nil {
+		return nil, err
+	}
+	readFn := &goast.FuncDecl{
+		Doc:  b.buildDocComment(fmt.Sprintf("%s runs the batch and calls f with the %s of each item.", name, result)),
+		Recv: recv,
+		Name: goast.NewIdent(name),
+		Type: &goast.FuncType{Params: &goast.FieldList{List: []*goast.Field{{Names: []*goast.Ident{goast.NewIdent("f")}, Type: callbackType}}}},
+		Body: &goast.BlockStmt{List: body},
+	}
+	closeFn := &goast.FuncDecl{
+		Doc:  b.buildDocComment("Close runs the batch unless its results were read and returns the error\nthat ended it, such as a failed commit."),
+		Recv: recv,
+		Name: goast.NewIdent("Close"),
+		Type: &goast.FuncType{Params: &goast.FieldList{}, Results: &goast.FieldList{List: []*goast.Field{{Type: goast.NewIdent("error")}}}},
+		Body: &goast.BlockStmt{List: []goast.Stmt{mustParseStmt("return b.batch.close()")}},
+	}
+	if b.opts.ConstraintErrors.Wrap {
+		classifyErrs(readFn.Body)
+	}
+
+	return append(decls, readFn, closeFn), nil
+}
+
 func (b *Builder) buildQueryFunc(q queryInfo) (*goast.FuncDecl, error) {
 	params := []*goast.Field{{Names: []*goast.Ident{goast.NewIdent("ctx")}, Type: selector("context", "Context")}}
 
 	// If we have a param struct (2+ params), use it instead of individual params
-	if q.paramStruct != nil {
+	switch {
+	case q.copyFrom != nil || q.command.IsBatch():
+		field, err := sliceArgField(q)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, field)
+	case q.paramStruct != nil:
 		params = append(params, &goast.Field{
 			Names: []*goast.Ident{goast.NewIdent("arg")},
 			Type:  goast.NewIdent(q.paramStruct.typeName),
 		})
-	} else {
+	default:
 		for _, p := range q.params {
 			expr, err := parser.ParseExpr(p.goType)
 			if err != nil {
@@ -1394,8 +3316,10 @@ func (b *Builder) buildQueryFunc(q queryInfo) (*goast.FuncDecl, error) {
 		}
 		results = append(results, &goast.Field{Type: expr})
 	}
-	errorType, _ := parser.ParseExpr("error")
-	results = append(results, &goast.Field{Type: errorType})
+	if q.returnsError() {
+		errorType, _ := parser.ParseExpr("error")
+		results = append(results, &goast.Field{Type: errorType})
+	}
 
 	body := make([]goast.Stmt, 0)
 
@@ -1438,7 +3362,7 @@ func (b *Builder) buildQueryFunc(q queryInfo) (*goast.FuncDecl, error) {
 			continue
 		}
 		makeStmt := mustParseStmt(fmt.Sprintf("%s := make([]any, len(%s))", p.sliceName, p.name))
-		loopStmt := mustParseStmt(fmt.Sprintf("for i := range %s {\n%s[i] = %s[i]\n}", p.name, p.sliceName, p.name))
+		loopStmt := mustParseStmt(fmt.Sprintf("for i := range %s {\n%s[i] = %s\n}", p.name, p.sliceName, b.bindValue(p.name+"[i]", p.storage)))
 		body = append(body, makeStmt, loopStmt)
 	}
 
@@ -1451,11 +3375,11 @@ func (b *Builder) buildQueryFunc(q queryInfo) (*goast.FuncDecl, error) {
 		for _, p := range q.params {
 			switch {
 			case p.isDynamicSlice:
-				body = append(body, mustParseStmt(fmt.Sprintf("for _, v := range %s {\nargs = append(args, v)\n}", p.name)))
+				body = append(body, mustParseStmt(fmt.Sprintf("for _, v := range %s {\nargs = append(args, %s)\n}", p.name, b.bindValue("v", p.storage))))
 			case p.variadic:
 				body = append(body, mustParseStmt(fmt.Sprintf("args = append(args, %s...)", p.sliceName)))
 			default:
-				body = append(body, mustParseStmt(fmt.Sprintf("args = append(args, %s)", p.name)))
+				body = append(body, mustParseStmt(fmt.Sprintf("args = append(args, %s)", b.bindValue(p.name, p.storage))))
 			}
 		}
 	}
@@ -1464,42 +3388,61 @@ func (b *Builder) buildQueryFunc(q queryInfo) (*goast.FuncDecl, error) {
 	case block.CommandExec:
 		// :exec returns just error, execute and discard result
 		if hasDynamic {
-			body = append(body, mustParseStmt(fmt.Sprintf("_, err := q.db.ExecContext(ctx, query, %s...)", callArgsName)))
+			body = append(body, mustParseStmt(fmt.Sprintf("_, err := q.db.%s(ctx, query, %s...)", b.dbMethod("Exec"), callArgsName)))
 		} else {
 			args := append([]string{"ctx", q.constName}, q.args...)
-			body = append(body, mustParseStmt(fmt.Sprintf("_, err := q.db.ExecContext(%s)", strings.Join(args, ", "))))
+			body = append(body, mustParseStmt(fmt.Sprintf("_, err := q.db.%s(%s)", b.dbMethod("Exec"), strings.Join(args, ", "))))
 		}
 		body = append(body, mustParseStmt("return err"))
 	case block.CommandExecResult:
 		if hasDynamic {
-			body = append(body, mustParseStmt(fmt.Sprintf("res, err := q.db.ExecContext(ctx, query, %s...)", callArgsName)))
+			body = append(body, mustParseStmt(fmt.Sprintf("res, err := q.db.%s(ctx, query, %s...)", b.dbMethod("Exec"), callArgsName)))
 		} else {
 			args := append([]string{"ctx", q.constName}, q.args...)
-			body = append(body, mustParseStmt(fmt.Sprintf("res, err := q.db.ExecContext(%s)", strings.Join(args, ", "))))
+			body = append(body, mustParseStmt(fmt.Sprintf("res, err := q.db.%s(%s)", b.dbMethod("Exec"), strings.Join(args, ", "))))
 		}
 		body = append(body, mustParseStmt("if err != nil {\nreturn QueryResult{}, err\n}"))
+		if b.usesPgx() {
+			// A command tag carries no last insert ID
+			body = append(body, mustParseStmt("return QueryResult{RowsAffected: res.RowsAffected()}, nil"))
+			break
+		}
 		body = append(body, mustParseStmt("result := QueryResult{}"))
 		body = append(body, mustParseStmt("if v, err := res.LastInsertId(); err == nil {\nresult.LastInsertID = v\n}"))
 		body = append(body, mustParseStmt("if v, err := res.RowsAffected(); err == nil {\nresult.RowsAffected = v\n}"))
 		body = append(body, mustParseStmt("return result, nil"))
 	case block.CommandExecRows:
 		if hasDynamic {
-			body = append(body, mustParseStmt(fmt.Sprintf("res, err := q.db.ExecContext(ctx, query, %s...)", callArgsName)))
+			body = append(body, mustParseStmt(fmt.Sprintf("res, err := q.db.%s(ctx, query, %s...)", b.dbMethod("Exec"), callArgsName)))
 		} else {
 			args := append([]string{"ctx", q.constName}, q.args...)
-			body = append(body, mustParseStmt(fmt.Sprintf("res, err := q.db.ExecContext(%s)", strings.Join(args, ", "))))
+			body = append(body, mustParseStmt(fmt.Sprintf("res, err := q.db.%s(%s)", b.dbMethod("Exec"), strings.Join(args, ", "))))
 		}
 		body = append(body, mustParseStmt("if err != nil {\nreturn 0, err\n}"))
-		body = append(body, mustParseStmt("return res.RowsAffected()"))
+		if b.usesPgx() {
+			body = append(body, mustParseStmt("return res.RowsAffected(), nil"))
+		} else {
+			body = append(body, mustParseStmt("return res.RowsAffected()"))
+		}
 	case block.CommandExecLastID:
 		if hasDynamic {
-			body = append(body, mustParseStmt(fmt.Sprintf("res, err := q.db.ExecContext(ctx, query, %s...)", callArgsName)))
+			body = append(body, mustParseStmt(fmt.Sprintf("res, err := q.db.%s(ctx, query, %s...)", b.dbMethod("Exec"), callArgsName)))
 		} else {
 			args := append([]string{"ctx", q.constName}, q.args...)
-			body = append(body, mustParseStmt(fmt.Sprintf("res, err := q.db.ExecContext(%s)", strings.Join(args, ", "))))
+			body = append(body, mustParseStmt(fmt.Sprintf("res, err := q.db.%s(%s)", b.dbMethod("Exec"), strings.Join(args, ", "))))
 		}
 		body = append(body, mustParseStmt("if err != nil {\nreturn 0, err\n}"))
 		body = append(body, mustParseStmt("return res.LastInsertId()"))
+	case block.CommandCopyFrom:
+		body = append(body, b.buildCopyFromBody(q)...)
+	case block.CommandBatchExec, block.CommandBatchOne, block.CommandBatchMany:
+		body = append(body,
+			mustParseStmt("args := make([][]any, len(arg))"),
+			mustParseStmt(fmt.Sprintf("for i, row := range arg {\nargs[i] = []any{%s}\n}", sliceArgValues(q))),
+			mustParseStmt(fmt.Sprintf("return &%sBatchResults{batch: newBatch(ctx, q.db, %s, args)}", q.methodName, q.constName)),
+		)
+	case block.CommandIter:
+		body = append(body, b.buildIterBody(q, hasDynamic, callArgsName))
 	default:
 		// Check if caching is enabled for this query
 		if q.cache != nil && q.cache.enabled {
@@ -1519,6 +3462,9 @@ func (b *Builder) buildQueryFunc(q queryInfo) (*goast.FuncDecl, error) {
 	if q.docComment != "" {
 		funcDecl.Doc = b.buildDocComment(q.docComment)
 	}
+	if b.opts.ConstraintErrors.Wrap {
+		classifyErrs(funcDecl.Body)
+	}
 
 	return funcDecl, nil
 }
@@ -1534,11 +3480,16 @@ func (b *Builder) buildCacheAwareQueryBody(body []goast.Stmt, q queryInfo, hasDy
 	}
 	body = append(body, mustParseStmt(keyBuilder))
 
-	// Check cache
+	// Check cache. :opt queries cache the row itself and hand out a pointer
+	// to a copy, so callers never share a cached value.
+	cachedType, cachedResult := q.returnType, "result"
+	if q.command == block.CommandOpt {
+		cachedType, cachedResult = q.rowType, "&result"
+	}
 	body = append(body, mustParseStmt(`if q.cache != nil {
 if cached, ok := q.cache.Get(ctx, cacheKey); ok {
-if result, ok := cached.(`+q.returnType+`); ok {
-return result, nil
+if result, ok := cached.(`+cachedType+`); ok {
+return `+cachedResult+`, nil
 }
 }
 }`))
@@ -1557,20 +3508,37 @@ func (b *Builder) buildStandardQueryBody(body []goast.Stmt, q queryInfo, hasDyna
 	}
 
 	// Optimization: Use QueryRowContext for :one queries (faster, no iterator overhead)
-	if q.command == block.CommandOne {
+	if q.command == block.CommandOne || q.command == block.CommandOpt {
 		if hasDynamic {
 			args := fmt.Sprintf("query, %s...", callArgsName)
-			body = append(body, mustParseStmt(fmt.Sprintf("row := q.db.QueryRowContext(ctx, %s)", args)))
+			body = append(body, mustParseStmt(fmt.Sprintf("row := q.db.%s(ctx, %s)", b.dbMethod("QueryRow"), args)))
 		} else {
 			args := append([]string{"ctx", q.constName}, q.args...)
-			body = append(body, mustParseStmt(fmt.Sprintf("row := q.db.QueryRowContext(%s)", strings.Join(args, ", "))))
+			body = append(body, mustParseStmt(fmt.Sprintf("row := q.db.%s(%s)", b.dbMethod("QueryRow"), strings.Join(args, ", "))))
+		}
+		// pgx reports query errors from Scan
+		if !b.usesPgx() {
+			body = append(body, mustParseStmt("if err := row.Err(); err != nil {\nreturn "+zero+", err\n}"))
 		}
-		body = append(body, mustParseStmt("if err := row.Err(); err != nil {\nreturn "+zero+", err\n}"))
 
 		// Inline scan directly - faster than using helper with Row
-		body = append(body, mustParseStmt("var item "+q.returnType))
-		body = append(body, mustParseStmt("err := row.Scan("+b.buildScanArgsFromHelper(q.helper)+")"))
+		if q.command == block.CommandOpt {
+			body = append(body, mustParseStmt("var item "+q.rowType))
+		} else {
+			body = append(body, mustParseStmt("var item "+q.returnType))
+		}
+		plan := b.buildScanPlan(q.helper, q.scalarStorage)
+		for _, decl := range plan.decls {
+			body = append(body, mustParseStmt(decl))
+		}
+		body = append(body, mustParseStmt("err := row.Scan("+plan.args+")"))
+		if q.command == block.CommandOpt {
+			body = append(body, mustParseStmt("if errors.Is(err, "+b.noRowsErr()+") {\nreturn nil, nil\n}"))
+		}
 		body = append(body, mustParseStmt("if err != nil {\nreturn "+zero+", err\n}"))
+		for _, assign := range plan.assigns {
+			body = append(body, mustParseStmt(assign))
+		}
 
 		// Cache the result before returning
 		if q.cache != nil && q.cache.enabled {
@@ -1578,23 +3546,27 @@ func (b *Builder) buildStandardQueryBody(body []goast.Stmt, q queryInfo, hasDyna
 q.cache.Set(ctx, cacheKey, item, %d)
 }`, int(q.cache.ttl.Seconds()))))
 		}
-		body = append(body, mustParseStmt("return item, nil"))
+		if q.command == block.CommandOpt {
+			body = append(body, mustParseStmt("return &item, nil"))
+		} else {
+			body = append(body, mustParseStmt("return item, nil"))
+		}
 		return body
 	}
 
 	// Standard Query for :many and other commands
 	if hasDynamic {
-		body = append(body, mustParseStmt(fmt.Sprintf("rows, err := q.db.QueryContext(ctx, query, %s...)", callArgsName)))
+		body = append(body, mustParseStmt(fmt.Sprintf("rows, err := q.db.%s(ctx, query, %s...)", b.dbMethod("Query"), callArgsName)))
 	} else {
 		args := append([]string{"ctx", q.constName}, q.args...)
-		body = append(body, mustParseStmt(fmt.Sprintf("rows, err := q.db.QueryContext(%s)", strings.Join(args, ", "))))
+		body = append(body, mustParseStmt(fmt.Sprintf("rows, err := q.db.%s(%s)", b.dbMethod("Query"), strings.Join(args, ", "))))
 	}
 	body = append(body, mustParseStmt("if err != nil {\nreturn "+zero+", err\n}"))
 	body = append(body, mustParseStmt("defer rows.Close()"))
 
 	// For :one queries with helpers, use the iterator pattern
 	if q.command == block.CommandOne {
-		body = append(body, mustParseStmt("if !rows.Next() {\nif err := rows.Err(); err != nil {\nreturn "+zero+", err\n}\nreturn "+zero+", sql.ErrNoRows\n}"))
+		body = append(body, mustParseStmt("if !rows.Next() {\nif err := rows.Err(); err != nil {\nreturn "+zero+", err\n}\nreturn "+zero+", "+b.noRowsErr()+"\n}"))
 		body = append(body, mustParseStmt("item, err := "+q.helper.funcName+"(rows)"))
 		body = append(body, mustParseStmt("if err != nil {\nreturn item, err\n}"))
 		body = append(body, mustParseStmt("if err := rows.Err(); err != nil {\nreturn item, err\n}"))
@@ -1634,15 +3606,36 @@ q.cache.Set(ctx, cacheKey, items, %d)
 	return body
 }
 
-func (b *Builder) buildScanArgsFromHelper(helper *helperSpec) string {
-	if helper == nil || len(helper.fields) == 0 {
-		return "&item"
-	}
-	args := make([]string, 0, len(helper.fields))
-	for _, fld := range helper.fields {
-		args = append(args, "&item."+fld.name)
-	}
-	return strings.Join(args, ", ")
+// buildIterBody returns the iterator an :iter method hands out. The query
+// runs when iteration starts, and rows are scanned one at a time. Returning
+// from the loop closes the rows, also when the consumer breaks early.
+func (b *Builder) buildIterBody(q queryInfo, hasDynamic bool, callArgsName string) goast.Stmt {
+	call := strings.Join(append([]string{"ctx", q.constName}, q.args...), ", ")
+	if hasDynamic {
+		call = fmt.Sprintf("ctx, query, %s...", callArgsName)
+	}
+	zero := q.rowType + "{}"
+	return mustParseStmt(fmt.Sprintf(`return func(yield func(%[1]s, error) bool) {
+rows, err := q.db.%[5]s(%[2]s)
+if err != nil {
+yield(%[3]s, err)
+return
+}
+defer rows.Close()
+for rows.Next() {
+item, err := %[4]s(rows)
+if err != nil {
+yield(item, err)
+return
+}
+if !yield(item, nil) {
+return
+}
+}
+if err := rows.Err(); err != nil {
+yield(%[3]s, err)
+}
+}`, q.rowType, call, zero, q.helper.funcName, b.dbMethod("Query")))
 }
 
 func selector(pkg, name string) *goast.SelectorExpr {
@@ -1721,6 +3714,17 @@ func (b *Builder) zeroValueForType(goType string) string {
 		if strings.HasPrefix(goType, "*") {
 			return "nil"
 		}
+		// Enum types are strings
+		for _, spec := range b.enums {
+			if spec.typeName == goType {
+				return `""`
+			}
+		}
+		for _, spec := range b.domains {
+			if spec != nil && spec.typeName == goType {
+				return spec.zeroValue()
+			}
+		}
 		// For sql.Null* types and other structs
 		if strings.HasPrefix(goType, "sql.Null") {
 			return goType + "{}"
@@ -4,11 +4,17 @@ package ast
 import (
 	"context"
 	"go/ast"
+	"go/format"
+	"go/token"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/electwix/db-catalyst/internal/config"
 	"github.com/electwix/db-catalyst/internal/query/analyzer"
 	"github.com/electwix/db-catalyst/internal/query/block"
+	"github.com/electwix/db-catalyst/internal/query/cache"
 	"github.com/electwix/db-catalyst/internal/query/parser"
 	"github.com/electwix/db-catalyst/internal/schema/model"
 )
@@ -159,8 +165,8 @@ func TestTypeResolver_ResolveType(t *testing.T) {
 		nullable bool
 		want     string
 	}{
-		{"integer not null", "INTEGER", false, "int32"},
-		{"integer nullable", "INTEGER", true, "*int32"},
+		{"integer not null", "INTEGER", false, "int64"},
+		{"integer nullable", "INTEGER", true, "sql.NullInt64"},
 		{"text not null", "TEXT", false, "string"},
 		{"text nullable", "TEXT", true, "sql.NullString"},
 		{"real not null", "REAL", false, "float64"},
@@ -211,10 +217,10 @@ func TestPreparedOptions(t *testing.T) {
 
 func TestBuilder_Options(t *testing.T) {
 	opts := Options{
-		Package:             "mypackage",
-		EmitJSONTags:        true,
-		EmitEmptySlices:     true,
-		EmitPointersForNull: true,
+		Package:         "mypackage",
+		EmitJSONTags:    true,
+		EmitEmptySlices: true,
+		NullStyle:       config.NullStylePointer,
 		Prepared: PreparedOptions{
 			Enabled:     true,
 			EmitMetrics: true,
@@ -232,8 +238,8 @@ func TestBuilder_Options(t *testing.T) {
 	if !builder.opts.EmitEmptySlices {
 		t.Error("EmitEmptySlices should be true")
 	}
-	if !builder.opts.EmitPointersForNull {
-		t.Error("EmitPointersForNull should be true")
+	if builder.opts.NullStyle != config.NullStylePointer {
+		t.Errorf("NullStyle = %q, want %q", builder.opts.NullStyle, config.NullStylePointer)
 	}
 	if !builder.opts.Prepared.Enabled {
 		t.Error("Prepared.Enabled should be true")
@@ -631,8 +637,8 @@ func TestBuildPreparedFile_EmptyQueries(t *testing.T) {
 
 func TestBuildParams_EdgeCases(t *testing.T) {
 	b := New(Options{
-		Package:             "test",
-		EmitPointersForNull: true,
+		Package:   "test",
+		NullStyle: config.NullStylePointer,
 	})
 
 	tests := []struct {
@@ -2067,12 +2073,12 @@ func TestTypeResolver_ResolveType_SQLiteTypes(t *testing.T) {
 		wantType string
 		wantSQL  bool
 	}{
-		{"INTEGER not null", "INTEGER", false, "int32", false},
-		{"INTEGER nullable", "INTEGER", true, "*int32", false}, // int32 uses pointer for nullable
+		{"INTEGER not null", "INTEGER", false, "int64", false},
+		{"INTEGER nullable", "INTEGER", true, "sql.NullInt64", true},
 		{"BIGINT not null", "BIGINT", false, "int64", false},
 		{"BIGINT nullable", "BIGINT", true, "sql.NullInt64", true},
 		{"SMALLINT not null", "SMALLINT", false, "int16", false},
-		{"SMALLINT nullable", "SMALLINT", true, "*int16", false}, // int16 uses pointer for nullable
+		{"SMALLINT nullable", "SMALLINT", true, "sql.NullInt16", true},
 		{"TINYINT not null", "TINYINT", false, "int8", false},
 		{"TINYINT nullable", "TINYINT", true, "*int8", false}, // int8 uses pointer for nullable
 		{"TEXT not null", "TEXT", false, "string", false},
@@ -2123,8 +2129,8 @@ func TestTypeResolver_ResolveType_WithPointersForNull(t *testing.T) {
 		nullable bool
 		want     string
 	}{
-		{"INTEGER not null", "INTEGER", false, "int32"},
-		{"INTEGER nullable with pointer", "INTEGER", true, "*int32"},
+		{"INTEGER not null", "INTEGER", false, "int64"},
+		{"INTEGER nullable with pointer", "INTEGER", true, "*int64"},
 		{"TEXT nullable with pointer", "TEXT", true, "*string"},
 		{"REAL nullable with pointer", "REAL", true, "*float64"},
 		{"BOOLEAN nullable with pointer", "BOOLEAN", true, "*bool"},
@@ -2139,3 +2145,1302 @@ func TestTypeResolver_ResolveType_WithPointersForNull(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildJSONOverride(t *testing.T) {
+	b := New(Options{
+		Package: "test",
+		ColumnOverrides: []config.ColumnOverride{
+			{
+				Column: "users.settings",
+				GoType: config.GoTypeDetails{Import: "github.com/example/types", Package: "types", Type: "types.Settings"},
+				JSON:   true,
+			},
+		},
+	})
+
+	tbl := &model.Table{
+		Name: "users",
+		Columns: []*model.Column{
+			{Name: "id", Type: "INTEGER", NotNull: true},
+			{Name: "settings", Type: "JSON"},
+		},
+	}
+	mdl, err := b.buildTableModel(tbl)
+	if err != nil {
+		t.Fatalf("buildTableModel() error = %v", err)
+	}
+	if got := mdl.fields[1].goType; got != "JSON[types.Settings]" {
+		t.Errorf("settings goType = %q, want JSON[types.Settings]", got)
+	}
+
+	file, err := b.buildJSONFile("test")
+	if err != nil {
+		t.Fatalf("buildJSONFile() error = %v", err)
+	}
+	if file.Path != "json.gen.go" {
+		t.Errorf("Path = %q, want json.gen.go", file.Path)
+	}
+	src := string(file.Raw)
+	for _, want := range []string{
+		"type JSON[T any] struct",
+		"func (j *JSON[T]) Scan(src any) error",
+		"func (j JSON[T]) Value() (driver.Value, error)",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("json.gen.go missing %q:\n%s", want, src)
+		}
+	}
+
+	if New(Options{Package: "test"}).usesJSONOverrides() {
+		t.Error("usesJSONOverrides() = true without JSON overrides")
+	}
+}
+
+func TestBuildArrayAggResult(t *testing.T) {
+	analyses := []analyzer.Result{
+		{
+			Query: parser.Query{Block: block.Block{Name: "ListUserIds", SQL: "SELECT email, array_agg(id) AS ids FROM users GROUP BY email", Command: block.CommandMany}},
+			Columns: []analyzer.ResultColumn{
+				{Name: "email", GoType: "string"},
+				{Name: "ids", GoType: "[]int64", Nullable: true},
+			},
+		},
+	}
+
+	for _, pkg := range []config.SQLPackage{config.SQLPackageDatabaseSQL, config.SQLPackagePgxV5} {
+		t.Run(string(pkg), func(t *testing.T) {
+			opts := Options{
+				Package:      "test",
+				Database:     config.DatabasePostgreSQL,
+				SQLPackage:   pkg,
+				TypeResolver: NewTypeResolverWithDatabase(nil, config.DatabasePostgreSQL),
+			}
+			files, err := New(opts).Build(context.Background(), &model.Catalog{}, analyses)
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			helpers := renderFiles(t, files)["helpers.gen.go"]
+			if !strings.Contains(helpers, "Ids   []int64\n") {
+				t.Errorf("helpers.gen.go should keep the []int64 of array_agg:\n%s", helpers)
+			}
+		})
+	}
+}
+
+// renderFiles formats the generated files, keyed by path.
+func renderFiles(t *testing.T, files []File) map[string]string {
+	t.Helper()
+	rendered := make(map[string]string, len(files))
+	for _, f := range files {
+		if f.Raw != nil {
+			rendered[f.Path] = string(f.Raw)
+			continue
+		}
+		var buf strings.Builder
+		if err := format.Node(&buf, token.NewFileSet(), f.Node); err != nil {
+			t.Fatalf("format %s: %v", f.Path, err)
+		}
+		rendered[f.Path] = buf.String()
+	}
+	return rendered
+}
+
+func TestBuildCopyFrom(t *testing.T) {
+	analyses := []analyzer.Result{
+		{
+			Query: parser.Query{
+				Block: block.Block{
+					Name:    "CreatePosts",
+					SQL:     "INSERT INTO posts (user_id, title) VALUES ($1, $2)",
+					Command: block.CommandCopyFrom,
+				},
+			},
+			Params: []analyzer.ResultParam{
+				{Name: "user_id", GoType: "int64"},
+				{Name: "title", GoType: "string"},
+			},
+			CopyFrom: &analyzer.CopyFromTarget{Table: "posts", Columns: []string{"user_id", "title"}},
+		},
+	}
+
+	tests := []struct {
+		database config.Database
+		want     []string
+		notWant  []string
+	}{
+		{
+			database: config.DatabasePostgreSQL,
+			want:     []string{"const copyFromMaxParams = 65535", "conn.CopyFrom(", `"$" + strconv.Itoa(len(args))`},
+		},
+		{
+			database: config.DatabaseSQLite,
+			want:     []string{"const copyFromMaxParams = 32766", `query.WriteString("?")`},
+			notWant:  []string{"pgx"},
+		},
+		{
+			database: config.DatabaseMySQL,
+			want:     []string{"const copyFromMaxParams = 65535", "return \"`\" +"},
+			notWant:  []string{"pgx"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.database), func(t *testing.T) {
+			b := New(Options{Package: "test", Database: tt.database, Prepared: PreparedOptions{Enabled: true}})
+			files, err := b.Build(context.Background(), &model.Catalog{}, analyses)
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+
+			rendered := renderFiles(t, files)
+
+			query := rendered["query_create_posts.go"]
+			for _, want := range []string{
+				"func (q *Queries) CreatePosts(ctx context.Context, arg []CreatePostsParams) (int64, error)",
+				"rows[i] = []any{row.UserId, row.Title}",
+				`return copyFrom(ctx, q.db, "posts", []string{"user_id", "title"}, rows)`,
+			} {
+				if !strings.Contains(query, want) {
+					t.Errorf("query file missing %q:\n%s", want, query)
+				}
+			}
+			if !strings.Contains(rendered["querier.gen.go"], "CreatePosts(ctx context.Context, arg []CreatePostsParams) (int64, error)") {
+				t.Errorf("querier missing CreatePosts:\n%s", rendered["querier.gen.go"])
+			}
+			prepared := rendered["prepared.gen.go"]
+			if !strings.Contains(prepared, "return p.queries.CreatePosts(ctx, arg)") || strings.Contains(prepared, "stmtCreatePosts") {
+				t.Errorf("prepared file should delegate CreatePosts:\n%s", prepared)
+			}
+
+			copyFrom, ok := rendered["copyfrom.gen.go"]
+			if !ok {
+				t.Fatal("expected copyfrom.gen.go")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(copyFrom, want) {
+					t.Errorf("copyfrom.gen.go missing %q:\n%s", want, copyFrom)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(copyFrom, notWant) {
+					t.Errorf("copyfrom.gen.go unexpectedly contains %q", notWant)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildBatch(t *testing.T) {
+	analyses := []analyzer.Result{
+		{
+			Query: parser.Query{Block: block.Block{Name: "SetTitle", SQL: "UPDATE posts SET title = ? WHERE id = ?", Command: block.CommandBatchExec}},
+			Params: []analyzer.ResultParam{
+				{Name: "title", GoType: "string"},
+				{Name: "id", GoType: "int64"},
+			},
+		},
+		{
+			Query:   parser.Query{Block: block.Block{Name: "GetTitle", SQL: "SELECT title FROM posts WHERE id = ?", Command: block.CommandBatchOne}},
+			Params:  []analyzer.ResultParam{{Name: "id", GoType: "int64"}},
+			Columns: []analyzer.ResultColumn{{Name: "title", GoType: "string"}},
+		},
+		{
+			Query:   parser.Query{Block: block.Block{Name: "ListPosts", SQL: "SELECT id, title FROM posts WHERE user_id = ?", Command: block.CommandBatchMany}},
+			Params:  []analyzer.ResultParam{{Name: "userId", GoType: "int64"}},
+			Columns: []analyzer.ResultColumn{{Name: "id", GoType: "int64"}, {Name: "title", GoType: "string"}},
+		},
+	}
+
+	tests := []struct {
+		database config.Database
+		want     []string
+		notWant  []string
+	}{
+		{
+			database: config.DatabasePostgreSQL,
+			want:     []string{"conn.SendBatch(b.ctx, &pgxBatch)", "b.err = b.runStmt(withRows, f)"},
+		},
+		{
+			database: config.DatabaseSQLite,
+			want:     []string{"b.err = b.runStmt(withRows, f)", "preparer.PrepareContext(b.ctx, b.query)"},
+			notWant:  []string{"pgx"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.database), func(t *testing.T) {
+			b := New(Options{Package: "test", Database: tt.database, Prepared: PreparedOptions{Enabled: true}})
+			files, err := b.Build(context.Background(), &model.Catalog{}, analyses)
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+
+			rendered := renderFiles(t, files)
+
+			checks := map[string][]string{
+				"query_set_title.go": {
+					"func (q *Queries) SetTitle(ctx context.Context, arg []SetTitleParams) *SetTitleBatchResults",
+					"args[i] = []any{row.Title, row.Id}",
+					"return &SetTitleBatchResults{batch: newBatch(ctx, q.db, querySetTitle, args)}",
+					"func (b *SetTitleBatchResults) Exec(f func(int, error))",
+					"func (b *SetTitleBatchResults) Close() error",
+				},
+				"query_get_title.go": {
+					"func (q *Queries) GetTitle(ctx context.Context, arg []int64) *GetTitleBatchResults",
+					"func (b *GetTitleBatchResults) QueryRow(f func(int, string, error))",
+					"err = scanBatchRow(rows, &item)",
+				},
+				"query_list_posts.go": {
+					"func (b *ListPostsBatchResults) Query(f func(int, []ListPostsRow, error))",
+					"if err = rows.Scan(&item.Id, &item.Title); err == nil {",
+				},
+				"querier.gen.go": {
+					"SetTitle(ctx context.Context, arg []SetTitleParams) *SetTitleBatchResults",
+				},
+				"prepared.gen.go": {
+					"return p.queries.ListPosts(ctx, arg)",
+				},
+			}
+			for path, wants := range checks {
+				for _, want := range wants {
+					if !strings.Contains(rendered[path], want) {
+						t.Errorf("%s missing %q:\n%s", path, want, rendered[path])
+					}
+				}
+			}
+
+			batchFile, ok := rendered["batch.gen.go"]
+			if !ok {
+				t.Fatal("expected batch.gen.go")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(batchFile, want) {
+					t.Errorf("batch.gen.go missing %q:\n%s", want, batchFile)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(batchFile, notWant) {
+					t.Errorf("batch.gen.go unexpectedly contains %q", notWant)
+				}
+			}
+			if _, ok := rendered["pgx.gen.go"]; ok != (tt.database == config.DatabasePostgreSQL) {
+				t.Errorf("pgx.gen.go emitted = %v for %s", ok, tt.database)
+			}
+		})
+	}
+}
+
+func TestBuildIter(t *testing.T) {
+	analyses := []analyzer.Result{
+		{
+			Query:   parser.Query{Block: block.Block{Name: "StreamPosts", SQL: "SELECT id, title FROM posts WHERE user_id = ?", Command: block.CommandIter}},
+			Params:  []analyzer.ResultParam{{Name: "userId", GoType: "int64"}},
+			Columns: []analyzer.ResultColumn{{Name: "id", GoType: "int64"}, {Name: "title", GoType: "string"}},
+		},
+	}
+
+	for _, threadSafe := range []bool{false, true} {
+		b := New(Options{Package: "test", Prepared: PreparedOptions{Enabled: true, ThreadSafe: threadSafe, EmitMetrics: true}})
+		files, err := b.Build(context.Background(), &model.Catalog{}, analyses)
+		if err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+
+		rendered := renderFiles(t, files)
+
+		checks := map[string][]string{
+			"query_stream_posts.go": {
+				"func (q *Queries) StreamPosts(ctx context.Context, userId int64) iter.Seq2[StreamPostsRow, error] {",
+				"return func(yield func(StreamPostsRow, error) bool) {",
+				"rows, err := q.db.QueryContext(ctx, queryStreamPosts, userId)",
+				"defer rows.Close()",
+				"item, err := scanStreamPostsRow(rows)",
+				"if !yield(item, nil) {",
+			},
+			"querier.gen.go": {
+				"StreamPosts(ctx context.Context, userId int64) iter.Seq2[StreamPostsRow, error]\n",
+			},
+			"helpers.gen.go": {
+				"func scanStreamPostsRow(",
+			},
+			"prepared.gen.go": {
+				"\"iter\"",
+				"func (p *PreparedQueries) StreamPosts(ctx context.Context, userId int64) iter.Seq2[StreamPostsRow, error] {",
+				"rows, err := stmt.QueryContext(ctx, userId)",
+				"recorder.ObservePreparedQuery(ctx, \"StreamPosts\", time.Since(start), err)",
+				"if !yield(item, nil) {",
+			},
+		}
+		if threadSafe {
+			checks["prepared.gen.go"] = append(checks["prepared.gen.go"], "stmt, err := p.prepareStreamPosts(ctx)")
+		}
+		for path, wants := range checks {
+			for _, want := range wants {
+				if !strings.Contains(rendered[path], want) {
+					t.Errorf("threadSafe=%v: %s missing %q:\n%s", threadSafe, path, want, rendered[path])
+				}
+			}
+		}
+	}
+}
+
+func TestBuildOpt(t *testing.T) {
+	analyses := []analyzer.Result{
+		{
+			Query:   parser.Query{Block: block.Block{Name: "FindUser", SQL: "SELECT id, name FROM users WHERE email = ?", Command: block.CommandOpt}},
+			Params:  []analyzer.ResultParam{{Name: "email", GoType: "string"}},
+			Columns: []analyzer.ResultColumn{{Name: "id", GoType: "int64"}, {Name: "name", GoType: "string"}},
+		},
+		{
+			Query: parser.Query{Block: block.Block{
+				Name:    "FindName",
+				SQL:     "SELECT name FROM users WHERE id = ?",
+				Command: block.CommandOpt,
+				Cache:   &cache.Annotation{TTL: time.Minute},
+			}},
+			Params:  []analyzer.ResultParam{{Name: "id", GoType: "int64"}},
+			Columns: []analyzer.ResultColumn{{Name: "name", GoType: "string"}},
+		},
+	}
+
+	b := New(Options{Package: "test", Prepared: PreparedOptions{Enabled: true}})
+	files, err := b.Build(context.Background(), &model.Catalog{}, analyses)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	rendered := renderFiles(t, files)
+
+	checks := map[string][]string{
+		"query_find_user.go": {
+			"func (q *Queries) FindUser(ctx context.Context, email string) (*FindUserRow, error) {",
+			"var item FindUserRow",
+			"if errors.Is(err, sql.ErrNoRows) {\n\t\treturn nil, nil\n\t}",
+			"return &item, nil",
+		},
+		"query_find_name.go": {
+			"func (q *Queries) FindName(ctx context.Context, id int64) (*string, error) {",
+			"if result, ok := cached.(string); ok {\n\t\t\t\treturn &result, nil",
+			"q.cache.Set(ctx, cacheKey, item, 60)",
+			"return &item, nil",
+		},
+		"querier.gen.go": {
+			"FindUser(ctx context.Context, email string) (*FindUserRow, error)",
+		},
+		"prepared.gen.go": {
+			"func (p *PreparedQueries) FindUser(ctx context.Context, email string) (*FindUserRow, error) {",
+			"func (p *PreparedQueries) FindName(ctx context.Context, id int64) (*string, error) {",
+			"if !rows.Next() {\n\t\tif err := rows.Err(); err != nil {\n\t\t\treturn nil, err\n\t\t}\n\t\treturn nil, nil\n\t}",
+			"return &item, nil",
+		},
+	}
+	for path, wants := range checks {
+		for _, want := range wants {
+			if !strings.Contains(rendered[path], want) {
+				t.Errorf("%s missing %q:\n%s", path, want, rendered[path])
+			}
+		}
+	}
+	if strings.Contains(rendered["prepared.gen.go"], "sql.ErrNoRows") {
+		t.Errorf("prepared.gen.go should not report sql.ErrNoRows for :opt queries")
+	}
+}
+
+func TestBuildEmbed(t *testing.T) {
+	catalog := &model.Catalog{
+		Tables: map[string]*model.Table{
+			"users": {Name: "users", Columns: []*model.Column{
+				{Name: "id", Type: "INTEGER", NotNull: true},
+				{Name: "name", Type: "TEXT", NotNull: true},
+			}},
+			"posts": {Name: "posts", Columns: []*model.Column{
+				{Name: "id", Type: "INTEGER", NotNull: true},
+				{Name: "title", Type: "TEXT", NotNull: true},
+			}},
+		},
+	}
+	sql := "SELECT sqlc.embed(u), sqlc.embed(p) FROM users u LEFT JOIN posts p ON p.user_id = u.id WHERE u.id = ?"
+	users := &analyzer.Embed{Table: "users"}
+	posts := &analyzer.Embed{Table: "posts", Nullable: true}
+	analyses := []analyzer.Result{{
+		Query: parser.Query{
+			Block: block.Block{Name: "GetUserWithPost", SQL: sql, Command: block.CommandOne},
+			Columns: []parser.Column{
+				{Expr: "sqlc.embed(u)", Embed: "u", StartOffset: 7, EndOffset: 20},
+				{Expr: "sqlc.embed(p)", Embed: "p", StartOffset: 22, EndOffset: 35},
+			},
+		},
+		Params: []analyzer.ResultParam{{Name: "id", GoType: "int64"}},
+		Columns: []analyzer.ResultColumn{
+			{Name: "id", Table: "users", GoType: "int64", Embed: users},
+			{Name: "name", Table: "users", GoType: "string", Embed: users},
+			{Name: "id", Table: "posts", GoType: "int64", Nullable: true, Embed: posts},
+			{Name: "title", Table: "posts", GoType: "string", Nullable: true, Embed: posts},
+		},
+	}}
+
+	b := New(Options{Package: "test"})
+	files, err := b.Build(context.Background(), catalog, analyses)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	rendered := renderFiles(t, files)
+
+	checks := map[string][]string{
+		"helpers.gen.go": {
+			"Users Users\n",
+			"Posts *Posts\n",
+			"var postsId sql.Null[int64]",
+			"rows.Scan(&item.Users.Id, &item.Users.Name, &postsId, &postsTitle)",
+			"if postsId.Valid || postsTitle.Valid {\n\t\titem.Posts = &Posts{Id: postsId.V, Title: postsTitle.V}\n\t}",
+		},
+		"query_get_user_with_post.go": {
+			"SELECT u.*, p.* FROM users u LEFT JOIN posts p",
+			"err := row.Scan(&item.Users.Id, &item.Users.Name, &postsId, &postsTitle)",
+			"item.Posts = &Posts{Id: postsId.V, Title: postsTitle.V}",
+		},
+	}
+	for path, wants := range checks {
+		for _, want := range wants {
+			if !strings.Contains(rendered[path], want) {
+				t.Errorf("%s missing %q:\n%s", path, want, rendered[path])
+			}
+		}
+	}
+}
+
+func TestBuildModelResult(t *testing.T) {
+	catalog := &model.Catalog{
+		Tables: map[string]*model.Table{
+			"authors": {Name: "authors", Columns: []*model.Column{
+				{Name: "id", Type: "INTEGER", NotNull: true},
+				{Name: "name", Type: "TEXT", NotNull: true},
+				{Name: "bio", Type: "TEXT"},
+			}},
+			"posts": {Name: "posts", Columns: []*model.Column{
+				{Name: "id", Type: "INTEGER", NotNull: true},
+				{Name: "author_id", Type: "INTEGER", NotNull: true},
+				{Name: "title", Type: "TEXT", NotNull: true},
+			}},
+		},
+	}
+	authorColumns := []analyzer.ResultColumn{
+		{Name: "id", Table: "authors", GoType: "int64"},
+		{Name: "name", Table: "authors", GoType: "string"},
+		{Name: "bio", Table: "authors", GoType: "string", Nullable: true},
+	}
+	nameColumns := []analyzer.ResultColumn{
+		{Name: "id", Table: "authors", GoType: "int64"},
+		{Name: "name", Table: "authors", GoType: "string"},
+	}
+	countColumns := []analyzer.ResultColumn{
+		{Name: "id", Table: "authors", GoType: "int64"},
+		{Name: "posts", GoType: "int64"},
+	}
+	bioColumns := []analyzer.ResultColumn{
+		{Name: "id", Table: "authors", GoType: "int64"},
+		{Name: "bio", Table: "authors", GoType: "string", Nullable: true},
+	}
+	postAuthorColumns := []analyzer.ResultColumn{
+		{Name: "id", Table: "posts", GoType: "int64"},
+		{Name: "title", Table: "posts", GoType: "string"},
+		{Name: "name", Table: "authors", GoType: "string"},
+	}
+	query := func(name string, command block.Command, sql string, columns []analyzer.ResultColumn) analyzer.Result {
+		return analyzer.Result{
+			Query:   parser.Query{Block: block.Block{Name: name, SQL: sql, Command: command}},
+			Columns: columns,
+		}
+	}
+	analyses := []analyzer.Result{
+		query("GetAuthor", block.CommandOne, "SELECT * FROM authors LIMIT 1", authorColumns),
+		query("ListAuthors", block.CommandMany, "SELECT * FROM authors", authorColumns),
+		query("ListNames", block.CommandMany, "SELECT id, name FROM authors", nameColumns),
+		query("FindName", block.CommandOne, "SELECT id, name FROM authors WHERE id = 1", nameColumns),
+		query("ListPostCounts", block.CommandMany, "SELECT a.id, count(*) AS posts FROM authors a JOIN posts p ON p.author_id = a.id GROUP BY a.id", countColumns),
+		query("TopPostCounts", block.CommandMany, "SELECT a.id, count(*) AS posts FROM authors a JOIN posts p ON p.author_id = a.id GROUP BY a.id LIMIT 3", countColumns),
+		query("ListBios", block.CommandMany, "SELECT id, bio FROM authors", bioColumns),
+		query("ListPostAuthors", block.CommandMany, "SELECT posts.id, posts.title, authors.name FROM posts JOIN authors ON authors.id = posts.author_id", postAuthorColumns),
+		query("FindPostAuthor", block.CommandOne, "SELECT posts.id, posts.title, authors.name FROM posts JOIN authors ON authors.id = posts.author_id LIMIT 1", postAuthorColumns),
+	}
+
+	// Shared Row names must not depend on the order of the queries.
+	for _, order := range []string{"forward", "reverse"} {
+		t.Run(order, func(t *testing.T) {
+			input := slices.Clone(analyses)
+			if order == "reverse" {
+				slices.Reverse(input)
+			}
+			files, err := New(Options{Package: "test"}).Build(context.Background(), catalog, input)
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+
+			rendered := renderFiles(t, files)
+
+			checks := map[string][]string{
+				"helpers.gen.go": {
+					"func scanAuthors(rows *sql.Rows) (Authors, error)",
+					"type AuthorsRow struct",
+					"type PostsAuthorsRow struct",
+					"type ListPostCountsRow struct",
+					"type ListBiosRow struct",
+				},
+				"query_get_author.go":        {"GetAuthor(ctx context.Context) (Authors, error)", "var item Authors"},
+				"query_list_authors.go":      {"ListAuthors(ctx context.Context) ([]Authors, error)", "scanAuthors(rows)"},
+				"query_list_names.go":        {"ListNames(ctx context.Context) ([]AuthorsRow, error)", "scanAuthorsRow(rows)"},
+				"query_find_name.go":         {"FindName(ctx context.Context) (AuthorsRow, error)"},
+				"query_list_post_authors.go": {"ListPostAuthors(ctx context.Context) ([]PostsAuthorsRow, error)"},
+				"query_find_post_author.go":  {"FindPostAuthor(ctx context.Context) (PostsAuthorsRow, error)"},
+				// Results with expressions are shared under the first query's name.
+				"query_top_post_counts.go":  {"TopPostCounts(ctx context.Context) ([]ListPostCountsRow, error)"},
+				"query_list_post_counts.go": {"ListPostCounts(ctx context.Context) ([]ListPostCountsRow, error)"},
+			}
+			for path, wants := range checks {
+				for _, want := range wants {
+					if !strings.Contains(rendered[path], want) {
+						t.Errorf("%s missing %q:\n%s", path, want, rendered[path])
+					}
+				}
+			}
+			for _, unwanted := range []string{"GetAuthorRow", "ListAuthorsRow", "ListNamesRow", "FindNameRow", "ListPostAuthorsRow", "FindPostAuthorRow", "TopPostCountsRow"} {
+				if strings.Contains(rendered["helpers.gen.go"], unwanted) {
+					t.Errorf("helpers.gen.go should not declare %s:\n%s", unwanted, rendered["helpers.gen.go"])
+				}
+			}
+		})
+	}
+}
+
+func TestBuildNullStyles(t *testing.T) {
+	catalog := &model.Catalog{
+		Tables: map[string]*model.Table{
+			"authors": {Name: "authors", Columns: []*model.Column{
+				{Name: "id", Type: "INTEGER", NotNull: true},
+				{Name: "bio", Type: "TEXT"},
+				{Name: "born", Type: "INTEGER"},
+			}},
+		},
+	}
+	analyses := []analyzer.Result{{
+		Query: parser.Query{Block: block.Block{Name: "GetAuthor", SQL: "SELECT * FROM authors WHERE bio = ?", Command: block.CommandOne}},
+		Columns: []analyzer.ResultColumn{
+			{Name: "id", Table: "authors", GoType: "int64"},
+			{Name: "bio", Table: "authors", GoType: "string", Nullable: true},
+			{Name: "born", Table: "authors", GoType: "int64", Nullable: true},
+		},
+		Params: []analyzer.ResultParam{{Name: "bio", GoType: "string", Nullable: true}},
+	}}
+	option := config.GoTypeDetails{Import: "github.com/acme/opt", Package: "opt", Type: "Option"}
+
+	tests := []struct {
+		name   string
+		opts   Options
+		checks map[string][]string
+	}{
+		{
+			name: "sql null",
+			opts: Options{Package: "test"},
+			checks: map[string][]string{
+				"models.gen.go":       {"Bio  sql.NullString", "Born sql.NullInt64"},
+				"query_get_author.go": {"GetAuthor(ctx context.Context, bio sql.NullString) (Authors, error)"},
+			},
+		},
+		{
+			name: "generic",
+			opts: Options{Package: "test", NullStyle: config.NullStyleGeneric},
+			checks: map[string][]string{
+				"models.gen.go":       {"Bio  sql.Null[string]", "Born sql.Null[int64]"},
+				"query_get_author.go": {"bio sql.Null[string]"},
+			},
+		},
+		{
+			name: "option",
+			opts: Options{Package: "test", NullStyle: config.NullStyleOption, NullOption: option},
+			checks: map[string][]string{
+				"models.gen.go":       {`"github.com/acme/opt"`, "Bio  opt.Option[string]"},
+				"query_get_author.go": {`"github.com/acme/opt"`, "bio opt.Option[string]"},
+			},
+		},
+		{
+			name: "column override",
+			opts: Options{
+				Package:    "test",
+				NullStyle:  config.NullStylePointer,
+				NullOption: option,
+				ColumnOverrides: []config.ColumnOverride{
+					{Column: "authors.bio", NullStyle: config.NullStyleOption},
+				},
+			},
+			checks: map[string][]string{
+				"models.gen.go": {`"github.com/acme/opt"`, "Bio  opt.Option[string]", "Born *int64"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := New(tt.opts).Build(context.Background(), catalog, analyses)
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			rendered := renderFiles(t, files)
+			for path, wants := range tt.checks {
+				for _, want := range wants {
+					if !strings.Contains(rendered[path], want) {
+						t.Errorf("%s missing %q:\n%s", path, want, rendered[path])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestBuildSQLiteTypes(t *testing.T) {
+	catalog := &model.Catalog{
+		Tables: map[string]*model.Table{
+			"events": {Name: "events", Columns: []*model.Column{
+				{Name: "id", Type: "INTEGER", NotNull: true},
+				{Name: "done", Type: "BOOLEAN", NotNull: true},
+				{Name: "at", Type: "DATETIME", NotNull: true},
+				{Name: "payload", Type: "JSON"},
+			}},
+		},
+	}
+	timeParam := analyzer.ResultParam{Name: "at", GoType: "time.Time", Storage: config.StorageUnix}
+	analyses := []analyzer.Result{
+		{
+			Query: parser.Query{Block: block.Block{Name: "ListEvents", SQL: "SELECT id, done, at, payload FROM events WHERE at = ?", Command: block.CommandMany}},
+			Columns: []analyzer.ResultColumn{
+				{Name: "id", Table: "events", GoType: "int64"},
+				{Name: "done", Table: "events", GoType: "bool"},
+				{Name: "at", Table: "events", GoType: "time.Time", Storage: config.StorageUnix},
+				{Name: "payload", Table: "events", GoType: "json.RawMessage", Nullable: true, Storage: config.StorageJSON},
+			},
+			Params: []analyzer.ResultParam{timeParam},
+		},
+		{
+			Query:   parser.Query{Block: block.Block{Name: "LatestEvent", SQL: "SELECT max(at) FROM events", Command: block.CommandOne}},
+			Columns: []analyzer.ResultColumn{{Name: "max_at", GoType: "time.Time", Storage: config.StorageUnix}},
+		},
+		{
+			Query: parser.Query{Block: block.Block{Name: "CreateEvent", SQL: "INSERT INTO events (at, payload) VALUES (?, ?)", Command: block.CommandExec}},
+			Params: []analyzer.ResultParam{
+				timeParam,
+				{Name: "payload", GoType: "json.RawMessage", Nullable: true, Storage: config.StorageJSON},
+			},
+		},
+		{
+			Query:   parser.Query{Block: block.Block{Name: "EventsAt", SQL: "SELECT id FROM events WHERE at IN (?, ?)", Command: block.CommandMany}},
+			Columns: []analyzer.ResultColumn{{Name: "id", Table: "events", GoType: "int64"}},
+			Params:  []analyzer.ResultParam{{Name: "ats", GoType: "time.Time", IsVariadic: true, VariadicCount: 2, Storage: config.StorageUnix}},
+		},
+	}
+
+	files, err := New(Options{
+		Package:     "test",
+		SQLiteTypes: config.SQLiteTypes{Enabled: true, TimeEncoding: config.StorageUnix},
+	}).Build(context.Background(), catalog, analyses)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	rendered := renderFiles(t, files)
+	checks := map[string][]string{
+		"models.gen.go": {"Done    bool", "At      time.Time", "Payload *json.RawMessage"},
+		"helpers.gen.go": {
+			"rows.Scan(&item.Id, &item.Done, scanSQLiteTime(&item.At, sqliteUnix), scanSQLiteJSON(&item.Payload))",
+		},
+		"query_list_events.go":  {"queryListEvents, bindSQLiteTime(at, sqliteUnix))"},
+		"query_latest_event.go": {"row.Scan(scanSQLiteTime(&item, sqliteUnix))"},
+		"query_create_event.go": {"bindSQLiteTime(arg.At, sqliteUnix), bindSQLiteJSON(arg.Payload))"},
+		"query_events_at.go":    {"bindSQLiteTime(ats[i], sqliteUnix)"},
+		"sqlite_types.gen.go":   {"func scanSQLiteTime(", "func bindSQLiteTime(", "func scanSQLiteJSON(", "func bindSQLiteJSON("},
+	}
+	for path, wants := range checks {
+		for _, want := range wants {
+			if !strings.Contains(rendered[path], want) {
+				t.Errorf("%s missing %q:\n%s", path, want, rendered[path])
+			}
+		}
+	}
+
+	plain := []analyzer.Result{{
+		Query:   parser.Query{Block: block.Block{Name: "CountEvents", SQL: "SELECT count(*) FROM events", Command: block.CommandOne}},
+		Columns: []analyzer.ResultColumn{{Name: "count", GoType: "int64"}},
+	}}
+	files, err = New(Options{Package: "test"}).Build(context.Background(), catalog, plain)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	for _, f := range files {
+		if f.Path == "sqlite_types.gen.go" {
+			t.Errorf("sqlite_types.gen.go emitted without [sqlite_types] values")
+		}
+	}
+}
+
+func TestBuildDriverHelpers(t *testing.T) {
+	analyses := []analyzer.Result{{
+		Query:   parser.Query{Block: block.Block{Name: "CountEvents", SQL: "SELECT count(*) FROM events", Command: block.CommandOne}},
+		Columns: []analyzer.ResultColumn{{Name: "count", GoType: "int64"}},
+	}}
+
+	tests := []struct {
+		name  string
+		opts  Options
+		wants []string // empty when driver.gen.go is not emitted
+	}{
+		{
+			name: "modernc",
+			opts: Options{Package: "test", Database: config.DatabaseSQLite, DriverHelpers: true},
+			wants: []string{
+				`"modernc.org/sqlite"`,
+				`const DriverName = "sqlite"`,
+				`dsn += sep + "_time_format=sqlite"`,
+				"var e *sqlite.Error",
+				"return e.Code(), true",
+				"func IsUniqueViolation(err error) bool",
+			},
+		},
+		{
+			name: "mattn",
+			opts: Options{Package: "test", Database: config.DatabaseSQLite, SQLiteDriver: config.DriverMattN, DriverHelpers: true},
+			wants: []string{
+				`sqlite3 "github.com/mattn/go-sqlite3"`,
+				`const DriverName = "sqlite3"`,
+				"var e sqlite3.Error",
+				"return int(e.ExtendedCode), true",
+				"func IsForeignKeyViolation(err error) bool",
+			},
+		},
+		{
+			name: "disabled",
+			opts: Options{Package: "test", Database: config.DatabaseSQLite},
+		},
+		{
+			name: "postgresql",
+			opts: Options{Package: "test", Database: config.DatabasePostgreSQL, DriverHelpers: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := New(tt.opts).Build(context.Background(), &model.Catalog{}, analyses)
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			var driverFile string
+			for _, f := range files {
+				if f.Path == "driver.gen.go" {
+					driverFile = string(f.Raw)
+				}
+			}
+			if len(tt.wants) == 0 {
+				if driverFile != "" {
+					t.Fatalf("unexpected driver.gen.go:\n%s", driverFile)
+				}
+				return
+			}
+			for _, want := range tt.wants {
+				if !strings.Contains(driverFile, want) {
+					t.Errorf("driver.gen.go missing %q:\n%s", want, driverFile)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildConstraintErrors(t *testing.T) {
+	catalog := &model.Catalog{Tables: map[string]*model.Table{
+		"users": {
+			Name:       "users",
+			PrimaryKey: &model.PrimaryKey{Columns: []string{"id"}},
+			UniqueKeys: []*model.UniqueKey{
+				{Columns: []string{"email"}},
+				{Name: "uq_handle", Columns: []string{"org", "handle"}},
+			},
+		},
+		"posts": {
+			Name:        "posts",
+			ForeignKeys: []*model.ForeignKey{{Columns: []string{"author_id"}, Ref: model.ForeignKeyRef{Table: "users"}}},
+			Indexes:     []*model.Index{{Name: "posts_slug_idx", Unique: true, Columns: []string{"slug"}}},
+		},
+	}}
+	analyses := []analyzer.Result{
+		{
+			Query:  parser.Query{Block: block.Block{Name: "CreateUser", SQL: "INSERT INTO users (email) VALUES (?)", Command: block.CommandExec}},
+			Params: []analyzer.ResultParam{{Name: "email", GoType: "string"}},
+		},
+		{
+			Query:    parser.Query{Block: block.Block{Name: "CreateUsers", SQL: "INSERT INTO users (email) VALUES (?)", Command: block.CommandCopyFrom}},
+			Params:   []analyzer.ResultParam{{Name: "email", GoType: "string"}},
+			CopyFrom: &analyzer.CopyFromTarget{Table: "users", Columns: []string{"email"}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		opts     Options
+		wants    []string // empty when errors.gen.go is not emitted
+		wrapped  bool
+		unwanted []string
+	}{
+		{
+			name: "sqlite",
+			opts: Options{Package: "test", Database: config.DatabaseSQLite, ConstraintErrors: config.ConstraintErrors{Enabled: true}},
+			wants: []string{
+				"var e *sqlite.Error",
+				`"users.email":             ErrUsersEmailUnique,`,
+				`"users.org, users.handle": ErrUsersOrgHandleUnique,`,
+				`"users.id":                ErrUsersPrimaryKey,`,
+				`"posts.slug":              ErrPostsSlugUnique,`,
+				`strings.Trim(detail[idx+2:], "0123456789")`,
+			},
+			unwanted: []string{"ErrPostsAuthorFK"},
+		},
+		{
+			name: "mattn",
+			opts: Options{Package: "test", Database: config.DatabaseSQLite, SQLiteDriver: config.DriverMattN, ConstraintErrors: config.ConstraintErrors{Enabled: true, Wrap: true}},
+			wants: []string{
+				"var e sqlite3.Error",
+				"switch int(e.ExtendedCode) {",
+			},
+			wrapped: true,
+		},
+		{
+			name: "postgresql",
+			opts: Options{Package: "test", Database: config.DatabasePostgreSQL, ConstraintErrors: config.ConstraintErrors{Enabled: true}},
+			wants: []string{
+				"var e *pgconn.PgError",
+				`"posts_author_id_fkey": ErrPostsAuthorFK,`,
+				`"users_email_key":      ErrUsersEmailUnique,`,
+				`"uq_handle":            ErrUsersOrgHandleUnique,`,
+				`"users_pkey":           ErrUsersPrimaryKey,`,
+			},
+		},
+		{
+			name: "mysql",
+			opts: Options{Package: "test", Database: config.DatabaseMySQL, ConstraintErrors: config.ConstraintErrors{Enabled: true, Wrap: true}},
+			wants: []string{
+				"var e *mysql.MySQLError",
+				`"posts_ibfk_1":         ErrPostsAuthorFK,`,
+				`"users.email":          ErrUsersEmailUnique,`,
+				`"users.PRIMARY":        ErrUsersPrimaryKey,`,
+			},
+			wrapped: true,
+		},
+		{
+			name: "disabled",
+			opts: Options{Package: "test", Database: config.DatabaseSQLite},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := New(tt.opts).Build(context.Background(), catalog, analyses)
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			rendered := renderFiles(t, files)
+			errorsFile := rendered["errors.gen.go"]
+			if len(tt.wants) == 0 {
+				if errorsFile != "" {
+					t.Fatalf("unexpected errors.gen.go:\n%s", errorsFile)
+				}
+				return
+			}
+			for _, want := range tt.wants {
+				if !strings.Contains(errorsFile, want) {
+					t.Errorf("errors.gen.go missing %q:\n%s", want, errorsFile)
+				}
+			}
+			for _, unwanted := range tt.unwanted {
+				if strings.Contains(errorsFile, unwanted) {
+					t.Errorf("errors.gen.go contains %q:\n%s", unwanted, errorsFile)
+				}
+			}
+			for _, path := range []string{"query_create_user.go", "query_create_users.go"} {
+				queryFile, ok := rendered[path]
+				if !ok {
+					t.Fatalf("%s not generated", path)
+				}
+				if got := strings.Contains(queryFile, "ClassifyError(err)"); got != tt.wrapped {
+					t.Errorf("%s wraps errors = %v, want %v:\n%s", path, got, tt.wrapped, queryFile)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildNamedTypes(t *testing.T) {
+	query := func(name string, command block.Command, resultType, paramsType string, params []analyzer.ResultParam, columns []analyzer.ResultColumn) analyzer.Result {
+		return analyzer.Result{
+			Query: parser.Query{Block: block.Block{
+				Name: name, SQL: "SELECT 1", Command: command, ResultType: resultType, ParamsType: paramsType,
+			}},
+			Params:  params,
+			Columns: columns,
+		}
+	}
+	userColumns := []analyzer.ResultColumn{
+		{Name: "id", GoType: "int64"},
+		{Name: "name", GoType: "string"},
+	}
+	inputParams := []analyzer.ResultParam{{Name: "name", GoType: "string"}}
+
+	b := New(Options{Package: "test"})
+	queries, err := b.buildQueries([]analyzer.Result{
+		query("GetUser", block.CommandOne, "UserDTO", "", []analyzer.ResultParam{{Name: "id", GoType: "int64"}}, userColumns),
+		query("ListUsers", block.CommandMany, "UserDTO", "", nil, userColumns),
+		query("CountUsers", block.CommandOne, "github.com/acme/api/dto.Count", "", nil, []analyzer.ResultColumn{{Name: "n", GoType: "int64"}}),
+		query("CreateUser", block.CommandExec, "", "UserInput", inputParams, nil),
+		query("RenameUser", block.CommandExec, "", "UserInput", inputParams, nil),
+	})
+	if err != nil {
+		t.Fatalf("buildQueries() error = %v", err)
+	}
+	byName := make(map[string]queryInfo)
+	for _, q := range queries {
+		byName[q.methodName] = q
+	}
+
+	if byName["GetUser"].helper != byName["ListUsers"].helper {
+		t.Error("queries naming the same @returns type should share one helper")
+	}
+	if got := byName["ListUsers"].returnType; got != "[]UserDTO" {
+		t.Errorf("ListUsers returnType = %q, want []UserDTO", got)
+	}
+	count := byName["CountUsers"]
+	if count.returnType != "dto.Count" || count.helper == nil || count.helper.emitsStruct() {
+		t.Errorf("CountUsers should return an undeclared dto.Count struct, got %q", count.returnType)
+	}
+	if !slices.Equal(count.typeImports(), []string{"github.com/acme/api/dto"}) {
+		t.Errorf("CountUsers typeImports() = %v", count.typeImports())
+	}
+	create, rename := byName["CreateUser"], byName["RenameUser"]
+	if create.paramStruct == nil || create.paramStruct != rename.paramStruct || create.paramStruct.typeName != "UserInput" {
+		t.Fatalf("queries naming the same @params type should share one struct, got %+v and %+v", create.paramStruct, rename.paramStruct)
+	}
+	if create.paramStruct.owner != "CreateUser" || !slices.Equal(rename.args, []string{"arg.Name"}) {
+		t.Errorf("unexpected owner %q or args %v", create.paramStruct.owner, rename.args)
+	}
+
+	errCases := []struct {
+		name     string
+		analyses []analyzer.Result
+		want     string
+	}{
+		{
+			name: "result fields differ",
+			analyses: []analyzer.Result{
+				query("GetUser", block.CommandOne, "UserDTO", "", nil, userColumns),
+				query("ListUsers", block.CommandMany, "UserDTO", "", nil, userColumns[:1]),
+			},
+			want: "@returns UserDTO fields differ from those of query GetUser",
+		},
+		{
+			name: "params fields differ",
+			analyses: []analyzer.Result{
+				query("CreateUser", block.CommandExec, "", "UserInput", inputParams, nil),
+				query("DeleteUser", block.CommandExec, "", "UserInput", []analyzer.ResultParam{{Name: "id", GoType: "int64"}}, nil),
+			},
+			want: "@params UserInput fields differ from those of query CreateUser",
+		},
+		{
+			name:     "returns without rows",
+			analyses: []analyzer.Result{query("DeleteUser", block.CommandExec, "UserDTO", "", inputParams, nil)},
+			want:     "@returns requires a command that returns rows",
+		},
+		{
+			name:     "invalid type name",
+			analyses: []analyzer.Result{query("GetUser", block.CommandOne, "userDTO", "", nil, userColumns)},
+			want:     `"userDTO" is not an exported Go type name`,
+		},
+		{
+			name:     "params named like a table model",
+			analyses: []analyzer.Result{query("CreateUser", block.CommandExec, "", "Users", inputParams, nil)},
+			want:     "@params Users is already declared as the users table model",
+		},
+		{
+			name: "returns named like generated params",
+			analyses: []analyzer.Result{
+				query("CreateUser", block.CommandExec, "", "", append(inputParams, analyzer.ResultParam{Name: "email", GoType: "string"}), nil),
+				query("GetUser", block.CommandOne, "CreateUserParams", "", nil, userColumns),
+			},
+			want: "@returns CreateUserParams is already declared as the params of query CreateUser",
+		},
+		{
+			name: "params named like a generated row",
+			analyses: []analyzer.Result{
+				query("ListUsers", block.CommandMany, "", "", nil, userColumns),
+				query("CreateUser", block.CommandExec, "", "ListUsersRow", inputParams, nil),
+			},
+			want: "@params ListUsersRow is already declared as the result of query ListUsers",
+		},
+		{
+			name:     "returns named like an enum",
+			analyses: []analyzer.Result{query("GetUser", block.CommandOne, "UserStatus", "", nil, userColumns)},
+			want:     "@returns UserStatus is already declared as the user_status enum",
+		},
+		{
+			name:     "params named like a domain",
+			analyses: []analyzer.Result{query("CreateUser", block.CommandExec, "", "Email", inputParams, nil)},
+			want:     "@params Email is already declared as the email domain",
+		},
+	}
+	for _, tc := range errCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := New(Options{Package: "test"})
+			b.models = map[string]*tableModel{"users": {tableName: "users", typeName: "Users"}}
+			b.enums = map[*model.Enum]*enumSpec{{Name: "user_status"}: {name: "user_status", typeName: "UserStatus"}}
+			b.domains = map[*model.Domain]*domainSpec{{Name: "email"}: {name: "email", typeName: "Email"}}
+			_, err := b.buildQueries(tc.analyses)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("buildQueries() error = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestBuildEnums(t *testing.T) {
+	status := &model.Enum{Name: "ticket_status", Values: []string{"open", "in progress", "closed"}}
+	priority := &model.Enum{Name: "tickets_priority", Values: []string{"low", "high"}}
+	catalog := &model.Catalog{
+		Tables: map[string]*model.Table{
+			"tickets": {Name: "tickets", Columns: []*model.Column{
+				{Name: "id", Type: "INTEGER", NotNull: true},
+				{Name: "status", Type: "ticket_status", NotNull: true},
+				{Name: "priority", Type: "TEXT", Enum: "tickets_priority"},
+			}},
+		},
+		Enums: map[string]*model.Enum{
+			"ticket_status":    status,
+			"tickets_priority": priority,
+		},
+	}
+	parse := func(name string, command block.Command, sql string) parser.Query {
+		q, diags := parser.Parse(block.Block{Name: name, SQL: sql, Command: command})
+		if len(diags) != 0 {
+			t.Fatalf("unexpected parser diagnostics: %+v", diags)
+		}
+		return q
+	}
+	analyses := []analyzer.Result{
+		{
+			Query: parse("ListTickets", block.CommandMany, "SELECT id, status, priority FROM tickets WHERE status IN (sqlc.slice('statuses'))"),
+			Columns: []analyzer.ResultColumn{
+				{Name: "id", Table: "tickets", GoType: "int64"},
+				{Name: "status", Table: "tickets", GoType: "string", Enum: status},
+				{Name: "priority", Table: "tickets", GoType: "string", Nullable: true, Enum: priority},
+			},
+			Params: []analyzer.ResultParam{
+				{Name: "statuses", GoType: "[]string", IsVariadic: true, Enum: status},
+			},
+		},
+		{
+			Query:   parse("GetStatus", block.CommandOne, "SELECT status FROM tickets WHERE priority = ?"),
+			Columns: []analyzer.ResultColumn{{Name: "status", Table: "tickets", GoType: "string", Enum: status}},
+			Params:  []analyzer.ResultParam{{Name: "priority", GoType: "string", Nullable: true, Enum: priority}},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		opts   Options
+		checks map[string][]string
+	}{
+		{
+			name: "null wrappers",
+			opts: Options{Package: "test"},
+			checks: map[string][]string{
+				"enums.gen.go": {
+					"type TicketStatus string",
+					`TicketStatusInProgress TicketStatus = "in progress"`,
+					"func (TicketStatus) Values() []TicketStatus",
+					"func (e TicketStatus) Valid() bool",
+					"case TicketStatusOpen, TicketStatusInProgress, TicketStatusClosed:",
+					"func (e *TicketStatus) Scan(src any) error",
+					"func (e TicketStatus) Value() (driver.Value, error)",
+					"type NullTicketsPriority struct",
+				},
+				"models.gen.go":         {"Status   TicketStatus", "Priority NullTicketsPriority"},
+				"query_list_tickets.go": {"ListTickets(ctx context.Context, statuses []TicketStatus) ([]Tickets, error)"},
+				"query_get_status.go":   {"GetStatus(ctx context.Context, priority NullTicketsPriority) (TicketStatus, error)"},
+			},
+		},
+		{
+			name: "pointers for null",
+			opts: Options{Package: "test", NullStyle: config.NullStylePointer},
+			checks: map[string][]string{
+				"models.gen.go":       {"Priority *TicketsPriority"},
+				"query_get_status.go": {"priority *TicketsPriority"},
+			},
+		},
+		{
+			name: "generic null",
+			opts: Options{Package: "test", NullStyle: config.NullStyleGeneric},
+			checks: map[string][]string{
+				"models.gen.go":       {"Priority sql.Null[TicketsPriority]"},
+				"query_get_status.go": {"priority sql.Null[TicketsPriority]"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := New(tt.opts).Build(context.Background(), catalog, analyses)
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			rendered := renderFiles(t, files)
+			for path, wants := range tt.checks {
+				for _, want := range wants {
+					if !strings.Contains(rendered[path], want) {
+						t.Errorf("%s missing %q:\n%s", path, want, rendered[path])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestBuildDomains(t *testing.T) {
+	email := &model.Domain{Name: "email_address", BaseType: "TEXT", Constraints: []*model.DomainConstraint{
+		{Type: "check", Expr: `VALUE ~* '^[^@\s]+@[^@\s]+$'`},
+	}}
+	money := &model.Domain{Name: "positive_money", BaseType: "NUMERIC(12,2)", Constraints: []*model.DomainConstraint{
+		{Type: "not_null"},
+		{Type: "check", Expr: "VALUE > 0 AND VALUE <= 1000000"},
+	}}
+	code := &model.Domain{Name: "short_code", BaseType: "VARCHAR(8)", Constraints: []*model.DomainConstraint{
+		{Type: "check", Expr: "length(VALUE) BETWEEN 2 AND 8"},
+		{Type: "check", Expr: "VALUE IN ('ab', 'cd') AND upper(VALUE) = VALUE"},
+	}}
+	stamp := &model.Domain{Name: "event_time", BaseType: "TIMESTAMPTZ"}
+	catalog := &model.Catalog{
+		Tables: map[string]*model.Table{
+			"accounts": {Name: "accounts", Columns: []*model.Column{
+				{Name: "id", Type: "BIGINT", NotNull: true},
+				{Name: "email", Type: "email_address"},
+				{Name: "balance", Type: "positive_money"},
+				{Name: "code", Type: "short_code", NotNull: true},
+				{Name: "created_at", Type: "event_time", NotNull: true},
+			}},
+		},
+		Domains: map[string]*model.Domain{
+			"email_address":  email,
+			"positive_money": money,
+			"short_code":     code,
+			"event_time":     stamp,
+		},
+	}
+	q, diags := parser.Parse(block.Block{Name: "FindAccounts", Command: block.CommandMany, SQL: "SELECT * FROM accounts WHERE email = $1"})
+	if len(diags) != 0 {
+		t.Fatalf("unexpected parser diagnostics: %+v", diags)
+	}
+	analyses := []analyzer.Result{{
+		Query: q,
+		Columns: []analyzer.ResultColumn{
+			{Name: "id", Table: "accounts", GoType: "int64"},
+			{Name: "email", Table: "accounts", GoType: "string", Nullable: true, Domain: email},
+			{Name: "balance", Table: "accounts", GoType: "float64", Domain: money},
+			{Name: "code", Table: "accounts", GoType: "string", Domain: code},
+			{Name: "created_at", Table: "accounts", GoType: "string", Domain: stamp},
+		},
+		Params: []analyzer.ResultParam{{Name: "email", GoType: "string", Nullable: true, Domain: email}},
+	}}
+
+	files, err := New(Options{Package: "test", TypeResolver: NewTypeResolverWithDatabase(nil, config.DatabasePostgreSQL)}).Build(context.Background(), catalog, analyses)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	rendered := renderFiles(t, files)
+	checks := map[string][]string{
+		"domains.gen.go": {
+			"type EmailAddress string",
+			`var emailAddressPattern = regexp.MustCompile("(?i)^[^@\\s]+@[^@\\s]+$")`,
+			"if !emailAddressPattern.MatchString(string(v)) {",
+			"type PositiveMoney struct {\n\tdecimal.Decimal\n}",
+			`if v.Decimal.Cmp(decimal.RequireFromString("0")) <= 0 {`,
+			`return fmt.Errorf("positive_money value %v violates CHECK (VALUE <= 1000000)", v)`,
+			"return v.Decimal.Value()",
+			"if utf8.RuneCountInString(string(v)) < 2 || utf8.RuneCountInString(string(v)) > 8 {",
+			`if v != "ab" && v != "cd" {`,
+			"//   - upper(VALUE) = VALUE",
+			"func (v ShortCode) Value() (driver.Value, error)",
+		},
+		"models.gen.go":          {"Email     *EmailAddress", "Balance   PositiveMoney", "Code      ShortCode", "CreatedAt time.Time"},
+		"query_find_accounts.go": {"FindAccounts(ctx context.Context, email *EmailAddress) ([]Accounts, error)"},
+	}
+	for path, wants := range checks {
+		for _, want := range wants {
+			if !strings.Contains(rendered[path], want) {
+				t.Errorf("%s missing %q:\n%s", path, want, rendered[path])
+			}
+		}
+	}
+	if strings.Contains(rendered["domains.gen.go"], "EventTime") {
+		t.Errorf("domains.gen.go declares a type for a domain over an unsupported base type:\n%s", rendered["domains.gen.go"])
+	}
+}
+
+func TestBuildPgx(t *testing.T) {
+	analyses := []analyzer.Result{
+		{
+			Query:   parser.Query{Block: block.Block{Name: "FindUser", SQL: "SELECT id, name FROM users WHERE email = $1", Command: block.CommandOpt}},
+			Params:  []analyzer.ResultParam{{Name: "email", GoType: "string"}},
+			Columns: []analyzer.ResultColumn{{Name: "id", GoType: "int64"}, {Name: "name", GoType: "string"}},
+		},
+		{
+			Query:   parser.Query{Block: block.Block{Name: "ListUsers", SQL: "SELECT id, name FROM users", Command: block.CommandMany}},
+			Columns: []analyzer.ResultColumn{{Name: "id", GoType: "int64"}, {Name: "name", GoType: "string"}},
+		},
+		{
+			Query: parser.Query{Block: block.Block{Name: "PurgeUsers", SQL: "DELETE FROM users", Command: block.CommandExecRows}},
+		},
+	}
+	opts := Options{
+		Package:      "test",
+		Database:     config.DatabasePostgreSQL,
+		SQLPackage:   config.SQLPackagePgxV5,
+		TypeResolver: NewTypeResolverWithDatabase(nil, config.DatabasePostgreSQL),
+	}
+
+	files, err := New(opts).Build(context.Background(), &model.Catalog{}, analyses)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	rendered := renderFiles(t, files)
+	checks := map[string][]string{
+		"querier.gen.go": {
+			"Exec(ctx context.Context, query string, args ...any) (pgconn.CommandTag, error)",
+			"Query(ctx context.Context, query string, args ...any) (pgx.Rows, error)",
+			"QueryRow(ctx context.Context, query string, args ...any) pgx.Row",
+		},
+		"helpers.gen.go": {"func scanFindUserRow(rows pgx.Rows) (FindUserRow, error) {"},
+		"query_find_user.go": {
+			"row := q.db.QueryRow(ctx, queryFindUser, email)",
+			"if errors.Is(err, pgx.ErrNoRows) {\n\t\treturn nil, nil\n\t}",
+		},
+		"query_list_users.go":  {"rows, err := q.db.Query(ctx, queryListUsers)"},
+		"query_purge_users.go": {"res, err := q.db.Exec(ctx, queryPurgeUsers)", "return res.RowsAffected(), nil"},
+	}
+	for path, wants := range checks {
+		for _, want := range wants {
+			if !strings.Contains(rendered[path], want) {
+				t.Errorf("%s missing %q:\n%s", path, want, rendered[path])
+			}
+		}
+	}
+	if _, ok := rendered["pgx.gen.go"]; ok {
+		t.Errorf("pgx.gen.go should not be emitted with sql_package %q", config.SQLPackagePgxV5)
+	}
+	if strings.Contains(rendered["query_find_user.go"], "row.Err()") {
+		t.Errorf("query_find_user.go should not check row.Err() with pgx:\n%s", rendered["query_find_user.go"])
+	}
+
+	lastID := []analyzer.Result{{
+		Query:  parser.Query{Block: block.Block{Name: "CreateUser", SQL: "INSERT INTO users (name) VALUES ($1)", Command: block.CommandExecLastID}},
+		Params: []analyzer.ResultParam{{Name: "name", GoType: "string"}},
+	}}
+	if _, err := New(opts).Build(context.Background(), &model.Catalog{}, lastID); err == nil || !strings.Contains(err.Error(), ":execlastid is not supported") {
+		t.Errorf("Build() error = %v, want :execlastid rejection", err)
+	}
+}
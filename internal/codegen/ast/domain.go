@@ -0,0 +1,640 @@
+package ast
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/imports"
+
+	"github.com/electwix/db-catalyst/internal/config"
+	"github.com/electwix/db-catalyst/internal/query/analyzer"
+	"github.com/electwix/db-catalyst/internal/schema/model"
+	"github.com/electwix/db-catalyst/internal/schema/tokenizer"
+)
+
+// domainKind classifies the Go base type of a domain, which decides how the
+// domain type is declared and which CHECK conditions Validate can enforce.
+type domainKind int
+
+const (
+	domainUnsupported domainKind = iota
+	domainString
+	domainInt
+	domainFloat
+	domainBool
+	domainBytes
+	domainDecimal // embeds decimal.Decimal to keep its Scan method
+)
+
+func domainKindOf(goType string) domainKind {
+	switch goType {
+	case "string":
+		return domainString
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		return domainInt
+	case "float32", "float64":
+		return domainFloat
+	case "bool":
+		return domainBool
+	case "[]byte":
+		return domainBytes
+	case "decimal.Decimal":
+		return domainDecimal
+	default:
+		return domainUnsupported
+	}
+}
+
+// domainSpec is the Go type generated for a schema domain.
+type domainSpec struct {
+	name     string
+	typeName string
+	baseType string
+	kind     domainKind
+	checks   []domainCheck
+	patterns []string // regular expressions of the ~ checks, by pattern variable
+	// unchecked lists the CHECK conditions Validate cannot enforce.
+	unchecked []string
+}
+
+// domainCheck is a CHECK condition of a domain translated to Go.
+type domainCheck struct {
+	violation string // Go condition that holds when v violates the check
+	expr      string // SQL text of the condition
+}
+
+// domainSpecFor returns the Go type of domain, building it on first use. It
+// returns nil for domains over a base type without a Go type of its own
+// kind; their columns use the base type's Go type.
+func (b *Builder) domainSpecFor(domain *model.Domain) *domainSpec {
+	if spec, ok := b.domains[domain]; ok {
+		return spec
+	}
+	var baseType string
+	if b.opts.TypeResolver != nil {
+		baseType = b.opts.TypeResolver.ResolveType(domain.BaseType, false).GoType
+	} else {
+		baseType = resolveType(analyzer.SQLiteTypeToGo(domain.BaseType), false).GoType
+	}
+	var spec *domainSpec
+	if kind := domainKindOf(baseType); kind != domainUnsupported {
+		spec = &domainSpec{
+			name:     domain.Name,
+			typeName: ExportedIdentifier(domain.Name),
+			baseType: baseType,
+			kind:     kind,
+		}
+		spec.addChecks(domain)
+	}
+	if b.domains == nil {
+		b.domains = make(map[*model.Domain]*domainSpec)
+	}
+	b.domains[domain] = spec
+	return spec
+}
+
+// domainTypeInfo returns the Go type holding values of domain. Nullable
+// values are pointers, like other named types, unless the null style wraps
+// them in a generic type. Domains without a Go type resolve like their base
+// type.
+func (b *Builder) domainTypeInfo(domain *model.Domain, goType string, nullable bool, style config.NullStyle) TypeInfo {
+	spec := b.domainSpecFor(domain)
+	if spec == nil {
+		if b.opts.TypeResolver != nil {
+			return b.opts.TypeResolver.ResolveTypeWithStyle(domain.BaseType, nullable, b.nullStyle(style))
+		}
+		return b.resolveFallbackType(goType, nullable, style)
+	}
+	if nullable {
+		return b.nullableTypeInfo(spec.typeName, b.nullStyle(style))
+	}
+	return TypeInfo{GoType: spec.typeName}
+}
+
+// addChecks translates the CHECK constraints of domain. Each constraint is
+// split at its top-level ANDs, and conditions of the supported forms become
+// Go checks: comparisons, BETWEEN and IN lists of literals, length(VALUE)
+// bounds and ~ regular expression matches.
+func (spec *domainSpec) addChecks(domain *model.Domain) {
+	for _, con := range domain.Constraints {
+		if con.Type != "check" {
+			continue
+		}
+		tokens, err := tokenizer.Scan("", []byte(con.Expr), false)
+		if err != nil {
+			spec.unchecked = append(spec.unchecked, con.Expr)
+			continue
+		}
+		tokens = tokens[:len(tokens)-1] // drop EOF
+		for _, cond := range splitConjuncts(tokens) {
+			check, ok := spec.translateCheck(cond)
+			if !ok {
+				spec.unchecked = append(spec.unchecked, tokenizer.Join(cond))
+				continue
+			}
+			if check.violation != "" {
+				check.expr = tokenizer.Join(cond)
+				spec.checks = append(spec.checks, check)
+			}
+		}
+	}
+}
+
+// splitConjuncts splits a condition at its top-level ANDs, looking into
+// parenthesized conjunctions. The AND of a BETWEEN does not split.
+func splitConjuncts(tokens []tokenizer.Token) [][]tokenizer.Token {
+	for isParenthesized(tokens) {
+		tokens = tokens[1 : len(tokens)-1]
+	}
+	var parts [][]tokenizer.Token
+	depth := 0
+	start := 0
+	between := false
+	for i, tok := range tokens {
+		switch {
+		case isSymbolToken(tok, "("):
+			depth++
+		case isSymbolToken(tok, ")"):
+			depth--
+		case depth == 0 && tok.Kind == tokenizer.KindKeyword && tok.Text == "BETWEEN":
+			between = true
+		case depth == 0 && tok.Kind == tokenizer.KindKeyword && tok.Text == "AND":
+			if between {
+				between = false
+				continue
+			}
+			parts = append(parts, tokens[start:i])
+			start = i + 1
+		}
+	}
+	parts = append(parts, tokens[start:])
+	if len(parts) == 1 {
+		return parts
+	}
+	var conds [][]tokenizer.Token
+	for _, part := range parts {
+		conds = append(conds, splitConjuncts(part)...)
+	}
+	return conds
+}
+
+// isParenthesized reports whether tokens are wrapped in a pair of matching
+// parentheses.
+func isParenthesized(tokens []tokenizer.Token) bool {
+	if len(tokens) < 2 || !isSymbolToken(tokens[0], "(") || !isSymbolToken(tokens[len(tokens)-1], ")") {
+		return false
+	}
+	depth := 0
+	for i, tok := range tokens {
+		if isSymbolToken(tok, "(") {
+			depth++
+		} else if isSymbolToken(tok, ")") {
+			depth--
+			if depth == 0 && i != len(tokens)-1 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func isSymbolToken(tok tokenizer.Token, text string) bool {
+	return tok.Kind == tokenizer.KindSymbol && tok.Text == text
+}
+
+// checkOperand is the domain value side of a condition: VALUE itself or its
+// length.
+type checkOperand int
+
+const (
+	operandNone checkOperand = iota
+	operandValue
+	operandLength
+)
+
+// parseCheckOperand parses VALUE or length(VALUE) at the start of tokens and
+// returns the number of tokens it spans.
+func parseCheckOperand(tokens []tokenizer.Token) (checkOperand, int) {
+	isValue := func(tok tokenizer.Token) bool {
+		return (tok.Kind == tokenizer.KindIdentifier || tok.Kind == tokenizer.KindKeyword) && strings.EqualFold(tok.Text, "VALUE")
+	}
+	if len(tokens) == 0 {
+		return operandNone, 0
+	}
+	if isValue(tokens[0]) {
+		return operandValue, 1
+	}
+	if len(tokens) >= 4 && tokens[0].Kind != tokenizer.KindString && isSymbolToken(tokens[1], "(") && isValue(tokens[2]) && isSymbolToken(tokens[3], ")") {
+		switch strings.ToLower(tokens[0].Text) {
+		case "length", "char_length", "character_length":
+			return operandLength, 4
+		}
+	}
+	return operandNone, 0
+}
+
+// checkLiteral is a number or string literal of a condition.
+type checkLiteral struct {
+	text   string // number text, or the unquoted string
+	number bool
+}
+
+// parseCheckLiteral parses a possibly negative number or a string literal at
+// the start of tokens and returns the number of tokens it spans.
+func parseCheckLiteral(tokens []tokenizer.Token) (checkLiteral, int, bool) {
+	if len(tokens) == 0 {
+		return checkLiteral{}, 0, false
+	}
+	switch tok := tokens[0]; {
+	case tok.Kind == tokenizer.KindNumber:
+		return checkLiteral{text: tok.Text, number: true}, 1, true
+	case tok.Kind == tokenizer.KindString:
+		return checkLiteral{text: tokenizer.StringValue(tok.Text)}, 1, true
+	case isSymbolToken(tok, "-") && len(tokens) > 1 && tokens[1].Kind == tokenizer.KindNumber:
+		return checkLiteral{text: "-" + tokens[1].Text, number: true}, 2, true
+	}
+	return checkLiteral{}, 0, false
+}
+
+// parseCheckOperator joins the adjacent symbols of an operator such as ~*
+// at the start of tokens and returns the number of tokens it spans.
+func parseCheckOperator(tokens []tokenizer.Token) (string, int) {
+	var op strings.Builder
+	n := 0
+	for n < len(tokens) && tokens[n].Kind == tokenizer.KindSymbol && !strings.Contains("(),", tokens[n].Text) {
+		if n > 0 && tokenizer.Join(tokens[n-1:n+1]) != tokens[n-1].Text+tokens[n].Text {
+			break
+		}
+		op.WriteString(tokens[n].Text)
+		n++
+	}
+	return op.String(), n
+}
+
+// goViolations maps SQL comparison operators to the Go operator of their
+// negation.
+var goViolations = map[string]string{
+	"=":  "!=",
+	"<>": "==",
+	"!=": "==",
+	"<":  ">=",
+	"<=": ">",
+	">":  "<=",
+	">=": "<",
+}
+
+// flippedOperators maps comparison operators to their form with swapped
+// operands.
+var flippedOperators = map[string]string{
+	"<":  ">",
+	"<=": ">=",
+	">":  "<",
+	">=": "<=",
+}
+
+// translateCheck translates a single condition into Go. An IS NOT NULL
+// condition translates to an empty check since only non-NULL values are
+// validated.
+func (spec *domainSpec) translateCheck(tokens []tokenizer.Token) (domainCheck, bool) {
+	operand, n := parseCheckOperand(tokens)
+	if operand == operandNone {
+		// literal op operand
+		lit, litLen, ok := parseCheckLiteral(tokens)
+		if !ok {
+			return domainCheck{}, false
+		}
+		op, opLen := parseCheckOperator(tokens[litLen:])
+		operand, n = parseCheckOperand(tokens[litLen+opLen:])
+		if operand == operandNone || litLen+opLen+n != len(tokens) {
+			return domainCheck{}, false
+		}
+		if flipped, ok := flippedOperators[op]; ok {
+			op = flipped
+		}
+		return spec.comparison(operand, op, lit)
+	}
+	rest := tokens[n:]
+
+	if len(rest) == 3 && isKeywordToken(rest[0], "IS") && isKeywordToken(rest[1], "NOT") && isKeywordToken(rest[2], "NULL") {
+		return domainCheck{}, true
+	}
+	negated := len(rest) > 0 && isKeywordToken(rest[0], "NOT")
+	if negated {
+		rest = rest[1:]
+	}
+	switch {
+	case len(rest) > 0 && isKeywordToken(rest[0], "BETWEEN"):
+		low, lowLen, ok := parseCheckLiteral(rest[1:])
+		if !ok || 1+lowLen >= len(rest) || !isKeywordToken(rest[1+lowLen], "AND") {
+			return domainCheck{}, false
+		}
+		high, highLen, ok := parseCheckLiteral(rest[2+lowLen:])
+		if !ok || 2+lowLen+highLen != len(rest) {
+			return domainCheck{}, false
+		}
+		return spec.between(operand, low, high, negated)
+	case len(rest) > 0 && isKeywordToken(rest[0], "IN"):
+		return spec.inList(operand, rest[1:], negated)
+	case negated:
+		return domainCheck{}, false
+	}
+
+	op, opLen := parseCheckOperator(rest)
+	lit, litLen, ok := parseCheckLiteral(rest[opLen:])
+	if !ok || opLen+litLen != len(rest) {
+		return domainCheck{}, false
+	}
+	switch op {
+	case "~", "~*", "!~", "!~*":
+		return spec.match(operand, op, lit)
+	}
+	return spec.comparison(operand, op, lit)
+}
+
+func isKeywordToken(tok tokenizer.Token, text string) bool {
+	return (tok.Kind == tokenizer.KindKeyword || tok.Kind == tokenizer.KindIdentifier) && strings.EqualFold(tok.Text, text)
+}
+
+// operandExpr returns the Go expression of the operand for comparisons with
+// lit, or false when the comparison cannot be checked in Go. String values
+// only support equality since their SQL ordering depends on the collation.
+func (spec *domainSpec) operandExpr(operand checkOperand, lit checkLiteral, equality bool) (string, string, bool) {
+	if operand == operandLength {
+		if !lit.number || !isIntegerLiteral(lit.text) {
+			return "", "", false
+		}
+		switch spec.kind {
+		case domainString:
+			return "utf8.RuneCountInString(string(v))", lit.text, true
+		case domainBytes:
+			return "len(v)", lit.text, true
+		default:
+			return "", "", false
+		}
+	}
+	switch spec.kind {
+	case domainInt:
+		if lit.number && isIntegerLiteral(lit.text) {
+			return "v", lit.text, true
+		}
+	case domainFloat:
+		if lit.number {
+			return "v", lit.text, true
+		}
+	case domainDecimal:
+		if lit.number {
+			return "v.Decimal", "decimal.RequireFromString(" + strconv.Quote(lit.text) + ")", true
+		}
+	case domainString:
+		if !lit.number && equality {
+			return "v", strconv.Quote(lit.text), true
+		}
+	}
+	return "", "", false
+}
+
+func isIntegerLiteral(text string) bool {
+	_, err := strconv.ParseInt(text, 10, 64)
+	return err == nil
+}
+
+// violation returns the Go condition of lhs failing the SQL comparison op
+// with rhs.
+func (spec *domainSpec) violation(lhs, op, rhs string) string {
+	if spec.kind == domainDecimal && lhs == "v.Decimal" {
+		return fmt.Sprintf("%s.Cmp(%s) %s 0", lhs, rhs, goViolations[op])
+	}
+	return fmt.Sprintf("%s %s %s", lhs, goViolations[op], rhs)
+}
+
+func (spec *domainSpec) comparison(operand checkOperand, op string, lit checkLiteral) (domainCheck, bool) {
+	if _, ok := goViolations[op]; !ok {
+		return domainCheck{}, false
+	}
+	equality := op == "=" || op == "<>" || op == "!="
+	lhs, rhs, ok := spec.operandExpr(operand, lit, equality)
+	if !ok {
+		return domainCheck{}, false
+	}
+	return domainCheck{violation: spec.violation(lhs, op, rhs)}, true
+}
+
+func (spec *domainSpec) between(operand checkOperand, low, high checkLiteral, negated bool) (domainCheck, bool) {
+	lhs, lowExpr, ok := spec.operandExpr(operand, low, false)
+	if !ok {
+		return domainCheck{}, false
+	}
+	_, highExpr, ok := spec.operandExpr(operand, high, false)
+	if !ok {
+		return domainCheck{}, false
+	}
+	if negated {
+		return domainCheck{violation: spec.violation(lhs, "<", lowExpr) + " && " + spec.violation(lhs, ">", highExpr)}, true
+	}
+	return domainCheck{violation: spec.violation(lhs, ">=", lowExpr) + " || " + spec.violation(lhs, "<=", highExpr)}, true
+}
+
+func (spec *domainSpec) inList(operand checkOperand, tokens []tokenizer.Token, negated bool) (domainCheck, bool) {
+	if len(tokens) < 3 || !isSymbolToken(tokens[0], "(") || !isSymbolToken(tokens[len(tokens)-1], ")") {
+		return domainCheck{}, false
+	}
+	items := tokens[1 : len(tokens)-1]
+	var conds []string
+	for len(items) > 0 {
+		lit, n, ok := parseCheckLiteral(items)
+		if !ok || spec.kind == domainDecimal {
+			return domainCheck{}, false
+		}
+		lhs, rhs, ok := spec.operandExpr(operand, lit, true)
+		if !ok {
+			return domainCheck{}, false
+		}
+		if negated {
+			conds = append(conds, spec.violation(lhs, "<>", rhs))
+		} else {
+			conds = append(conds, spec.violation(lhs, "=", rhs))
+		}
+		items = items[n:]
+		if len(items) > 0 {
+			if !isSymbolToken(items[0], ",") || len(items) == 1 {
+				return domainCheck{}, false
+			}
+			items = items[1:]
+		}
+	}
+	if negated {
+		return domainCheck{violation: strings.Join(conds, " || ")}, true
+	}
+	return domainCheck{violation: strings.Join(conds, " && ")}, true
+}
+
+// match translates a POSIX regular expression match. Patterns RE2 cannot
+// compile are left unchecked.
+func (spec *domainSpec) match(operand checkOperand, op string, lit checkLiteral) (domainCheck, bool) {
+	if operand != operandValue || spec.kind != domainString || lit.number {
+		return domainCheck{}, false
+	}
+	pattern := lit.text
+	if strings.HasSuffix(op, "*") {
+		pattern = "(?i)" + pattern
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		return domainCheck{}, false
+	}
+	name := spec.patternVar(len(spec.patterns))
+	spec.patterns = append(spec.patterns, pattern)
+	if strings.HasPrefix(op, "!") {
+		return domainCheck{violation: name + ".MatchString(string(v))"}, true
+	}
+	return domainCheck{violation: "!" + name + ".MatchString(string(v))"}, true
+}
+
+// patternVar returns the name of the variable holding the i-th pattern.
+func (spec *domainSpec) patternVar(i int) string {
+	name := UnexportedIdentifier(spec.name) + "Pattern"
+	if i > 0 {
+		name += strconv.Itoa(i + 1)
+	}
+	return name
+}
+
+// zeroValue returns the zero value of the domain type.
+func (spec *domainSpec) zeroValue() string {
+	switch spec.kind {
+	case domainString:
+		return `""`
+	case domainInt, domainFloat:
+		return "0"
+	case domainBool:
+		return "false"
+	case domainBytes:
+		return "nil"
+	default:
+		return spec.typeName + "{}"
+	}
+}
+
+// driverValue returns the expression converting v to a driver.Value.
+func (spec *domainSpec) driverValue() string {
+	switch spec.kind {
+	case domainString:
+		return "string(v), nil"
+	case domainInt:
+		return "int64(v), nil"
+	case domainFloat:
+		return "float64(v), nil"
+	case domainBool:
+		return "bool(v), nil"
+	case domainBytes:
+		return "[]byte(v), nil"
+	default:
+		return "v.Decimal.Value()"
+	}
+}
+
+// domainTypes returns the domains of the current build that have a Go type,
+// sorted by type name.
+func (b *Builder) domainTypes() []*domainSpec {
+	var specs []*domainSpec
+	for _, spec := range b.domains {
+		if spec != nil {
+			specs = append(specs, spec)
+		}
+	}
+	slices.SortFunc(specs, func(a, b *domainSpec) int {
+		return strings.Compare(a.typeName, b.typeName)
+	})
+	return specs
+}
+
+// buildDomainsFile emits a named type per schema domain over the Go type of
+// its base type, with a Validate method enforcing the CHECK constraints it
+// can translate. Value validates before a value reaches the database.
+func (b *Builder) buildDomainsFile(pkg string, specs []*domainSpec) (File, error) {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	fmt.Fprintf(&buf, "import (\n")
+	fmt.Fprintf(&buf, "\t\"database/sql/driver\"\n")
+	fmt.Fprintf(&buf, "\t\"fmt\"\n")
+	fmt.Fprintf(&buf, "\t\"regexp\"\n")
+	fmt.Fprintf(&buf, "\t\"unicode/utf8\"\n")
+	if slices.ContainsFunc(specs, func(spec *domainSpec) bool { return spec.kind == domainDecimal }) {
+		fmt.Fprintf(&buf, "\n\t\"github.com/shopspring/decimal\"\n")
+	}
+	fmt.Fprintf(&buf, ")\n")
+
+	for _, spec := range specs {
+		typ := spec.typeName
+		fmt.Fprintf(&buf, "\n// %s is a value of the %s domain.\n", typ, spec.name)
+		if spec.kind == domainDecimal {
+			fmt.Fprintf(&buf, "type %s struct {\n\tdecimal.Decimal\n}\n\n", typ)
+		} else {
+			fmt.Fprintf(&buf, "type %s %s\n\n", typ, spec.baseType)
+		}
+
+		switch len(spec.patterns) {
+		case 0:
+		case 1:
+			fmt.Fprintf(&buf, "var %s = regexp.MustCompile(%s)\n\n", spec.patternVar(0), strconv.Quote(spec.patterns[0]))
+		default:
+			fmt.Fprintf(&buf, "var (\n")
+			for i, pattern := range spec.patterns {
+				fmt.Fprintf(&buf, "\t%s = regexp.MustCompile(%s)\n", spec.patternVar(i), strconv.Quote(pattern))
+			}
+			fmt.Fprintf(&buf, ")\n\n")
+		}
+
+		fmt.Fprintf(&buf, "// Validate returns an error if v violates a CHECK constraint of the %s\n", spec.name)
+		fmt.Fprintf(&buf, "// domain.")
+		if len(spec.unchecked) > 0 {
+			fmt.Fprintf(&buf, " These conditions are left to the database:\n//\n")
+			for _, expr := range spec.unchecked {
+				fmt.Fprintf(&buf, "//   - %s\n", expr)
+			}
+		} else {
+			fmt.Fprintf(&buf, "\n")
+		}
+		fmt.Fprintf(&buf, "func (v %s) Validate() error {\n", typ)
+		verb := "%v"
+		if spec.kind == domainString {
+			verb = "%q"
+		}
+		for _, check := range spec.checks {
+			message := fmt.Sprintf("%s value %s violates CHECK (%s)", escapeFormat(spec.name), verb, escapeFormat(check.expr))
+			fmt.Fprintf(&buf, "\tif %s {\n", check.violation)
+			fmt.Fprintf(&buf, "\t\treturn fmt.Errorf(%s, v)\n", strconv.Quote(message))
+			fmt.Fprintf(&buf, "\t}\n")
+		}
+		fmt.Fprintf(&buf, "\treturn nil\n")
+		fmt.Fprintf(&buf, "}\n\n")
+
+		fmt.Fprintf(&buf, "// Value implements driver.Valuer, validating v first.\n")
+		fmt.Fprintf(&buf, "func (v %s) Value() (driver.Value, error) {\n", typ)
+		fmt.Fprintf(&buf, "\tif err := v.Validate(); err != nil {\n")
+		fmt.Fprintf(&buf, "\t\treturn nil, err\n")
+		fmt.Fprintf(&buf, "\t}\n")
+		fmt.Fprintf(&buf, "\treturn %s\n", spec.driverValue())
+		fmt.Fprintf(&buf, "}\n")
+	}
+
+	formatted, err := imports.Process("", []byte(buf.String()), nil)
+	if err != nil {
+		return File{}, err
+	}
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, "", formatted, parser.ParseComments)
+	if err != nil {
+		return File{}, err
+	}
+	return File{Path: "domains.gen.go", Node: node, Raw: formatted}, nil
+}
+
+// escapeFormat escapes the verbs of text for use in a format string.
+func escapeFormat(text string) string {
+	return strings.ReplaceAll(text, "%", "%%")
+}
@@ -0,0 +1,149 @@
+package ast
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"path"
+	"strings"
+
+	"golang.org/x/tools/imports"
+
+	"github.com/electwix/db-catalyst/internal/config"
+)
+
+// sqliteDriverSpec describes the configured sqlite_driver to the generated
+// code.
+type sqliteDriverSpec struct {
+	importPath string
+	importName string
+	driverName string
+	errorType  string // the error type returned by the driver
+	errorCode  string // the extended result code of an error e
+}
+
+func sqliteDriverSpecFor(driver config.Driver) sqliteDriverSpec {
+	if driver == config.DriverMattN {
+		return sqliteDriverSpec{
+			importPath: "github.com/mattn/go-sqlite3",
+			importName: "sqlite3",
+			driverName: "sqlite3",
+			errorType:  "sqlite3.Error",
+			errorCode:  "int(e.ExtendedCode)",
+		}
+	}
+	return sqliteDriverSpec{
+		importPath: "modernc.org/sqlite",
+		importName: "sqlite",
+		driverName: "sqlite",
+		errorType:  "*sqlite.Error",
+		errorCode:  "e.Code()",
+	}
+}
+
+// buildDriverFile emits the helpers of emit_driver_helpers for the configured
+// sqlite_driver: Open, which imports the driver, and error helpers that
+// classify driver errors by their extended result code, so switching drivers
+// needs no change to error handling.
+func (b *Builder) buildDriverFile(pkg string) (File, error) {
+	spec := sqliteDriverSpecFor(b.opts.SQLiteDriver)
+	modernc := b.opts.SQLiteDriver != config.DriverMattN
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	fmt.Fprintf(&buf, "import (\n")
+	fmt.Fprintf(&buf, "\t\"database/sql\"\n")
+	fmt.Fprintf(&buf, "\t\"errors\"\n")
+	if modernc {
+		fmt.Fprintf(&buf, "\t\"strings\"\n")
+	}
+	if path.Base(spec.importPath) == spec.importName {
+		fmt.Fprintf(&buf, "\n\t%q\n", spec.importPath)
+	} else {
+		fmt.Fprintf(&buf, "\n\t%s %q\n", spec.importName, spec.importPath)
+	}
+	fmt.Fprintf(&buf, ")\n\n")
+
+	fmt.Fprintf(&buf, "// DriverName is the database/sql name of the %s driver.\n", spec.importPath)
+	fmt.Fprintf(&buf, "const DriverName = %q\n\n", spec.driverName)
+
+	if modernc {
+		fmt.Fprintf(&buf, "// Open opens the SQLite database dsn with %s. Unless dsn sets\n", spec.importPath)
+		fmt.Fprintf(&buf, "// _time_format, times are written in the format of SQLite's date functions\n")
+		fmt.Fprintf(&buf, "// rather than by time.Time.String.\n")
+		fmt.Fprintf(&buf, "func Open(dsn string) (*sql.DB, error) {\n")
+		fmt.Fprintf(&buf, "\tif !strings.Contains(dsn, \"_time_format=\") {\n")
+		fmt.Fprintf(&buf, "\t\tsep := \"?\"\n")
+		fmt.Fprintf(&buf, "\t\tif strings.Contains(dsn, \"?\") {\n")
+		fmt.Fprintf(&buf, "\t\t\tsep = \"&\"\n")
+		fmt.Fprintf(&buf, "\t\t}\n")
+		fmt.Fprintf(&buf, "\t\tdsn += sep + \"_time_format=sqlite\"\n")
+		fmt.Fprintf(&buf, "\t}\n")
+		fmt.Fprintf(&buf, "\treturn sql.Open(DriverName, dsn)\n")
+		fmt.Fprintf(&buf, "}\n\n")
+	} else {
+		fmt.Fprintf(&buf, "// Open opens the SQLite database dsn with %s.\n", spec.importPath)
+		fmt.Fprintf(&buf, "func Open(dsn string) (*sql.DB, error) {\n")
+		fmt.Fprintf(&buf, "\treturn sql.Open(DriverName, dsn)\n")
+		fmt.Fprintf(&buf, "}\n\n")
+	}
+
+	fmt.Fprintf(&buf, "// SQLite result codes classified by the error helpers. An extended result\n")
+	fmt.Fprintf(&buf, "// code carries its primary code in the low byte.\n")
+	fmt.Fprintf(&buf, "const (\n")
+	fmt.Fprintf(&buf, "\tsqliteBusy                 = 5\n")
+	fmt.Fprintf(&buf, "\tsqliteReadOnly             = 8\n")
+	fmt.Fprintf(&buf, "\tsqliteConstraintForeignKey = 787\n")
+	fmt.Fprintf(&buf, "\tsqliteConstraintPrimaryKey = 1555\n")
+	fmt.Fprintf(&buf, "\tsqliteConstraintUnique     = 2067\n")
+	fmt.Fprintf(&buf, ")\n\n")
+
+	fmt.Fprintf(&buf, "// SQLiteErrorCode returns the extended result code of the %s\n", spec.importPath)
+	fmt.Fprintf(&buf, "// error in the chain of err.\n")
+	fmt.Fprintf(&buf, "func SQLiteErrorCode(err error) (int, bool) {\n")
+	fmt.Fprintf(&buf, "\tvar e %s\n", spec.errorType)
+	fmt.Fprintf(&buf, "\tif !errors.As(err, &e) {\n")
+	fmt.Fprintf(&buf, "\t\treturn 0, false\n")
+	fmt.Fprintf(&buf, "\t}\n")
+	fmt.Fprintf(&buf, "\treturn %s, true\n", spec.errorCode)
+	fmt.Fprintf(&buf, "}\n\n")
+
+	fmt.Fprintf(&buf, "// IsBusy reports whether err is SQLITE_BUSY: the database is locked by\n")
+	fmt.Fprintf(&buf, "// another connection.\n")
+	fmt.Fprintf(&buf, "func IsBusy(err error) bool {\n")
+	fmt.Fprintf(&buf, "\tcode, ok := SQLiteErrorCode(err)\n")
+	fmt.Fprintf(&buf, "\treturn ok && code&0xff == sqliteBusy\n")
+	fmt.Fprintf(&buf, "}\n\n")
+
+	fmt.Fprintf(&buf, "// IsReadOnly reports whether err is SQLITE_READONLY: the database cannot\n")
+	fmt.Fprintf(&buf, "// be written.\n")
+	fmt.Fprintf(&buf, "func IsReadOnly(err error) bool {\n")
+	fmt.Fprintf(&buf, "\tcode, ok := SQLiteErrorCode(err)\n")
+	fmt.Fprintf(&buf, "\treturn ok && code&0xff == sqliteReadOnly\n")
+	fmt.Fprintf(&buf, "}\n\n")
+
+	fmt.Fprintf(&buf, "// IsUniqueViolation reports whether err violates a UNIQUE or PRIMARY KEY\n")
+	fmt.Fprintf(&buf, "// constraint.\n")
+	fmt.Fprintf(&buf, "func IsUniqueViolation(err error) bool {\n")
+	fmt.Fprintf(&buf, "\tcode, ok := SQLiteErrorCode(err)\n")
+	fmt.Fprintf(&buf, "\treturn ok && (code == sqliteConstraintUnique || code == sqliteConstraintPrimaryKey)\n")
+	fmt.Fprintf(&buf, "}\n\n")
+
+	fmt.Fprintf(&buf, "// IsForeignKeyViolation reports whether err violates a FOREIGN KEY\n")
+	fmt.Fprintf(&buf, "// constraint.\n")
+	fmt.Fprintf(&buf, "func IsForeignKeyViolation(err error) bool {\n")
+	fmt.Fprintf(&buf, "\tcode, ok := SQLiteErrorCode(err)\n")
+	fmt.Fprintf(&buf, "\treturn ok && code == sqliteConstraintForeignKey\n")
+	fmt.Fprintf(&buf, "}\n")
+
+	formatted, err := imports.Process("", []byte(buf.String()), nil)
+	if err != nil {
+		return File{}, err
+	}
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, "", formatted, parser.ParseComments)
+	if err != nil {
+		return File{}, err
+	}
+	return File{Path: "driver.gen.go", Node: node, Raw: formatted}, nil
+}
@@ -0,0 +1,477 @@
+package ast
+
+import (
+	"cmp"
+	"fmt"
+	goast "go/ast"
+	"go/parser"
+	"go/token"
+	"maps"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/imports"
+
+	"github.com/electwix/db-catalyst/internal/config"
+)
+
+// constraintErrorSpec is the generated error of one UNIQUE, PRIMARY KEY or
+// FOREIGN KEY constraint.
+type constraintErrorSpec struct {
+	varName    string
+	table      string
+	constraint string
+	columns    []string
+	kind       string // ErrUniqueViolation or ErrForeignKeyViolation
+	// key identifies the constraint in the errors of the driver.
+	key string
+}
+
+// constraintErrorSpecs returns the errors of the catalog's constraints in
+// table order. Each constraint is identified the way the database reports
+// it: SQLite names the columns of a UNIQUE or PRIMARY KEY constraint,
+// PostgreSQL and MySQL name the constraint, using the name the database
+// assigns when the schema declares none. SQLite does not say which FOREIGN
+// KEY constraint failed, so its foreign keys get no error of their own.
+func (b *Builder) constraintErrorSpecs() ([]constraintErrorSpec, error) {
+	if b.catalog == nil {
+		return nil, nil
+	}
+	var specs []constraintErrorSpec
+	used := make(map[string]int)
+	seen := make(map[string]struct{})
+	add := func(table, constraint, name string, columns []string, kind, key string) error {
+		if _, dup := seen[key]; dup {
+			return nil
+		}
+		seen[key] = struct{}{}
+		varName, err := UniqueName(name, used)
+		if err != nil {
+			return err
+		}
+		if constraint == "" {
+			constraint = key
+		}
+		specs = append(specs, constraintErrorSpec{
+			varName:    varName,
+			table:      table,
+			constraint: constraint,
+			columns:    columns,
+			kind:       kind,
+			key:        key,
+		})
+		return nil
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(b.catalog.Tables)) {
+		tbl := b.catalog.Tables[name]
+		table := unqualifiedName(tbl.Name)
+		prefix := "Err" + ExportedIdentifier(table)
+		mysqlKeys := make(map[string]struct{})
+
+		if pk := tbl.PrimaryKey; pk != nil && len(pk.Columns) > 0 {
+			var constraint, key string
+			switch b.opts.Database {
+			case config.DatabasePostgreSQL:
+				constraint = cmp.Or(pk.Name, table+"_pkey")
+				key = constraint
+			case config.DatabaseMySQL:
+				constraint = "PRIMARY"
+				key = table + ".PRIMARY"
+			default:
+				constraint = pk.Name
+				key = sqliteColumnsKey(table, pk.Columns)
+			}
+			if err := add(table, constraint, prefix+"PrimaryKey", pk.Columns, "ErrUniqueViolation", key); err != nil {
+				return nil, err
+			}
+		}
+
+		type uniqueKey struct {
+			name    string
+			columns []string
+		}
+		uniques := make([]uniqueKey, 0, len(tbl.UniqueKeys))
+		for _, uk := range tbl.UniqueKeys {
+			uniques = append(uniques, uniqueKey{uk.Name, uk.Columns})
+		}
+		for _, idx := range tbl.Indexes {
+			if idx.Unique && idx.Name != "" {
+				uniques = append(uniques, uniqueKey{idx.Name, idx.Columns})
+			}
+		}
+		for _, uk := range uniques {
+			if len(uk.columns) == 0 {
+				continue
+			}
+			constraint := uk.name
+			var key string
+			switch b.opts.Database {
+			case config.DatabasePostgreSQL:
+				constraint = cmp.Or(constraint, table+"_"+strings.Join(uk.columns, "_")+"_key")
+				key = constraint
+			case config.DatabaseMySQL:
+				if constraint == "" {
+					// MySQL names a key after its first column, numbering
+					// duplicates from 2.
+					constraint = uk.columns[0]
+					for i := 2; ; i++ {
+						if _, taken := mysqlKeys[constraint]; !taken {
+							break
+						}
+						constraint = uk.columns[0] + "_" + strconv.Itoa(i)
+					}
+				}
+				mysqlKeys[constraint] = struct{}{}
+				key = table + "." + constraint
+			default:
+				key = sqliteColumnsKey(table, uk.columns)
+			}
+			varName := prefix
+			for _, col := range uk.columns {
+				varName += ExportedIdentifier(col)
+			}
+			if err := add(table, constraint, varName+"Unique", uk.columns, "ErrUniqueViolation", key); err != nil {
+				return nil, err
+			}
+		}
+
+		if b.opts.Database != config.DatabasePostgreSQL && b.opts.Database != config.DatabaseMySQL {
+			continue
+		}
+		unnamed := 0
+		for _, fk := range tbl.ForeignKeys {
+			if len(fk.Columns) == 0 {
+				continue
+			}
+			constraint := fk.Name
+			if constraint == "" {
+				if b.opts.Database == config.DatabaseMySQL {
+					unnamed++
+					constraint = table + "_ibfk_" + strconv.Itoa(unnamed)
+				} else {
+					constraint = table + "_" + strings.Join(fk.Columns, "_") + "_fkey"
+				}
+			}
+			varName := prefix
+			for _, col := range fk.Columns {
+				if trimmed := strings.TrimSuffix(strings.ToLower(col), "_id"); trimmed != "" {
+					col = col[:len(trimmed)]
+				}
+				varName += ExportedIdentifier(col)
+			}
+			if err := add(table, constraint, varName+"FK", fk.Columns, "ErrForeignKeyViolation", constraint); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return specs, nil
+}
+
+// unqualifiedName strips the schema from a table name.
+func unqualifiedName(name string) string {
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		return name[idx+1:]
+	}
+	return name
+}
+
+// sqliteColumnsKey returns the columns as SQLite lists them in the message of
+// a failed UNIQUE or PRIMARY KEY constraint.
+func sqliteColumnsKey(table string, columns []string) string {
+	qualified := make([]string, len(columns))
+	for i, col := range columns {
+		qualified[i] = table + "." + col
+	}
+	return strings.Join(qualified, ", ")
+}
+
+// buildErrorsFile emits the errors of constraint_errors: an error for each
+// constraint of the catalog and ClassifyError, which maps the errors of the
+// database driver to them.
+func (b *Builder) buildErrorsFile(pkg string) (File, error) {
+	specs, err := b.constraintErrorSpecs()
+	if err != nil {
+		return File{}, err
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	fmt.Fprintf(&buf, "import (\n")
+	fmt.Fprintf(&buf, "\t\"errors\"\n")
+	fmt.Fprintf(&buf, "\t\"fmt\"\n")
+	switch b.opts.Database {
+	case config.DatabasePostgreSQL:
+		fmt.Fprintf(&buf, "\n\t\"github.com/jackc/pgx/v5/pgconn\"\n")
+	case config.DatabaseMySQL:
+		fmt.Fprintf(&buf, "\t\"strings\"\n")
+		fmt.Fprintf(&buf, "\n\t\"github.com/go-sql-driver/mysql\"\n")
+	default:
+		spec := sqliteDriverSpecFor(b.opts.SQLiteDriver)
+		fmt.Fprintf(&buf, "\t\"strings\"\n")
+		if path.Base(spec.importPath) == spec.importName {
+			fmt.Fprintf(&buf, "\n\t%q\n", spec.importPath)
+		} else {
+			fmt.Fprintf(&buf, "\n\t%s %q\n", spec.importName, spec.importPath)
+		}
+	}
+	fmt.Fprintf(&buf, ")\n\n")
+
+	fmt.Fprintf(&buf, "// Constraint violations returned by ClassifyError. The errors of the\n")
+	fmt.Fprintf(&buf, "// schema's constraints unwrap to them.\n")
+	fmt.Fprintf(&buf, "var (\n")
+	fmt.Fprintf(&buf, "\tErrUniqueViolation     = errors.New(\"unique violation\")\n")
+	fmt.Fprintf(&buf, "\tErrForeignKeyViolation = errors.New(\"foreign key violation\")\n")
+	fmt.Fprintf(&buf, ")\n\n")
+
+	fmt.Fprintf(&buf, "// ConstraintError is a violation of a UNIQUE, PRIMARY KEY or FOREIGN KEY\n")
+	fmt.Fprintf(&buf, "// constraint of the schema.\n")
+	fmt.Fprintf(&buf, "type ConstraintError struct {\n")
+	fmt.Fprintf(&buf, "\tTable      string\n")
+	fmt.Fprintf(&buf, "\tConstraint string\n")
+	fmt.Fprintf(&buf, "\tColumns    []string\n")
+	fmt.Fprintf(&buf, "\tkind       error\n")
+	fmt.Fprintf(&buf, "}\n\n")
+	fmt.Fprintf(&buf, "func (e *ConstraintError) Error() string {\n")
+	fmt.Fprintf(&buf, "\treturn e.kind.Error() + \": \" + e.Constraint\n")
+	fmt.Fprintf(&buf, "}\n\n")
+	fmt.Fprintf(&buf, "// Unwrap returns ErrUniqueViolation or ErrForeignKeyViolation.\n")
+	fmt.Fprintf(&buf, "func (e *ConstraintError) Unwrap() error {\n")
+	fmt.Fprintf(&buf, "\treturn e.kind\n")
+	fmt.Fprintf(&buf, "}\n\n")
+
+	fmt.Fprintf(&buf, "// ErrCheckViolation is a violation of the CHECK constraint Constraint, as\n")
+	fmt.Fprintf(&buf, "// named by the database.\n")
+	fmt.Fprintf(&buf, "type ErrCheckViolation struct {\n")
+	fmt.Fprintf(&buf, "\tConstraint string\n")
+	fmt.Fprintf(&buf, "}\n\n")
+	fmt.Fprintf(&buf, "func (e ErrCheckViolation) Error() string {\n")
+	fmt.Fprintf(&buf, "\treturn \"check violation: \" + e.Constraint\n")
+	fmt.Fprintf(&buf, "}\n\n")
+
+	if len(specs) > 0 {
+		fmt.Fprintf(&buf, "// Errors of the schema's constraints.\n")
+		fmt.Fprintf(&buf, "var (\n")
+		for _, spec := range specs {
+			fmt.Fprintf(&buf, "\t// %s is a violation of %s on %s (%s).\n", spec.varName, spec.constraint, spec.table, strings.Join(spec.columns, ", "))
+			fmt.Fprintf(&buf, "\t%s = &ConstraintError{Table: %q, Constraint: %q, Columns: %#v, kind: %s}\n", spec.varName, spec.table, spec.constraint, spec.columns, spec.kind)
+		}
+		fmt.Fprintf(&buf, ")\n\n")
+	}
+
+	fmt.Fprintf(&buf, "// constraintErrors holds the errors of the schema's constraints by the\n")
+	fmt.Fprintf(&buf, "// constraint the driver reports.\n")
+	fmt.Fprintf(&buf, "var constraintErrors = map[string]*ConstraintError{\n")
+	for _, spec := range specs {
+		fmt.Fprintf(&buf, "\t%q: %s,\n", spec.key, spec.varName)
+	}
+	fmt.Fprintf(&buf, "}\n\n")
+
+	fmt.Fprintf(&buf, "type violation int\n\n")
+	fmt.Fprintf(&buf, "const (\n")
+	fmt.Fprintf(&buf, "\tviolationNone violation = iota\n")
+	fmt.Fprintf(&buf, "\tviolationUnique\n")
+	fmt.Fprintf(&buf, "\tviolationForeignKey\n")
+	fmt.Fprintf(&buf, "\tviolationCheck\n")
+	fmt.Fprintf(&buf, ")\n\n")
+
+	switch b.opts.Database {
+	case config.DatabasePostgreSQL:
+		writePostgresViolation(&buf)
+	case config.DatabaseMySQL:
+		writeMySQLViolation(&buf)
+	default:
+		writeSQLiteViolation(&buf, b.opts.SQLiteDriver)
+	}
+
+	fmt.Fprintf(&buf, "// ClassifyError returns err wrapped in the error of the constraint it\n")
+	fmt.Fprintf(&buf, "// violates, or in ErrUniqueViolation, ErrForeignKeyViolation or\n")
+	fmt.Fprintf(&buf, "// ErrCheckViolation when the constraint is not one of the schema's.\n")
+	fmt.Fprintf(&buf, "// Other errors, including nil, are returned unchanged.\n")
+	fmt.Fprintf(&buf, "func ClassifyError(err error) error {\n")
+	fmt.Fprintf(&buf, "\tvar check ErrCheckViolation\n")
+	fmt.Fprintf(&buf, "\tif errors.Is(err, ErrUniqueViolation) || errors.Is(err, ErrForeignKeyViolation) || errors.As(err, &check) {\n")
+	fmt.Fprintf(&buf, "\t\treturn err\n")
+	fmt.Fprintf(&buf, "\t}\n")
+	fmt.Fprintf(&buf, "\tkind, constraint := constraintViolation(err)\n")
+	fmt.Fprintf(&buf, "\tswitch kind {\n")
+	fmt.Fprintf(&buf, "\tcase violationUnique, violationForeignKey:\n")
+	fmt.Fprintf(&buf, "\t\ttarget := ErrUniqueViolation\n")
+	fmt.Fprintf(&buf, "\t\tif kind == violationForeignKey {\n")
+	fmt.Fprintf(&buf, "\t\t\ttarget = ErrForeignKeyViolation\n")
+	fmt.Fprintf(&buf, "\t\t}\n")
+	fmt.Fprintf(&buf, "\t\tif e, ok := constraintErrors[constraint]; ok && e.kind == target {\n")
+	fmt.Fprintf(&buf, "\t\t\treturn fmt.Errorf(\"%%w: %%w\", e, err)\n")
+	fmt.Fprintf(&buf, "\t\t}\n")
+	fmt.Fprintf(&buf, "\t\treturn fmt.Errorf(\"%%w: %%w\", target, err)\n")
+	fmt.Fprintf(&buf, "\tcase violationCheck:\n")
+	fmt.Fprintf(&buf, "\t\treturn fmt.Errorf(\"%%w: %%w\", ErrCheckViolation{Constraint: constraint}, err)\n")
+	fmt.Fprintf(&buf, "\t}\n")
+	fmt.Fprintf(&buf, "\treturn err\n")
+	fmt.Fprintf(&buf, "}\n")
+
+	formatted, err := imports.Process("", []byte(buf.String()), nil)
+	if err != nil {
+		return File{}, err
+	}
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, "", formatted, parser.ParseComments)
+	if err != nil {
+		return File{}, err
+	}
+	return File{Path: "errors.gen.go", Node: node, Raw: formatted}, nil
+}
+
+func writeSQLiteViolation(buf *strings.Builder, driver config.Driver) {
+	spec := sqliteDriverSpecFor(driver)
+	fmt.Fprintf(buf, "// constraintViolation returns the constraint err violates. SQLite reports\n")
+	fmt.Fprintf(buf, "// the columns of a UNIQUE or PRIMARY KEY constraint and the name of a CHECK\n")
+	fmt.Fprintf(buf, "// constraint, but not which FOREIGN KEY constraint failed.\n")
+	fmt.Fprintf(buf, "func constraintViolation(err error) (violation, string) {\n")
+	fmt.Fprintf(buf, "\tvar e %s\n", spec.errorType)
+	fmt.Fprintf(buf, "\tif !errors.As(err, &e) {\n")
+	fmt.Fprintf(buf, "\t\treturn violationNone, \"\"\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "\tvar kind violation\n")
+	fmt.Fprintf(buf, "\tswitch %s {\n", spec.errorCode)
+	fmt.Fprintf(buf, "\tcase 1555, 2067: // SQLITE_CONSTRAINT_PRIMARYKEY, SQLITE_CONSTRAINT_UNIQUE\n")
+	fmt.Fprintf(buf, "\t\tkind = violationUnique\n")
+	fmt.Fprintf(buf, "\tcase 787: // SQLITE_CONSTRAINT_FOREIGNKEY\n")
+	fmt.Fprintf(buf, "\t\treturn violationForeignKey, \"\"\n")
+	fmt.Fprintf(buf, "\tcase 275: // SQLITE_CONSTRAINT_CHECK\n")
+	fmt.Fprintf(buf, "\t\tkind = violationCheck\n")
+	fmt.Fprintf(buf, "\tdefault:\n")
+	fmt.Fprintf(buf, "\t\treturn violationNone, \"\"\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "\tmsg := e.Error()\n")
+	fmt.Fprintf(buf, "\tconst marker = \"constraint failed: \"\n")
+	fmt.Fprintf(buf, "\tidx := strings.LastIndex(msg, marker)\n")
+	fmt.Fprintf(buf, "\tif idx < 0 {\n")
+	fmt.Fprintf(buf, "\t\treturn kind, \"\"\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "\tdetail := msg[idx+len(marker):]\n")
+	if driver != config.DriverMattN {
+		fmt.Fprintf(buf, "\t// The message ends with the result code, as in \" (2067)\".\n")
+		fmt.Fprintf(buf, "\tif idx := strings.LastIndex(detail, \" (\"); idx >= 0 && strings.Trim(detail[idx+2:], \"0123456789\") == \")\" {\n")
+		fmt.Fprintf(buf, "\t\tdetail = detail[:idx]\n")
+		fmt.Fprintf(buf, "\t}\n")
+	}
+	fmt.Fprintf(buf, "\treturn kind, detail\n")
+	fmt.Fprintf(buf, "}\n\n")
+}
+
+func writePostgresViolation(buf *strings.Builder) {
+	fmt.Fprintf(buf, "// constraintViolation returns the constraint err violates by its SQLSTATE.\n")
+	fmt.Fprintf(buf, "func constraintViolation(err error) (violation, string) {\n")
+	fmt.Fprintf(buf, "\tvar e *pgconn.PgError\n")
+	fmt.Fprintf(buf, "\tif !errors.As(err, &e) {\n")
+	fmt.Fprintf(buf, "\t\treturn violationNone, \"\"\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "\tswitch e.Code {\n")
+	fmt.Fprintf(buf, "\tcase \"23505\": // unique_violation\n")
+	fmt.Fprintf(buf, "\t\treturn violationUnique, e.ConstraintName\n")
+	fmt.Fprintf(buf, "\tcase \"23503\": // foreign_key_violation\n")
+	fmt.Fprintf(buf, "\t\treturn violationForeignKey, e.ConstraintName\n")
+	fmt.Fprintf(buf, "\tcase \"23514\": // check_violation\n")
+	fmt.Fprintf(buf, "\t\treturn violationCheck, e.ConstraintName\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "\treturn violationNone, \"\"\n")
+	fmt.Fprintf(buf, "}\n\n")
+}
+
+func writeMySQLViolation(buf *strings.Builder) {
+	fmt.Fprintf(buf, "// constraintViolation returns the constraint err violates by its error\n")
+	fmt.Fprintf(buf, "// number. MySQL names the constraint only in the message.\n")
+	fmt.Fprintf(buf, "func constraintViolation(err error) (violation, string) {\n")
+	fmt.Fprintf(buf, "\tvar e *mysql.MySQLError\n")
+	fmt.Fprintf(buf, "\tif !errors.As(err, &e) {\n")
+	fmt.Fprintf(buf, "\t\treturn violationNone, \"\"\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "\tswitch e.Number {\n")
+	fmt.Fprintf(buf, "\tcase 1062: // ER_DUP_ENTRY: Duplicate entry '1' for key 'users.email'\n")
+	fmt.Fprintf(buf, "\t\treturn violationUnique, quotedAfter(e.Message, \"for key '\", \"'\")\n")
+	fmt.Fprintf(buf, "\tcase 1451, 1452: // ER_ROW_IS_REFERENCED_2, ER_NO_REFERENCED_ROW_2\n")
+	fmt.Fprintf(buf, "\t\treturn violationForeignKey, quotedAfter(e.Message, \"CONSTRAINT `\", \"`\")\n")
+	fmt.Fprintf(buf, "\tcase 3819: // ER_CHECK_CONSTRAINT_VIOLATED\n")
+	fmt.Fprintf(buf, "\t\treturn violationCheck, quotedAfter(e.Message, \"constraint '\", \"'\")\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "\treturn violationNone, \"\"\n")
+	fmt.Fprintf(buf, "}\n\n")
+	fmt.Fprintf(buf, "// quotedAfter returns the text between the last open in msg and the next\n")
+	fmt.Fprintf(buf, "// closing quote.\n")
+	fmt.Fprintf(buf, "func quotedAfter(msg, open, quote string) string {\n")
+	fmt.Fprintf(buf, "\tidx := strings.LastIndex(msg, open)\n")
+	fmt.Fprintf(buf, "\tif idx < 0 {\n")
+	fmt.Fprintf(buf, "\t\treturn \"\"\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "\tname, _, _ := strings.Cut(msg[idx+len(open):], quote)\n")
+	fmt.Fprintf(buf, "\treturn name\n")
+	fmt.Fprintf(buf, "}\n\n")
+}
+
+// classifiedErrs returns the error operands through which the code of n hands
+// errors to its caller: the err of a return statement and the err passed to
+// a yield or f callback.
+func classifiedErrs(n goast.Node) []*goast.Expr {
+	var slots []*goast.Expr
+	isErr := func(expr goast.Expr) bool {
+		ident, ok := expr.(*goast.Ident)
+		return ok && ident.Name == "err"
+	}
+	goast.Inspect(n, func(node goast.Node) bool {
+		switch node := node.(type) {
+		case *goast.ReturnStmt:
+			if last := len(node.Results) - 1; last >= 0 && isErr(node.Results[last]) {
+				slots = append(slots, &node.Results[last])
+			}
+		case *goast.CallExpr:
+			fn, ok := node.Fun.(*goast.Ident)
+			if last := len(node.Args) - 1; ok && (fn.Name == "yield" || fn.Name == "f") && last >= 0 && isErr(node.Args[last]) {
+				slots = append(slots, &node.Args[last])
+			}
+		}
+		return true
+	})
+	return slots
+}
+
+// classifyErrs makes the code of n return its errors through ClassifyError.
+func classifyErrs(n goast.Node) {
+	for _, slot := range classifiedErrs(n) {
+		*slot = &goast.CallExpr{Fun: goast.NewIdent("ClassifyError"), Args: []goast.Expr{*slot}}
+	}
+}
+
+// classifyPreparedErrs rewrites the query methods of the PreparedQueries
+// source like classifyErrs.
+func classifyPreparedErrs(src []byte, methods map[string]struct{}) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	var offsets []int
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*goast.FuncDecl)
+		if !ok || fn.Recv == nil {
+			continue
+		}
+		if _, ok := methods[fn.Name.Name]; !ok {
+			continue
+		}
+		for _, slot := range classifiedErrs(fn.Body) {
+			offsets = append(offsets, fset.Position((*slot).Pos()).Offset)
+		}
+	}
+	slices.Sort(offsets)
+	out := make([]byte, 0, len(src)+len(offsets)*len("ClassifyError()"))
+	prev := 0
+	for _, off := range offsets {
+		out = append(out, src[prev:off]...)
+		out = append(out, "ClassifyError(err)"...)
+		prev = off + len("err")
+	}
+	return append(out, src[prev:]...), nil
+}
@@ -0,0 +1,373 @@
+package ast
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"strings"
+
+	"golang.org/x/tools/imports"
+
+	"github.com/electwix/db-catalyst/internal/config"
+)
+
+// sqliteTimeConst returns the generated constant naming the time encoding
+// of storage, or "" when the storage holds no time.
+func sqliteTimeConst(storage config.SQLiteStorage) string {
+	switch storage {
+	case config.StorageUnix:
+		return "sqliteUnix"
+	case config.StorageUnixMillis:
+		return "sqliteUnixMillis"
+	case config.StorageRFC3339:
+		return "sqliteRFC3339"
+	case config.StorageJulianDay:
+		return "sqliteJulianDay"
+	default:
+		return ""
+	}
+}
+
+// scanDest wraps the scan destination expr in the adapter decoding the
+// storage of a [sqlite_types] column. Times are decoded from their encoding
+// and JSON from TEXT, which database/sql cannot convert to json.RawMessage.
+func (b *Builder) scanDest(expr string, storage config.SQLiteStorage) string {
+	if enc := sqliteTimeConst(storage); enc != "" {
+		b.sqliteTime = true
+		return fmt.Sprintf("scanSQLiteTime(%s, %s)", expr, enc)
+	}
+	if storage == config.StorageJSON {
+		b.sqliteJSON = true
+		return fmt.Sprintf("scanSQLiteJSON(%s)", expr)
+	}
+	return expr
+}
+
+// bindValue wraps the value expr in the adapter encoding it for the storage
+// of a [sqlite_types] column. Times are stored in their encoding and JSON as
+// TEXT, since drivers store a []byte as BLOB.
+func (b *Builder) bindValue(expr string, storage config.SQLiteStorage) string {
+	if enc := sqliteTimeConst(storage); enc != "" {
+		b.sqliteTime = true
+		return fmt.Sprintf("bindSQLiteTime(%s, %s)", expr, enc)
+	}
+	if storage == config.StorageJSON {
+		b.sqliteJSON = true
+		return fmt.Sprintf("bindSQLiteJSON(%s)", expr)
+	}
+	return expr
+}
+
+// bindArg returns the query argument expr of p. Slices are passed as built
+// by the generated method, which binds each element itself.
+func (b *Builder) bindArg(expr string, p paramSpec) string {
+	if p.variadic || p.isDynamicSlice {
+		return expr
+	}
+	return b.bindValue(expr, p.storage)
+}
+
+// buildSQLiteTypesFile emits the adapters between [sqlite_types] values and
+// their storage: scanSQLiteTime and bindSQLiteTime convert times from and to
+// unix seconds, unix milliseconds, RFC 3339 text or Julian days, and
+// scanSQLiteJSON and bindSQLiteJSON convert JSON from and to TEXT.
+func (b *Builder) buildSQLiteTypesFile(pkg string) (File, error) {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	fmt.Fprintf(&buf, "import (\n")
+	fmt.Fprintf(&buf, "\t\"database/sql\"\n")
+	fmt.Fprintf(&buf, "\t\"database/sql/driver\"\n")
+	if b.sqliteJSON {
+		fmt.Fprintf(&buf, "\t\"encoding/json\"\n")
+	}
+	fmt.Fprintf(&buf, "\t\"fmt\"\n")
+	if b.sqliteTime {
+		fmt.Fprintf(&buf, "\t\"math\"\n")
+		fmt.Fprintf(&buf, "\t\"strconv\"\n")
+		fmt.Fprintf(&buf, "\t\"strings\"\n")
+		fmt.Fprintf(&buf, "\t\"time\"\n")
+	}
+	fmt.Fprintf(&buf, ")\n\n")
+
+	if b.sqliteTime {
+		writeSQLiteTimeAdapters(&buf, b.opts.SQLiteDriver != config.DriverMattN)
+	}
+	if b.sqliteJSON {
+		writeSQLiteJSONAdapter(&buf)
+	}
+
+	formatted, err := imports.Process("", []byte(buf.String()), nil)
+	if err != nil {
+		return File{}, err
+	}
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, "", formatted, parser.ParseComments)
+	if err != nil {
+		return File{}, err
+	}
+	return File{Path: "sqlite_types.gen.go", Node: node, Raw: formatted}, nil
+}
+
+// writeSQLiteTimeAdapters writes the time adapters. Times written by
+// modernc.org/sqlite, which uses time.Time.String unless the DSN sets
+// _time_format, are only parsed for that driver.
+func writeSQLiteTimeAdapters(buf *strings.Builder, modernc bool) {
+	fmt.Fprintf(buf, "// sqliteTimeEncoding is how a SQLite column stores a time.\n")
+	fmt.Fprintf(buf, "type sqliteTimeEncoding int\n\n")
+	fmt.Fprintf(buf, "const (\n")
+	fmt.Fprintf(buf, "\tsqliteUnix sqliteTimeEncoding = iota\n")
+	fmt.Fprintf(buf, "\tsqliteUnixMillis\n")
+	fmt.Fprintf(buf, "\tsqliteRFC3339\n")
+	fmt.Fprintf(buf, "\tsqliteJulianDay\n")
+	fmt.Fprintf(buf, ")\n\n")
+
+	fmt.Fprintf(buf, "// julianDayUnixEpoch is the Julian day of 1970-01-01 00:00:00 UTC.\n")
+	fmt.Fprintf(buf, "const julianDayUnixEpoch = 2440587.5\n\n")
+
+	fmt.Fprintf(buf, "// sqliteTimeLayouts are the text formats of times written by SQLite and\n")
+	fmt.Fprintf(buf, "// the driver.\n")
+	fmt.Fprintf(buf, "var sqliteTimeLayouts = []string{\n")
+	fmt.Fprintf(buf, "\ttime.RFC3339Nano,\n")
+	fmt.Fprintf(buf, "\t\"2006-01-02 15:04:05.999999999Z07:00\",\n")
+	if modernc {
+		fmt.Fprintf(buf, "\t\"2006-01-02 15:04:05.999999999 -0700 MST\",\n")
+	}
+	fmt.Fprintf(buf, "\t\"2006-01-02T15:04:05.999999999\",\n")
+	fmt.Fprintf(buf, "\t\"2006-01-02 15:04:05.999999999\",\n")
+	fmt.Fprintf(buf, "\t\"2006-01-02 15:04\",\n")
+	fmt.Fprintf(buf, "\t\"2006-01-02T15:04\",\n")
+	fmt.Fprintf(buf, "\t\"2006-01-02\",\n")
+	fmt.Fprintf(buf, "}\n\n")
+
+	fmt.Fprintf(buf, "func (e sqliteTimeEncoding) encode(t time.Time) driver.Value {\n")
+	fmt.Fprintf(buf, "\tswitch e {\n")
+	fmt.Fprintf(buf, "\tcase sqliteUnix:\n")
+	fmt.Fprintf(buf, "\t\treturn t.Unix()\n")
+	fmt.Fprintf(buf, "\tcase sqliteUnixMillis:\n")
+	fmt.Fprintf(buf, "\t\treturn t.UnixMilli()\n")
+	fmt.Fprintf(buf, "\tcase sqliteJulianDay:\n")
+	fmt.Fprintf(buf, "\t\treturn float64(t.UnixMilli())/86400000 + julianDayUnixEpoch\n")
+	fmt.Fprintf(buf, "\tdefault:\n")
+	fmt.Fprintf(buf, "\t\treturn t.UTC().Format(time.RFC3339Nano)\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "}\n\n")
+
+	fmt.Fprintf(buf, "func (e sqliteTimeEncoding) decode(src any) (time.Time, error) {\n")
+	fmt.Fprintf(buf, "\tswitch v := src.(type) {\n")
+	fmt.Fprintf(buf, "\tcase time.Time:\n")
+	fmt.Fprintf(buf, "\t\treturn v, nil\n")
+	fmt.Fprintf(buf, "\tcase int64:\n")
+	fmt.Fprintf(buf, "\t\tswitch e {\n")
+	fmt.Fprintf(buf, "\t\tcase sqliteUnixMillis:\n")
+	fmt.Fprintf(buf, "\t\t\treturn time.UnixMilli(v), nil\n")
+	fmt.Fprintf(buf, "\t\tcase sqliteJulianDay:\n")
+	fmt.Fprintf(buf, "\t\t\treturn e.fromFloat(float64(v)), nil\n")
+	fmt.Fprintf(buf, "\t\tdefault:\n")
+	fmt.Fprintf(buf, "\t\t\treturn time.Unix(v, 0), nil\n")
+	fmt.Fprintf(buf, "\t\t}\n")
+	fmt.Fprintf(buf, "\tcase float64:\n")
+	fmt.Fprintf(buf, "\t\treturn e.fromFloat(v), nil\n")
+	fmt.Fprintf(buf, "\tcase []byte:\n")
+	fmt.Fprintf(buf, "\t\treturn e.parse(string(v))\n")
+	fmt.Fprintf(buf, "\tcase string:\n")
+	fmt.Fprintf(buf, "\t\treturn e.parse(v)\n")
+	fmt.Fprintf(buf, "\tdefault:\n")
+	fmt.Fprintf(buf, "\t\treturn time.Time{}, fmt.Errorf(\"cannot scan %%T into time.Time\", src)\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "}\n\n")
+
+	fmt.Fprintf(buf, "func (e sqliteTimeEncoding) fromFloat(v float64) time.Time {\n")
+	fmt.Fprintf(buf, "\tswitch e {\n")
+	fmt.Fprintf(buf, "\tcase sqliteUnixMillis:\n")
+	fmt.Fprintf(buf, "\t\treturn time.UnixMilli(int64(math.Round(v)))\n")
+	fmt.Fprintf(buf, "\tcase sqliteJulianDay:\n")
+	fmt.Fprintf(buf, "\t\treturn time.UnixMilli(int64(math.Round((v - julianDayUnixEpoch) * 86400000)))\n")
+	fmt.Fprintf(buf, "\tdefault:\n")
+	fmt.Fprintf(buf, "\t\treturn time.UnixMilli(int64(math.Round(v * 1000)))\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "}\n\n")
+
+	fmt.Fprintf(buf, "// parse reads a time stored as text, which may also hold a number.\n")
+	fmt.Fprintf(buf, "func (e sqliteTimeEncoding) parse(s string) (time.Time, error) {\n")
+	if modernc {
+		fmt.Fprintf(buf, "\t// time.Time.String appends the monotonic clock reading\n")
+		fmt.Fprintf(buf, "\tif i := strings.Index(s, \" m=\"); i >= 0 {\n")
+		fmt.Fprintf(buf, "\t\ts = s[:i]\n")
+		fmt.Fprintf(buf, "\t}\n")
+	}
+	fmt.Fprintf(buf, "\tfor _, layout := range sqliteTimeLayouts {\n")
+	fmt.Fprintf(buf, "\t\tif t, err := time.Parse(layout, s); err == nil {\n")
+	fmt.Fprintf(buf, "\t\t\treturn t, nil\n")
+	fmt.Fprintf(buf, "\t\t}\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "\tif v, err := strconv.ParseFloat(s, 64); err == nil {\n")
+	fmt.Fprintf(buf, "\t\treturn e.fromFloat(v), nil\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "\treturn time.Time{}, fmt.Errorf(\"cannot parse %%q as time.Time\", s)\n")
+	fmt.Fprintf(buf, "}\n\n")
+
+	fmt.Fprintf(buf, "type sqliteTimeScanner struct {\n")
+	fmt.Fprintf(buf, "\tdest any\n")
+	fmt.Fprintf(buf, "\tenc  sqliteTimeEncoding\n")
+	fmt.Fprintf(buf, "}\n\n")
+
+	fmt.Fprintf(buf, "// scanSQLiteTime scans a time stored with enc into dest, a *time.Time,\n")
+	fmt.Fprintf(buf, "// a **time.Time or a sql.Scanner of nullable times.\n")
+	fmt.Fprintf(buf, "func scanSQLiteTime(dest any, enc sqliteTimeEncoding) sql.Scanner {\n")
+	fmt.Fprintf(buf, "\treturn sqliteTimeScanner{dest: dest, enc: enc}\n")
+	fmt.Fprintf(buf, "}\n\n")
+
+	fmt.Fprintf(buf, "// Scan implements sql.Scanner.\n")
+	fmt.Fprintf(buf, "func (s sqliteTimeScanner) Scan(src any) error {\n")
+	fmt.Fprintf(buf, "\tif src == nil {\n")
+	fmt.Fprintf(buf, "\t\tswitch d := s.dest.(type) {\n")
+	fmt.Fprintf(buf, "\t\tcase **time.Time:\n")
+	fmt.Fprintf(buf, "\t\t\t*d = nil\n")
+	fmt.Fprintf(buf, "\t\t\treturn nil\n")
+	fmt.Fprintf(buf, "\t\tcase sql.Scanner:\n")
+	fmt.Fprintf(buf, "\t\t\treturn d.Scan(nil)\n")
+	fmt.Fprintf(buf, "\t\tdefault:\n")
+	fmt.Fprintf(buf, "\t\t\treturn fmt.Errorf(\"cannot scan NULL into %%T\", s.dest)\n")
+	fmt.Fprintf(buf, "\t\t}\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "\tt, err := s.enc.decode(src)\n")
+	fmt.Fprintf(buf, "\tif err != nil {\n")
+	fmt.Fprintf(buf, "\t\treturn err\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "\tswitch d := s.dest.(type) {\n")
+	fmt.Fprintf(buf, "\tcase *time.Time:\n")
+	fmt.Fprintf(buf, "\t\t*d = t\n")
+	fmt.Fprintf(buf, "\t\treturn nil\n")
+	fmt.Fprintf(buf, "\tcase **time.Time:\n")
+	fmt.Fprintf(buf, "\t\t*d = &t\n")
+	fmt.Fprintf(buf, "\t\treturn nil\n")
+	fmt.Fprintf(buf, "\tcase sql.Scanner:\n")
+	fmt.Fprintf(buf, "\t\treturn d.Scan(t)\n")
+	fmt.Fprintf(buf, "\tdefault:\n")
+	fmt.Fprintf(buf, "\t\treturn fmt.Errorf(\"cannot scan time.Time into %%T\", s.dest)\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "}\n\n")
+
+	fmt.Fprintf(buf, "type sqliteTimeValuer struct {\n")
+	fmt.Fprintf(buf, "\tv   any\n")
+	fmt.Fprintf(buf, "\tenc sqliteTimeEncoding\n")
+	fmt.Fprintf(buf, "}\n\n")
+
+	fmt.Fprintf(buf, "// bindSQLiteTime binds v, a time.Time, a *time.Time or a driver.Valuer of\n")
+	fmt.Fprintf(buf, "// nullable times, in the storage of enc.\n")
+	fmt.Fprintf(buf, "func bindSQLiteTime(v any, enc sqliteTimeEncoding) driver.Valuer {\n")
+	fmt.Fprintf(buf, "\treturn sqliteTimeValuer{v: v, enc: enc}\n")
+	fmt.Fprintf(buf, "}\n\n")
+
+	fmt.Fprintf(buf, "// Value implements driver.Valuer.\n")
+	fmt.Fprintf(buf, "func (s sqliteTimeValuer) Value() (driver.Value, error) {\n")
+	fmt.Fprintf(buf, "\tv := s.v\n")
+	fmt.Fprintf(buf, "\tif valuer, ok := v.(driver.Valuer); ok {\n")
+	fmt.Fprintf(buf, "\t\tvar err error\n")
+	fmt.Fprintf(buf, "\t\tif v, err = valuer.Value(); err != nil {\n")
+	fmt.Fprintf(buf, "\t\t\treturn nil, err\n")
+	fmt.Fprintf(buf, "\t\t}\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "\tswitch t := v.(type) {\n")
+	fmt.Fprintf(buf, "\tcase nil:\n")
+	fmt.Fprintf(buf, "\t\treturn nil, nil\n")
+	fmt.Fprintf(buf, "\tcase time.Time:\n")
+	fmt.Fprintf(buf, "\t\treturn s.enc.encode(t), nil\n")
+	fmt.Fprintf(buf, "\tcase *time.Time:\n")
+	fmt.Fprintf(buf, "\t\tif t == nil {\n")
+	fmt.Fprintf(buf, "\t\t\treturn nil, nil\n")
+	fmt.Fprintf(buf, "\t\t}\n")
+	fmt.Fprintf(buf, "\t\treturn s.enc.encode(*t), nil\n")
+	fmt.Fprintf(buf, "\tdefault:\n")
+	fmt.Fprintf(buf, "\t\treturn nil, fmt.Errorf(\"cannot bind %%T as time.Time\", v)\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "}\n\n")
+}
+
+func writeSQLiteJSONAdapter(buf *strings.Builder) {
+	fmt.Fprintf(buf, "type sqliteJSONScanner struct {\n")
+	fmt.Fprintf(buf, "\tdest any\n")
+	fmt.Fprintf(buf, "}\n\n")
+
+	fmt.Fprintf(buf, "// scanSQLiteJSON scans JSON stored as TEXT or BLOB into dest, a\n")
+	fmt.Fprintf(buf, "// *json.RawMessage, a **json.RawMessage or a sql.Scanner of nullable JSON.\n")
+	fmt.Fprintf(buf, "func scanSQLiteJSON(dest any) sql.Scanner {\n")
+	fmt.Fprintf(buf, "\treturn sqliteJSONScanner{dest: dest}\n")
+	fmt.Fprintf(buf, "}\n\n")
+
+	fmt.Fprintf(buf, "// Scan implements sql.Scanner.\n")
+	fmt.Fprintf(buf, "func (s sqliteJSONScanner) Scan(src any) error {\n")
+	fmt.Fprintf(buf, "\tvar data json.RawMessage\n")
+	fmt.Fprintf(buf, "\tswitch v := src.(type) {\n")
+	fmt.Fprintf(buf, "\tcase nil:\n")
+	fmt.Fprintf(buf, "\tcase string:\n")
+	fmt.Fprintf(buf, "\t\tdata = json.RawMessage(v)\n")
+	fmt.Fprintf(buf, "\tcase []byte:\n")
+	fmt.Fprintf(buf, "\t\tdata = append(json.RawMessage{}, v...)\n")
+	fmt.Fprintf(buf, "\tdefault:\n")
+	fmt.Fprintf(buf, "\t\treturn fmt.Errorf(\"cannot scan %%T into json.RawMessage\", src)\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "\tswitch d := s.dest.(type) {\n")
+	fmt.Fprintf(buf, "\tcase *json.RawMessage:\n")
+	fmt.Fprintf(buf, "\t\t*d = data\n")
+	fmt.Fprintf(buf, "\tcase **json.RawMessage:\n")
+	fmt.Fprintf(buf, "\t\tif data == nil {\n")
+	fmt.Fprintf(buf, "\t\t\t*d = nil\n")
+	fmt.Fprintf(buf, "\t\t} else {\n")
+	fmt.Fprintf(buf, "\t\t\t*d = &data\n")
+	fmt.Fprintf(buf, "\t\t}\n")
+	fmt.Fprintf(buf, "\tcase sql.Scanner:\n")
+	fmt.Fprintf(buf, "\t\tif data == nil {\n")
+	fmt.Fprintf(buf, "\t\t\treturn d.Scan(nil)\n")
+	fmt.Fprintf(buf, "\t\t}\n")
+	fmt.Fprintf(buf, "\t\treturn d.Scan([]byte(data))\n")
+	fmt.Fprintf(buf, "\tdefault:\n")
+	fmt.Fprintf(buf, "\t\treturn fmt.Errorf(\"cannot scan json.RawMessage into %%T\", s.dest)\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "\treturn nil\n")
+	fmt.Fprintf(buf, "}\n\n")
+
+	fmt.Fprintf(buf, "type sqliteJSONValuer struct {\n")
+	fmt.Fprintf(buf, "\tv any\n")
+	fmt.Fprintf(buf, "}\n\n")
+
+	fmt.Fprintf(buf, "// bindSQLiteJSON binds JSON as TEXT, which drivers would otherwise store\n")
+	fmt.Fprintf(buf, "// as a BLOB.\n")
+	fmt.Fprintf(buf, "func bindSQLiteJSON(v any) driver.Valuer {\n")
+	fmt.Fprintf(buf, "\treturn sqliteJSONValuer{v: v}\n")
+	fmt.Fprintf(buf, "}\n\n")
+
+	fmt.Fprintf(buf, "// Value implements driver.Valuer.\n")
+	fmt.Fprintf(buf, "func (s sqliteJSONValuer) Value() (driver.Value, error) {\n")
+	fmt.Fprintf(buf, "\tv := s.v\n")
+	fmt.Fprintf(buf, "\tif valuer, ok := v.(driver.Valuer); ok {\n")
+	fmt.Fprintf(buf, "\t\tvar err error\n")
+	fmt.Fprintf(buf, "\t\tif v, err = valuer.Value(); err != nil {\n")
+	fmt.Fprintf(buf, "\t\t\treturn nil, err\n")
+	fmt.Fprintf(buf, "\t\t}\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "\tswitch data := v.(type) {\n")
+	fmt.Fprintf(buf, "\tcase nil:\n")
+	fmt.Fprintf(buf, "\t\treturn nil, nil\n")
+	fmt.Fprintf(buf, "\tcase json.RawMessage:\n")
+	fmt.Fprintf(buf, "\t\tif data == nil {\n")
+	fmt.Fprintf(buf, "\t\t\treturn nil, nil\n")
+	fmt.Fprintf(buf, "\t\t}\n")
+	fmt.Fprintf(buf, "\t\treturn string(data), nil\n")
+	fmt.Fprintf(buf, "\tcase *json.RawMessage:\n")
+	fmt.Fprintf(buf, "\t\tif data == nil || *data == nil {\n")
+	fmt.Fprintf(buf, "\t\t\treturn nil, nil\n")
+	fmt.Fprintf(buf, "\t\t}\n")
+	fmt.Fprintf(buf, "\t\treturn string(*data), nil\n")
+	fmt.Fprintf(buf, "\tcase []byte:\n")
+	fmt.Fprintf(buf, "\t\tif data == nil {\n")
+	fmt.Fprintf(buf, "\t\t\treturn nil, nil\n")
+	fmt.Fprintf(buf, "\t\t}\n")
+	fmt.Fprintf(buf, "\t\treturn string(data), nil\n")
+	fmt.Fprintf(buf, "\tcase string:\n")
+	fmt.Fprintf(buf, "\t\treturn data, nil\n")
+	fmt.Fprintf(buf, "\tdefault:\n")
+	fmt.Fprintf(buf, "\t\treturn nil, fmt.Errorf(\"cannot bind %%T as JSON\", v)\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "}\n")
+}
@@ -19,10 +19,11 @@ type TypeInfo struct {
 
 // TypeResolver handles mapping between SQL types and Go types.
 type TypeResolver struct {
-	transformer         *transform.Transformer
-	emitPointersForNull bool
-	database            config.Database
-	customTypes         map[string]config.CustomTypeMapping
+	transformer *transform.Transformer
+	nullStyle   config.NullStyle
+	nullOption  config.GoTypeDetails
+	database    config.Database
+	customTypes map[string]config.CustomTypeMapping
 }
 
 // NewTypeResolver creates a new TypeResolver with optional custom type support.
@@ -50,20 +51,58 @@ func NewTypeResolverWithDatabase(transformer *transform.Transformer, database co
 	return resolver
 }
 
-// NewTypeResolverWithOptions creates a TypeResolver with all options configured.
+// NewTypeResolverWithOptions creates a SQLite TypeResolver that uses pointers
+// for nullable values when emitPointersForNull is set.
+//
+// Deprecated: Use NewTypeResolverFull with config.NullStylePointer instead.
 func NewTypeResolverWithOptions(transformer *transform.Transformer, emitPointersForNull bool) *TypeResolver {
 	resolver := NewTypeResolverWithDatabase(transformer, config.DatabaseSQLite)
-	resolver.emitPointersForNull = emitPointersForNull
+	if emitPointersForNull {
+		resolver.nullStyle = config.NullStylePointer
+	}
 	return resolver
 }
 
-// NewTypeResolverFull creates a TypeResolver with all options.
-func NewTypeResolverFull(transformer *transform.Transformer, database config.Database, emitPointersForNull bool) *TypeResolver {
+// NewTypeResolverFull creates a TypeResolver with all options. option is the
+// generic type of config.NullStyleOption.
+func NewTypeResolverFull(transformer *transform.Transformer, database config.Database, nullStyle config.NullStyle, option config.GoTypeDetails) *TypeResolver {
 	resolver := NewTypeResolverWithDatabase(transformer, database)
-	resolver.emitPointersForNull = emitPointersForNull
+	resolver.nullStyle = nullStyle
+	resolver.nullOption = option
 	return resolver
 }
 
+// NullStyle returns the style of nullable values, defaulting to pgtype types
+// for PostgreSQL and the sql.Null* types otherwise.
+func (r *TypeResolver) NullStyle() config.NullStyle {
+	switch {
+	case r.nullStyle != "":
+		return r.nullStyle
+	case r.database == config.DatabasePostgreSQL:
+		return config.NullStylePgtype
+	default:
+		return config.NullStyleSQLNull
+	}
+}
+
+// Nullable returns the type of a nullable value of the Go type base in
+// style, or in the resolver's style when style is empty.
+func (r *TypeResolver) Nullable(base string, style config.NullStyle) TypeInfo {
+	if style == "" {
+		style = r.NullStyle()
+	}
+	nt := types.Nullable(base, style, r.nullOption)
+	info := TypeInfo{GoType: nt.GoType, UsesSQLNull: nt.UsesSQLNull, Import: nt.Import, Package: nt.Package}
+	// A wrapped type keeps its own import; database/sql is always found and
+	// the builder imports the option type.
+	if nt.GoType != base && strings.Contains(nt.GoType, base) {
+		if baseInfo := withKnownImport(TypeInfo{GoType: base}); baseInfo.Import != "" {
+			info.Import, info.Package = baseInfo.Import, baseInfo.Package
+		}
+	}
+	return info
+}
+
 // findCustomMappingBySQLType looks up a custom type mapping by SQL type
 func (r *TypeResolver) findCustomMappingBySQLType(sqlType string) *config.CustomTypeMapping {
 	if r.transformer == nil {
@@ -83,38 +122,91 @@ func (r *TypeResolver) findCustomMappingBySQLType(sqlType string) *config.Custom
 
 // ResolveType determines the Go type for a given SQL type or existing Go type.
 func (r *TypeResolver) ResolveType(typeOrSQLType string, nullable bool) TypeInfo {
+	return r.ResolveTypeWithStyle(typeOrSQLType, nullable, "")
+}
+
+// ResolveTypeWithStyle is ResolveType with nullable values represented in
+// style, such as the null_style of a column override. An empty style means
+// the resolver's style.
+func (r *TypeResolver) ResolveTypeWithStyle(typeOrSQLType string, nullable bool, style config.NullStyle) TypeInfo {
+	// JSON override wrappers decode NULL themselves.
+	if strings.HasPrefix(typeOrSQLType, "JSON[") {
+		return TypeInfo{GoType: typeOrSQLType, UsesSQLNull: false}
+	}
+
 	// Check if this is already a Go type (contains package qualifiers like "example.IDWrap")
-	if info, ok := r.resolveGoType(typeOrSQLType, nullable); ok {
+	if info, ok := r.resolveGoType(typeOrSQLType, nullable, style); ok {
 		return info
 	}
 
 	// This is a SQL type, check if it has a custom type mapping
-	if info, ok := r.resolveCustomType(typeOrSQLType, nullable); ok {
+	if info, ok := r.resolveCustomTypeWithStyle(typeOrSQLType, nullable, style); ok {
 		return info
 	}
 
 	// Handle standard SQL types based on database
 	goType := r.sqlTypeToGo(typeOrSQLType)
-	return r.resolveStandardType(goType, nullable)
+	return r.resolveStandardTypeWithStyle(goType, nullable, style)
+}
+
+// knownImports maps the packages of the Go types SQL types resolve to onto
+// their import paths, so generated code does not depend on goimports finding
+// the right package, such as pgtype of pgx/v5 rather than an older pgx.
+var knownImports = map[string]string{
+	"pgtype":  "github.com/jackc/pgx/v5/pgtype",
+	"uuid":    "github.com/google/uuid",
+	"decimal": "github.com/shopspring/decimal",
+}
+
+// withKnownImport sets the import of info when its type is from one of the
+// known packages.
+func withKnownImport(info TypeInfo) TypeInfo {
+	if info.Import != "" {
+		return info
+	}
+	pkg, _, ok := strings.Cut(strings.TrimLeft(info.GoType, "*[]"), ".")
+	if !ok {
+		return info
+	}
+	if path, ok := knownImports[pkg]; ok {
+		info.Import, info.Package = path, pkg
+	}
+	return info
 }
 
 // resolveGoType handles types that are already Go types (with package qualifiers).
 // Note: Primitive Go types like "string", "int" are NOT handled here - they go through
 // sqlTypeToGo and resolveNullableType for database-specific null handling.
-func (r *TypeResolver) resolveGoType(typeOrSQLType string, nullable bool) (TypeInfo, bool) {
+func (r *TypeResolver) resolveGoType(typeOrSQLType string, nullable bool, style config.NullStyle) (TypeInfo, bool) {
+	// Slices and maps, such as the []int64 of array_agg, hold NULL as nil.
+	if strings.HasPrefix(typeOrSQLType, "[]") || strings.HasPrefix(typeOrSQLType, "map[") {
+		return withKnownImport(TypeInfo{GoType: typeOrSQLType}), true
+	}
 	// Only handle types with package qualifiers (contain ".") or pointers
 	if !strings.Contains(typeOrSQLType, ".") && !strings.HasPrefix(typeOrSQLType, "*") {
 		return TypeInfo{}, false
 	}
-	goType := typeOrSQLType
-	if nullable && !strings.HasPrefix(goType, "*") {
-		goType = "*" + goType
+	info := withKnownImport(TypeInfo{GoType: typeOrSQLType})
+	if !nullable {
+		return info, true
 	}
-	return TypeInfo{GoType: goType, UsesSQLNull: false}, true
+	// Qualified types such as time.Time may be replaced by the style's null
+	// type; wrapped types keep their own import.
+	nullInfo := r.Nullable(typeOrSQLType, style)
+	if info.Import != "" && strings.Contains(nullInfo.GoType, typeOrSQLType) {
+		nullInfo.Import, nullInfo.Package = info.Import, info.Package
+	}
+	return nullInfo, true
 }
 
 // resolveCustomType handles custom type mappings.
 func (r *TypeResolver) resolveCustomType(sqlType string, nullable bool) (TypeInfo, bool) {
+	return r.resolveCustomTypeWithStyle(sqlType, nullable, "")
+}
+
+// resolveCustomTypeWithStyle handles custom type mappings, representing
+// nullable values in style.
+func (r *TypeResolver) resolveCustomTypeWithStyle(sqlType string, nullable bool, style config.NullStyle) (TypeInfo, bool) {
 	if r.transformer == nil {
 		return TypeInfo{}, false
 	}
@@ -126,8 +218,7 @@ func (r *TypeResolver) resolveCustomType(sqlType string, nullable bool) (TypeInf
 		if err != nil {
 			return TypeInfo{GoType: "any", UsesSQLNull: false}, true
 		}
-		goType = r.applyNullability(goType, isPointer, nullable)
-		return r.buildCustomTypeInfo(mapping, goType)
+		return r.buildCustomTypeInfo(mapping, goType, isPointer, nullable, style)
 	}
 
 	// Skip standard SQLite types - they should not map to custom types
@@ -146,37 +237,39 @@ func (r *TypeResolver) resolveCustomType(sqlType string, nullable bool) (TypeInf
 		return TypeInfo{GoType: "any", UsesSQLNull: false}, true
 	}
 
-	goType = r.applyNullability(goType, isPointer, nullable)
-	return r.buildCustomTypeInfo(customMapping, goType)
+	return r.buildCustomTypeInfo(customMapping, goType, isPointer, nullable, style)
 }
 
-// applyNullability adds pointer for nullable types.
-func (r *TypeResolver) applyNullability(goType string, isPointer, nullable bool) string {
+// applyNullability returns goType as a pointer when the mapping asks for
+// one, or as a nullable value in style when nullable is set.
+func (r *TypeResolver) applyNullability(goType string, isPointer, nullable bool, style config.NullStyle) TypeInfo {
 	if isPointer && !strings.HasPrefix(goType, "*") {
-		return "*" + goType
+		return TypeInfo{GoType: "*" + goType}
 	}
-	if nullable && !strings.HasPrefix(goType, "*") {
-		return "*" + goType
+	if nullable {
+		info := r.Nullable(goType, style)
+		// Custom types have no sql.Null* or pgtype counterpart.
+		if info.Import == "" || info.UsesSQLNull {
+			info.Import, info.Package = "", ""
+		}
+		return info
 	}
-	return goType
+	return TypeInfo{GoType: goType}
 }
 
 // buildCustomTypeInfo creates TypeInfo for custom types with import info.
-func (r *TypeResolver) buildCustomTypeInfo(mapping *config.CustomTypeMapping, goType string) (TypeInfo, bool) {
+func (r *TypeResolver) buildCustomTypeInfo(mapping *config.CustomTypeMapping, goType string, isPointer, nullable bool, style config.NullStyle) (TypeInfo, bool) {
 	importPath, packageName, err := r.transformer.GetImportsForCustomType(mapping.CustomType)
 	if err != nil {
-		return TypeInfo{GoType: goType, UsesSQLNull: false}, true
+		return r.applyNullability(goType, isPointer, nullable, style), true
 	}
 
 	// Prefix the type with its package name for proper qualification
-	qualifiedType := packageName + "." + goType
-
-	return TypeInfo{
-		GoType:      qualifiedType,
-		UsesSQLNull: false,
-		Import:      importPath,
-		Package:     packageName,
-	}, true
+	info := r.applyNullability(packageName+"."+strings.TrimPrefix(goType, "*"), isPointer || strings.HasPrefix(goType, "*"), nullable, style)
+	if info.Import == "" {
+		info.Import, info.Package = importPath, packageName
+	}
+	return info, true
 }
 
 // sqlTypeToGo converts SQL type to Go type based on database dialect.
@@ -204,39 +297,10 @@ func (r *TypeResolver) sqlTypeToGo(sqlType string) string {
 	}
 }
 
-// sqliteTypeToGo converts SQLite type to Go type.
+// sqliteTypeToGo converts SQLite type to Go type with the mapping the
+// analyzer types query results with.
 func (r *TypeResolver) sqliteTypeToGo(sqlType string) string {
-	upperType := strings.ToUpper(sqlType)
-
-	switch {
-	case strings.Contains(upperType, "INT"):
-		switch {
-		case strings.Contains(upperType, "BIGINT"):
-			return "int64"
-		case strings.Contains(upperType, "SMALLINT"):
-			return "int16"
-		case strings.Contains(upperType, "TINYINT"):
-			return "int8"
-		default:
-			return "int32"
-		}
-	case strings.Contains(upperType, "TEXT"), strings.Contains(upperType, "CHAR"), strings.Contains(upperType, "VARCHAR"):
-		return "string"
-	case strings.Contains(upperType, "BLOB"):
-		return "[]byte"
-	case strings.Contains(upperType, "REAL"), strings.Contains(upperType, "FLOAT"), strings.Contains(upperType, "DOUBLE"):
-		return "float64"
-	case strings.Contains(upperType, "BOOLEAN"), strings.Contains(upperType, "BOOL"):
-		return "bool"
-	case strings.Contains(upperType, "NUMERIC"), strings.Contains(upperType, "DECIMAL"):
-		return "float64"
-	case strings.Contains(upperType, "DATETIME"), strings.Contains(upperType, "TIMESTAMP"):
-		return "time.Time"
-	case strings.Contains(upperType, "DATE"):
-		return "time.Time"
-	default:
-		return "any"
-	}
+	return types.SQLiteGoType(sqlType)
 }
 
 // postgresTypeToGo converts PostgreSQL type to Go type.
@@ -290,7 +354,7 @@ func (r *TypeResolver) postgresTypeToGo(sqlType string) string {
 	case strings.Contains(upperType, "UUID"):
 		return "uuid.UUID"
 	case strings.Contains(upperType, "JSON") || strings.Contains(upperType, "JSONB"):
-		return "[]byte"
+		return "json.RawMessage"
 	case strings.Contains(upperType, "XML"):
 		return "string"
 
@@ -308,9 +372,37 @@ func (r *TypeResolver) postgresTypeToGo(sqlType string) string {
 
 // mysqlTypeToGo converts MySQL type to Go type.
 func (r *TypeResolver) mysqlTypeToGo(sqlType string) string {
-	// For now, use SQLite mappings as base for MySQL
-	// This can be expanded later
-	return r.sqliteTypeToGo(sqlType)
+	upperType := strings.ToUpper(sqlType)
+
+	switch {
+	case strings.Contains(upperType, "INT"):
+		switch {
+		case strings.Contains(upperType, "BIGINT"):
+			return "int64"
+		case strings.Contains(upperType, "SMALLINT"):
+			return "int16"
+		case strings.Contains(upperType, "TINYINT"):
+			return "int8"
+		default:
+			return "int32"
+		}
+	case strings.Contains(upperType, "TEXT"), strings.Contains(upperType, "CHAR"), strings.Contains(upperType, "VARCHAR"):
+		return "string"
+	case strings.Contains(upperType, "BLOB"):
+		return "[]byte"
+	case strings.Contains(upperType, "REAL"), strings.Contains(upperType, "FLOAT"), strings.Contains(upperType, "DOUBLE"):
+		return "float64"
+	case strings.Contains(upperType, "BOOLEAN"), strings.Contains(upperType, "BOOL"):
+		return "bool"
+	case strings.Contains(upperType, "NUMERIC"), strings.Contains(upperType, "DECIMAL"):
+		return "float64"
+	case strings.Contains(upperType, "DATETIME"), strings.Contains(upperType, "TIMESTAMP"):
+		return "time.Time"
+	case strings.Contains(upperType, "DATE"):
+		return "time.Time"
+	default:
+		return "any"
+	}
 }
 
 // isStandardSQLiteType checks if a type is a standard SQLite type (not a custom type).
@@ -334,89 +426,22 @@ func (r *TypeResolver) isStandardSQLiteType(sqlType string) bool {
 
 // resolveStandardType determines null handling for standard types.
 func (r *TypeResolver) resolveStandardType(goType string, nullable bool) TypeInfo {
+	return r.resolveStandardTypeWithStyle(goType, nullable, "")
+}
+
+// resolveStandardTypeWithStyle determines null handling for standard types,
+// representing nullable values in style.
+func (r *TypeResolver) resolveStandardTypeWithStyle(goType string, nullable bool, style config.NullStyle) TypeInfo {
 	base := strings.TrimSpace(goType)
 	if base == "" {
 		base = "any"
 	}
 
 	if nullable {
-		return r.resolveNullableType(base)
-	}
-
-	return TypeInfo{GoType: base, UsesSQLNull: strings.HasPrefix(base, "sql.Null")}
-}
-
-// resolveNullableType handles nullable type resolution based on database.
-func (r *TypeResolver) resolveNullableType(base string) TypeInfo {
-	// Never wrap 'any' with a pointer - it's already the most generic nullable type
-	if base == "any" {
-		return TypeInfo{GoType: "any", UsesSQLNull: false}
-	}
-
-	if r.emitPointersForNull {
-		// Use pointer types instead of sql.Null*/pgtype
-		if !strings.HasPrefix(base, "*") {
-			return TypeInfo{GoType: "*" + base, UsesSQLNull: false}
-		}
-		return TypeInfo{GoType: base, UsesSQLNull: false}
+		return withKnownImport(r.Nullable(base, style))
 	}
 
-	// Use database-specific null types
-	switch r.database {
-	case config.DatabasePostgreSQL:
-		return r.resolvePostgresNullableType(base)
-	default:
-		return r.resolveSQLiteNullableType(base)
-	}
-}
-
-// resolveSQLiteNullableType handles SQLite nullable types.
-func (r *TypeResolver) resolveSQLiteNullableType(base string) TypeInfo {
-	switch base {
-	case "int64":
-		return TypeInfo{GoType: "sql.NullInt64", UsesSQLNull: true}
-	case "float64":
-		return TypeInfo{GoType: "sql.NullFloat64", UsesSQLNull: true}
-	case "string":
-		return TypeInfo{GoType: "sql.NullString", UsesSQLNull: true}
-	case "bool":
-		return TypeInfo{GoType: "sql.NullBool", UsesSQLNull: true}
-	case "time.Time":
-		return TypeInfo{GoType: "sql.NullTime", UsesSQLNull: true}
-	case "any":
-		// Don't wrap 'any' with a pointer - it's already the most generic type
-		return TypeInfo{GoType: "any", UsesSQLNull: false}
-	default:
-		// For custom types or blobs, use pointer
-		if !strings.HasPrefix(base, "*") {
-			return TypeInfo{GoType: "*" + base, UsesSQLNull: false}
-		}
-	}
-	return TypeInfo{GoType: base, UsesSQLNull: strings.HasPrefix(base, "sql.Null")}
-}
-
-// resolvePostgresNullableType handles PostgreSQL nullable types.
-func (r *TypeResolver) resolvePostgresNullableType(base string) TypeInfo {
-	switch base {
-	case "int32":
-		return TypeInfo{GoType: "pgtype.Int4", UsesSQLNull: false, Import: "github.com/jackc/pgx/v5/pgtype", Package: "pgtype"}
-	case "int64":
-		return TypeInfo{GoType: "pgtype.Int8", UsesSQLNull: false, Import: "github.com/jackc/pgx/v5/pgtype", Package: "pgtype"}
-	case "float64":
-		return TypeInfo{GoType: "pgtype.Float8", UsesSQLNull: false, Import: "github.com/jackc/pgx/v5/pgtype", Package: "pgtype"}
-	case "string":
-		return TypeInfo{GoType: "pgtype.Text", UsesSQLNull: false, Import: "github.com/jackc/pgx/v5/pgtype", Package: "pgtype"}
-	case "bool":
-		return TypeInfo{GoType: "pgtype.Bool", UsesSQLNull: false, Import: "github.com/jackc/pgx/v5/pgtype", Package: "pgtype"}
-	case "time.Time":
-		return TypeInfo{GoType: "pgtype.Timestamptz", UsesSQLNull: false, Import: "github.com/jackc/pgx/v5/pgtype", Package: "pgtype"}
-	default:
-		// For custom types or other types, use pointer
-		if !strings.HasPrefix(base, "*") {
-			return TypeInfo{GoType: "*" + base, UsesSQLNull: false}
-		}
-	}
-	return TypeInfo{GoType: base, UsesSQLNull: false}
+	return withKnownImport(TypeInfo{GoType: base, UsesSQLNull: strings.HasPrefix(base, "sql.Null")})
 }
 
 // GetRequiredImports returns the imports needed for the resolved types.
@@ -0,0 +1,88 @@
+// Package docs generates schema reference documentation from a database catalog.
+// Every table, view, and enum gets its own page, rendered both as Markdown and
+// as a static HTML bundle whose pages cross-link to each other.
+package docs
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/electwix/db-catalyst/internal/query/analyzer"
+	"github.com/electwix/db-catalyst/internal/schema/model"
+)
+
+const defaultDir = "docs"
+
+// Options configures the documentation generator.
+type Options struct {
+	// Dir is the output directory prefix for generated pages. Defaults to "docs".
+	Dir string
+	// Title is the heading used on the index page.
+	Title string
+}
+
+// Generator renders schema reference pages.
+type Generator struct {
+	dir   string
+	title string
+}
+
+// File represents a generated documentation file.
+type File struct {
+	Path    string
+	Content []byte
+}
+
+// New creates a new documentation generator with the specified options.
+func New(opts Options) *Generator {
+	g := &Generator{
+		dir:   opts.Dir,
+		title: opts.Title,
+	}
+	if g.dir == "" {
+		g.dir = defaultDir
+	}
+	if g.title == "" {
+		g.title = "Schema Reference"
+	}
+	return g
+}
+
+// Generate renders Markdown and HTML pages for the catalog. Analyses are used
+// to list the named queries that read or write each table and view.
+func (g *Generator) Generate(catalog *model.Catalog, analyses []analyzer.Result) ([]File, error) {
+	if catalog == nil {
+		return nil, nil
+	}
+
+	idx := newIndex(catalog, analyses)
+	pages := make([]page, 0, len(idx.tables)+len(idx.views)+len(idx.enums))
+	for _, table := range idx.tables {
+		pages = append(pages, idx.tablePage(table))
+	}
+	for _, view := range idx.views {
+		pages = append(pages, idx.viewPage(view))
+	}
+	for _, enum := range idx.enums {
+		pages = append(pages, idx.enumPage(enum))
+	}
+	home := idx.indexPage(g.title)
+
+	files := make([]File, 0, 2*(len(pages)+1)) //nolint:mnd // one Markdown and one HTML file per page
+	files = append(files,
+		File{Path: g.dir + "/index.md", Content: renderMarkdown(home)},
+		File{Path: g.dir + "/html/index.html", Content: renderHTML(home, g.title)},
+	)
+	for _, p := range pages {
+		files = append(files,
+			File{Path: fmt.Sprintf("%s/%s/%s.md", g.dir, p.ref.dir(), p.ref.slug()), Content: renderMarkdown(p)},
+			File{Path: fmt.Sprintf("%s/html/%s/%s.html", g.dir, p.ref.dir(), p.ref.slug()), Content: renderHTML(p, g.title)},
+		)
+	}
+
+	slices.SortFunc(files, func(a, b File) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return files, nil
+}
@@ -0,0 +1,207 @@
+package docs_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/electwix/db-catalyst/internal/codegen/docs"
+	"github.com/electwix/db-catalyst/internal/query/analyzer"
+	"github.com/electwix/db-catalyst/internal/query/block"
+	"github.com/electwix/db-catalyst/internal/query/parser"
+	"github.com/electwix/db-catalyst/internal/schema/model"
+)
+
+func buildCatalog() *model.Catalog {
+	catalog := model.NewCatalog()
+	catalog.Tables["users"] = &model.Table{
+		Name: "users",
+		Doc:  "Registered accounts.",
+		Columns: []*model.Column{
+			{Name: "id", Type: "INTEGER", NotNull: true},
+			{Name: "email", Type: "TEXT", NotNull: true},
+			{Name: "status", Type: "user_status", Default: &model.Value{Kind: model.ValueKindString, Text: "active"}},
+		},
+		PrimaryKey: &model.PrimaryKey{Columns: []string{"id"}},
+		UniqueKeys: []*model.UniqueKey{{Name: "users_email_key", Columns: []string{"email"}}},
+		Indexes:    []*model.Index{{Name: "idx_users_status", Columns: []string{"status"}}},
+	}
+	catalog.Tables["posts"] = &model.Table{
+		Name: "posts",
+		Columns: []*model.Column{
+			{Name: "id", Type: "INTEGER", NotNull: true},
+			{Name: "author_id", Type: "INTEGER", NotNull: true, References: &model.ForeignKeyRef{Table: "users", Columns: []string{"id"}}},
+			{Name: "title", Type: "TEXT"},
+		},
+		PrimaryKey: &model.PrimaryKey{Columns: []string{"id"}},
+	}
+	catalog.Views["active_users"] = &model.View{
+		Name: "active_users",
+		Doc:  "Users that can sign in.",
+		SQL:  "SELECT id, email FROM users WHERE status = 'active'",
+	}
+	catalog.Enums["user_status"] = &model.Enum{Name: "user_status", Values: []string{"active", "banned"}}
+	return catalog
+}
+
+func analysis(name string, cmd block.Command, sql string) analyzer.Result {
+	return analyzer.Result{Query: parser.Query{Block: block.Block{
+		Path:    "queries/users.sql",
+		Name:    name,
+		Command: cmd,
+		SQL:     sql,
+		Line:    1,
+	}}}
+}
+
+func TestGenerate(t *testing.T) {
+	analyses := []analyzer.Result{
+		analysis("ListPostsByAuthor", block.CommandMany, "SELECT p.id, p.title FROM posts p JOIN users u ON u.id = p.author_id WHERE u.email = ?"),
+		analysis("CreateUser", block.CommandExec, "INSERT INTO users (email) VALUES (?) ON CONFLICT (email) DO UPDATE SET email = excluded.email"),
+		analysis("ArchiveUser", block.CommandExecRows, "UPDATE users SET status = 'banned' WHERE id IN (SELECT author_id FROM posts)"),
+		analysis("ListActive", block.CommandMany, "SELECT * FROM main.active_users"),
+	}
+
+	files, err := docs.New(docs.Options{}).Generate(buildCatalog(), analyses)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	byPath := make(map[string]string, len(files))
+	for _, f := range files {
+		byPath[f.Path] = string(f.Content)
+	}
+
+	wantPaths := []string{
+		"docs/index.md",
+		"docs/tables/users.md",
+		"docs/tables/posts.md",
+		"docs/views/active_users.md",
+		"docs/enums/user_status.md",
+		"docs/html/index.html",
+		"docs/html/tables/users.html",
+		"docs/html/tables/posts.html",
+		"docs/html/views/active_users.html",
+		"docs/html/enums/user_status.html",
+	}
+	if len(files) != len(wantPaths) {
+		t.Errorf("got %d files, want %d", len(files), len(wantPaths))
+	}
+	for _, p := range wantPaths {
+		if _, ok := byPath[p]; !ok {
+			t.Errorf("missing file %s", p)
+		}
+	}
+
+	tests := []struct {
+		path     string
+		contains []string
+		excludes []string
+	}{
+		{
+			path: "docs/index.md",
+			contains: []string{
+				"# Schema Reference",
+				"| [users](tables/users.md) | 3 | Registered accounts. |",
+				"[active_users](views/active_users.md)",
+				"[user_status](enums/user_status.md)",
+			},
+		},
+		{
+			path: "docs/tables/users.md",
+			contains: []string{
+				"[Index](../index.md)",
+				"# Table `users`",
+				"Registered accounts.",
+				"| `id` | `INTEGER` | no |  | PRIMARY KEY |",
+				"| `email` | `TEXT` | no |  | UNIQUE |",
+				"| `status` | [`user_status`](../enums/user_status.md) | yes | `'active'` |  |",
+				"| `idx_users_status` | `status` | no |",
+				"## Incoming foreign keys",
+				"| [posts](../tables/posts.md) | `author_id` | `id` |",
+				"| `ArchiveUser` | `:execrows` | write | queries/users.sql:1 |",
+				"| `CreateUser` | `:exec` | write |",
+				"| `ListPostsByAuthor` | `:many` | read |",
+			},
+			excludes: []string{"## Outgoing foreign keys"},
+		},
+		{
+			path: "docs/tables/posts.md",
+			contains: []string{
+				"| `author_id` | `INTEGER` | no |  | REFERENCES [users](../tables/users.md)(id) |",
+				"## Outgoing foreign keys",
+				"| `ArchiveUser` | `:execrows` | read |",
+			},
+			excludes: []string{"## Incoming foreign keys", "## Indexes"},
+		},
+		{
+			path: "docs/views/active_users.md",
+			contains: []string{
+				"# View `active_users`",
+				"```sql\nSELECT id, email FROM users WHERE status = 'active'\n```",
+				"| `ListActive` | `:many` | read |",
+			},
+		},
+		{
+			path: "docs/enums/user_status.md",
+			contains: []string{
+				"| `banned` |",
+				"| [users](../tables/users.md) | `status` | yes |",
+			},
+		},
+		{
+			path: "docs/html/tables/posts.html",
+			contains: []string{
+				`<a href="../index.html">Index</a>`,
+				`<a href="../tables/users.html">users</a>`,
+				"<h2>Queries</h2>",
+			},
+		},
+		{
+			path: "docs/html/index.html",
+			contains: []string{
+				`<a href="tables/users.html">users</a>`,
+				`<a href="enums/user_status.html">user_status</a>`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			content := byPath[tt.path]
+			for _, want := range tt.contains {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %q in:\n%s", want, content)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(content, unwanted) {
+					t.Errorf("did not expect %q in:\n%s", unwanted, content)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateEscapesHTML(t *testing.T) {
+	catalog := model.NewCatalog()
+	catalog.Views["v"] = &model.View{Name: "v", Doc: "a <b> & c", SQL: "SELECT 1 < 2"}
+
+	files, err := docs.New(docs.Options{Dir: "reference"}).Generate(catalog, nil)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	var html string
+	for _, f := range files {
+		if f.Path == "reference/html/views/v.html" {
+			html = string(f.Content)
+		}
+	}
+	if html == "" {
+		t.Fatal("missing reference/html/views/v.html")
+	}
+	for _, want := range []string{"a &lt;b&gt; &amp; c", "SELECT 1 &lt; 2", "No named queries reference this relation."} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %q in:\n%s", want, html)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package docs
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/electwix/db-catalyst/internal/query/analyzer"
+	"github.com/electwix/db-catalyst/internal/schema/model"
+	"github.com/electwix/db-catalyst/internal/schema/tokenizer"
+)
+
+// access records how a query touches a relation.
+type access int
+
+const (
+	accessRead access = 1 << iota
+	accessWrite
+)
+
+func (a access) String() string {
+	switch a {
+	case accessRead:
+		return "read"
+	case accessWrite:
+		return "write"
+	case accessRead | accessWrite:
+		return "read, write"
+	default:
+		return ""
+	}
+}
+
+// queryUse describes a named query referencing a relation.
+type queryUse struct {
+	name    string
+	command string
+	path    string
+	line    int
+	access  access
+}
+
+// incomingFK is a foreign key on another table that references the documented table.
+type incomingFK struct {
+	table   string
+	columns []string
+	refCols []string
+}
+
+// catalogIndex holds the sorted catalog objects and the cross references between them.
+type catalogIndex struct {
+	tables   []*model.Table
+	views    []*model.View
+	enums    []*model.Enum
+	refs     map[string]ref
+	incoming map[string][]incomingFK
+	usage    map[string][]queryUse
+}
+
+func newIndex(catalog *model.Catalog, analyses []analyzer.Result) *catalogIndex {
+	idx := &catalogIndex{
+		refs:     make(map[string]ref),
+		incoming: make(map[string][]incomingFK),
+		usage:    make(map[string][]queryUse),
+	}
+	for _, t := range catalog.Tables {
+		idx.tables = append(idx.tables, t)
+	}
+	for _, v := range catalog.Views {
+		idx.views = append(idx.views, v)
+	}
+	for _, e := range catalog.Enums {
+		idx.enums = append(idx.enums, e)
+	}
+	slices.SortFunc(idx.tables, func(a, b *model.Table) int { return cmp.Compare(a.Name, b.Name) })
+	slices.SortFunc(idx.views, func(a, b *model.View) int { return cmp.Compare(a.Name, b.Name) })
+	slices.SortFunc(idx.enums, func(a, b *model.Enum) int { return cmp.Compare(a.Name, b.Name) })
+
+	for _, e := range idx.enums {
+		idx.refs[key(e.Name)] = ref{kind: refEnum, name: e.Name}
+	}
+	for _, v := range idx.views {
+		idx.refs[key(v.Name)] = ref{kind: refView, name: v.Name}
+	}
+	for _, t := range idx.tables {
+		idx.refs[key(t.Name)] = ref{kind: refTable, name: t.Name}
+	}
+
+	for _, t := range idx.tables {
+		for _, fk := range outgoingFKs(t) {
+			target := key(fk.Ref.Table)
+			idx.incoming[target] = append(idx.incoming[target], incomingFK{
+				table:   t.Name,
+				columns: fk.Columns,
+				refCols: fk.Ref.Columns,
+			})
+		}
+	}
+
+	for _, res := range analyses {
+		blk := res.Query.Block
+		for name, acc := range relationAccess(blk.Path, blk.SQL) {
+			if _, ok := idx.refs[name]; !ok {
+				continue
+			}
+			idx.usage[name] = append(idx.usage[name], queryUse{
+				name:    blk.Name,
+				command: blk.Command.String(),
+				path:    blk.Path,
+				line:    blk.Line,
+				access:  acc,
+			})
+		}
+	}
+	for name := range idx.usage {
+		slices.SortFunc(idx.usage[name], func(a, b queryUse) int {
+			return cmp.Compare(a.name, b.name)
+		})
+	}
+
+	return idx
+}
+
+func key(name string) string {
+	return strings.ToLower(tokenizer.NormalizeIdentifier(name))
+}
+
+// lookup returns the page reference for a relation or enum name.
+func (idx *catalogIndex) lookup(name string) (ref, bool) {
+	r, ok := idx.refs[key(name)]
+	return r, ok
+}
+
+// outgoingFKs merges table-level FOREIGN KEY constraints with inline column REFERENCES.
+func outgoingFKs(t *model.Table) []*model.ForeignKey {
+	fks := make([]*model.ForeignKey, 0, len(t.ForeignKeys))
+	fks = append(fks, t.ForeignKeys...)
+	for _, col := range t.Columns {
+		if col.References == nil {
+			continue
+		}
+		fks = append(fks, &model.ForeignKey{
+			Columns: []string{col.Name},
+			Ref:     *col.References,
+		})
+	}
+	return fks
+}
+
+// relationAccess scans a query for relation names following FROM, JOIN, INTO,
+// UPDATE, and DELETE FROM and classifies each as a read or a write.
+func relationAccess(path, sql string) map[string]access {
+	tokens, err := tokenizer.Scan(path, []byte(sql), false)
+	if err != nil {
+		return nil
+	}
+	out := make(map[string]access)
+	for i, tok := range tokens {
+		var acc access
+		switch strings.ToUpper(tok.Text) {
+		case "FROM":
+			acc = accessRead
+			if i > 0 && strings.EqualFold(tokens[i-1].Text, "DELETE") {
+				acc = accessWrite
+			}
+		case "JOIN", "USING":
+			acc = accessRead
+		case "INTO":
+			acc = accessWrite
+		case "UPDATE":
+			// ON CONFLICT DO UPDATE and ON DUPLICATE KEY UPDATE target the insert table.
+			if i > 0 && (strings.EqualFold(tokens[i-1].Text, "DO") || strings.EqualFold(tokens[i-1].Text, "KEY")) {
+				continue
+			}
+			acc = accessWrite
+		default:
+			continue
+		}
+		if name, ok := relationNameAt(tokens, i+1); ok {
+			out[key(name)] |= acc
+		}
+	}
+	return out
+}
+
+// relationNameAt returns the relation name starting at tokens[i], skipping a schema qualifier.
+func relationNameAt(tokens []tokenizer.Token, i int) (string, bool) {
+	if i >= len(tokens) {
+		return "", false
+	}
+	if strings.EqualFold(tokens[i].Text, "ONLY") && i+1 < len(tokens) {
+		i++
+	}
+	tok := tokens[i]
+	if tok.Kind != tokenizer.KindIdentifier {
+		return "", false
+	}
+	name := tok.Text
+	for i+2 < len(tokens) && tokens[i+1].Text == "." && tokens[i+2].Kind == tokenizer.KindIdentifier {
+		name = tokens[i+2].Text
+		i += 2
+	}
+	return name, true
+}
@@ -0,0 +1,139 @@
+package docs
+
+import (
+	"strings"
+	"unicode"
+)
+
+// refKind identifies the kind of object a page documents.
+type refKind int
+
+const (
+	refIndex refKind = iota
+	refTable
+	refView
+	refEnum
+)
+
+// ref addresses a documentation page and knows how to link to it.
+type ref struct {
+	kind refKind
+	name string
+}
+
+func (r ref) dir() string {
+	switch r.kind {
+	case refTable:
+		return "tables"
+	case refView:
+		return "views"
+	case refEnum:
+		return "enums"
+	default:
+		return ""
+	}
+}
+
+func (r ref) label() string {
+	switch r.kind {
+	case refTable:
+		return "Table"
+	case refView:
+		return "View"
+	case refEnum:
+		return "Enum"
+	default:
+		return ""
+	}
+}
+
+// slug returns a file-system safe page name.
+func (r ref) slug() string {
+	if r.kind == refIndex {
+		return "index"
+	}
+	var b strings.Builder
+	for _, c := range strings.ToLower(r.name) {
+		switch {
+		case unicode.IsLetter(c), unicode.IsDigit(c), c == '_', c == '-':
+			b.WriteRune(c)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+// href returns the relative link from the page at `from` to r using ext.
+func (r ref) href(from ref, ext string) string {
+	prefix := ""
+	if from.kind != refIndex {
+		prefix = "../"
+	}
+	if r.kind == refIndex {
+		return prefix + "index." + ext
+	}
+	return prefix + r.dir() + "/" + r.slug() + "." + ext
+}
+
+// page is a format-neutral description of a documentation page.
+type page struct {
+	ref      ref
+	title    string
+	doc      string
+	sections []section
+}
+
+// section is a titled part of a page holding a table, a code block, or text.
+type section struct {
+	heading string
+	grid    *grid
+	code    string
+	text    string
+}
+
+// grid is a tabular block of cells.
+type grid struct {
+	header []string
+	rows   [][]cell
+}
+
+// cell is a sequence of spans rendered side by side.
+type cell []span
+
+// span is a fragment of cell content, optionally linked or rendered as code.
+type span struct {
+	text string
+	link *ref
+	code bool
+}
+
+func textCell(s string) cell {
+	if s == "" {
+		return nil
+	}
+	return cell{{text: s}}
+}
+
+func codeCell(s string) cell {
+	if s == "" {
+		return nil
+	}
+	return cell{{text: s, code: true}}
+}
+
+func linkCell(target ref, text string) cell {
+	return cell{{text: text, link: &target}}
+}
+
+// joinCells concatenates cells, separating them with sep.
+func joinCells(cells []cell, sep string) cell {
+	var out cell
+	for i, c := range cells {
+		if i > 0 {
+			out = append(out, span{text: sep})
+		}
+		out = append(out, c...)
+	}
+	return out
+}
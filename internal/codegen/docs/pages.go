@@ -0,0 +1,278 @@
+package docs
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/electwix/db-catalyst/internal/schema/model"
+)
+
+func (idx *catalogIndex) indexPage(title string) page {
+	p := page{ref: ref{kind: refIndex}, title: title}
+
+	if len(idx.tables) > 0 {
+		g := &grid{header: []string{"Table", "Columns", "Description"}}
+		for _, t := range idx.tables {
+			g.rows = append(g.rows, []cell{
+				linkCell(ref{kind: refTable, name: t.Name}, t.Name),
+				textCell(strconv.Itoa(len(t.Columns))),
+				textCell(summary(t.Doc)),
+			})
+		}
+		p.sections = append(p.sections, section{heading: "Tables", grid: g})
+	}
+	if len(idx.views) > 0 {
+		g := &grid{header: []string{"View", "Description"}}
+		for _, v := range idx.views {
+			g.rows = append(g.rows, []cell{
+				linkCell(ref{kind: refView, name: v.Name}, v.Name),
+				textCell(summary(v.Doc)),
+			})
+		}
+		p.sections = append(p.sections, section{heading: "Views", grid: g})
+	}
+	if len(idx.enums) > 0 {
+		g := &grid{header: []string{"Enum", "Values"}}
+		for _, e := range idx.enums {
+			g.rows = append(g.rows, []cell{
+				linkCell(ref{kind: refEnum, name: e.Name}, e.Name),
+				textCell(strconv.Itoa(len(e.Values))),
+			})
+		}
+		p.sections = append(p.sections, section{heading: "Enums", grid: g})
+	}
+	if len(p.sections) == 0 {
+		p.sections = append(p.sections, section{heading: "Objects", text: "The catalog is empty."})
+	}
+	return p
+}
+
+func (idx *catalogIndex) tablePage(t *model.Table) page {
+	p := page{ref: ref{kind: refTable, name: t.Name}, title: t.Name, doc: t.Doc}
+
+	pkCols := make(map[string]bool)
+	if t.PrimaryKey != nil {
+		for _, c := range t.PrimaryKey.Columns {
+			pkCols[key(c)] = true
+		}
+	}
+	uniqueCols := make(map[string]bool)
+	for _, uk := range t.UniqueKeys {
+		if len(uk.Columns) == 1 {
+			uniqueCols[key(uk.Columns[0])] = true
+		}
+	}
+	fks := outgoingFKs(t)
+
+	columns := &grid{header: []string{"Column", "Type", "Nullable", "Default", "Constraints"}}
+	for _, col := range t.Columns {
+		var constraints []cell
+		if pkCols[key(col.Name)] {
+			constraints = append(constraints, textCell("PRIMARY KEY"))
+		}
+		if uniqueCols[key(col.Name)] {
+			constraints = append(constraints, textCell("UNIQUE"))
+		}
+		for _, fk := range fks {
+			if len(fk.Columns) == 1 && key(fk.Columns[0]) == key(col.Name) {
+				constraints = append(constraints, append(textCell("REFERENCES "), idx.refCell(fk.Ref)...))
+			}
+		}
+		columns.rows = append(columns.rows, []cell{
+			codeCell(col.Name),
+			idx.typeCell(col.Type),
+			textCell(nullability(col.NotNull)),
+			codeCell(defaultText(col.Default)),
+			joinCells(constraints, ", "),
+		})
+	}
+	p.sections = append(p.sections, section{heading: "Columns", grid: columns})
+
+	if g := tableConstraints(t); g != nil {
+		p.sections = append(p.sections, section{heading: "Constraints", grid: g})
+	}
+
+	if len(t.Indexes) > 0 {
+		indexes := &grid{header: []string{"Index", "Columns", "Unique"}}
+		sorted := slices.Clone(t.Indexes)
+		model.SortIndexes(sorted)
+		for _, ix := range sorted {
+			unique := "no"
+			if ix.Unique {
+				unique = "yes"
+			}
+			indexes.rows = append(indexes.rows, []cell{
+				codeCell(ix.Name),
+				codeCell(strings.Join(ix.Columns, ", ")),
+				textCell(unique),
+			})
+		}
+		p.sections = append(p.sections, section{heading: "Indexes", grid: indexes})
+	}
+
+	if len(fks) > 0 {
+		outgoing := &grid{header: []string{"Columns", "References", "Referenced columns"}}
+		for _, fk := range fks {
+			outgoing.rows = append(outgoing.rows, []cell{
+				codeCell(strings.Join(fk.Columns, ", ")),
+				idx.relationCell(fk.Ref.Table),
+				codeCell(strings.Join(fk.Ref.Columns, ", ")),
+			})
+		}
+		p.sections = append(p.sections, section{heading: "Outgoing foreign keys", grid: outgoing})
+	}
+
+	if in := idx.incoming[key(t.Name)]; len(in) > 0 {
+		incoming := &grid{header: []string{"Table", "Columns", "Referenced columns"}}
+		for _, fk := range in {
+			incoming.rows = append(incoming.rows, []cell{
+				idx.relationCell(fk.table),
+				codeCell(strings.Join(fk.columns, ", ")),
+				codeCell(strings.Join(fk.refCols, ", ")),
+			})
+		}
+		p.sections = append(p.sections, section{heading: "Incoming foreign keys", grid: incoming})
+	}
+
+	p.sections = append(p.sections, idx.querySection(t.Name))
+	return p
+}
+
+func (idx *catalogIndex) viewPage(v *model.View) page {
+	p := page{ref: ref{kind: refView, name: v.Name}, title: v.Name, doc: v.Doc}
+	if sql := strings.TrimSpace(v.SQL); sql != "" {
+		p.sections = append(p.sections, section{heading: "Definition", code: sql})
+	}
+	p.sections = append(p.sections, idx.querySection(v.Name))
+	return p
+}
+
+func (idx *catalogIndex) enumPage(e *model.Enum) page {
+	p := page{ref: ref{kind: refEnum, name: e.Name}, title: e.Name}
+	values := &grid{header: []string{"Value"}}
+	for _, v := range e.Values {
+		values.rows = append(values.rows, []cell{codeCell(v)})
+	}
+	p.sections = append(p.sections, section{heading: "Values", grid: values})
+
+	usedBy := &grid{header: []string{"Table", "Column", "Nullable"}}
+	for _, t := range idx.tables {
+		for _, col := range t.Columns {
+			if key(baseTypeName(col.Type)) != key(e.Name) {
+				continue
+			}
+			usedBy.rows = append(usedBy.rows, []cell{
+				linkCell(ref{kind: refTable, name: t.Name}, t.Name),
+				codeCell(col.Name),
+				textCell(nullability(col.NotNull)),
+			})
+		}
+	}
+	if len(usedBy.rows) > 0 {
+		p.sections = append(p.sections, section{heading: "Used by", grid: usedBy})
+	}
+	return p
+}
+
+func (idx *catalogIndex) querySection(name string) section {
+	uses := idx.usage[key(name)]
+	if len(uses) == 0 {
+		return section{heading: "Queries", text: "No named queries reference this relation."}
+	}
+	g := &grid{header: []string{"Query", "Command", "Access", "Source"}}
+	for _, u := range uses {
+		g.rows = append(g.rows, []cell{
+			codeCell(u.name),
+			codeCell(u.command),
+			textCell(u.access.String()),
+			textCell(fmt.Sprintf("%s:%d", u.path, u.line)),
+		})
+	}
+	return section{heading: "Queries", grid: g}
+}
+
+// tableConstraints lists the table-level primary and unique keys.
+func tableConstraints(t *model.Table) *grid {
+	g := &grid{header: []string{"Kind", "Name", "Columns"}}
+	if t.PrimaryKey != nil && len(t.PrimaryKey.Columns) > 0 {
+		g.rows = append(g.rows, []cell{
+			textCell("PRIMARY KEY"),
+			codeCell(t.PrimaryKey.Name),
+			codeCell(strings.Join(t.PrimaryKey.Columns, ", ")),
+		})
+	}
+	keys := slices.Clone(t.UniqueKeys)
+	model.SortUniqueKeys(keys)
+	for _, uk := range keys {
+		g.rows = append(g.rows, []cell{
+			textCell("UNIQUE"),
+			codeCell(uk.Name),
+			codeCell(strings.Join(uk.Columns, ", ")),
+		})
+	}
+	if len(g.rows) == 0 {
+		return nil
+	}
+	return g
+}
+
+// typeCell links column types that name a catalog enum.
+func (idx *catalogIndex) typeCell(typ string) cell {
+	if r, ok := idx.lookup(baseTypeName(typ)); ok && r.kind == refEnum {
+		return cell{{text: typ, link: &r, code: true}}
+	}
+	return codeCell(typ)
+}
+
+func (idx *catalogIndex) relationCell(name string) cell {
+	if r, ok := idx.lookup(name); ok {
+		return linkCell(r, name)
+	}
+	return textCell(name)
+}
+
+func (idx *catalogIndex) refCell(fk model.ForeignKeyRef) cell {
+	out := idx.relationCell(fk.Table)
+	if len(fk.Columns) > 0 {
+		out = append(out, span{text: "(" + strings.Join(fk.Columns, ", ") + ")"})
+	}
+	return out
+}
+
+func nullability(notNull bool) string {
+	if notNull {
+		return "no"
+	}
+	return "yes"
+}
+
+func defaultText(v *model.Value) string {
+	if v == nil {
+		return ""
+	}
+	if v.Kind == model.ValueKindString {
+		return "'" + v.Text + "'"
+	}
+	return v.Text
+}
+
+// baseTypeName strips a schema qualifier, array suffix, and size arguments from a type name.
+func baseTypeName(typ string) string {
+	name := strings.TrimSpace(typ)
+	if i := strings.IndexByte(name, '('); i >= 0 {
+		name = name[:i]
+	}
+	name = strings.TrimSuffix(strings.TrimSpace(name), "[]")
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// summary returns the first line of a doc comment.
+func summary(doc string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(doc), "\n")
+	return line
+}
@@ -0,0 +1,150 @@
+package docs
+
+import (
+	"bytes"
+	"html"
+	"strings"
+)
+
+const (
+	extMarkdown = "md"
+	extHTML     = "html"
+)
+
+func renderMarkdown(p page) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("<!-- Code generated by db-catalyst. DO NOT EDIT. -->\n\n")
+	if p.ref.kind != refIndex {
+		buf.WriteString("[Index](" + ref{kind: refIndex}.href(p.ref, extMarkdown) + ")\n\n")
+		buf.WriteString("# " + p.ref.label() + " `" + p.title + "`\n\n")
+	} else {
+		buf.WriteString("# " + p.title + "\n\n")
+	}
+	if doc := strings.TrimSpace(p.doc); doc != "" {
+		buf.WriteString(doc)
+		buf.WriteString("\n\n")
+	}
+	for _, s := range p.sections {
+		buf.WriteString("## " + s.heading + "\n\n")
+		switch {
+		case s.grid != nil:
+			writeMarkdownGrid(&buf, p.ref, s.grid)
+		case s.code != "":
+			buf.WriteString("```sql\n")
+			buf.WriteString(s.code)
+			buf.WriteString("\n```\n")
+		default:
+			buf.WriteString(s.text)
+			buf.WriteString("\n")
+		}
+		buf.WriteString("\n")
+	}
+	return append(bytes.TrimRight(buf.Bytes(), "\n"), '\n')
+}
+
+func writeMarkdownGrid(buf *bytes.Buffer, from ref, g *grid) {
+	buf.WriteString("|")
+	for _, h := range g.header {
+		buf.WriteString(" " + h + " |")
+	}
+	buf.WriteString("\n|")
+	for range g.header {
+		buf.WriteString(" --- |")
+	}
+	buf.WriteString("\n")
+	for _, row := range g.rows {
+		buf.WriteString("|")
+		for _, c := range row {
+			buf.WriteString(" ")
+			for _, sp := range c {
+				buf.WriteString(markdownSpan(from, sp))
+			}
+			buf.WriteString(" |")
+		}
+		buf.WriteString("\n")
+	}
+}
+
+func markdownSpan(from ref, sp span) string {
+	text := strings.ReplaceAll(sp.text, "|", `\|`)
+	text = strings.ReplaceAll(text, "\n", " ")
+	if sp.code {
+		text = "`" + text + "`"
+	}
+	if sp.link != nil {
+		text = "[" + text + "](" + sp.link.href(from, extMarkdown) + ")"
+	}
+	return text
+}
+
+const htmlStyle = `body{font-family:system-ui,sans-serif;margin:2rem auto;max-width:60rem;padding:0 1rem;color:#222}` +
+	`table{border-collapse:collapse;margin-bottom:1rem}th,td{border:1px solid #ccc;padding:.3rem .6rem;text-align:left}` +
+	`th{background:#f4f4f4}code,pre{font-family:ui-monospace,monospace}pre{background:#f4f4f4;padding:.6rem;overflow:auto}` +
+	`nav{margin-bottom:1rem}`
+
+func renderHTML(p page, siteTitle string) []byte {
+	var buf bytes.Buffer
+	title := p.title
+	if p.ref.kind != refIndex {
+		title = p.ref.label() + " " + p.title + " - " + siteTitle
+	}
+	buf.WriteString("<!DOCTYPE html>\n<!-- Code generated by db-catalyst. DO NOT EDIT. -->\n")
+	buf.WriteString("<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n")
+	buf.WriteString("<title>" + html.EscapeString(title) + "</title>\n")
+	buf.WriteString("<style>" + htmlStyle + "</style>\n</head>\n<body>\n")
+	if p.ref.kind != refIndex {
+		buf.WriteString("<nav><a href=\"" + ref{kind: refIndex}.href(p.ref, extHTML) + "\">Index</a></nav>\n")
+		buf.WriteString("<h1>" + p.ref.label() + " <code>" + html.EscapeString(p.title) + "</code></h1>\n")
+	} else {
+		buf.WriteString("<h1>" + html.EscapeString(p.title) + "</h1>\n")
+	}
+	if doc := strings.TrimSpace(p.doc); doc != "" {
+		for para := range strings.SplitSeq(doc, "\n\n") {
+			buf.WriteString("<p>" + html.EscapeString(para) + "</p>\n")
+		}
+	}
+	for _, s := range p.sections {
+		buf.WriteString("<h2>" + html.EscapeString(s.heading) + "</h2>\n")
+		switch {
+		case s.grid != nil:
+			writeHTMLGrid(&buf, p.ref, s.grid)
+		case s.code != "":
+			buf.WriteString("<pre><code>" + html.EscapeString(s.code) + "</code></pre>\n")
+		default:
+			buf.WriteString("<p>" + html.EscapeString(s.text) + "</p>\n")
+		}
+	}
+	buf.WriteString("</body>\n</html>\n")
+	return buf.Bytes()
+}
+
+func writeHTMLGrid(buf *bytes.Buffer, from ref, g *grid) {
+	buf.WriteString("<table>\n<thead><tr>")
+	for _, h := range g.header {
+		buf.WriteString("<th>" + html.EscapeString(h) + "</th>")
+	}
+	buf.WriteString("</tr></thead>\n<tbody>\n")
+	for _, row := range g.rows {
+		buf.WriteString("<tr>")
+		for _, c := range row {
+			buf.WriteString("<td>")
+			for _, sp := range c {
+				buf.WriteString(htmlSpan(from, sp))
+			}
+			buf.WriteString("</td>")
+		}
+		buf.WriteString("</tr>\n")
+	}
+	buf.WriteString("</tbody>\n</table>\n")
+}
+
+func htmlSpan(from ref, sp span) string {
+	text := html.EscapeString(sp.text)
+	if sp.code {
+		text = "<code>" + text + "</code>"
+	}
+	if sp.link != nil {
+		text = "<a href=\"" + html.EscapeString(sp.link.href(from, extHTML)) + "\">" + text + "</a>"
+	}
+	return text
+}
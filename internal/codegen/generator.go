@@ -7,6 +7,7 @@ import (
 	"slices"
 
 	astbuilder "github.com/electwix/db-catalyst/internal/codegen/ast"
+	"github.com/electwix/db-catalyst/internal/codegen/docs"
 	"github.com/electwix/db-catalyst/internal/codegen/render"
 	"github.com/electwix/db-catalyst/internal/codegen/sql"
 	"github.com/electwix/db-catalyst/internal/config"
@@ -34,17 +35,30 @@ type SQLOptions struct {
 	EmitIFNotExists bool
 }
 
+// DocsOptions configures schema reference documentation output.
+type DocsOptions struct {
+	Enabled bool
+	Dir     string
+}
+
 // Options configures the Generator.
 type Options struct {
-	Package             string
-	Database            config.Database
-	EmitJSONTags        bool
-	EmitEmptySlices     bool
-	EmitPointersForNull bool
-	Prepared            PreparedOptions
-	CustomTypes         []config.CustomTypeMapping
-	ColumnOverrides     []config.ColumnOverride
-	SQL                 SQLOptions
+	Package         string
+	Database        config.Database
+	SQLPackage      config.SQLPackage
+	EmitJSONTags    bool
+	EmitEmptySlices bool
+	NullStyle       config.NullStyle
+	NullOption      config.GoTypeDetails
+	Prepared        PreparedOptions
+	CustomTypes     []config.CustomTypeMapping
+	ColumnOverrides []config.ColumnOverride
+	SQLiteTypes     config.SQLiteTypes
+	SQLiteDriver    config.Driver
+	DriverHelpers   bool
+	Errors          config.ConstraintErrors
+	SQL             SQLOptions
+	Docs            DocsOptions
 }
 
 // codegen implements Generator to produce Go code from parsed schemas and queries.
@@ -79,6 +93,14 @@ func (g *codegen) Generate(ctx context.Context, catalog *model.Catalog, analyses
 		files = append(files, sqlFiles...)
 	}
 
+	if g.opts.Docs.Enabled {
+		docFiles, err := g.generateDocs(catalog, analyses)
+		if err != nil {
+			return nil, fmt.Errorf("generate docs: %w", err)
+		}
+		files = append(files, docFiles...)
+	}
+
 	goFiles, err := g.generateGo(ctx, catalog, analyses)
 	if err != nil {
 		return nil, fmt.Errorf("generate Go: %w", err)
@@ -126,26 +148,48 @@ func (g *codegen) generateSQL(catalog *model.Catalog) ([]File, error) {
 	return files, nil
 }
 
+func (g *codegen) generateDocs(catalog *model.Catalog, analyses []analyzer.Result) ([]File, error) {
+	generator := docs.New(docs.Options{Dir: g.opts.Docs.Dir})
+
+	docFiles, err := generator.Generate(catalog, analyses)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]File, 0, len(docFiles))
+	for _, f := range docFiles {
+		files = append(files, File{Path: f.Path, Content: f.Content})
+	}
+	return files, nil
+}
+
 func (g *codegen) generateGo(ctx context.Context, catalog *model.Catalog, analyses []analyzer.Result) ([]File, error) {
 	transformer := transform.New(g.opts.CustomTypes)
 	database := g.opts.Database
 	if database == "" {
 		database = config.DatabaseSQLite
 	}
-	typeResolver := astbuilder.NewTypeResolverFull(transformer, database, g.opts.EmitPointersForNull)
+	typeResolver := astbuilder.NewTypeResolverFull(transformer, database, g.opts.NullStyle, g.opts.NullOption)
 
 	builder := astbuilder.New(astbuilder.Options{
-		Package:             g.opts.Package,
-		EmitJSONTags:        g.opts.EmitJSONTags,
-		EmitEmptySlices:     g.opts.EmitEmptySlices,
-		EmitPointersForNull: g.opts.EmitPointersForNull,
-		TypeResolver:        typeResolver,
-		ColumnOverrides:     g.opts.ColumnOverrides,
+		Package:         g.opts.Package,
+		EmitJSONTags:    g.opts.EmitJSONTags,
+		EmitEmptySlices: g.opts.EmitEmptySlices,
+		NullStyle:       g.opts.NullStyle,
+		NullOption:      g.opts.NullOption,
+		TypeResolver:    typeResolver,
+		ColumnOverrides: g.opts.ColumnOverrides,
+		SQLiteTypes:     g.opts.SQLiteTypes,
+		SQLiteDriver:    g.opts.SQLiteDriver,
+		DriverHelpers:   g.opts.DriverHelpers,
+		Database:        database,
+		SQLPackage:      g.opts.SQLPackage,
 		Prepared: astbuilder.PreparedOptions{
 			Enabled:     g.opts.Prepared.Enabled,
 			EmitMetrics: g.opts.Prepared.EmitMetrics,
 			ThreadSafe:  g.opts.Prepared.ThreadSafe,
 		},
+		ConstraintErrors: g.opts.Errors,
 	})
 
 	astFiles, err := builder.Build(ctx, catalog, analyses)
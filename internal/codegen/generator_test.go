@@ -324,10 +324,10 @@ func TestNewGenerator(t *testing.T) {
 		{
 			name: "with all options enabled",
 			opts: Options{
-				Package:             "store",
-				EmitJSONTags:        true,
-				EmitEmptySlices:     true,
-				EmitPointersForNull: true,
+				Package:         "store",
+				EmitJSONTags:    true,
+				EmitEmptySlices: true,
+				NullStyle:       config.NullStylePointer,
 				Prepared: PreparedOptions{
 					Enabled:     true,
 					EmitMetrics: true,
@@ -669,15 +669,15 @@ func TestGeneratorGenerate_OptionsCombinations(t *testing.T) {
 		},
 		{
 			name: "pointers for null only",
-			opts: Options{Package: "test", EmitPointersForNull: true},
+			opts: Options{Package: "test", NullStyle: config.NullStylePointer},
 		},
 		{
 			name: "all emit options",
 			opts: Options{
-				Package:             "test",
-				EmitJSONTags:        true,
-				EmitEmptySlices:     true,
-				EmitPointersForNull: true,
+				Package:         "test",
+				EmitJSONTags:    true,
+				EmitEmptySlices: true,
+				NullStyle:       config.NullStylePointer,
 			},
 		},
 		{
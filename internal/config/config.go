@@ -31,6 +31,36 @@ var validDrivers = map[Driver]struct{}{
 	DriverMattN:   {},
 }
 
+// SQLiteStorage identifies how a SQLite column stores the values of a
+// semantic Go type that the drivers do not convert by themselves.
+type SQLiteStorage string
+
+const (
+	// StorageUnix stores time.Time values as INTEGER unix seconds.
+	StorageUnix SQLiteStorage = "unix"
+	// StorageUnixMillis stores time.Time values as INTEGER unix milliseconds.
+	StorageUnixMillis SQLiteStorage = "unix_millis"
+	// StorageRFC3339 stores time.Time values as RFC 3339 TEXT in UTC.
+	StorageRFC3339 SQLiteStorage = "rfc3339"
+	// StorageJulianDay stores time.Time values as REAL julian day numbers.
+	StorageJulianDay SQLiteStorage = "julian_day"
+	// StorageJSON stores json.RawMessage values as TEXT.
+	StorageJSON SQLiteStorage = "json"
+)
+
+var validTimeEncodings = map[SQLiteStorage]struct{}{
+	StorageUnix:       {},
+	StorageUnixMillis: {},
+	StorageRFC3339:    {},
+	StorageJulianDay:  {},
+}
+
+// IsTime reports whether s stores time.Time values.
+func (s SQLiteStorage) IsTime() bool {
+	_, ok := validTimeEncodings[s]
+	return ok
+}
+
 // Language identifies the target programming language for code generation.
 type Language string
 
@@ -67,6 +97,45 @@ var validDatabases = map[Database]struct{}{
 	DatabaseMySQL:      {},
 }
 
+// SQLPackage identifies the Go database package generated code targets.
+type SQLPackage string
+
+const (
+	// SQLPackageDatabaseSQL targets database/sql.
+	SQLPackageDatabaseSQL SQLPackage = "database/sql"
+	// SQLPackagePgxV5 targets github.com/jackc/pgx/v5 directly.
+	SQLPackagePgxV5 SQLPackage = "pgx/v5"
+)
+
+var validSQLPackages = map[SQLPackage]struct{}{
+	SQLPackageDatabaseSQL: {},
+	SQLPackagePgxV5:       {},
+}
+
+// NullStyle identifies how generated code represents NULL values.
+type NullStyle string
+
+const (
+	// NullStyleSQLNull uses the sql.Null* types, such as sql.NullString.
+	NullStyleSQLNull NullStyle = "sql_null"
+	// NullStylePointer uses pointers, such as *string.
+	NullStylePointer NullStyle = "pointer"
+	// NullStyleGeneric uses the generic sql.Null[T] added in Go 1.22.
+	NullStyleGeneric NullStyle = "generic"
+	// NullStylePgtype uses the pgx/v5 pgtype types, such as pgtype.Text.
+	NullStylePgtype NullStyle = "pgtype"
+	// NullStyleOption uses the generic type named by null_option_type.
+	NullStyleOption NullStyle = "option"
+)
+
+var validNullStyles = map[NullStyle]struct{}{
+	NullStyleSQLNull: {},
+	NullStylePointer: {},
+	NullStyleGeneric: {},
+	NullStylePgtype:  {},
+	NullStyleOption:  {},
+}
+
 // CustomTypeMapping defines how a custom type maps to SQLite and Go types.
 type CustomTypeMapping struct {
 	CustomType string `toml:"custom_type"`
@@ -129,8 +198,10 @@ func (g *GoTypeDetails) UnmarshalTOML(data any) error {
 
 // rawColumnOverride is used for TOML unmarshaling before conversion.
 type rawColumnOverride struct {
-	Column string `toml:"column"`
-	GoType any    `toml:"go_type"`
+	Column    string    `toml:"column"`
+	GoType    any       `toml:"go_type"`
+	JSON      bool      `toml:"json"`
+	NullStyle NullStyle `toml:"null_style"`
 }
 
 // ColumnOverride defines column-specific type overrides (sqlc compatibility).
@@ -138,15 +209,62 @@ type rawColumnOverride struct {
 type ColumnOverride struct {
 	Column string        `toml:"column"`
 	GoType GoTypeDetails `toml:"go_type"`
+	// JSON stores GoType in the column as JSON, for decoding JSON columns
+	// into structs.
+	JSON bool `toml:"json"`
+	// NullStyle overrides the generation null_style for the column; an
+	// override may set it without a go_type.
+	NullStyle NullStyle `toml:"null_style"`
+}
+
+// FunctionConfig declares a SQL function that is not built into the database,
+// such as an application-registered SQLite function or a PostgreSQL extension.
+type FunctionConfig struct {
+	Name      string   `toml:"name"`
+	Args      []string `toml:"args"`
+	Optional  int      `toml:"optional"`
+	Variadic  bool     `toml:"variadic"`
+	Aggregate bool     `toml:"aggregate"`
+	// Returns is the SQL result type, mapped like a column of that type.
+	Returns string `toml:"returns"`
+	// GoType overrides Returns with a Go type, either builtin or fully
+	// qualified such as "github.com/acme/geo.Point".
+	GoType string `toml:"go_type"`
+	// Nullable forces the result nullability; when unset the result is
+	// nullable only if an argument is.
+	Nullable *bool `toml:"nullable"`
+
+	// GoImport and GoPackage are derived from a qualified GoType.
+	GoImport  string `toml:"-"`
+	GoPackage string `toml:"-"`
 }
 
 // GenerationOptions captures additional generation options.
 type GenerationOptions struct {
-	EmitEmptySlices     bool   `toml:"emit_empty_slices"`
-	EmitPreparedQueries bool   `toml:"emit_prepared_queries"`
-	EmitJSONTags        bool   `toml:"emit_json_tags"`
-	EmitPointersForNull bool   `toml:"emit_pointers_for_null"`
-	SQLDialect          string `toml:"sql_dialect"`
+	EmitEmptySlices     bool `toml:"emit_empty_slices"`
+	EmitPreparedQueries bool `toml:"emit_prepared_queries"`
+	EmitJSONTags        bool `toml:"emit_json_tags"`
+	// Deprecated: use NullStyle = "pointer".
+	EmitPointersForNull bool      `toml:"emit_pointers_for_null"`
+	NullStyle           NullStyle `toml:"null_style"`
+	NullOptionType      string    `toml:"null_option_type"`
+	SQLDialect          string    `toml:"sql_dialect"`
+	EmitDocs            bool      `toml:"emit_docs"`
+	EmitDriverHelpers   bool      `toml:"emit_driver_helpers"`
+}
+
+// SQLiteTypesConfig captures the opt-in SQLite semantic type mapping.
+type SQLiteTypesConfig struct {
+	Enabled      bool          `toml:"enabled"`
+	TimeEncoding SQLiteStorage `toml:"time_encoding"`
+}
+
+// SQLiteTypes is the normalized SQLite semantic type configuration. When
+// enabled, BOOLEAN columns map to bool, DATE, DATETIME and TIMESTAMP columns
+// to time.Time stored in TimeEncoding, and JSON columns to json.RawMessage.
+type SQLiteTypes struct {
+	Enabled      bool
+	TimeEncoding SQLiteStorage
 }
 
 // CacheConfig captures caching configuration for incremental builds.
@@ -163,20 +281,41 @@ type Cache struct {
 
 // JobPlan is the fully-resolved configuration used by downstream stages.
 type JobPlan struct {
-	Package             string
-	Out                 string
-	Language            Language
-	Database            Database
-	SQLiteDriver        Driver
-	Schemas             []string
-	Queries             []string
-	CustomTypes         []CustomTypeMapping
-	ColumnOverrides     map[string]ColumnOverride
-	EmitJSONTags        bool
-	EmitPointersForNull bool
-	PreparedQueries     PreparedQueries
-	SQLDialect          string
-	Cache               Cache
+	Package         string
+	Out             string
+	Language        Language
+	Database        Database
+	SQLPackage      SQLPackage
+	SQLiteDriver    Driver
+	Schemas         []string
+	Queries         []string
+	CustomTypes     []CustomTypeMapping
+	ColumnOverrides map[string]ColumnOverride
+	Functions       []FunctionConfig
+	EmitJSONTags    bool
+	NullStyle       NullStyle
+	NullOption      GoTypeDetails // Generic type of NullStyleOption
+	PreparedQueries PreparedQueries
+	SQLiteTypes     SQLiteTypes
+	Errors          ConstraintErrors
+	SQLDialect      string
+	EmitDocs        bool
+	DriverHelpers   bool // Open and error helpers for SQLiteDriver
+	Cache           Cache
+}
+
+// ConstraintErrorsConfig captures the opt-in typed constraint errors.
+type ConstraintErrorsConfig struct {
+	Enabled bool `toml:"enabled"`
+	Wrap    bool `toml:"wrap"`
+}
+
+// ConstraintErrors is the normalized constraint error configuration. When
+// enabled, ClassifyError maps driver errors to errors of the schema's
+// constraints; Wrap makes generated methods return classified errors.
+type ConstraintErrors struct {
+	Enabled bool
+	Wrap    bool
 }
 
 // PreparedQueriesConfig captures optional prepared statement generation settings.
@@ -201,14 +340,18 @@ type Config struct {
 	Out          string            `toml:"out"`
 	Language     Language          `toml:"language"`
 	Database     Database          `toml:"database"`
+	SQLPackage   SQLPackage        `toml:"sql_package"`
 	SQLiteDriver Driver            `toml:"sqlite_driver"`
 	Schemas      []string          `toml:"schemas"`
 	Queries      []string          `toml:"queries"`
 	CustomTypes  CustomTypesConfig `toml:"custom_types"`
+	Functions    []FunctionConfig  `toml:"functions"`
 	// Overrides are parsed separately to handle flexible go_type formats
-	Generation      GenerationOptions     `toml:"generation"`
-	PreparedQueries PreparedQueriesConfig `toml:"prepared_queries"`
-	Cache           CacheConfig           `toml:"cache"`
+	Generation      GenerationOptions      `toml:"generation"`
+	PreparedQueries PreparedQueriesConfig  `toml:"prepared_queries"`
+	SQLiteTypes     SQLiteTypesConfig      `toml:"sqlite_types"`
+	Errors          ConstraintErrorsConfig `toml:"constraint_errors"`
+	Cache           CacheConfig            `toml:"cache"`
 }
 
 // LoadOptions tunes config loading behavior.
@@ -295,6 +438,11 @@ func Load(path string, opts LoadOptions) (Result, error) {
 		return res, err
 	}
 
+	sqlPackage, err := resolveSQLPackage(path, cfg.SQLPackage, db, cfg.PreparedQueries.Enabled)
+	if err != nil {
+		return res, err
+	}
+
 	baseDir := filepath.Dir(path)
 
 	var resolver fileset.Resolver
@@ -333,6 +481,34 @@ func Load(path string, opts LoadOptions) (Result, error) {
 	convertedOverrides := convertRawOverrides(rawOverrides)
 	columnOverrides := normalizeColumnOverrides(convertedOverrides)
 
+	nullStyle, err := resolveNullStyle(path, cfg.Generation, db)
+	if err != nil {
+		return res, err
+	}
+	for _, o := range convertedOverrides {
+		if err := validateNullStyle(path, "overrides null_style for "+o.Column, o.NullStyle, db, cfg.Generation.NullOptionType); err != nil {
+			return res, err
+		}
+	}
+
+	sqliteTypes, err := resolveSQLiteTypes(path, cfg.SQLiteTypes, db)
+	if err != nil {
+		return res, err
+	}
+
+	if cfg.Errors.Wrap && !cfg.Errors.Enabled {
+		return res, fmt.Errorf("%s: constraint_errors wrap requires enabled", path)
+	}
+
+	if cfg.Generation.EmitDriverHelpers && db != DatabaseSQLite {
+		return res, fmt.Errorf("%s: emit_driver_helpers requires database %q", path, DatabaseSQLite)
+	}
+
+	functions, err := normalizeFunctions(path, cfg.Functions)
+	if err != nil {
+		return res, err
+	}
+
 	// Set default cache directory if enabled but not specified
 	cacheDir := cfg.Cache.Dir
 	if cfg.Cache.Enabled && cacheDir == "" {
@@ -340,19 +516,26 @@ func Load(path string, opts LoadOptions) (Result, error) {
 	}
 
 	res.Plan = JobPlan{
-		Package:             cfg.Package,
-		Out:                 out,
-		Language:            lang,
-		Database:            db,
-		SQLiteDriver:        driver,
-		Schemas:             schemas,
-		Queries:             queries,
-		CustomTypes:         customTypes,
-		ColumnOverrides:     columnOverrides,
-		EmitJSONTags:        cfg.Generation.EmitJSONTags,
-		EmitPointersForNull: cfg.Generation.EmitPointersForNull,
-		PreparedQueries:     prepared,
-		SQLDialect:          cfg.Generation.SQLDialect,
+		Package:         cfg.Package,
+		Out:             out,
+		Language:        lang,
+		Database:        db,
+		SQLPackage:      sqlPackage,
+		SQLiteDriver:    driver,
+		Schemas:         schemas,
+		Queries:         queries,
+		CustomTypes:     customTypes,
+		ColumnOverrides: columnOverrides,
+		Functions:       functions,
+		EmitJSONTags:    cfg.Generation.EmitJSONTags,
+		NullStyle:       nullStyle,
+		NullOption:      nullOptionType(cfg.Generation.NullOptionType),
+		PreparedQueries: prepared,
+		SQLiteTypes:     sqliteTypes,
+		Errors:          ConstraintErrors{Enabled: cfg.Errors.Enabled, Wrap: cfg.Errors.Wrap},
+		SQLDialect:      cfg.Generation.SQLDialect,
+		EmitDocs:        cfg.Generation.EmitDocs,
+		DriverHelpers:   cfg.Generation.EmitDriverHelpers,
 		Cache: Cache{
 			Enabled: cfg.Cache.Enabled,
 			Dir:     cacheDir,
@@ -369,18 +552,22 @@ func collectUnknownKeys(data []byte) ([]string, error) {
 	}
 
 	known := map[string]struct{}{
-		"package":          {},
-		"out":              {},
-		"language":         {},
-		"database":         {},
-		"sqlite_driver":    {},
-		"schemas":          {},
-		"queries":          {},
-		"custom_types":     {},
-		"overrides":        {},
-		"generation":       {},
-		"prepared_queries": {},
-		"cache":            {},
+		"package":           {},
+		"out":               {},
+		"language":          {},
+		"database":          {},
+		"sql_package":       {},
+		"sqlite_driver":     {},
+		"schemas":           {},
+		"queries":           {},
+		"custom_types":      {},
+		"functions":         {},
+		"overrides":         {},
+		"generation":        {},
+		"prepared_queries":  {},
+		"sqlite_types":      {},
+		"constraint_errors": {},
+		"cache":             {},
 	}
 
 	unknown := make([]string, 0)
@@ -478,6 +665,99 @@ func resolveDatabase(path string, db Database) (Database, error) {
 	return db, nil
 }
 
+// resolveSQLPackage validates sql_package. pgx/v5 requires PostgreSQL, and
+// prepared statements are left to pgx's statement cache.
+func resolveSQLPackage(path string, pkg SQLPackage, db Database, prepared bool) (SQLPackage, error) {
+	if pkg == "" {
+		return SQLPackageDatabaseSQL, nil
+	}
+	if _, ok := validSQLPackages[pkg]; !ok {
+		return "", fmt.Errorf("%s: unsupported sql_package %q", path, pkg)
+	}
+	if pkg == SQLPackagePgxV5 {
+		if db != DatabasePostgreSQL {
+			return "", fmt.Errorf("%s: sql_package %q requires database %q", path, pkg, DatabasePostgreSQL)
+		}
+		if prepared {
+			return "", fmt.Errorf("%s: prepared_queries is not supported with sql_package %q", path, pkg)
+		}
+	}
+	return pkg, nil
+}
+
+// resolveNullStyle returns the null_style of gen, which defaults to pgtype
+// types for PostgreSQL, pointers when the deprecated emit_pointers_for_null
+// is set and the sql.Null* types otherwise.
+func resolveNullStyle(path string, gen GenerationOptions, db Database) (NullStyle, error) {
+	style := gen.NullStyle
+	switch {
+	case style == "" && gen.EmitPointersForNull:
+		style = NullStylePointer
+	case style == "" && db == DatabasePostgreSQL:
+		style = NullStylePgtype
+	case style == "":
+		style = NullStyleSQLNull
+	case gen.EmitPointersForNull && style != NullStylePointer:
+		return "", fmt.Errorf("%s: emit_pointers_for_null conflicts with null_style %q", path, style)
+	}
+	if err := validateNullStyle(path, "null_style", style, db, gen.NullOptionType); err != nil {
+		return "", err
+	}
+	return style, nil
+}
+
+// validateNullStyle checks that style, set by field, can be generated for db.
+// An empty style is valid and means the generation null_style.
+func validateNullStyle(path, field string, style NullStyle, db Database, optionType string) error {
+	if style == "" {
+		return nil
+	}
+	if _, ok := validNullStyles[style]; !ok {
+		return fmt.Errorf("%s: unsupported %s %q", path, field, style)
+	}
+	switch style {
+	case NullStylePgtype:
+		if db != DatabasePostgreSQL {
+			return fmt.Errorf("%s: %s %q requires database %q", path, field, style, DatabasePostgreSQL)
+		}
+	case NullStyleOption:
+		if !token.IsIdentifier(nullOptionType(optionType).Type) {
+			return fmt.Errorf("%s: %s %q requires null_option_type, such as \"github.com/acme/opt.Option\"", path, field, style)
+		}
+	}
+	return nil
+}
+
+// resolveSQLiteTypes validates [sqlite_types], whose time_encoding defaults
+// to RFC 3339 text.
+func resolveSQLiteTypes(path string, cfg SQLiteTypesConfig, db Database) (SQLiteTypes, error) {
+	if !cfg.Enabled {
+		return SQLiteTypes{}, nil
+	}
+	if db != DatabaseSQLite {
+		return SQLiteTypes{}, fmt.Errorf("%s: sqlite_types requires database %q", path, DatabaseSQLite)
+	}
+	encoding := cfg.TimeEncoding
+	if encoding == "" {
+		encoding = StorageRFC3339
+	}
+	if !encoding.IsTime() {
+		return SQLiteTypes{}, fmt.Errorf("%s: unsupported sqlite_types time_encoding %q", path, encoding)
+	}
+	return SQLiteTypes{Enabled: true, TimeEncoding: encoding}, nil
+}
+
+// nullOptionType splits a null_option_type such as
+// "github.com/acme/opt.Option" into its import path, package and name.
+func nullOptionType(qualified string) GoTypeDetails {
+	importPath, typeName := extractImportAndType(qualified)
+	details := GoTypeDetails{Import: importPath, Type: typeName}
+	if importPath != "" {
+		details.Package = extractPackageName(importPath)
+	}
+	return details
+}
+
 func resolvePatterns(resolver fileset.Resolver, field string, patterns []string) ([]string, error) {
 	paths, err := resolver.Resolve(patterns)
 	if err != nil {
@@ -567,6 +847,39 @@ func extractPackageName(importPath string) string {
 	return parts[len(parts)-1]
 }
 
+// normalizeFunctions validates declared functions and splits qualified Go
+// types into their import path and package name.
+func normalizeFunctions(path string, fns []FunctionConfig) ([]FunctionConfig, error) {
+	result := make([]FunctionConfig, 0, len(fns))
+	seen := make(map[string]struct{}, len(fns))
+	for i, fn := range fns {
+		if fn.Name == "" {
+			return nil, fmt.Errorf("%s: functions[%d]: name is required", path, i)
+		}
+		key := strings.ToLower(fn.Name)
+		if _, ok := seen[key]; ok {
+			return nil, fmt.Errorf("%s: duplicate function %q", path, fn.Name)
+		}
+		seen[key] = struct{}{}
+		if fn.Optional < 0 || fn.Optional > len(fn.Args) {
+			return nil, fmt.Errorf("%s: function %q: optional must be between 0 and %d", path, fn.Name, len(fn.Args))
+		}
+
+		if strings.Contains(fn.GoType, "/") {
+			importPath, typeName := extractImportAndType(fn.GoType)
+			fn.GoImport = importPath
+			fn.GoPackage = extractPackageName(importPath)
+			pointer := ""
+			if strings.HasPrefix(typeName, "*") {
+				pointer, typeName = "*", typeName[1:]
+			}
+			fn.GoType = pointer + fn.GoPackage + "." + typeName
+		}
+		result = append(result, fn)
+	}
+	return result, nil
+}
+
 // normalizeColumnOverrides processes column overrides into a normalized lookup map.
 // The key format is "table.column" (lowercase for case-insensitive lookup).
 func normalizeColumnOverrides(overrides []ColumnOverride) map[string]ColumnOverride {
@@ -598,7 +911,9 @@ func convertRawOverrides(raw []rawColumnOverride) []ColumnOverride {
 	result := make([]ColumnOverride, 0, len(raw))
 	for _, r := range raw {
 		co := ColumnOverride{
-			Column: r.Column,
+			Column:    r.Column,
+			JSON:      r.JSON,
+			NullStyle: r.NullStyle,
 		}
 
 		switch v := r.GoType.(type) {
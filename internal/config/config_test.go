@@ -428,6 +428,482 @@ go_type = { import = "github.com/example/types", type = "CustomID", pointer = tr
 	}
 }
 
+func TestLoadColumnOverridesJSON(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	copyFixtureDir(t, tempDir, "schemas")
+	copyFixtureDir(t, tempDir, "queries")
+
+	configPath := writeConfig(t, tempDir, `
+package = "demo"
+out = "gen"
+schemas = ["schemas/*.sql"]
+queries = ["queries/*.sql"]
+
+[[overrides]]
+column = "user_.settings"
+go_type = { import = "github.com/example/types", type = "Settings" }
+json = true
+`)
+
+	result, err := Load(configPath, LoadOptions{})
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	override, ok := result.Plan.ColumnOverrides["user_.settings"]
+	if !ok {
+		t.Fatalf("expected override for 'user_.settings' to be present")
+	}
+	if !override.JSON {
+		t.Errorf("expected JSON to be true")
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", result.Warnings)
+	}
+}
+
+func TestLoadFunctions(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	copyFixtureDir(t, tempDir, "schemas")
+	copyFixtureDir(t, tempDir, "queries")
+
+	configPath := writeConfig(t, tempDir, `
+package = "demo"
+out = "gen"
+schemas = ["schemas/*.sql"]
+queries = ["queries/*.sql"]
+
+[[functions]]
+name = "regexp"
+args = ["TEXT", "TEXT"]
+returns = "BOOLEAN"
+nullable = false
+
+[[functions]]
+name = "haversine"
+args = ["REAL", "REAL", "REAL", "REAL"]
+returns = "REAL"
+
+[[functions]]
+name = "st_centroid"
+args = ["BLOB"]
+go_type = "*github.com/acme/geo.Point"
+`)
+
+	result, err := Load(configPath, LoadOptions{Strict: true})
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	fns := result.Plan.Functions
+	if len(fns) != 3 {
+		t.Fatalf("expected 3 functions, got %d", len(fns))
+	}
+	if fns[0].Name != "regexp" || fns[0].Returns != "BOOLEAN" || fns[0].Nullable == nil || *fns[0].Nullable {
+		t.Errorf("unexpected regexp declaration: %+v", fns[0])
+	}
+	if len(fns[1].Args) != 4 || fns[1].Nullable != nil {
+		t.Errorf("unexpected haversine declaration: %+v", fns[1])
+	}
+	if fns[2].GoType != "*geo.Point" || fns[2].GoImport != "github.com/acme/geo" || fns[2].GoPackage != "geo" {
+		t.Errorf("unexpected st_centroid Go type: %q %q %q", fns[2].GoType, fns[2].GoImport, fns[2].GoPackage)
+	}
+}
+
+func TestLoadFunctionsInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		functions string
+		wantErr   string
+	}{
+		{
+			name:      "missing name",
+			functions: "[[functions]]\nreturns = \"TEXT\"",
+			wantErr:   "functions[0]: name is required",
+		},
+		{
+			name:      "duplicate",
+			functions: "[[functions]]\nname = \"f\"\n[[functions]]\nname = \"F\"",
+			wantErr:   `duplicate function "F"`,
+		},
+		{
+			name:      "optional out of range",
+			functions: "[[functions]]\nname = \"f\"\nargs = [\"TEXT\"]\noptional = 2",
+			wantErr:   "optional must be between 0 and 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tempDir := t.TempDir()
+			copyFixtureDir(t, tempDir, "schemas")
+			copyFixtureDir(t, tempDir, "queries")
+
+			configPath := writeConfig(t, tempDir, `
+package = "demo"
+out = "gen"
+schemas = ["schemas/*.sql"]
+queries = ["queries/*.sql"]
+`+tt.functions)
+
+			_, err := Load(configPath, LoadOptions{})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Load error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadSQLPackage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		extra   string
+		want    SQLPackage
+		wantErr string
+	}{
+		{
+			name: "default",
+			want: SQLPackageDatabaseSQL,
+		},
+		{
+			name:  "pgx",
+			extra: "database = \"postgresql\"\nsql_package = \"pgx/v5\"",
+			want:  SQLPackagePgxV5,
+		},
+		{
+			name:    "unknown",
+			extra:   "sql_package = \"pgx/v4\"",
+			wantErr: `unsupported sql_package "pgx/v4"`,
+		},
+		{
+			name:    "pgx without postgresql",
+			extra:   "sql_package = \"pgx/v5\"",
+			wantErr: `sql_package "pgx/v5" requires database "postgresql"`,
+		},
+		{
+			name:    "pgx with prepared queries",
+			extra:   "database = \"postgresql\"\nsql_package = \"pgx/v5\"\n[prepared_queries]\nenabled = true",
+			wantErr: "prepared_queries is not supported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tempDir := t.TempDir()
+			copyFixtureDir(t, tempDir, "schemas")
+			copyFixtureDir(t, tempDir, "queries")
+
+			configPath := writeConfig(t, tempDir, `
+package = "demo"
+out = "gen"
+schemas = ["schemas/*.sql"]
+queries = ["queries/*.sql"]
+`+tt.extra)
+
+			result, err := Load(configPath, LoadOptions{Strict: true})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load returned error: %v", err)
+			}
+			if result.Plan.SQLPackage != tt.want {
+				t.Fatalf("SQLPackage = %q, want %q", result.Plan.SQLPackage, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadNullStyle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		extra      string
+		want       NullStyle
+		wantOption GoTypeDetails
+		wantErr    string
+	}{
+		{
+			name: "sqlite default",
+			want: NullStyleSQLNull,
+		},
+		{
+			name:  "postgresql default",
+			extra: "database = \"postgresql\"",
+			want:  NullStylePgtype,
+		},
+		{
+			name:  "deprecated pointers",
+			extra: "[generation]\nemit_pointers_for_null = true",
+			want:  NullStylePointer,
+		},
+		{
+			name:  "generic",
+			extra: "[generation]\nnull_style = \"generic\"",
+			want:  NullStyleGeneric,
+		},
+		{
+			name:       "option",
+			extra:      "[generation]\nnull_style = \"option\"\nnull_option_type = \"github.com/acme/opt.Option\"",
+			want:       NullStyleOption,
+			wantOption: GoTypeDetails{Import: "github.com/acme/opt", Package: "opt", Type: "Option"},
+		},
+		{
+			name:    "option without type",
+			extra:   "[generation]\nnull_style = \"option\"",
+			wantErr: `null_style "option" requires null_option_type`,
+		},
+		{
+			name:    "unknown",
+			extra:   "[generation]\nnull_style = \"nullable\"",
+			wantErr: `unsupported null_style "nullable"`,
+		},
+		{
+			name:    "pgtype without postgresql",
+			extra:   "[generation]\nnull_style = \"pgtype\"",
+			wantErr: `null_style "pgtype" requires database "postgresql"`,
+		},
+		{
+			name:    "conflicting pointers",
+			extra:   "[generation]\nnull_style = \"generic\"\nemit_pointers_for_null = true",
+			wantErr: `emit_pointers_for_null conflicts with null_style "generic"`,
+		},
+		{
+			name:    "override",
+			extra:   "[[overrides]]\ncolumn = \"users.email\"\nnull_style = \"pgtype\"",
+			wantErr: `overrides null_style for users.email "pgtype" requires database "postgresql"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tempDir := t.TempDir()
+			copyFixtureDir(t, tempDir, "schemas")
+			copyFixtureDir(t, tempDir, "queries")
+
+			configPath := writeConfig(t, tempDir, `
+package = "demo"
+out = "gen"
+schemas = ["schemas/*.sql"]
+queries = ["queries/*.sql"]
+`+tt.extra)
+
+			result, err := Load(configPath, LoadOptions{Strict: true})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load returned error: %v", err)
+			}
+			if result.Plan.NullStyle != tt.want {
+				t.Fatalf("NullStyle = %q, want %q", result.Plan.NullStyle, tt.want)
+			}
+			if result.Plan.NullOption != tt.wantOption {
+				t.Fatalf("NullOption = %+v, want %+v", result.Plan.NullOption, tt.wantOption)
+			}
+		})
+	}
+}
+
+func TestLoadSQLiteTypes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		extra   string
+		want    SQLiteTypes
+		wantErr string
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name:  "default encoding",
+			extra: "[sqlite_types]\nenabled = true",
+			want:  SQLiteTypes{Enabled: true, TimeEncoding: StorageRFC3339},
+		},
+		{
+			name:  "unix millis",
+			extra: "[sqlite_types]\nenabled = true\ntime_encoding = \"unix_millis\"",
+			want:  SQLiteTypes{Enabled: true, TimeEncoding: StorageUnixMillis},
+		},
+		{
+			name:    "unknown encoding",
+			extra:   "[sqlite_types]\nenabled = true\ntime_encoding = \"json\"",
+			wantErr: `unsupported sqlite_types time_encoding "json"`,
+		},
+		{
+			name:    "postgresql",
+			extra:   "database = \"postgresql\"\n[sqlite_types]\nenabled = true",
+			wantErr: `sqlite_types requires database "sqlite"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tempDir := t.TempDir()
+			copyFixtureDir(t, tempDir, "schemas")
+			copyFixtureDir(t, tempDir, "queries")
+
+			configPath := writeConfig(t, tempDir, `
+package = "demo"
+out = "gen"
+schemas = ["schemas/*.sql"]
+queries = ["queries/*.sql"]
+`+tt.extra)
+
+			result, err := Load(configPath, LoadOptions{Strict: true})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load returned error: %v", err)
+			}
+			if result.Plan.SQLiteTypes != tt.want {
+				t.Fatalf("SQLiteTypes = %+v, want %+v", result.Plan.SQLiteTypes, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadDriverHelpers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		extra   string
+		want    bool
+		wantErr string
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name:  "sqlite",
+			extra: "sqlite_driver = \"mattn\"\n[generation]\nemit_driver_helpers = true",
+			want:  true,
+		},
+		{
+			name:    "postgresql",
+			extra:   "database = \"postgresql\"\n[generation]\nemit_driver_helpers = true",
+			wantErr: `emit_driver_helpers requires database "sqlite"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tempDir := t.TempDir()
+			copyFixtureDir(t, tempDir, "schemas")
+			copyFixtureDir(t, tempDir, "queries")
+
+			configPath := writeConfig(t, tempDir, `
+package = "demo"
+out = "gen"
+schemas = ["schemas/*.sql"]
+queries = ["queries/*.sql"]
+`+tt.extra)
+
+			result, err := Load(configPath, LoadOptions{Strict: true})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load returned error: %v", err)
+			}
+			if result.Plan.DriverHelpers != tt.want {
+				t.Fatalf("DriverHelpers = %v, want %v", result.Plan.DriverHelpers, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConstraintErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		extra   string
+		want    ConstraintErrors
+		wantErr string
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name:  "wrap",
+			extra: "[constraint_errors]\nenabled = true\nwrap = true",
+			want:  ConstraintErrors{Enabled: true, Wrap: true},
+		},
+		{
+			name:    "wrap without enabled",
+			extra:   "[constraint_errors]\nwrap = true",
+			wantErr: "constraint_errors wrap requires enabled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tempDir := t.TempDir()
+			copyFixtureDir(t, tempDir, "schemas")
+			copyFixtureDir(t, tempDir, "queries")
+
+			configPath := writeConfig(t, tempDir, `
+package = "demo"
+out = "gen"
+schemas = ["schemas/*.sql"]
+queries = ["queries/*.sql"]
+`+tt.extra)
+
+			result, err := Load(configPath, LoadOptions{Strict: true})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load returned error: %v", err)
+			}
+			if result.Plan.Errors != tt.want {
+				t.Fatalf("Errors = %+v, want %+v", result.Plan.Errors, tt.want)
+			}
+		})
+	}
+}
+
 func TestNormalizeColumnOverrides(t *testing.T) {
 	testCases := []struct {
 		name      string
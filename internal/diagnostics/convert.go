@@ -4,6 +4,7 @@ package diagnostics
 import (
 	"fmt"
 	"io"
+	"regexp"
 	"strings"
 
 	queryanalyzer "github.com/electwix/db-catalyst/internal/query/analyzer"
@@ -211,8 +212,18 @@ func addSuggestions(d Diagnostic) Diagnostic {
 		d.Notes = append(d.Notes, "Aggregates and expressions in SELECT must have an alias for result naming")
 	}
 
+	// Compound query errors
+	compound := compoundBranchPattern.FindStringSubmatch(d.Message)
+	if compound != nil && compound[3] != "" {
+		operator, branch, got, want := compound[1], compound[2], compound[3], compound[4]
+		d.Suggestions = append(d.Suggestions, Suggestion{
+			Message: fmt.Sprintf("Make %s branch %s return %s columns like the first branch, not %s", operator, branch, want, got),
+		})
+		d.Notes = append(d.Notes, "Every branch of a UNION, INTERSECT or EXCEPT must return the same number of columns")
+	}
+
 	// CTE errors
-	if strings.Contains(msg, "cte") {
+	if strings.Contains(msg, "cte") && compound == nil {
 		if strings.Contains(msg, "missing") {
 			d.Suggestions = append(d.Suggestions, Suggestion{
 				Message: "Ensure CTE has a SELECT body",
@@ -272,11 +283,18 @@ func addSuggestions(d Diagnostic) Diagnostic {
 	return d
 }
 
+// compoundBranchPattern matches the analyzer messages about a branch of a
+// compound query, capturing the operator, the branch number and, for column
+// count mismatches, the branch's and the first branch's column counts.
+var compoundBranchPattern = regexp.MustCompile(`(?i)^((?:UNION|INTERSECT|EXCEPT)(?: ALL)?) branch (\d+) returns (?:(\d+) columns; expected (\d+))?`)
+
 // classifyQueryAnalyzerError determines the appropriate error code for analyzer messages.
 func classifyQueryAnalyzerError(msg string) string {
 	msgLower := strings.ToLower(msg)
 
 	switch {
+	case compoundBranchPattern.MatchString(msg):
+		return ErrQueryCompoundMismatch
 	case strings.Contains(msgLower, "unknown table") || strings.Contains(msgLower, "unknown relation"):
 		return ErrQueryUnknownTable
 	case strings.Contains(msgLower, "unknown column"):
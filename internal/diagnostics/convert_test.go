@@ -286,6 +286,42 @@ func TestAddSuggestions(t *testing.T) {
 	}
 }
 
+func TestCompoundBranchDiagnostics(t *testing.T) {
+	tests := []struct {
+		name           string
+		message        string
+		wantSuggestion string
+	}{
+		{
+			name:           "column count",
+			message:        "UNION ALL branch 2 returns 1 columns; expected 2",
+			wantSuggestion: "Make UNION ALL branch 2 return 2 columns like the first branch, not 1",
+		},
+		{
+			name:           "column type",
+			message:        "EXCEPT branch 2 returns string for column id; expected int64, defaulting to interface{}",
+			wantSuggestion: "Add explicit type casting or use a typed column",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := FromQueryAnalyzer(queryanalyzer.Diagnostic{Path: "test.sql", Line: 3, Column: 1, Message: tt.message})
+			if d.Code != ErrQueryCompoundMismatch {
+				t.Errorf("Code = %q, want %q", d.Code, ErrQueryCompoundMismatch)
+			}
+			enriched := addSuggestions(d)
+			var got []string
+			for _, s := range enriched.Suggestions {
+				got = append(got, s.Message)
+			}
+			if len(got) != 1 || got[0] != tt.wantSuggestion {
+				t.Errorf("suggestions = %q, want [%q]", got, tt.wantSuggestion)
+			}
+		})
+	}
+}
+
 func TestEnrichWithSuggestions(t *testing.T) {
 	c := NewCollection()
 	c.Add(Error("unknown column 'foo'").Build())
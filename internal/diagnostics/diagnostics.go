@@ -443,16 +443,17 @@ const (
 	ErrSchemaInvalidIndex   = "E110"
 
 	// Query parsing errors (E2xx)
-	ErrQueryParseError    = "E201"
-	ErrQueryInvalidVerb   = "E202"
-	ErrQueryInvalidParam  = "E203"
-	ErrQueryInvalidCTE    = "E204"
-	ErrQueryMissingAlias  = "E205"
-	ErrQueryAmbiguousCol  = "E206"
-	ErrQueryUnknownTable  = "E207"
-	ErrQueryUnknownColumn = "E208"
-	ErrQueryTypeMismatch  = "E209"
-	ErrQueryInvalidSyntax = "E210"
+	ErrQueryParseError       = "E201"
+	ErrQueryInvalidVerb      = "E202"
+	ErrQueryInvalidParam     = "E203"
+	ErrQueryInvalidCTE       = "E204"
+	ErrQueryMissingAlias     = "E205"
+	ErrQueryAmbiguousCol     = "E206"
+	ErrQueryUnknownTable     = "E207"
+	ErrQueryUnknownColumn    = "E208"
+	ErrQueryTypeMismatch     = "E209"
+	ErrQueryInvalidSyntax    = "E210"
+	ErrQueryCompoundMismatch = "E211"
 
 	// Configuration errors (E3xx)
 	ErrConfigInvalid        = "E301"
@@ -493,16 +494,17 @@ func CodeDescription(code string) string {
 		ErrSchemaInvalidIndex:   "Invalid index definition",
 
 		// Query errors
-		ErrQueryParseError:    "Query parsing failed",
-		ErrQueryInvalidVerb:   "Invalid or unsupported SQL verb",
-		ErrQueryInvalidParam:  "Invalid parameter syntax",
-		ErrQueryInvalidCTE:    "Invalid common table expression",
-		ErrQueryMissingAlias:  "Missing required alias",
-		ErrQueryAmbiguousCol:  "Ambiguous column reference",
-		ErrQueryUnknownTable:  "Reference to unknown table",
-		ErrQueryUnknownColumn: "Reference to unknown column",
-		ErrQueryTypeMismatch:  "Type mismatch in expression",
-		ErrQueryInvalidSyntax: "Invalid SQL syntax",
+		ErrQueryParseError:       "Query parsing failed",
+		ErrQueryInvalidVerb:      "Invalid or unsupported SQL verb",
+		ErrQueryInvalidParam:     "Invalid parameter syntax",
+		ErrQueryInvalidCTE:       "Invalid common table expression",
+		ErrQueryMissingAlias:     "Missing required alias",
+		ErrQueryAmbiguousCol:     "Ambiguous column reference",
+		ErrQueryUnknownTable:     "Reference to unknown table",
+		ErrQueryUnknownColumn:    "Reference to unknown column",
+		ErrQueryTypeMismatch:     "Type mismatch in expression",
+		ErrQueryInvalidSyntax:    "Invalid SQL syntax",
+		ErrQueryCompoundMismatch: "Compound query branches do not match",
 
 		// Config errors
 		ErrConfigInvalid:        "Invalid configuration",
@@ -507,6 +507,7 @@ func TestCodeDescription(t *testing.T) {
 	}{
 		{ErrSchemaParseError, "Schema parsing failed"},
 		{ErrQueryUnknownTable, "Reference to unknown table"},
+		{ErrQueryCompoundMismatch, "Compound query branches do not match"},
 		{ErrConfigInvalid, "Invalid configuration"},
 		{"UNKNOWN_CODE", "Unknown error code"},
 	}
@@ -57,6 +57,10 @@ type Engine interface {
 
 	// QueryHints returns available query hints for this database.
 	QueryHints() []QueryHint
+
+	// Functions returns the catalog of built-in SQL functions used to type
+	// and validate function calls in queries.
+	Functions() *FunctionCatalog
 }
 
 // ConnectionPoolConfig defines recommended connection pool settings for a database.
@@ -186,8 +190,12 @@ type IndexSuggestion struct {
 
 // Options configures an engine instance.
 type Options struct {
-	// EmitPointersForNull enables pointer types for nullable values.
-	EmitPointersForNull bool
+	// NullStyle selects the types of nullable values; when empty each
+	// engine uses its default, such as pgtype types for PostgreSQL.
+	NullStyle config.NullStyle
+
+	// NullOption is the generic type of config.NullStyleOption.
+	NullOption config.GoTypeDetails
 
 	// CustomTypes provides custom type mappings.
 	CustomTypes []config.CustomTypeMapping
@@ -213,3 +221,14 @@ func MustNew(dialect string, opts Options) Engine {
 func FromConfig(cfg config.Database, opts Options) (Engine, error) {
 	return New(string(cfg), opts)
 }
+
+// NullableType returns the type of a nullable value of the Go type base in
+// the null style of o, or in defaultStyle when o sets none.
+func (o Options) NullableType(base string, defaultStyle config.NullStyle) TypeInfo {
+	style := o.NullStyle
+	if style == "" {
+		style = defaultStyle
+	}
+	nt := types.Nullable(base, style, o.NullOption)
+	return TypeInfo{GoType: nt.GoType, UsesSQLNull: nt.UsesSQLNull, Import: nt.Import, Package: nt.Package, IsPointer: nt.IsPointer}
+}
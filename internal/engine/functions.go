@@ -0,0 +1,212 @@
+package engine
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ResultRule selects how a function's result type is derived.
+type ResultRule int
+
+const (
+	// ResultFixed returns Function.Returns regardless of the arguments.
+	ResultFixed ResultRule = iota
+
+	// ResultArg returns the type of the argument at Function.ArgOffset.
+	ResultArg
+
+	// ResultCommonArgs returns the common type of the arguments from
+	// Function.ArgOffset onward, widening integers to floats.
+	ResultCommonArgs
+
+	// ResultNumericArg returns the type of the argument at Function.ArgOffset,
+	// which must be numeric (SUM).
+	ResultNumericArg
+
+	// ResultArrayOfArg returns a slice of the argument type at Function.ArgOffset.
+	ResultArrayOfArg
+)
+
+// NullRule selects when a function's result may be NULL.
+type NullRule int
+
+const (
+	// NullStrict makes the result nullable when any argument is nullable.
+	NullStrict NullRule = iota
+
+	// NullNever marks results that are never NULL.
+	NullNever
+
+	// NullAlways marks results that may be NULL whatever the arguments.
+	NullAlways
+
+	// NullAllArgs makes the result nullable only when every argument is (COALESCE).
+	NullAllArgs
+)
+
+// Function describes the signature of a built-in or user-declared SQL function.
+type Function struct {
+	// Name is the function name as written in SQL.
+	Name string
+
+	// Args lists the types of the positional arguments; "any" accepts any
+	// value. Only the argument count is checked.
+	Args []string
+
+	// Optional is the number of trailing Args that may be omitted.
+	Optional int
+
+	// Variadic allows the last argument to repeat.
+	Variadic bool
+
+	// Aggregate marks aggregate functions. A variadic aggregate called with
+	// more than one argument, such as SQLite's MIN(a, b), is a scalar call.
+	Aggregate bool
+
+	// Result selects how the return type is derived from the arguments.
+	Result ResultRule
+
+	// Returns is the Go result type for ResultFixed; "any" leaves it untyped.
+	Returns string
+
+	// ReturnsSQL is a SQL result type for ResultFixed, resolved through the
+	// dialect type mapping when Returns is empty.
+	ReturnsSQL string
+
+	// Import and Package qualify Returns when it names a type from another package.
+	Import  string
+	Package string
+
+	// ArgOffset is the first argument consulted by argument-based result rules.
+	ArgOffset int
+
+	// Null selects when the result may be NULL.
+	Null NullRule
+}
+
+// MinArgs returns the fewest arguments the function accepts.
+func (f Function) MinArgs() int {
+	return len(f.Args) - f.Optional
+}
+
+// MaxArgs returns the most arguments the function accepts, or -1 when variadic.
+func (f Function) MaxArgs() int {
+	if f.Variadic {
+		return -1
+	}
+	return len(f.Args)
+}
+
+// Accepts reports whether the function can be called with n arguments.
+func (f Function) Accepts(n int) bool {
+	if n < f.MinArgs() {
+		return false
+	}
+	return f.Variadic || n <= len(f.Args)
+}
+
+// Arity describes the accepted argument count for diagnostics.
+func (f Function) Arity() string {
+	minArgs, maxArgs := f.MinArgs(), f.MaxArgs()
+	switch {
+	case maxArgs < 0:
+		return fmt.Sprintf("at least %d", minArgs)
+	case minArgs == maxArgs:
+		return fmt.Sprintf("%d", minArgs)
+	default:
+		return fmt.Sprintf("%d to %d", minArgs, maxArgs)
+	}
+}
+
+// FunctionCatalog indexes function signatures by case-insensitive name.
+type FunctionCatalog struct {
+	funcs map[string]Function
+}
+
+// NewFunctionCatalog builds a catalog from one or more function sets.
+// Later sets override earlier definitions of the same name.
+func NewFunctionCatalog(sets ...[]Function) *FunctionCatalog {
+	c := &FunctionCatalog{funcs: make(map[string]Function)}
+	for _, set := range sets {
+		for _, fn := range set {
+			c.funcs[strings.ToUpper(fn.Name)] = fn
+		}
+	}
+	return c
+}
+
+// With returns a copy of the catalog extended with fns, which replace any
+// existing definitions of the same name.
+func (c *FunctionCatalog) With(fns ...Function) *FunctionCatalog {
+	out := &FunctionCatalog{funcs: make(map[string]Function)}
+	if c != nil {
+		maps.Copy(out.funcs, c.funcs)
+	}
+	for _, fn := range fns {
+		out.funcs[strings.ToUpper(fn.Name)] = fn
+	}
+	return out
+}
+
+// Lookup returns the signature registered for name.
+func (c *FunctionCatalog) Lookup(name string) (Function, bool) {
+	if c == nil {
+		return Function{}, false
+	}
+	fn, ok := c.funcs[strings.ToUpper(name)]
+	return fn, ok
+}
+
+// Functions returns all signatures sorted by name.
+func (c *FunctionCatalog) Functions() []Function {
+	if c == nil {
+		return nil
+	}
+	out := make([]Function, 0, len(c.funcs))
+	for _, fn := range c.funcs {
+		out = append(out, fn)
+	}
+	slices.SortFunc(out, func(a, b Function) int {
+		return strings.Compare(strings.ToUpper(a.Name), strings.ToUpper(b.Name))
+	})
+	return out
+}
+
+// StandardFunctions returns the functions shared by every supported dialect.
+func StandardFunctions() []Function {
+	return []Function{
+		{Name: "count", Args: []string{"any"}, Aggregate: true, Result: ResultFixed, Returns: "int64", Null: NullNever},
+		{Name: "sum", Args: []string{"any"}, Aggregate: true, Result: ResultNumericArg, Null: NullAlways},
+		{Name: "avg", Args: []string{"any"}, Aggregate: true, Result: ResultFixed, Returns: "float64", Null: NullAlways},
+		{Name: "min", Args: []string{"any"}, Aggregate: true, Result: ResultArg, Null: NullAlways},
+		{Name: "max", Args: []string{"any"}, Aggregate: true, Result: ResultArg, Null: NullAlways},
+		{Name: "coalesce", Args: []string{"any", "any"}, Variadic: true, Result: ResultCommonArgs, Null: NullAllArgs},
+		{Name: "nullif", Args: []string{"any", "any"}, Result: ResultArg, Null: NullAlways},
+		{Name: "abs", Args: []string{"any"}, Result: ResultArg},
+		{Name: "round", Args: []string{"float64", "int64"}, Optional: 1, Result: ResultFixed, Returns: "float64"},
+		{Name: "lower", Args: []string{"string"}, Result: ResultFixed, Returns: "string"},
+		{Name: "upper", Args: []string{"string"}, Result: ResultFixed, Returns: "string"},
+		{Name: "trim", Args: []string{"string", "string"}, Optional: 1, Result: ResultFixed, Returns: "string"},
+		{Name: "ltrim", Args: []string{"string", "string"}, Optional: 1, Result: ResultFixed, Returns: "string"},
+		{Name: "rtrim", Args: []string{"string", "string"}, Optional: 1, Result: ResultFixed, Returns: "string"},
+		{Name: "replace", Args: []string{"string", "string", "string"}, Result: ResultFixed, Returns: "string"},
+		{Name: "substr", Args: []string{"string", "int64", "int64"}, Optional: 1, Result: ResultFixed, Returns: "string"},
+		{Name: "substring", Args: []string{"string", "int64", "int64"}, Optional: 1, Result: ResultFixed, Returns: "string"},
+		{Name: "length", Args: []string{"any"}, Result: ResultFixed, Returns: "int64"},
+
+		// Window functions.
+		{Name: "row_number", Result: ResultFixed, Returns: "int64", Null: NullNever},
+		{Name: "rank", Result: ResultFixed, Returns: "int64", Null: NullNever},
+		{Name: "dense_rank", Result: ResultFixed, Returns: "int64", Null: NullNever},
+		{Name: "percent_rank", Result: ResultFixed, Returns: "float64", Null: NullNever},
+		{Name: "cume_dist", Result: ResultFixed, Returns: "float64", Null: NullNever},
+		{Name: "ntile", Args: []string{"int64"}, Result: ResultFixed, Returns: "int64", Null: NullNever},
+		{Name: "lag", Args: []string{"any", "int64", "any"}, Optional: 2, Result: ResultArg, Null: NullAlways},
+		{Name: "lead", Args: []string{"any", "int64", "any"}, Optional: 2, Result: ResultArg, Null: NullAlways},
+		{Name: "first_value", Args: []string{"any"}, Result: ResultArg, Null: NullAlways},
+		{Name: "last_value", Args: []string{"any"}, Result: ResultArg, Null: NullAlways},
+		{Name: "nth_value", Args: []string{"any", "int64"}, Result: ResultArg, Null: NullAlways},
+	}
+}
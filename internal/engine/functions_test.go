@@ -0,0 +1,115 @@
+package engine
+
+import "testing"
+
+func TestFunction_Arity(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      Function
+		accepts []int
+		rejects []int
+		arity   string
+	}{
+		{
+			name:    "fixed",
+			fn:      Function{Name: "replace", Args: []string{"string", "string", "string"}},
+			accepts: []int{3},
+			rejects: []int{0, 2, 4},
+			arity:   "3",
+		},
+		{
+			name:    "optional",
+			fn:      Function{Name: "round", Args: []string{"float64", "int64"}, Optional: 1},
+			accepts: []int{1, 2},
+			rejects: []int{0, 3},
+			arity:   "1 to 2",
+		},
+		{
+			name:    "variadic",
+			fn:      Function{Name: "coalesce", Args: []string{"any", "any"}, Variadic: true},
+			accepts: []int{2, 3, 10},
+			rejects: []int{0, 1},
+			arity:   "at least 2",
+		},
+		{
+			name:    "no arguments",
+			fn:      Function{Name: "random"},
+			accepts: []int{0},
+			rejects: []int{1},
+			arity:   "0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, n := range tt.accepts {
+				if !tt.fn.Accepts(n) {
+					t.Errorf("Accepts(%d) = false, want true", n)
+				}
+			}
+			for _, n := range tt.rejects {
+				if tt.fn.Accepts(n) {
+					t.Errorf("Accepts(%d) = true, want false", n)
+				}
+			}
+			if got := tt.fn.Arity(); got != tt.arity {
+				t.Errorf("Arity() = %q, want %q", got, tt.arity)
+			}
+		})
+	}
+}
+
+func TestFunctionCatalog_Lookup(t *testing.T) {
+	catalog := NewFunctionCatalog(StandardFunctions(), []Function{
+		{Name: "min", Args: []string{"any"}, Variadic: true, Aggregate: true, Result: ResultCommonArgs},
+	})
+
+	fn, ok := catalog.Lookup("COUNT")
+	if !ok || fn.Returns != "int64" {
+		t.Errorf("Lookup(COUNT) = %+v, %v; want int64 result", fn, ok)
+	}
+	if fn, ok := catalog.Lookup("Min"); !ok || !fn.Variadic {
+		t.Errorf("Lookup(Min) = %+v, %v; want the overriding variadic definition", fn, ok)
+	}
+	if _, ok := catalog.Lookup("no_such_function"); ok {
+		t.Error("Lookup(no_such_function) should fail")
+	}
+
+	var nilCatalog *FunctionCatalog
+	if _, ok := nilCatalog.Lookup("count"); ok {
+		t.Error("nil catalog Lookup should fail")
+	}
+
+	fns := catalog.Functions()
+	for i := 1; i < len(fns); i++ {
+		if fns[i-1].Name > fns[i].Name {
+			t.Fatalf("Functions() not sorted: %s before %s", fns[i-1].Name, fns[i].Name)
+		}
+	}
+}
+
+func TestFunctionCatalog_With(t *testing.T) {
+	base := NewFunctionCatalog(StandardFunctions())
+	extended := base.With(
+		Function{Name: "haversine", Args: []string{"REAL", "REAL", "REAL", "REAL"}, ReturnsSQL: "REAL"},
+		Function{Name: "lower", Args: []string{"any"}, Returns: "any"},
+	)
+
+	if _, ok := extended.Lookup("HAVERSINE"); !ok {
+		t.Error("With() should add haversine")
+	}
+	if fn, _ := extended.Lookup("lower"); fn.Returns != "any" {
+		t.Errorf("With() should replace lower, got %+v", fn)
+	}
+	if _, ok := base.Lookup("haversine"); ok {
+		t.Error("With() must not modify the receiver")
+	}
+	if fn, _ := base.Lookup("lower"); fn.Returns != "string" {
+		t.Errorf("receiver lower changed to %+v", fn)
+	}
+
+	var nilCatalog *FunctionCatalog
+	if _, ok := nilCatalog.With(Function{Name: "f"}).Lookup("f"); !ok {
+		t.Error("nil catalog With() should return the added functions")
+	}
+}
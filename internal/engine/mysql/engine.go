@@ -11,15 +11,17 @@ import (
 
 // Engine implements the engine.Engine interface for MySQL.
 type Engine struct {
-	opts   engine.Options
-	parser diagnostic.SchemaParser
+	opts      engine.Options
+	parser    diagnostic.SchemaParser
+	functions *engine.FunctionCatalog
 }
 
 // New creates a new MySQL engine instance.
 func New(opts engine.Options) (engine.Engine, error) {
 	return &Engine{
-		opts:   opts,
-		parser: mysql.New(),
+		opts:      opts,
+		parser:    mysql.New(),
+		functions: engine.NewFunctionCatalog(engine.StandardFunctions(), Functions()),
 	}, nil
 }
 
@@ -163,3 +165,8 @@ func (e *Engine) QueryHints() []engine.QueryHint {
 		},
 	}
 }
+
+// Functions returns the MySQL built-in function catalog.
+func (e *Engine) Functions() *engine.FunctionCatalog {
+	return e.functions
+}
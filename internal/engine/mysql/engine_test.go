@@ -254,3 +254,16 @@ func TestEngine_QueryHints_HasIndexHints(t *testing.T) {
 		t.Error("MySQL should have index hints (USE_INDEX, FORCE_INDEX, IGNORE_INDEX)")
 	}
 }
+
+func TestEngine_Functions(t *testing.T) {
+	e, err := New(engine.Options{})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for _, name := range []string{"DATE_FORMAT", "IFNULL", "GROUP_CONCAT", "count"} {
+		if _, ok := e.Functions().Lookup(name); !ok {
+			t.Errorf("Functions() missing %s", name)
+		}
+	}
+}
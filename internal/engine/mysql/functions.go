@@ -0,0 +1,69 @@
+package mysql
+
+import "github.com/electwix/db-catalyst/internal/engine"
+
+// Functions returns the MySQL built-in functions beyond engine.StandardFunctions.
+func Functions() []engine.Function {
+	return []engine.Function{
+		// Date and time functions.
+		{Name: "now", Args: []string{"int64"}, Optional: 1, Result: engine.ResultFixed, Returns: "time.Time", Null: engine.NullNever},
+		{Name: "sysdate", Args: []string{"int64"}, Optional: 1, Result: engine.ResultFixed, Returns: "time.Time", Null: engine.NullNever},
+		{Name: "curdate", Result: engine.ResultFixed, Returns: "time.Time", Null: engine.NullNever},
+		{Name: "utc_timestamp", Args: []string{"int64"}, Optional: 1, Result: engine.ResultFixed, Returns: "time.Time", Null: engine.NullNever},
+		{Name: "date_format", Args: []string{"time.Time", "string"}, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "date_add", Args: []string{"time.Time", "any"}, Result: engine.ResultFixed, Returns: "time.Time"},
+		{Name: "date_sub", Args: []string{"time.Time", "any"}, Result: engine.ResultFixed, Returns: "time.Time"},
+		{Name: "datediff", Args: []string{"time.Time", "time.Time"}, Result: engine.ResultFixed, Returns: "int64"},
+		{Name: "timestampdiff", Args: []string{"any", "time.Time", "time.Time"}, Result: engine.ResultFixed, Returns: "int64"},
+		{Name: "unix_timestamp", Args: []string{"time.Time"}, Optional: 1, Result: engine.ResultFixed, Returns: "int64"},
+		{Name: "from_unixtime", Args: []string{"int64", "string"}, Optional: 1, Result: engine.ResultFixed, Returns: "time.Time"},
+		{Name: "year", Args: []string{"time.Time"}, Result: engine.ResultFixed, Returns: "int64"},
+		{Name: "month", Args: []string{"time.Time"}, Result: engine.ResultFixed, Returns: "int64"},
+		{Name: "day", Args: []string{"time.Time"}, Result: engine.ResultFixed, Returns: "int64"},
+		{Name: "extract", Args: []string{"any"}, Result: engine.ResultFixed, Returns: "int64"},
+
+		// String functions.
+		{Name: "concat", Args: []string{"any"}, Variadic: true, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "concat_ws", Args: []string{"string", "any"}, Variadic: true, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "char_length", Args: []string{"string"}, Result: engine.ResultFixed, Returns: "int64"},
+		{Name: "left", Args: []string{"string", "int64"}, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "right", Args: []string{"string", "int64"}, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "lpad", Args: []string{"string", "int64", "string"}, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "rpad", Args: []string{"string", "int64", "string"}, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "locate", Args: []string{"string", "string", "int64"}, Optional: 1, Result: engine.ResultFixed, Returns: "int64"},
+		{Name: "md5", Args: []string{"string"}, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "uuid", Result: engine.ResultFixed, Returns: "string", Null: engine.NullNever},
+
+		// Conditional and math functions.
+		{Name: "ifnull", Args: []string{"any", "any"}, Result: engine.ResultCommonArgs, Null: engine.NullAllArgs},
+		{Name: "if", Args: []string{"bool", "any", "any"}, Result: engine.ResultCommonArgs, ArgOffset: 1},
+		{Name: "greatest", Args: []string{"any", "any"}, Variadic: true, Result: engine.ResultCommonArgs},
+		{Name: "least", Args: []string{"any", "any"}, Variadic: true, Result: engine.ResultCommonArgs},
+		{Name: "ceil", Args: []string{"any"}, Result: engine.ResultFixed, Returns: "int64"},
+		{Name: "floor", Args: []string{"any"}, Result: engine.ResultFixed, Returns: "int64"},
+		{Name: "truncate", Args: []string{"any", "int64"}, Result: engine.ResultArg},
+		{Name: "mod", Args: []string{"any", "any"}, Result: engine.ResultCommonArgs},
+		{Name: "pow", Args: []string{"float64", "float64"}, Result: engine.ResultFixed, Returns: "float64"},
+		{Name: "power", Args: []string{"float64", "float64"}, Result: engine.ResultFixed, Returns: "float64"},
+		{Name: "sqrt", Args: []string{"float64"}, Result: engine.ResultFixed, Returns: "float64"},
+		{Name: "rand", Args: []string{"int64"}, Optional: 1, Result: engine.ResultFixed, Returns: "float64", Null: engine.NullNever},
+		{Name: "last_insert_id", Args: []string{"int64"}, Optional: 1, Result: engine.ResultFixed, Returns: "int64", Null: engine.NullNever},
+		{Name: "row_count", Result: engine.ResultFixed, Returns: "int64", Null: engine.NullNever},
+		{Name: "found_rows", Result: engine.ResultFixed, Returns: "int64", Null: engine.NullNever},
+
+		// Aggregate functions.
+		{Name: "group_concat", Args: []string{"any"}, Variadic: true, Aggregate: true, Result: engine.ResultFixed, Returns: "string", Null: engine.NullAlways},
+		{Name: "bit_and", Args: []string{"any"}, Aggregate: true, Result: engine.ResultFixed, Returns: "uint64", Null: engine.NullNever},
+		{Name: "bit_or", Args: []string{"any"}, Aggregate: true, Result: engine.ResultFixed, Returns: "uint64", Null: engine.NullNever},
+		{Name: "json_arrayagg", Args: []string{"any"}, Aggregate: true, Result: engine.ResultFixed, Returns: "json.RawMessage", Null: engine.NullAlways},
+		{Name: "json_objectagg", Args: []string{"any", "any"}, Aggregate: true, Result: engine.ResultFixed, Returns: "json.RawMessage", Null: engine.NullAlways},
+
+		// JSON functions. JSON_EXTRACT returns JSON; JSON_UNQUOTE turns it into text.
+		{Name: "json_extract", Args: []string{"any", "string"}, Variadic: true, Result: engine.ResultFixed, Returns: "json.RawMessage", Null: engine.NullAlways},
+		{Name: "json_unquote", Args: []string{"any"}, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "json_object", Args: []string{"any"}, Optional: 1, Variadic: true, Result: engine.ResultFixed, Returns: "json.RawMessage", Null: engine.NullNever},
+		{Name: "json_array", Args: []string{"any"}, Optional: 1, Variadic: true, Result: engine.ResultFixed, Returns: "json.RawMessage", Null: engine.NullNever},
+		{Name: "json_length", Args: []string{"any", "string"}, Optional: 1, Result: engine.ResultFixed, Returns: "int64", Null: engine.NullAlways},
+		{Name: "json_contains", Args: []string{"any", "any", "string"}, Optional: 1, Result: engine.ResultFixed, Returns: "bool"},
+	}
+}
@@ -3,6 +3,7 @@ package mysql
 import (
 	"strings"
 
+	"github.com/electwix/db-catalyst/internal/config"
 	"github.com/electwix/db-catalyst/internal/engine"
 	"github.com/electwix/db-catalyst/internal/types"
 )
@@ -177,7 +178,7 @@ func (m *typeMapper) sqlTypeToGo(sqlType string) string {
 
 	// JSON
 	case strings.Contains(upperType, "JSON"):
-		return "[]byte" // Or json.RawMessage with encoding/json
+		return "json.RawMessage"
 
 	// Enum and Set
 	case strings.Contains(upperType, "ENUM"), strings.Contains(upperType, "SET"):
@@ -188,39 +189,8 @@ func (m *typeMapper) sqlTypeToGo(sqlType string) string {
 	}
 }
 
-// resolveNullableType handles nullable type resolution for MySQL.
+// resolveNullableType handles nullable type resolution for MySQL, using
+// the sql.Null* types unless another null style is configured.
 func (m *typeMapper) resolveNullableType(base string) engine.TypeInfo {
-	if m.opts.EmitPointersForNull {
-		if !strings.HasPrefix(base, "*") {
-			return engine.TypeInfo{GoType: "*" + base, IsPointer: true}
-		}
-		return engine.TypeInfo{GoType: base, IsPointer: true}
-	}
-
-	// Use sql.Null* types for nullable values (same as SQLite)
-	switch base {
-	case "int8":
-		return engine.TypeInfo{GoType: "sql.NullInt64", Import: "database/sql", Package: "sql", UsesSQLNull: true}
-	case "int16":
-		return engine.TypeInfo{GoType: "sql.NullInt16", Import: "database/sql", Package: "sql", UsesSQLNull: true}
-	case "int32":
-		return engine.TypeInfo{GoType: "sql.NullInt32", Import: "database/sql", Package: "sql", UsesSQLNull: true}
-	case "int64":
-		return engine.TypeInfo{GoType: "sql.NullInt64", Import: "database/sql", Package: "sql", UsesSQLNull: true}
-	case "float32":
-		return engine.TypeInfo{GoType: "sql.NullFloat64", Import: "database/sql", Package: "sql", UsesSQLNull: true}
-	case "float64":
-		return engine.TypeInfo{GoType: "sql.NullFloat64", Import: "database/sql", Package: "sql", UsesSQLNull: true}
-	case "string":
-		return engine.TypeInfo{GoType: "sql.NullString", Import: "database/sql", Package: "sql", UsesSQLNull: true}
-	case "bool":
-		return engine.TypeInfo{GoType: "sql.NullBool", Import: "database/sql", Package: "sql", UsesSQLNull: true}
-	case "time.Time":
-		return engine.TypeInfo{GoType: "sql.NullTime", Import: "database/sql", Package: "sql", UsesSQLNull: true}
-	default:
-		if !strings.HasPrefix(base, "*") {
-			return engine.TypeInfo{GoType: "*" + base, IsPointer: true}
-		}
-		return engine.TypeInfo{GoType: base, IsPointer: true}
-	}
+	return m.opts.NullableType(base, config.NullStyleSQLNull)
 }
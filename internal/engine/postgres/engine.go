@@ -11,15 +11,17 @@ import (
 
 // Engine implements the engine.Engine interface for PostgreSQL.
 type Engine struct {
-	opts   engine.Options
-	parser diagnostic.SchemaParser
+	opts      engine.Options
+	parser    diagnostic.SchemaParser
+	functions *engine.FunctionCatalog
 }
 
 // New creates a new PostgreSQL engine instance.
 func New(opts engine.Options) (engine.Engine, error) {
 	return &Engine{
-		opts:   opts,
-		parser: postgres.New(),
+		opts:      opts,
+		parser:    postgres.New(),
+		functions: engine.NewFunctionCatalog(engine.StandardFunctions(), Functions()),
 	}, nil
 }
 
@@ -147,3 +149,8 @@ func (e *Engine) QueryHints() []engine.QueryHint {
 		},
 	}
 }
+
+// Functions returns the PostgreSQL built-in function catalog.
+func (e *Engine) Functions() *engine.FunctionCatalog {
+	return e.functions
+}
@@ -228,3 +228,16 @@ func TestEngine_QueryHints_ContainsExpected(t *testing.T) {
 		}
 	}
 }
+
+func TestEngine_Functions(t *testing.T) {
+	e, err := New(engine.Options{})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for _, name := range []string{"now", "date_trunc", "array_agg", "jsonb_build_object", "gen_random_uuid", "count"} {
+		if _, ok := e.Functions().Lookup(name); !ok {
+			t.Errorf("Functions() missing %s", name)
+		}
+	}
+}
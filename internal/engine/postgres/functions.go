@@ -0,0 +1,88 @@
+package postgres
+
+import "github.com/electwix/db-catalyst/internal/engine"
+
+// Functions returns the PostgreSQL built-in functions beyond engine.StandardFunctions.
+func Functions() []engine.Function {
+	return []engine.Function{
+		// Date and time functions.
+		{Name: "now", Result: engine.ResultFixed, Returns: "time.Time", Null: engine.NullNever},
+		{Name: "clock_timestamp", Result: engine.ResultFixed, Returns: "time.Time", Null: engine.NullNever},
+		{Name: "statement_timestamp", Result: engine.ResultFixed, Returns: "time.Time", Null: engine.NullNever},
+		{Name: "transaction_timestamp", Result: engine.ResultFixed, Returns: "time.Time", Null: engine.NullNever},
+		{Name: "date_trunc", Args: []string{"string", "time.Time", "string"}, Optional: 1, Result: engine.ResultFixed, Returns: "time.Time"},
+		{Name: "date_part", Args: []string{"string", "time.Time"}, Result: engine.ResultFixed, Returns: "float64"},
+		{Name: "extract", Args: []string{"any"}, Result: engine.ResultFixed, Returns: "float64"},
+		{Name: "age", Args: []string{"time.Time", "time.Time"}, Optional: 1, Result: engine.ResultFixed, Returns: "pgtype.Interval"},
+		{Name: "to_timestamp", Args: []string{"any", "string"}, Optional: 1, Result: engine.ResultFixed, Returns: "time.Time"},
+		{Name: "to_char", Args: []string{"any", "string"}, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "make_interval", Args: []string{"int64"}, Optional: 1, Variadic: true, Result: engine.ResultFixed, Returns: "pgtype.Interval"},
+
+		// String functions.
+		{Name: "concat", Args: []string{"any"}, Variadic: true, Result: engine.ResultFixed, Returns: "string", Null: engine.NullNever},
+		{Name: "concat_ws", Args: []string{"string", "any"}, Variadic: true, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "char_length", Args: []string{"string"}, Result: engine.ResultFixed, Returns: "int32"},
+		{Name: "position", Args: []string{"any"}, Result: engine.ResultFixed, Returns: "int32"},
+		{Name: "left", Args: []string{"string", "int64"}, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "right", Args: []string{"string", "int64"}, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "lpad", Args: []string{"string", "int64", "string"}, Optional: 1, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "rpad", Args: []string{"string", "int64", "string"}, Optional: 1, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "split_part", Args: []string{"string", "string", "int64"}, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "regexp_replace", Args: []string{"string", "string", "string", "string"}, Optional: 1, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "format", Args: []string{"string", "any"}, Optional: 1, Variadic: true, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "md5", Args: []string{"string"}, Result: engine.ResultFixed, Returns: "string"},
+
+		// Conditional and math functions.
+		{Name: "greatest", Args: []string{"any"}, Variadic: true, Result: engine.ResultCommonArgs, Null: engine.NullAllArgs},
+		{Name: "least", Args: []string{"any"}, Variadic: true, Result: engine.ResultCommonArgs, Null: engine.NullAllArgs},
+		{Name: "ceil", Args: []string{"any"}, Result: engine.ResultArg},
+		{Name: "floor", Args: []string{"any"}, Result: engine.ResultArg},
+		{Name: "trunc", Args: []string{"any", "int64"}, Optional: 1, Result: engine.ResultArg},
+		{Name: "mod", Args: []string{"any", "any"}, Result: engine.ResultCommonArgs},
+		{Name: "power", Args: []string{"float64", "float64"}, Result: engine.ResultFixed, Returns: "float64"},
+		{Name: "sqrt", Args: []string{"float64"}, Result: engine.ResultFixed, Returns: "float64"},
+		{Name: "random", Result: engine.ResultFixed, Returns: "float64", Null: engine.NullNever},
+
+		// Sequence and identifier functions.
+		{Name: "nextval", Args: []string{"string"}, Result: engine.ResultFixed, Returns: "int64", Null: engine.NullNever},
+		{Name: "currval", Args: []string{"string"}, Result: engine.ResultFixed, Returns: "int64", Null: engine.NullNever},
+		{Name: "setval", Args: []string{"string", "int64", "bool"}, Optional: 1, Result: engine.ResultFixed, Returns: "int64", Null: engine.NullNever},
+		{Name: "gen_random_uuid", Result: engine.ResultFixed, Returns: "uuid.UUID", Null: engine.NullNever},
+
+		// Aggregate functions.
+		{Name: "array_agg", Args: []string{"any"}, Aggregate: true, Result: engine.ResultArrayOfArg, Null: engine.NullAlways},
+		{Name: "string_agg", Args: []string{"any", "string"}, Aggregate: true, Result: engine.ResultFixed, Returns: "string", Null: engine.NullAlways},
+		{Name: "bool_and", Args: []string{"bool"}, Aggregate: true, Result: engine.ResultFixed, Returns: "bool", Null: engine.NullAlways},
+		{Name: "bool_or", Args: []string{"bool"}, Aggregate: true, Result: engine.ResultFixed, Returns: "bool", Null: engine.NullAlways},
+		{Name: "json_agg", Args: []string{"any"}, Aggregate: true, Result: engine.ResultFixed, Returns: "json.RawMessage", Null: engine.NullAlways},
+		{Name: "jsonb_agg", Args: []string{"any"}, Aggregate: true, Result: engine.ResultFixed, Returns: "json.RawMessage", Null: engine.NullAlways},
+
+		// Array functions.
+		{Name: "array_length", Args: []string{"any", "int64"}, Result: engine.ResultFixed, Returns: "int32", Null: engine.NullAlways},
+		{Name: "cardinality", Args: []string{"any"}, Result: engine.ResultFixed, Returns: "int32"},
+		{Name: "unnest", Args: []string{"any"}, Variadic: true, Result: engine.ResultFixed, Returns: "any"},
+		{Name: "generate_series", Args: []string{"any", "any", "any"}, Optional: 1, Result: engine.ResultCommonArgs},
+
+		// JSON functions.
+		{Name: "to_json", Args: []string{"any"}, Result: engine.ResultFixed, Returns: "json.RawMessage"},
+		{Name: "to_jsonb", Args: []string{"any"}, Result: engine.ResultFixed, Returns: "json.RawMessage"},
+		{Name: "json_build_object", Args: []string{"any"}, Optional: 1, Variadic: true, Result: engine.ResultFixed, Returns: "json.RawMessage", Null: engine.NullNever},
+		{Name: "jsonb_build_object", Args: []string{"any"}, Optional: 1, Variadic: true, Result: engine.ResultFixed, Returns: "json.RawMessage", Null: engine.NullNever},
+		{Name: "json_build_array", Args: []string{"any"}, Optional: 1, Variadic: true, Result: engine.ResultFixed, Returns: "json.RawMessage", Null: engine.NullNever},
+		{Name: "jsonb_build_array", Args: []string{"any"}, Optional: 1, Variadic: true, Result: engine.ResultFixed, Returns: "json.RawMessage", Null: engine.NullNever},
+		{Name: "jsonb_array_length", Args: []string{"any"}, Result: engine.ResultFixed, Returns: "int32"},
+		{Name: "jsonb_set", Args: []string{"any", "any", "any", "bool"}, Optional: 1, Result: engine.ResultFixed, Returns: "json.RawMessage"},
+		{Name: "jsonb_typeof", Args: []string{"any"}, Result: engine.ResultFixed, Returns: "string"},
+
+		// JSON extraction. Paths may be missing from the document.
+		{Name: "json_extract_path", Args: []string{"any", "string"}, Variadic: true, Result: engine.ResultFixed, Returns: "json.RawMessage", Null: engine.NullAlways},
+		{Name: "jsonb_extract_path", Args: []string{"any", "string"}, Variadic: true, Result: engine.ResultFixed, Returns: "json.RawMessage", Null: engine.NullAlways},
+		{Name: "json_extract_path_text", Args: []string{"any", "string"}, Variadic: true, Result: engine.ResultFixed, Returns: "string", Null: engine.NullAlways},
+		{Name: "jsonb_extract_path_text", Args: []string{"any", "string"}, Variadic: true, Result: engine.ResultFixed, Returns: "string", Null: engine.NullAlways},
+		{Name: "jsonb_path_query", Args: []string{"any", "string", "any", "bool"}, Optional: 2, Result: engine.ResultFixed, Returns: "json.RawMessage"},
+		{Name: "jsonb_path_query_array", Args: []string{"any", "string", "any", "bool"}, Optional: 2, Result: engine.ResultFixed, Returns: "json.RawMessage"},
+		{Name: "jsonb_path_query_first", Args: []string{"any", "string", "any", "bool"}, Optional: 2, Result: engine.ResultFixed, Returns: "json.RawMessage", Null: engine.NullAlways},
+		{Name: "jsonb_path_exists", Args: []string{"any", "string", "any", "bool"}, Optional: 2, Result: engine.ResultFixed, Returns: "bool"},
+		{Name: "jsonb_path_match", Args: []string{"any", "string", "any", "bool"}, Optional: 2, Result: engine.ResultFixed, Returns: "bool", Null: engine.NullAlways},
+	}
+}
@@ -3,6 +3,7 @@ package postgres
 import (
 	"strings"
 
+	"github.com/electwix/db-catalyst/internal/config"
 	"github.com/electwix/db-catalyst/internal/engine"
 	"github.com/electwix/db-catalyst/internal/types"
 )
@@ -137,7 +138,7 @@ func (m *typeMapper) sqlTypeToGo(sqlType string) string {
 	case strings.Contains(upperType, "UUID"):
 		return "uuid.UUID"
 	case strings.Contains(upperType, "JSON") || strings.Contains(upperType, "JSONB"):
-		return "[]byte"
+		return "json.RawMessage"
 	case strings.Contains(upperType, "XML"):
 		return "string"
 
@@ -152,37 +153,8 @@ func (m *typeMapper) sqlTypeToGo(sqlType string) string {
 	}
 }
 
-// resolveNullableType handles nullable type resolution for PostgreSQL.
+// resolveNullableType handles nullable type resolution for PostgreSQL, using
+// pgtype types unless another null style is configured.
 func (m *typeMapper) resolveNullableType(base, _ string) engine.TypeInfo {
-	if m.opts.EmitPointersForNull {
-		if !strings.HasPrefix(base, "*") {
-			return engine.TypeInfo{GoType: "*" + base, IsPointer: true}
-		}
-		return engine.TypeInfo{GoType: base, IsPointer: true}
-	}
-
-	// Use pgtype types for nullable values
-	switch base {
-	case "int32":
-		return engine.TypeInfo{GoType: "pgtype.Int4", Import: "github.com/jackc/pgx/v5/pgtype", Package: "pgtype"}
-	case "int64":
-		return engine.TypeInfo{GoType: "pgtype.Int8", Import: "github.com/jackc/pgx/v5/pgtype", Package: "pgtype"}
-	case "float64":
-		return engine.TypeInfo{GoType: "pgtype.Float8", Import: "github.com/jackc/pgx/v5/pgtype", Package: "pgtype"}
-	case "string":
-		return engine.TypeInfo{GoType: "pgtype.Text", Import: "github.com/jackc/pgx/v5/pgtype", Package: "pgtype"}
-	case "bool":
-		return engine.TypeInfo{GoType: "pgtype.Bool", Import: "github.com/jackc/pgx/v5/pgtype", Package: "pgtype"}
-	case "time.Time":
-		return engine.TypeInfo{GoType: "pgtype.Timestamptz", Import: "github.com/jackc/pgx/v5/pgtype", Package: "pgtype"}
-	case "uuid.UUID":
-		return engine.TypeInfo{GoType: "uuid.UUID", Import: "github.com/google/uuid", Package: "uuid", IsPointer: true}
-	case "decimal.Decimal":
-		return engine.TypeInfo{GoType: "decimal.Decimal", Import: "github.com/shopspring/decimal", Package: "decimal", IsPointer: true}
-	default:
-		if !strings.HasPrefix(base, "*") {
-			return engine.TypeInfo{GoType: "*" + base, IsPointer: true}
-		}
-		return engine.TypeInfo{GoType: base, IsPointer: true}
-	}
+	return m.opts.NullableType(base, config.NullStylePgtype)
 }
@@ -14,6 +14,7 @@ type Engine struct {
 	typeMapper *typeMapper
 	parser     schemaparser.SchemaParser
 	sqlGen     *sqlGenerator
+	functions  *engine.FunctionCatalog
 }
 
 // New creates a new SQLite engine instance.
@@ -31,6 +32,7 @@ func New(opts engine.Options) (engine.Engine, error) {
 	}
 
 	e.sqlGen = newSQLGenerator()
+	e.functions = engine.NewFunctionCatalog(engine.StandardFunctions(), Functions())
 
 	return e, nil
 }
@@ -119,3 +121,8 @@ func (e *Engine) QueryHints() []engine.QueryHint {
 	// SQLite does not support query hints
 	return nil
 }
+
+// Functions returns the SQLite built-in function catalog.
+func (e *Engine) Functions() *engine.FunctionCatalog {
+	return e.functions
+}
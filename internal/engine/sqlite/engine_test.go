@@ -182,3 +182,16 @@ func TestEngine_SQLGenerator_NotNil(t *testing.T) {
 		t.Error("SQLGenerator() should not return nil")
 	}
 }
+
+func TestEngine_Functions(t *testing.T) {
+	e, err := New(engine.Options{})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for _, name := range []string{"json_extract", "strftime", "unixepoch", "printf", "length", "count"} {
+		if _, ok := e.Functions().Lookup(name); !ok {
+			t.Errorf("Functions() missing %s", name)
+		}
+	}
+}
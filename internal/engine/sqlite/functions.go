@@ -0,0 +1,90 @@
+package sqlite
+
+import "github.com/electwix/db-catalyst/internal/engine"
+
+// Functions returns the SQLite built-in functions beyond engine.StandardFunctions.
+func Functions() []engine.Function {
+	return []engine.Function{
+		// Scalar functions.
+		{Name: "changes", Result: engine.ResultFixed, Returns: "int64", Null: engine.NullNever},
+		{Name: "char", Args: []string{"int64"}, Variadic: true, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "concat", Args: []string{"any"}, Variadic: true, Result: engine.ResultFixed, Returns: "string", Null: engine.NullNever},
+		{Name: "concat_ws", Args: []string{"string", "any"}, Variadic: true, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "format", Args: []string{"string", "any"}, Optional: 1, Variadic: true, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "glob", Args: []string{"string", "string"}, Result: engine.ResultFixed, Returns: "bool"},
+		{Name: "hex", Args: []string{"any"}, Result: engine.ResultFixed, Returns: "string", Null: engine.NullNever},
+		{Name: "ifnull", Args: []string{"any", "any"}, Result: engine.ResultCommonArgs, Null: engine.NullAllArgs},
+		{Name: "iif", Args: []string{"bool", "any", "any"}, Result: engine.ResultCommonArgs, ArgOffset: 1},
+		{Name: "instr", Args: []string{"string", "string"}, Result: engine.ResultFixed, Returns: "int64"},
+		{Name: "last_insert_rowid", Result: engine.ResultFixed, Returns: "int64", Null: engine.NullNever},
+		{Name: "like", Args: []string{"string", "string", "string"}, Optional: 1, Result: engine.ResultFixed, Returns: "bool"},
+		{Name: "likelihood", Args: []string{"any", "float64"}, Result: engine.ResultArg},
+		{Name: "likely", Args: []string{"any"}, Result: engine.ResultArg},
+		{Name: "max", Args: []string{"any"}, Variadic: true, Aggregate: true, Result: engine.ResultCommonArgs, Null: engine.NullAlways},
+		{Name: "min", Args: []string{"any"}, Variadic: true, Aggregate: true, Result: engine.ResultCommonArgs, Null: engine.NullAlways},
+		{Name: "octet_length", Args: []string{"any"}, Result: engine.ResultFixed, Returns: "int64"},
+		{Name: "printf", Args: []string{"string", "any"}, Optional: 1, Variadic: true, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "quote", Args: []string{"any"}, Result: engine.ResultFixed, Returns: "string", Null: engine.NullNever},
+		{Name: "random", Result: engine.ResultFixed, Returns: "int64", Null: engine.NullNever},
+		{Name: "randomblob", Args: []string{"int64"}, Result: engine.ResultFixed, Returns: "[]byte", Null: engine.NullNever},
+		{Name: "sign", Args: []string{"any"}, Result: engine.ResultFixed, Returns: "int64"},
+		{Name: "soundex", Args: []string{"string"}, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "sqlite_version", Result: engine.ResultFixed, Returns: "string", Null: engine.NullNever},
+		{Name: "total_changes", Result: engine.ResultFixed, Returns: "int64", Null: engine.NullNever},
+		{Name: "typeof", Args: []string{"any"}, Result: engine.ResultFixed, Returns: "string", Null: engine.NullNever},
+		{Name: "unhex", Args: []string{"string", "string"}, Optional: 1, Result: engine.ResultFixed, Returns: "[]byte", Null: engine.NullAlways},
+		{Name: "unicode", Args: []string{"string"}, Result: engine.ResultFixed, Returns: "int64"},
+		{Name: "unlikely", Args: []string{"any"}, Result: engine.ResultArg},
+		{Name: "zeroblob", Args: []string{"int64"}, Result: engine.ResultFixed, Returns: "[]byte", Null: engine.NullNever},
+
+		// Math functions.
+		{Name: "ceil", Args: []string{"any"}, Result: engine.ResultArg},
+		{Name: "ceiling", Args: []string{"any"}, Result: engine.ResultArg},
+		{Name: "floor", Args: []string{"any"}, Result: engine.ResultArg},
+		{Name: "trunc", Args: []string{"any"}, Result: engine.ResultArg},
+		{Name: "exp", Args: []string{"float64"}, Result: engine.ResultFixed, Returns: "float64"},
+		{Name: "ln", Args: []string{"float64"}, Result: engine.ResultFixed, Returns: "float64"},
+		{Name: "log", Args: []string{"float64", "float64"}, Optional: 1, Result: engine.ResultFixed, Returns: "float64"},
+		{Name: "log10", Args: []string{"float64"}, Result: engine.ResultFixed, Returns: "float64"},
+		{Name: "log2", Args: []string{"float64"}, Result: engine.ResultFixed, Returns: "float64"},
+		{Name: "mod", Args: []string{"float64", "float64"}, Result: engine.ResultFixed, Returns: "float64"},
+		{Name: "pi", Result: engine.ResultFixed, Returns: "float64", Null: engine.NullNever},
+		{Name: "pow", Args: []string{"float64", "float64"}, Result: engine.ResultFixed, Returns: "float64"},
+		{Name: "power", Args: []string{"float64", "float64"}, Result: engine.ResultFixed, Returns: "float64"},
+		{Name: "sqrt", Args: []string{"float64"}, Result: engine.ResultFixed, Returns: "float64"},
+
+		// Date and time functions. The first argument defaults to 'now'.
+		{Name: "date", Args: []string{"any", "string"}, Optional: 2, Variadic: true, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "time", Args: []string{"any", "string"}, Optional: 2, Variadic: true, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "datetime", Args: []string{"any", "string"}, Optional: 2, Variadic: true, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "julianday", Args: []string{"any", "string"}, Optional: 2, Variadic: true, Result: engine.ResultFixed, Returns: "float64"},
+		{Name: "unixepoch", Args: []string{"any", "string"}, Optional: 2, Variadic: true, Result: engine.ResultFixed, Returns: "int64"},
+		{Name: "strftime", Args: []string{"string", "any", "string"}, Optional: 2, Variadic: true, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "timediff", Args: []string{"any", "any"}, Result: engine.ResultFixed, Returns: "string"},
+
+		// Aggregate functions.
+		{Name: "group_concat", Args: []string{"any", "string"}, Optional: 1, Aggregate: true, Result: engine.ResultFixed, Returns: "string", Null: engine.NullAlways},
+		{Name: "string_agg", Args: []string{"any", "string"}, Aggregate: true, Result: engine.ResultFixed, Returns: "string", Null: engine.NullAlways},
+		{Name: "total", Args: []string{"any"}, Aggregate: true, Result: engine.ResultFixed, Returns: "float64", Null: engine.NullNever},
+
+		// JSON functions. json_extract returns SQL values for scalars and JSON
+		// text for objects and arrays, both of which scan into a string.
+		{Name: "json", Args: []string{"any"}, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "json_array", Args: []string{"any"}, Optional: 1, Variadic: true, Result: engine.ResultFixed, Returns: "string", Null: engine.NullNever},
+		{Name: "json_array_length", Args: []string{"any", "string"}, Optional: 1, Result: engine.ResultFixed, Returns: "int64", Null: engine.NullAlways},
+		{Name: "json_extract", Args: []string{"any", "string"}, Variadic: true, Result: engine.ResultFixed, Returns: "string", Null: engine.NullAlways},
+		{Name: "json_insert", Args: []string{"any", "string", "any"}, Variadic: true, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "json_object", Args: []string{"string", "any"}, Optional: 2, Variadic: true, Result: engine.ResultFixed, Returns: "string", Null: engine.NullNever},
+		{Name: "json_patch", Args: []string{"any", "any"}, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "json_quote", Args: []string{"any"}, Result: engine.ResultFixed, Returns: "string", Null: engine.NullNever},
+		{Name: "json_remove", Args: []string{"any", "string"}, Optional: 1, Variadic: true, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "json_replace", Args: []string{"any", "string", "any"}, Variadic: true, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "json_set", Args: []string{"any", "string", "any"}, Variadic: true, Result: engine.ResultFixed, Returns: "string"},
+		{Name: "json_type", Args: []string{"any", "string"}, Optional: 1, Result: engine.ResultFixed, Returns: "string", Null: engine.NullAlways},
+		{Name: "json_valid", Args: []string{"any"}, Result: engine.ResultFixed, Returns: "bool"},
+		{Name: "json_group_array", Args: []string{"any"}, Aggregate: true, Result: engine.ResultFixed, Returns: "string", Null: engine.NullNever},
+		{Name: "json_group_object", Args: []string{"string", "any"}, Aggregate: true, Result: engine.ResultFixed, Returns: "string", Null: engine.NullNever},
+		{Name: "json_each", Args: []string{"any", "string"}, Optional: 1, Result: engine.ResultFixed, Returns: "any"},
+		{Name: "json_tree", Args: []string{"any", "string"}, Optional: 1, Result: engine.ResultFixed, Returns: "any"},
+	}
+}
@@ -3,6 +3,7 @@ package sqlite
 import (
 	"strings"
 
+	"github.com/electwix/db-catalyst/internal/config"
 	"github.com/electwix/db-catalyst/internal/engine"
 	"github.com/electwix/db-catalyst/internal/types"
 )
@@ -59,7 +60,7 @@ func (m *typeMapper) SQLToGo(sqlType string, nullable bool) engine.TypeInfo {
 		}
 	}
 
-	base := m.sqlTypeToGo(sqlType)
+	base := types.SQLiteGoType(sqlType)
 
 	if nullable {
 		return m.resolveNullableType(base)
@@ -145,66 +146,8 @@ func (m *typeMapper) resolveCustomType(custom customTypeMapping, nullable bool)
 	}
 }
 
-//nolint:goconst // Type names are naturally repeated and don't need constants
-func (m *typeMapper) sqlTypeToGo(sqlType string) string {
-	upperType := strings.ToUpper(sqlType)
-
-	switch {
-	case strings.Contains(upperType, "INT"):
-		switch {
-		case strings.Contains(upperType, "BIGINT"):
-			return "int64"
-		case strings.Contains(upperType, "SMALLINT"):
-			return "int16"
-		case strings.Contains(upperType, "TINYINT"):
-			return "int8"
-		default:
-			return "int64" // SQLite INTEGER is int64
-		}
-	case strings.Contains(upperType, "TEXT"), strings.Contains(upperType, "CHAR"), strings.Contains(upperType, "VARCHAR"), strings.Contains(upperType, "CLOB"):
-		return "string"
-	case strings.Contains(upperType, "BLOB"):
-		return "[]byte"
-	case strings.Contains(upperType, "REAL"), strings.Contains(upperType, "FLOAT"), strings.Contains(upperType, "DOUBLE"):
-		return "float64"
-	case strings.Contains(upperType, "NUMERIC"), strings.Contains(upperType, "DECIMAL"):
-		return "float64"
-	case strings.Contains(upperType, "BOOLEAN"), strings.Contains(upperType, "BOOL"):
-		return "bool"
-	case strings.Contains(upperType, "DATE"), strings.Contains(upperType, "DATETIME"), strings.Contains(upperType, "TIMESTAMP"):
-		return "time.Time"
-	default:
-		return "any"
-	}
-}
-
-// resolveNullableType handles nullable type resolution for SQLite.
+// resolveNullableType handles nullable type resolution for SQLite, using
+// the sql.Null* types unless another null style is configured.
 func (m *typeMapper) resolveNullableType(base string) engine.TypeInfo {
-	if m.opts.EmitPointersForNull {
-		if !strings.HasPrefix(base, "*") {
-			return engine.TypeInfo{GoType: "*" + base, IsPointer: true}
-		}
-		return engine.TypeInfo{GoType: base, IsPointer: true}
-	}
-
-	// Use sql.Null* types for nullable values
-	switch base {
-	case "int64":
-		return engine.TypeInfo{GoType: "sql.NullInt64", Import: "database/sql", Package: "sql", UsesSQLNull: true}
-	case "int32":
-		return engine.TypeInfo{GoType: "sql.NullInt32", Import: "database/sql", Package: "sql", UsesSQLNull: true}
-	case "float64":
-		return engine.TypeInfo{GoType: "sql.NullFloat64", Import: "database/sql", Package: "sql", UsesSQLNull: true}
-	case "string":
-		return engine.TypeInfo{GoType: "sql.NullString", Import: "database/sql", Package: "sql", UsesSQLNull: true}
-	case "bool":
-		return engine.TypeInfo{GoType: "sql.NullBool", Import: "database/sql", Package: "sql", UsesSQLNull: true}
-	case "time.Time":
-		return engine.TypeInfo{GoType: "sql.NullTime", Import: "database/sql", Package: "sql", UsesSQLNull: true}
-	default:
-		if !strings.HasPrefix(base, "*") {
-			return engine.TypeInfo{GoType: "*" + base, IsPointer: true}
-		}
-		return engine.TypeInfo{GoType: base, IsPointer: true}
-	}
+	return m.opts.NullableType(base, config.NullStyleSQLNull)
 }
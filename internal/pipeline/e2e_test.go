@@ -9,6 +9,9 @@ import (
 	"testing"
 
 	"github.com/electwix/db-catalyst/internal/codegen"
+	"github.com/electwix/db-catalyst/internal/config"
+	"github.com/electwix/db-catalyst/internal/engine"
+	_ "github.com/electwix/db-catalyst/internal/engine/builtin" // Register built-in engines
 )
 
 var update = flag.Bool("update", false, "update golden files")
@@ -42,7 +45,20 @@ func runE2ETestCase(t *testing.T, caseDir string) {
 	// Copy fixtures to temp dir
 	copyDir(t, tmpDir, caseDir)
 
-	p := Pipeline{Env: Environment{Writer: &diskWriter{}}}
+	loaded, err := config.Load(configPath, config.LoadOptions{})
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	env := Environment{Writer: &diskWriter{}}
+	if loaded.Plan.Database != config.DatabaseSQLite {
+		eng, err := engine.New(string(loaded.Plan.Database), engine.Options{})
+		if err != nil {
+			t.Fatalf("create engine: %v", err)
+		}
+		env.Engine = eng
+	}
+
+	p := Pipeline{Env: env}
 	summary, err := p.Run(ctx, RunOptions{
 		ConfigPath: configPath,
 	})
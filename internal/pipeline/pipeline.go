@@ -7,8 +7,10 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -219,7 +221,7 @@ func (p *Pipeline) Run(ctx context.Context, opts RunOptions) (summary Summary, e
 		plan.EmitJSONTags = false
 	}
 	if opts.EmitPointersForNull {
-		plan.EmitPointersForNull = true
+		plan.NullStyle = config.NullStylePointer
 	}
 	outDir := plan.Out
 	if opts.OutOverride != "" {
@@ -323,13 +325,19 @@ func (p *Pipeline) generateCode(ctx context.Context, plan config.JobPlan, catalo
 		}
 
 		factory := codegen.NewGeneratorFactory(codegen.Options{
-			Package:             plan.Package,
-			Database:            plan.Database,
-			EmitJSONTags:        plan.EmitJSONTags,
-			EmitEmptySlices:     plan.PreparedQueries.EmitEmptySlices,
-			EmitPointersForNull: plan.EmitPointersForNull,
-			CustomTypes:         plan.CustomTypes,
-			ColumnOverrides:     columnOverrides,
+			Package:         plan.Package,
+			Database:        plan.Database,
+			SQLPackage:      plan.SQLPackage,
+			EmitJSONTags:    plan.EmitJSONTags,
+			EmitEmptySlices: plan.PreparedQueries.EmitEmptySlices,
+			NullStyle:       plan.NullStyle,
+			NullOption:      plan.NullOption,
+			CustomTypes:     plan.CustomTypes,
+			ColumnOverrides: columnOverrides,
+			SQLiteTypes:     plan.SQLiteTypes,
+			SQLiteDriver:    plan.SQLiteDriver,
+			DriverHelpers:   plan.DriverHelpers,
+			Errors:          plan.Errors,
 			Prepared: codegen.PreparedOptions{
 				Enabled:     plan.PreparedQueries.Enabled,
 				EmitMetrics: plan.PreparedQueries.Metrics,
@@ -340,6 +348,9 @@ func (p *Pipeline) generateCode(ctx context.Context, plan config.JobPlan, catalo
 				Dialect:         plan.SQLDialect,
 				EmitIFNotExists: opts.EmitIFNotExists,
 			},
+			Docs: codegen.DocsOptions{
+				Enabled: plan.EmitDocs,
+			},
 		})
 		var err error
 		generator, err = factory.Create(plan.Language)
@@ -585,6 +596,79 @@ func mergeCatalog(dest, src *model.Catalog, addDiag func(queryanalyzer.Diagnosti
 		}
 		dest.Views[key] = view
 	}
+	for key, enum := range src.Enums {
+		if existing, ok := dest.Enums[key]; ok {
+			message := fmt.Sprintf("duplicate enum %q (previous definition at %s:%d:%d)", enum.Name, existing.Span.File, existing.Span.StartLine, existing.Span.StartColumn)
+			addDiag(newDiagnostic(enum.Span.File, enum.Span.StartLine, enum.Span.StartColumn, queryanalyzer.SeverityError, message))
+			continue
+		}
+		dest.Enums[key] = enum
+	}
+	for key, domain := range src.Domains {
+		if existing, ok := dest.Domains[key]; ok {
+			message := fmt.Sprintf("duplicate domain %q (previous definition at %s:%d:%d)", domain.Name, existing.Span.File, existing.Span.StartLine, existing.Span.StartColumn)
+			addDiag(newDiagnostic(domain.Span.File, domain.Span.StartLine, domain.Span.StartColumn, queryanalyzer.SeverityError, message))
+			continue
+		}
+		dest.Domains[key] = domain
+	}
+	for key, fn := range src.Functions {
+		// CREATE OR REPLACE FUNCTION may legitimately redefine a function.
+		dest.Functions[key] = fn
+	}
+}
+
+// declaredFunctions converts functions created in the schema and declared in
+// the configuration into function catalog entries. Configured declarations
+// come last so they take precedence over schema definitions.
+func declaredFunctions(catalog *model.Catalog, configured []config.FunctionConfig) []engine.Function {
+	var fns []engine.Function
+	if catalog != nil {
+		keys := slices.Sorted(maps.Keys(catalog.Functions))
+		for _, key := range keys {
+			def := catalog.Functions[key]
+			fn := engine.Function{
+				Name:       def.Name,
+				Args:       def.Args,
+				Optional:   def.Optional,
+				Variadic:   def.Variadic,
+				Result:     engine.ResultFixed,
+				ReturnsSQL: def.Returns,
+			}
+			switch strings.ToLower(def.Returns) {
+			case "", "void", "trigger", "event_trigger":
+				fn.ReturnsSQL = ""
+				fn.Returns = "any"
+			}
+			fns = append(fns, fn)
+		}
+	}
+
+	for _, decl := range configured {
+		fn := engine.Function{
+			Name:       decl.Name,
+			Args:       decl.Args,
+			Optional:   decl.Optional,
+			Variadic:   decl.Variadic,
+			Aggregate:  decl.Aggregate,
+			Result:     engine.ResultFixed,
+			Returns:    decl.GoType,
+			ReturnsSQL: decl.Returns,
+			Import:     decl.GoImport,
+			Package:    decl.GoPackage,
+		}
+		if fn.Returns == "" && fn.ReturnsSQL == "" {
+			fn.Returns = "any"
+		}
+		if decl.Nullable != nil {
+			fn.Null = engine.NullNever
+			if *decl.Nullable {
+				fn.Null = engine.NullAlways
+			}
+		}
+		fns = append(fns, fn)
+	}
+	return fns
 }
 
 func fileMatches(path string, content []byte) (bool, error) {
@@ -686,16 +770,22 @@ func (p *Pipeline) analyzeQueries(ctx context.Context, plan config.JobPlan, cata
 
 	analyzer := queryanalyzer.NewWithCustomTypes(catalog, customTypesMap)
 	analyzer.SetColumnOverrides(plan.ColumnOverrides)
+	analyzer.SetSQLiteTypes(plan.SQLiteTypes)
 
 	// Set up type resolver for database-specific type mapping
 	// Use engine's type mapper if available, otherwise fall back to legacy TypeResolver
 	if p.Env.Engine != nil {
 		analyzer.SetTypeResolver(&engineTypeResolver{mapper: p.Env.Engine.TypeMapper()})
+		analyzer.SetFunctions(p.Env.Engine.Functions())
 	} else {
 		transformer := transform.New(nil) // No overrides for now
 		typeResolver := ast.NewTypeResolverWithDatabase(transformer, plan.Database)
 		analyzer.SetTypeResolver(&analyzerTypeResolver{resolver: typeResolver})
+		if eng, err := engine.New(string(plan.Database), engine.Options{}); err == nil {
+			analyzer.SetFunctions(eng.Functions())
+		}
 	}
+	analyzer.AddFunctions(declaredFunctions(catalog, plan.Functions)...)
 	analyses := make([]queryanalyzer.Result, 0, len(queries))
 	for _, q := range queries {
 		result := analyzer.Analyze(q)
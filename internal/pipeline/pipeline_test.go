@@ -4,6 +4,10 @@ package pipeline
 import (
 	"context"
 	"errors"
+	goast "go/ast"
+	goparser "go/parser"
+	"go/token"
+	"go/types"
 	"io"
 	"log/slog"
 	"os"
@@ -12,6 +16,9 @@ import (
 	"testing"
 
 	"github.com/electwix/db-catalyst/internal/codegen"
+	"github.com/electwix/db-catalyst/internal/config"
+	"github.com/electwix/db-catalyst/internal/engine"
+	_ "github.com/electwix/db-catalyst/internal/engine/builtin" // Register built-in engines
 	"github.com/electwix/db-catalyst/internal/logging"
 	"github.com/electwix/db-catalyst/internal/query/analyzer"
 	"github.com/electwix/db-catalyst/internal/query/block"
@@ -365,6 +372,244 @@ SELECT * FROM users WHERE id = :id;`
 	_ = summary
 }
 
+func TestPipeline_Run_WithDeclaredFunctions(t *testing.T) {
+	tmpDir := t.TempDir()
+	configContent := `package = "test"
+out = "out"
+schemas = ["schema.sql"]
+queries = ["queries.sql"]
+
+[[functions]]
+name = "regexp"
+args = ["TEXT", "TEXT"]
+returns = "BOOLEAN"
+nullable = false
+
+[[functions]]
+name = "haversine"
+args = ["REAL", "REAL", "REAL", "REAL"]
+returns = "REAL"
+`
+	schemaContent := `CREATE TABLE places (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    lat REAL NOT NULL,
+    lon REAL NOT NULL
+);`
+	queryContent := `-- name: SearchPlaces :many
+SELECT id, regexp(:pattern, name) AS matched, haversine(lat, lon, :lat, :lon) AS distance FROM places;`
+
+	for name, content := range map[string]string{
+		"db-catalyst.toml": configContent,
+		"schema.sql":       schemaContent,
+		"queries.sql":      queryContent,
+	} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	writer := &MemoryWriter{}
+	pipeline := &Pipeline{
+		Env: Environment{
+			Logger: logging.NewSlogAdapter(slog.Default()),
+			Writer: writer,
+		},
+	}
+
+	summary, err := pipeline.Run(context.Background(), RunOptions{
+		ConfigPath: filepath.Join(tmpDir, "db-catalyst.toml"),
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	for _, d := range summary.Diagnostics {
+		t.Errorf("unexpected diagnostic: %s", d.Message)
+	}
+
+	var generated string
+	for _, data := range writer.Files {
+		generated += string(data)
+	}
+	for _, want := range []string{"Matched  bool", "Distance float64"} {
+		if !strings.Contains(generated, want) {
+			t.Errorf("generated code missing %q:\n%s", want, generated)
+		}
+	}
+}
+
+func TestPipeline_Run_ModelMatchesRowTypes(t *testing.T) {
+	tmpDir := t.TempDir()
+	configContent := `package = "test"
+out = "out"
+schemas = ["schema.sql"]
+queries = ["queries.sql"]
+
+[generation]
+null_style = "sql_null"
+`
+	schemaContent := `CREATE TABLE players (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    level INTEGER,
+    rank SMALLINT
+);`
+	queryContent := `-- name: ListPlayers :many
+SELECT * FROM players;
+
+-- name: ListPlayerLevels :many
+SELECT id, level, rank FROM players;`
+
+	for name, content := range map[string]string{
+		"db-catalyst.toml": configContent,
+		"schema.sql":       schemaContent,
+		"queries.sql":      queryContent,
+	} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	eng, err := engine.New(string(config.DatabaseSQLite), engine.Options{NullStyle: config.NullStyleSQLNull})
+	if err != nil {
+		t.Fatalf("engine.New() error = %v", err)
+	}
+	writer := &MemoryWriter{}
+	pipeline := &Pipeline{
+		Env: Environment{
+			Logger: logging.NewSlogAdapter(slog.Default()),
+			Writer: writer,
+			Engine: eng,
+		},
+	}
+
+	summary, err := pipeline.Run(context.Background(), RunOptions{
+		ConfigPath: filepath.Join(tmpDir, "db-catalyst.toml"),
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	for _, d := range summary.Diagnostics {
+		t.Errorf("unexpected diagnostic: %s", d.Message)
+	}
+
+	fields := make(map[string]map[string]string)
+	for path, data := range writer.Files {
+		file, err := goparser.ParseFile(token.NewFileSet(), path, data, 0)
+		if err != nil {
+			t.Fatalf("parse %s: %v", path, err)
+		}
+		goast.Inspect(file, func(n goast.Node) bool {
+			spec, ok := n.(*goast.TypeSpec)
+			if !ok {
+				return true
+			}
+			if st, ok := spec.Type.(*goast.StructType); ok {
+				fields[spec.Name.Name] = make(map[string]string)
+				for _, f := range st.Fields.List {
+					for _, name := range f.Names {
+						fields[spec.Name.Name][name.Name] = types.ExprString(f.Type)
+					}
+				}
+			}
+			return false
+		})
+	}
+
+	model, row := fields["Players"], fields["ListPlayerLevelsRow"]
+	if model == nil || row == nil {
+		t.Fatalf("missing Players or ListPlayerLevelsRow in generated structs: %v", fields)
+	}
+	for name, want := range map[string]string{"Id": "int64", "Level": "sql.NullInt64", "Rank": "sql.NullInt16"} {
+		if model[name] != want || row[name] != want {
+			t.Errorf("%s: model %q, row %q, want %q", name, model[name], row[name], want)
+		}
+	}
+	if _, ok := fields["ListPlayersRow"]; ok {
+		t.Errorf("SELECT * generated ListPlayersRow instead of reusing the Players model")
+	}
+}
+
+// runExample generates the example in dir and fails the test when the
+// committed code differs from the generated code.
+func runExample(t *testing.T, dir string, database config.Database) *Summary {
+	t.Helper()
+	eng, err := engine.New(string(database), engine.Options{})
+	if err != nil {
+		t.Fatalf("engine.New() error = %v", err)
+	}
+	pipeline := &Pipeline{
+		Env: Environment{
+			Logger: logging.NewSlogAdapter(slog.New(slog.NewTextHandler(io.Discard, nil))),
+			Writer: &MemoryWriter{},
+			Engine: eng,
+		},
+	}
+
+	summary, err := pipeline.Run(context.Background(), RunOptions{
+		ConfigPath: filepath.Join(dir, "db-catalyst.toml"),
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	for _, f := range summary.Files {
+		committed, err := os.ReadFile(filepath.Clean(f.Path))
+		if err != nil {
+			t.Errorf("read %s: %v", f.Path, err)
+			continue
+		}
+		if string(committed) != string(f.Content) {
+			t.Errorf("%s is stale; regenerate %s", f.Path, dir)
+		}
+	}
+	return &summary
+}
+
+func TestPipeline_Run_PostgreSQLExample(t *testing.T) {
+	runExample(t, filepath.Join("..", "..", "examples", "postgresql"), config.DatabasePostgreSQL)
+}
+
+func TestPipeline_Run_ComplexExample(t *testing.T) {
+	summary := runExample(t, filepath.Join("..", "..", "examples", "complex"), config.DatabaseSQLite)
+	for _, d := range summary.Diagnostics {
+		t.Errorf("unexpected diagnostic: %s", d.Message)
+	}
+
+	var querier []byte
+	for _, f := range summary.Files {
+		if filepath.Base(f.Path) == "querier.gen.go" {
+			querier = f.Content
+		}
+	}
+
+	// Every method returns a table model, its own Row type, or a Row type
+	// named after a table; never the Row type of another query.
+	file, err := goparser.ParseFile(token.NewFileSet(), "querier.gen.go", querier, 0)
+	if err != nil {
+		t.Fatalf("parse querier.gen.go: %v", err)
+	}
+	goast.Inspect(file, func(n goast.Node) bool {
+		spec, ok := n.(*goast.TypeSpec)
+		if !ok || spec.Name.Name != "Querier" {
+			return true
+		}
+		for _, method := range spec.Type.(*goast.InterfaceType).Methods.List {
+			fn := method.Type.(*goast.FuncType)
+			name := method.Names[0].Name
+			result := strings.TrimLeft(types.ExprString(fn.Results.List[0].Type), "[]*")
+			if !strings.HasSuffix(result, "Row") || result == name+"Row" {
+				continue
+			}
+			switch strings.TrimSuffix(result, "Row") {
+			case "Authors", "Posts", "Tags":
+			default:
+				t.Errorf("%s returns %s", name, result)
+			}
+		}
+		return false
+	})
+}
+
 // mockGenerator is a test double for codegen.Generator
 type mockGenerator struct {
 	files []codegen.File
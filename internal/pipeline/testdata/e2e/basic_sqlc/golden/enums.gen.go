@@ -0,0 +1,76 @@
+package basic
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// PostsStatus is a value of the posts_status enum.
+type PostsStatus string
+
+const (
+	PostsStatusDraft     PostsStatus = "draft"
+	PostsStatusPublished PostsStatus = "published"
+)
+
+// Values returns the values of the posts_status enum in schema order.
+func (PostsStatus) Values() []PostsStatus {
+	return []PostsStatus{
+		PostsStatusDraft,
+		PostsStatusPublished,
+	}
+}
+
+// Valid reports whether e is a value of the posts_status enum.
+func (e PostsStatus) Valid() bool {
+	switch e {
+	case PostsStatusDraft, PostsStatusPublished:
+		return true
+	}
+	return false
+}
+
+// Scan implements sql.Scanner.
+func (e *PostsStatus) Scan(src any) error {
+	switch v := src.(type) {
+	case string:
+		*e = PostsStatus(v)
+	case []byte:
+		*e = PostsStatus(v)
+	default:
+		return fmt.Errorf("cannot scan %T into PostsStatus", src)
+	}
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (e PostsStatus) Value() (driver.Value, error) {
+	if !e.Valid() {
+		return nil, fmt.Errorf("invalid posts_status value %q", string(e))
+	}
+	return string(e), nil
+}
+
+// NullPostsStatus is a nullable PostsStatus.
+type NullPostsStatus struct {
+	PostsStatus PostsStatus
+	Valid       bool // Valid is true if PostsStatus is not NULL
+}
+
+// Scan implements sql.Scanner.
+func (n *NullPostsStatus) Scan(src any) error {
+	if src == nil {
+		n.PostsStatus, n.Valid = "", false
+		return nil
+	}
+	n.Valid = true
+	return n.PostsStatus.Scan(src)
+}
+
+// Value implements driver.Valuer.
+func (n NullPostsStatus) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.PostsStatus.Value()
+}
@@ -3,14 +3,14 @@ package basic
 import "database/sql"
 
 type Posts struct {
-	Id       int32
-	AuthorId int32
+	Id       int64
+	AuthorId int64
 	Title    string
 	Body     string
-	Status   string
+	Status   PostsStatus
 }
 type Users struct {
-	Id        int32
+	Id        int64
 	Username  string
 	Email     string
 	CreatedAt sql.NullTime
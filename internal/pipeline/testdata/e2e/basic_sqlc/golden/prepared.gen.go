@@ -90,7 +90,7 @@ func (p *PreparedQueries) Close() error {
 	return err
 }
 
-func (p *PreparedQueries) CreateUser(ctx context.Context, arg CreateUserParams) (int32, error) {
+func (p *PreparedQueries) CreateUser(ctx context.Context, arg CreateUserParams) (int64, error) {
 	stmt := p.stmtCreateUser
 	rows, err := stmt.QueryContext(ctx, arg.Username, arg.Email)
 	if err != nil {
@@ -103,7 +103,7 @@ func (p *PreparedQueries) CreateUser(ctx context.Context, arg CreateUserParams)
 		}
 		return 0, sql.ErrNoRows
 	}
-	var item int32
+	var item int64
 	err = rows.Scan(&item)
 	if err != nil {
 		return 0, err
@@ -114,20 +114,20 @@ func (p *PreparedQueries) CreateUser(ctx context.Context, arg CreateUserParams)
 	return item, nil
 }
 
-func (p *PreparedQueries) GetUser(ctx context.Context, id int32) (GetUserRow, error) {
+func (p *PreparedQueries) GetUser(ctx context.Context, id int64) (Users, error) {
 	stmt := p.stmtGetUser
 	rows, err := stmt.QueryContext(ctx, id)
 	if err != nil {
-		return GetUserRow{}, err
+		return Users{}, err
 	}
 	defer rows.Close()
 	if !rows.Next() {
 		if err := rows.Err(); err != nil {
-			return GetUserRow{}, err
+			return Users{}, err
 		}
-		return GetUserRow{}, sql.ErrNoRows
+		return Users{}, sql.ErrNoRows
 	}
-	item, err := scanGetUserRow(rows)
+	item, err := scanUsers(rows)
 	if err != nil {
 		return item, err
 	}
@@ -137,16 +137,16 @@ func (p *PreparedQueries) GetUser(ctx context.Context, id int32) (GetUserRow, er
 	return item, nil
 }
 
-func (p *PreparedQueries) ListPostsByAuthor(ctx context.Context, authorId int32) ([]ListPostsByAuthorRow, error) {
+func (p *PreparedQueries) ListPostsByAuthor(ctx context.Context, authorId int64) ([]Posts, error) {
 	stmt := p.stmtListPostsByAuthor
 	rows, err := stmt.QueryContext(ctx, authorId)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := make([]ListPostsByAuthorRow, 0)
+	items := make([]Posts, 0)
 	for rows.Next() {
-		item, err := scanListPostsByAuthorRow(rows)
+		item, err := scanPosts(rows)
 		if err != nil {
 			return nil, err
 		}
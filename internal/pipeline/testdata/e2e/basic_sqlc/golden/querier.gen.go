@@ -6,9 +6,9 @@ import (
 )
 
 type Querier interface {
-	CreateUser(ctx context.Context, arg CreateUserParams) (int32, error)
-	GetUser(ctx context.Context, id int32) (GetUserRow, error)
-	ListPostsByAuthor(ctx context.Context, authorId int32) ([]ListPostsByAuthorRow, error)
+	CreateUser(ctx context.Context, arg CreateUserParams) (int64, error)
+	GetUser(ctx context.Context, id int64) (Users, error)
+	ListPostsByAuthor(ctx context.Context, authorId int64) ([]Posts, error)
 }
 type DBTX interface {
 	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
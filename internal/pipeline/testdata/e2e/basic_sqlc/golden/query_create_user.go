@@ -9,12 +9,12 @@ type CreateUserParams struct {
 
 const queryCreateUser string = `INSERT INTO users (username, email) VALUES (?, ?) RETURNING id;`
 
-func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (int32, error) {
+func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (int64, error) {
 	row := q.db.QueryRowContext(ctx, queryCreateUser, arg.Username, arg.Email)
 	if err := row.Err(); err != nil {
 		return 0, err
 	}
-	var item int32
+	var item int64
 	err := row.Scan(&item)
 	if err != nil {
 		return 0, err
@@ -4,15 +4,15 @@ import "context"
 
 const queryGetUser string = `SELECT * FROM users WHERE id = :id;`
 
-func (q *Queries) GetUser(ctx context.Context, id int32) (GetUserRow, error) {
+func (q *Queries) GetUser(ctx context.Context, id int64) (Users, error) {
 	row := q.db.QueryRowContext(ctx, queryGetUser, id)
 	if err := row.Err(); err != nil {
-		return GetUserRow{}, err
+		return Users{}, err
 	}
-	var item GetUserRow
+	var item Users
 	err := row.Scan(&item.Id, &item.Username, &item.Email, &item.CreatedAt)
 	if err != nil {
-		return GetUserRow{}, err
+		return Users{}, err
 	}
 	return item, nil
 }
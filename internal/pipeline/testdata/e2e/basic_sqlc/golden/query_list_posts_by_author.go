@@ -4,15 +4,15 @@ import "context"
 
 const queryListPostsByAuthor string = `SELECT * FROM posts WHERE author_id = :author_id ORDER BY id DESC;`
 
-func (q *Queries) ListPostsByAuthor(ctx context.Context, authorId int32) ([]ListPostsByAuthorRow, error) {
+func (q *Queries) ListPostsByAuthor(ctx context.Context, authorId int64) ([]Posts, error) {
 	rows, err := q.db.QueryContext(ctx, queryListPostsByAuthor, authorId)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := make([]ListPostsByAuthorRow, 0)
+	items := make([]Posts, 0)
 	for rows.Next() {
-		item, err := scanListPostsByAuthorRow(rows)
+		item, err := scanPosts(rows)
 		if err != nil {
 			return nil, err
 		}
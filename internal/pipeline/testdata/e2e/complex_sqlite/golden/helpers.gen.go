@@ -3,10 +3,10 @@ package complex
 import "database/sql"
 
 type GetItemWithTagsRow struct {
-	Id       int32
+	Id       int64
 	Name     string
 	Metadata any
-	Tags     any
+	Tags     sql.NullString
 }
 
 func scanGetItemWithTagsRow(rows *sql.Rows) (GetItemWithTagsRow, error) {
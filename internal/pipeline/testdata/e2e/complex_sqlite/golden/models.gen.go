@@ -1,7 +1,7 @@
 package complex
 
 type Items struct {
-	Id       int32
+	Id       int64
 	Name     string
 	Metadata any
 }
@@ -80,7 +80,7 @@ func (p *PreparedQueries) DeleteTag(ctx context.Context, tag string) error {
 	return err
 }
 
-func (p *PreparedQueries) GetItemWithTags(ctx context.Context, id *int32) (GetItemWithTagsRow, error) {
+func (p *PreparedQueries) GetItemWithTags(ctx context.Context, id sql.NullInt64) (GetItemWithTagsRow, error) {
 	stmt := p.stmtGetItemWithTags
 	rows, err := stmt.QueryContext(ctx, id)
 	if err != nil {
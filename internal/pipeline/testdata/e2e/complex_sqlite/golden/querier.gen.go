@@ -7,7 +7,7 @@ import (
 
 type Querier interface {
 	DeleteTag(ctx context.Context, tag string) error
-	GetItemWithTags(ctx context.Context, id *int32) (GetItemWithTagsRow, error)
+	GetItemWithTags(ctx context.Context, id sql.NullInt64) (GetItemWithTagsRow, error)
 }
 type DBTX interface {
 	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
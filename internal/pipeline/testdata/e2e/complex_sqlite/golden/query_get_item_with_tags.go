@@ -1,6 +1,9 @@
 package complex
 
-import "context"
+import (
+	"context"
+	"database/sql"
+)
 
 const queryGetItemWithTags string = `WITH RECURSIVE tag_list AS (
     SELECT tag FROM item_tags WHERE item_id = :id
@@ -9,7 +12,7 @@ SELECT i.*, (SELECT GROUP_CONCAT(tag) FROM tag_list) as tags
 FROM items i
 WHERE i.id = :id;`
 
-func (q *Queries) GetItemWithTags(ctx context.Context, id *int32) (GetItemWithTagsRow, error) {
+func (q *Queries) GetItemWithTags(ctx context.Context, id sql.NullInt64) (GetItemWithTagsRow, error) {
 	row := q.db.QueryRowContext(ctx, queryGetItemWithTags, id)
 	if err := row.Err(); err != nil {
 		return GetItemWithTagsRow{}, err
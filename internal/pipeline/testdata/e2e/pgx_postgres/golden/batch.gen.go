@@ -0,0 +1,101 @@
+package tickets
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrBatchAlreadyClosed is reported for every item of a batch whose results
+// were already read.
+var ErrBatchAlreadyClosed = errors.New("batch already closed")
+
+// batchRows is the result set of one item of a batch.
+type batchRows interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+}
+
+// batch queues one execution of query for each argument list in args.
+type batch struct {
+	ctx   context.Context
+	db    DBTX
+	query string
+	args  [][]any
+	ran   bool
+	err   error
+}
+
+func newBatch(ctx context.Context, db DBTX, query string, args [][]any) *batch {
+	return &batch{ctx: ctx, db: db, query: query, args: args}
+}
+
+// exec runs the batch and calls f with the error of each item.
+func (b *batch) exec(f func(int, error)) {
+	b.run(false, func(i int, _ batchRows, err error) {
+		if f != nil {
+			f(i, err)
+		}
+	})
+}
+
+// rows runs the batch and calls f with the rows or the error of each item.
+// The rows are closed when f returns.
+func (b *batch) rows(f func(int, batchRows, error)) {
+	b.run(true, f)
+}
+
+// close runs the batch unless its results were read and returns the error
+// that ended it, such as a failed commit.
+func (b *batch) close() error {
+	if !b.ran {
+		b.exec(nil)
+	}
+	return b.err
+}
+
+func (b *batch) run(withRows bool, f func(int, batchRows, error)) {
+	if b.ran {
+		for i := range b.args {
+			f(i, nil, ErrBatchAlreadyClosed)
+		}
+		return
+	}
+	b.ran = true
+	if len(b.args) == 0 {
+		return
+	}
+	var pgxBatch pgx.Batch
+	for _, args := range b.args {
+		pgxBatch.Queue(b.query, args...)
+	}
+	results := b.db.SendBatch(b.ctx, &pgxBatch)
+	for i := range b.args {
+		if !withRows {
+			_, err := results.Exec()
+			f(i, nil, err)
+			continue
+		}
+		rows, err := results.Query()
+		if err != nil {
+			f(i, nil, err)
+			continue
+		}
+		f(i, rows, nil)
+		rows.Close()
+	}
+	b.err = results.Close()
+}
+
+// scanBatchRow scans the first row of rows into dest.
+func scanBatchRow(rows batchRows, dest ...any) error {
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return pgx.ErrNoRows
+	}
+	return rows.Scan(dest...)
+}
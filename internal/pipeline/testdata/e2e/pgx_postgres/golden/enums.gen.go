@@ -0,0 +1,76 @@
+package tickets
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// TicketStatus is a value of the ticket_status enum.
+type TicketStatus string
+
+const (
+	TicketStatusOpen   TicketStatus = "open"
+	TicketStatusClosed TicketStatus = "closed"
+)
+
+// Values returns the values of the ticket_status enum in schema order.
+func (TicketStatus) Values() []TicketStatus {
+	return []TicketStatus{
+		TicketStatusOpen,
+		TicketStatusClosed,
+	}
+}
+
+// Valid reports whether e is a value of the ticket_status enum.
+func (e TicketStatus) Valid() bool {
+	switch e {
+	case TicketStatusOpen, TicketStatusClosed:
+		return true
+	}
+	return false
+}
+
+// Scan implements sql.Scanner.
+func (e *TicketStatus) Scan(src any) error {
+	switch v := src.(type) {
+	case string:
+		*e = TicketStatus(v)
+	case []byte:
+		*e = TicketStatus(v)
+	default:
+		return fmt.Errorf("cannot scan %T into TicketStatus", src)
+	}
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (e TicketStatus) Value() (driver.Value, error) {
+	if !e.Valid() {
+		return nil, fmt.Errorf("invalid ticket_status value %q", string(e))
+	}
+	return string(e), nil
+}
+
+// NullTicketStatus is a nullable TicketStatus.
+type NullTicketStatus struct {
+	TicketStatus TicketStatus
+	Valid        bool // Valid is true if TicketStatus is not NULL
+}
+
+// Scan implements sql.Scanner.
+func (n *NullTicketStatus) Scan(src any) error {
+	if src == nil {
+		n.TicketStatus, n.Valid = "", false
+		return nil
+	}
+	n.Valid = true
+	return n.TicketStatus.Scan(src)
+}
+
+// Value implements driver.Valuer.
+func (n NullTicketStatus) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.TicketStatus.Value()
+}
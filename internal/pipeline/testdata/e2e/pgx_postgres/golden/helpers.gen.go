@@ -0,0 +1,27 @@
+package tickets
+
+import (
+	"github.com/jackc/pgx/v5"
+)
+
+type FindTicketRow struct {
+	Id    int64
+	Title string
+}
+
+func scanFindTicketRow(rows pgx.Rows) (FindTicketRow, error) {
+	var item FindTicketRow
+	if err := rows.Scan(&item.Id, &item.Title); err != nil {
+		return item, err
+	}
+	return item, nil
+}
+
+// scanTickets scans a row into the Tickets model.
+func scanTickets(rows pgx.Rows) (Tickets, error) {
+	var item Tickets
+	if err := rows.Scan(&item.Id, &item.Title, &item.Note, &item.Status, &item.CreatedAt); err != nil {
+		return item, err
+	}
+	return item, nil
+}
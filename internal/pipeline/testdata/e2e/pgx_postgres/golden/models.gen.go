@@ -0,0 +1,15 @@
+package tickets
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Tickets struct {
+	Id        int64
+	Title     string
+	Note      pgtype.Text
+	Status    TicketStatus
+	CreatedAt time.Time
+}
@@ -0,0 +1,41 @@
+package tickets
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type Querier interface {
+	BatchClose(ctx context.Context, arg []int64) *BatchCloseBatchResults
+	CloseTicket(ctx context.Context, id int64) error
+	CopyTickets(ctx context.Context, arg []CopyTicketsParams) (int64, error)
+	CreateTicket(ctx context.Context, arg CreateTicketParams) (int64, error)
+	DeleteClosed(ctx context.Context) (int64, error)
+	FindTicket(ctx context.Context, title string) (*FindTicketRow, error)
+	GetTicket(ctx context.Context, id int64) (Tickets, error)
+	ListTickets(ctx context.Context) ([]Tickets, error)
+}
+type DBTX interface {
+	Exec(ctx context.Context, query string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, query string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, query string, args ...any) pgx.Row
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+type Queries struct {
+	db DBTX
+}
+
+func New(db DBTX) *Queries {
+	return &Queries{db: db}
+}
+func (q *Queries) WithTx(tx DBTX) *Queries {
+	return &Queries{db: tx}
+}
+
+type QueryResult struct {
+	LastInsertID int64
+	RowsAffected int64
+}
@@ -0,0 +1,29 @@
+package tickets
+
+import "context"
+
+const queryBatchClose string = `UPDATE tickets SET status = 'closed' WHERE id = $1;`
+
+func (q *Queries) BatchClose(ctx context.Context, arg []int64) *BatchCloseBatchResults {
+	args := make([][]any, len(arg))
+	for i, row := range arg {
+		args[i] = []any{row}
+	}
+	return &BatchCloseBatchResults{batch: newBatch(ctx, q.db, queryBatchClose, args)}
+}
+
+// BatchCloseBatchResults holds the queued executions of BatchClose.
+type BatchCloseBatchResults struct {
+	batch *batch
+}
+
+// Exec runs the batch and calls f with the error of each item.
+func (b *BatchCloseBatchResults) Exec(f func(int, error)) {
+	b.batch.exec(f)
+}
+
+// Close runs the batch unless its results were read and returns the error
+// that ended it, such as a failed commit.
+func (b *BatchCloseBatchResults) Close() error {
+	return b.batch.close()
+}
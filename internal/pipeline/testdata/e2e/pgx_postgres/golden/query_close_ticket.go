@@ -0,0 +1,10 @@
+package tickets
+
+import "context"
+
+const queryCloseTicket string = `UPDATE tickets SET status = 'closed' WHERE id = $1;`
+
+func (q *Queries) CloseTicket(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, queryCloseTicket, id)
+	return err
+}
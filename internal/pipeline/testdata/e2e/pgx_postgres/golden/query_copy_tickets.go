@@ -0,0 +1,23 @@
+package tickets
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type CopyTicketsParams struct {
+	Title  string
+	Note   pgtype.Text
+	Status TicketStatus
+}
+
+const queryCopyTickets string = `INSERT INTO tickets (title, note, status) VALUES ($1, $2, $3);`
+
+func (q *Queries) CopyTickets(ctx context.Context, arg []CopyTicketsParams) (int64, error) {
+	rows := make([][]any, len(arg))
+	for i, row := range arg {
+		rows[i] = []any{row.Title, row.Note, row.Status}
+	}
+	return copyFrom(ctx, q.db, "tickets", []string{"title", "note", "status"}, rows)
+}
@@ -0,0 +1,25 @@
+package tickets
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type CreateTicketParams struct {
+	Title  string
+	Note   pgtype.Text
+	Status TicketStatus
+}
+
+const queryCreateTicket string = `INSERT INTO tickets (title, note, status) VALUES ($1, $2, $3) RETURNING id;`
+
+func (q *Queries) CreateTicket(ctx context.Context, arg CreateTicketParams) (int64, error) {
+	row := q.db.QueryRow(ctx, queryCreateTicket, arg.Title, arg.Note, arg.Status)
+	var item int64
+	err := row.Scan(&item)
+	if err != nil {
+		return 0, err
+	}
+	return item, nil
+}
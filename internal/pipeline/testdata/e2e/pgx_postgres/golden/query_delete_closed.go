@@ -0,0 +1,13 @@
+package tickets
+
+import "context"
+
+const queryDeleteClosed string = `DELETE FROM tickets WHERE status = 'closed';`
+
+func (q *Queries) DeleteClosed(ctx context.Context) (int64, error) {
+	res, err := q.db.Exec(ctx, queryDeleteClosed)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected(), nil
+}
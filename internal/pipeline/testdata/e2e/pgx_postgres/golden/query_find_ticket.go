@@ -0,0 +1,23 @@
+package tickets
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const queryFindTicket string = `SELECT id, title FROM tickets WHERE title = $1;`
+
+func (q *Queries) FindTicket(ctx context.Context, title string) (*FindTicketRow, error) {
+	row := q.db.QueryRow(ctx, queryFindTicket, title)
+	var item FindTicketRow
+	err := row.Scan(&item.Id, &item.Title)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
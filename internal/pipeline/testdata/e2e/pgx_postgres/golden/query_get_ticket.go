@@ -0,0 +1,15 @@
+package tickets
+
+import "context"
+
+const queryGetTicket string = `SELECT * FROM tickets WHERE id = $1;`
+
+func (q *Queries) GetTicket(ctx context.Context, id int64) (Tickets, error) {
+	row := q.db.QueryRow(ctx, queryGetTicket, id)
+	var item Tickets
+	err := row.Scan(&item.Id, &item.Title, &item.Note, &item.Status, &item.CreatedAt)
+	if err != nil {
+		return Tickets{}, err
+	}
+	return item, nil
+}
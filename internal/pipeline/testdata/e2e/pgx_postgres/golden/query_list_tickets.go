@@ -0,0 +1,25 @@
+package tickets
+
+import "context"
+
+const queryListTickets string = `SELECT * FROM tickets ORDER BY id;`
+
+func (q *Queries) ListTickets(ctx context.Context) ([]Tickets, error) {
+	rows, err := q.db.Query(ctx, queryListTickets)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Tickets
+	for rows.Next() {
+		item, err := scanTickets(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,23 @@
+-- name: CreateTicket :one
+INSERT INTO tickets (title, note, status) VALUES ($1, $2, $3) RETURNING id;
+
+-- name: GetTicket :one
+SELECT * FROM tickets WHERE id = $1;
+
+-- name: FindTicket :opt
+SELECT id, title FROM tickets WHERE title = $1;
+
+-- name: ListTickets :many
+SELECT * FROM tickets ORDER BY id;
+
+-- name: CloseTicket :exec
+UPDATE tickets SET status = 'closed' WHERE id = $1;
+
+-- name: DeleteClosed :execrows
+DELETE FROM tickets WHERE status = 'closed';
+
+-- name: CopyTickets :copyfrom
+INSERT INTO tickets (title, note, status) VALUES ($1, $2, $3);
+
+-- name: BatchClose :batchexec
+UPDATE tickets SET status = 'closed' WHERE id = $1;
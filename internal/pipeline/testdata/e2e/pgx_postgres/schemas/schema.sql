@@ -0,0 +1,9 @@
+CREATE TYPE ticket_status AS ENUM ('open', 'closed');
+
+CREATE TABLE tickets (
+    id BIGSERIAL PRIMARY KEY,
+    title TEXT NOT NULL,
+    note TEXT,
+    status ticket_status NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -11,6 +11,8 @@ import (
 	"unicode/utf8"
 
 	"github.com/electwix/db-catalyst/internal/config"
+	"github.com/electwix/db-catalyst/internal/engine"
+	"github.com/electwix/db-catalyst/internal/engine/sqlite"
 	"github.com/electwix/db-catalyst/internal/query/block"
 	"github.com/electwix/db-catalyst/internal/query/parser"
 	"github.com/electwix/db-catalyst/internal/schema/model"
@@ -32,8 +34,14 @@ type Analyzer struct {
 	CustomTypes     map[string]config.CustomTypeMapping
 	ColumnOverrides map[string]config.ColumnOverride
 	typeResolver    TypeResolver
+	functions       *engine.FunctionCatalog
+	sqliteTypes     config.SQLiteTypes
 }
 
+// defaultFunctions is used when no engine function catalog is configured;
+// like the default type mapping it assumes SQLite.
+var defaultFunctions = engine.NewFunctionCatalog(engine.StandardFunctions(), sqlite.Functions())
+
 // TypeResolver interface for database-specific type mapping.
 type TypeResolver interface {
 	ResolveType(sqlType string, nullable bool) TypeInfo
@@ -52,6 +60,7 @@ type Result struct {
 	Query       parser.Query
 	Columns     []ResultColumn
 	Params      []ResultParam
+	CopyFrom    *CopyFromTarget // Insert target of a :copyfrom query
 	Diagnostics []Diagnostic
 }
 
@@ -63,6 +72,15 @@ type ResultColumn struct {
 	Nullable bool
 	Import   string
 	Package  string
+	Enum     *model.Enum   // Schema enum the column's values come from
+	Domain   *model.Domain // Schema domain the column's values come from
+	Embed    *Embed        // Set on the columns of a sqlc.embed result
+	// NullStyle is the null_style a column override sets for the schema
+	// column the values come from, empty for the generation null_style.
+	NullStyle config.NullStyle
+	// Storage is set when the values come from a [sqlite_types] column the
+	// driver cannot scan into GoType by itself.
+	Storage config.SQLiteStorage
 }
 
 // ResultParam describes a single input parameter of a query.
@@ -75,6 +93,14 @@ type ResultParam struct {
 	VariadicCount int
 	Import        string
 	Package       string
+	Enum          *model.Enum   // Schema enum the parameter's values come from
+	Domain        *model.Domain // Schema domain the parameter's values come from
+	// NullStyle is the null_style a column override sets for the schema
+	// column the parameter is compared with or assigned to.
+	NullStyle config.NullStyle
+	// Storage is set when that column is a [sqlite_types] column the driver
+	// cannot bind GoType to by itself.
+	Storage config.SQLiteStorage
 }
 
 // Diagnostic represents an issue found during analysis.
@@ -114,6 +140,12 @@ const (
 
 type queryScope struct {
 	entries map[string]*scopeEntry
+	// from lists the keys of the FROM clause relations in order, for
+	// expanding an unqualified star.
+	from []string
+	// outer holds the relations of the enclosing query that a LATERAL
+	// subquery may reference; they are consulted when a name is not found.
+	outer *queryScope
 }
 
 type scopeEntry struct {
@@ -129,6 +161,10 @@ type scopeColumn struct {
 	nullable    bool
 	importPath  string
 	packageName string
+	enum        *model.Enum
+	domain      *model.Domain
+	nullStyle   config.NullStyle
+	storage     config.SQLiteStorage
 }
 
 type textIndex struct {
@@ -137,10 +173,14 @@ type textIndex struct {
 }
 
 type paramInfo struct {
-	GoType   string
-	Nullable bool
-	Import   string
-	Package  string
+	GoType    string
+	Nullable  bool
+	Import    string
+	Package   string
+	Enum      *model.Enum
+	Domain    *model.Domain
+	NullStyle config.NullStyle
+	Storage   config.SQLiteStorage
 }
 
 type posKey struct {
@@ -237,61 +277,82 @@ func (a *Analyzer) Analyze(q parser.Query) Result {
 		}
 	}
 
-	workingScope := newQueryScope()
+	var workingScope *queryScope
+	mainTokens := tokens
 	if tokens != nil {
 		// Populate baseScope with all aliases in the entire block (including CTEs)
 		// so that inferParamTypes can resolve columns like u.id correctly.
 		addAliasesFromTokens(baseScope, tokens)
 
 		mainIdx := findMainStatementStart(tokens)
-		mainTokens := tokens
 		if mainIdx >= 0 {
 			mainTokens = tokens[mainIdx:]
 		}
 
-		// Only add tables/CTEs that are actually referenced in FROM/JOIN/INSERT/UPDATE/DELETE
-		referenced := discoverReferencedRelations(mainTokens)
-		// Always include CTEs in the working scope if they are referenced
-		for _, cte := range q.CTEs {
-			referenced = append(referenced, cte.Name)
+		for _, d := range a.addDerivedTables(baseScope, q, mainTokens, hasCatalog) {
+			addDiag(d)
 		}
-
-		for _, ref := range referenced {
-			if entry, ok := baseScope.get(ref); ok {
-				workingScope.addEntry(ref, entry)
+		workingScope = relationScope(baseScope, mainTokens, q.CTEs)
+		if q.Verb == parser.VerbInsert {
+			for _, d := range a.resolveUpsert(mainTokens, q, hasCatalog, baseScope, workingScope) {
+				addDiag(d)
 			}
 		}
-		addAliasesFromTokens(workingScope, mainTokens)
 	} else {
 		workingScope = baseScope.clone()
 	}
 
-	for _, col := range q.Columns {
-		if col.Expr == "*" || strings.HasSuffix(col.Expr, ".*") {
-			expanded, diags := expandStar(col, workingScope, q.Block, hasCatalog)
-			result.Columns = append(result.Columns, expanded...)
-			for _, d := range diags {
-				addDiag(d)
-			}
-			continue
+	// Result columns see outer joins and NULL-rejecting predicates; parameters
+	// keep the schema nullability of the columns they are compared with.
+	// Each branch of a compound query sees only its own relations.
+	columnScope := workingScope.clone()
+	columnTokens := mainTokens
+	var branchTokens [][]tokenizer.Token
+	if len(q.Compound) > 0 {
+		branchTokens = splitCompoundTokens(mainTokens, len(q.Compound)+1)
+		if branchTokens != nil {
+			columnTokens = branchTokens[0]
+			columnScope = relationScope(baseScope, columnTokens, q.CTEs)
 		}
+	}
+	a.applyJoinNullability(columnScope, columnTokens)
 
-		rc, diags := resolveResultColumn(col, workingScope, q.Block, hasCatalog)
-		result.Columns = append(result.Columns, rc)
-		for _, d := range diags {
-			addDiag(d)
-		}
+	columns, diags := a.resolveSelectColumns(q.Columns, columnScope, baseScope, q.Block, hasCatalog)
+	result.Columns = append(result.Columns, columns...)
+	for _, d := range diags {
+		addDiag(d)
+	}
+	for _, d := range a.resolveCompoundBranches(q, result.Columns, branchTokens, baseScope, hasCatalog) {
+		addDiag(d)
 	}
 
 	// Handle RETURNING clause for DML statements
 	if (q.Verb == parser.VerbInsert || q.Verb == parser.VerbUpdate || q.Verb == parser.VerbDelete) && tokens != nil {
-		returningCols, diags := discoverReturningColumns(tokens, q.Block, workingScope, hasCatalog)
+		returningCols, diags := a.discoverReturningColumns(tokens, q.Block, columnScope, hasCatalog)
 		result.Columns = append(result.Columns, returningCols...)
 		for _, d := range diags {
 			addDiag(d)
 		}
 	}
 
+	for _, d := range applyNonNullAnnotations(result.Columns, q.Block) {
+		addDiag(d)
+	}
+
+	if q.Block.Command.IsBatch() {
+		for _, d := range checkBatch(q) {
+			addDiag(d)
+		}
+	}
+
+	if q.Block.Command == block.CommandCopyFrom && tokens != nil {
+		target, diags := a.resolveCopyFrom(q, mainTokens, hasCatalog)
+		result.CopyFrom = target
+		for _, d := range diags {
+			addDiag(d)
+		}
+	}
+
 	paramInfos := a.inferParamTypes(q, workingScope, baseScope)
 
 	// Build a map of explicit type overrides from block annotations
@@ -319,15 +380,39 @@ func (a *Analyzer) Analyze(q parser.Query) Result {
 			rp.Nullable = info.Nullable
 			rp.Import = info.Import
 			rp.Package = info.Package
+			rp.Enum = info.Enum
+			rp.Domain = info.Domain
+			rp.NullStyle = info.NullStyle
+			rp.Storage = info.Storage
 		}
 		result.Params = append(result.Params, rp)
 	}
 
+	if tokens != nil && hasCatalog {
+		for _, d := range a.validateFunctionCalls(tokens, baseScope, q) {
+			addDiag(d)
+		}
+	}
+
 	// Validate all identifiers in the main statement (WHERE, ORDER BY, etc.)
 	if tokens != nil && hasCatalog {
 		mainIdx := findMainStatementStart(tokens)
-		if mainIdx >= 0 {
-			diags := a.validateIdentifiers(tokens[mainIdx:], workingScope, q.Block)
+		switch {
+		case branchTokens != nil:
+			// Aliases are local to each branch of a compound query.
+			for _, branch := range branchTokens {
+				body, branchScope := withoutDerivedTables(branch), relationScope(baseScope, branch, q.CTEs)
+				for _, d := range a.validateIdentifiers(body, branchScope, q.Block) {
+					addDiag(d)
+				}
+				for _, d := range validateWindows(body, branchScope, q) {
+					addDiag(d)
+				}
+			}
+		case mainIdx >= 0:
+			mainTokens := withoutDerivedTables(tokens[mainIdx:])
+			diags := a.validateIdentifiers(mainTokens, workingScope, q.Block)
+			diags = append(diags, validateWindows(mainTokens, workingScope, q)...)
 			for _, d := range diags {
 				addDiag(d)
 			}
@@ -337,6 +422,65 @@ func (a *Analyzer) Analyze(q parser.Query) Result {
 	return result
 }
 
+// relationScope returns a scope holding the tables and CTEs referenced by
+// tokens, under their names and aliases.
+func relationScope(baseScope *queryScope, tokens []tokenizer.Token, ctes []parser.CTE) *queryScope {
+	scope := newQueryScope()
+	relations := parseFromClauses(tokens).relations
+	// The relations inside derived tables belong to their own scopes.
+	outerTokens := withoutDerivedTables(tokens)
+	// Only add tables/CTEs that are actually referenced in FROM/JOIN/INSERT/UPDATE/DELETE
+	referenced := discoverReferencedRelations(outerTokens)
+	// Always include CTEs in the working scope if they are referenced
+	for _, cte := range ctes {
+		referenced = append(referenced, cte.Name)
+	}
+
+	for _, ref := range referenced {
+		if entry, ok := baseScope.get(ref); ok {
+			scope.addEntry(ref, entry)
+		}
+	}
+	addAliasesFromTokens(scope, outerTokens)
+
+	// Derived tables are resolved into baseScope under their aliases.
+	for _, rel := range relations {
+		scope.from = append(scope.from, rel.key())
+		if rel.subquery == nil {
+			continue
+		}
+		if entry, ok := baseScope.get(rel.alias); ok {
+			scope.addEntry(rel.alias, entry)
+		}
+	}
+	return scope
+}
+
+// resolveSelectColumns resolves the columns of a SELECT list, expanding stars.
+func (a *Analyzer) resolveSelectColumns(cols []parser.Column, scope, relations *queryScope, blk block.Block, hasCatalog bool) ([]ResultColumn, []Diagnostic) {
+	var out []ResultColumn
+	var diags []Diagnostic
+	for _, col := range cols {
+		if col.Embed != "" {
+			embedded, embedDiags := a.resolveEmbed(col, scope, relations, blk, hasCatalog)
+			out = append(out, embedded...)
+			diags = append(diags, embedDiags...)
+			continue
+		}
+		if col.Expr == "*" || strings.HasSuffix(col.Expr, ".*") {
+			expanded, starDiags := expandStar(col, scope, blk, hasCatalog)
+			out = append(out, expanded...)
+			diags = append(diags, starDiags...)
+			continue
+		}
+
+		rc, colDiags := a.resolveResultColumn(col, scope, relations, blk, hasCatalog)
+		out = append(out, rc)
+		diags = append(diags, colDiags...)
+	}
+	return out, diags
+}
+
 func findMainStatementStart(tokens []tokenizer.Token) int {
 	depth := 0
 	for i, tok := range tokens {
@@ -486,6 +630,7 @@ func (a *Analyzer) resolveCTE(cte parser.CTE, parent parser.Query, scope *queryS
 			}
 		}
 		addAliasesFromTokens(workingScope, anchorTokens)
+		a.applyJoinNullability(workingScope, anchorTokens)
 	} else {
 		workingScope = scope.clone()
 	}
@@ -497,27 +642,25 @@ func (a *Analyzer) resolveCTE(cte parser.CTE, parent parser.Query, scope *queryS
 	}
 
 	resolved := make([]scopeColumn, 0, len(columnNames))
+	// Columns the anchor leaves untyped, such as NULL literals, may still be
+	// typed by the recursive term.
+	var untyped []int
 	for idx, col := range anchorQuery.Columns {
 		name := columnNames[idx]
 		sc := scopeColumn{name: name, owner: cte.Name, goType: "any", nullable: true}
 
 		suppressDefaultWarning := false
 		if hasCatalog {
-			colInfo, warn := a.resolveCTEColumn(col, cte, workingScope, parent.Block.Path)
+			colInfo, warn := a.resolveCTEColumn(col, cte, workingScope, scope, parent.Block.Path)
 			sc.goType = colInfo.goType
 			sc.nullable = colInfo.nullable
+			sc.storage = colInfo.storage
 			suppressDefaultWarning = colInfo.suppressWarning
 			diags = append(diags, warn...)
 		}
 
 		if sc.goType == "any" && hasCatalog && !suppressDefaultWarning {
-			diags = append(diags, Diagnostic{
-				Path:     parent.Block.Path,
-				Line:     col.Line,
-				Column:   col.Column,
-				Message:  fmt.Sprintf("unable to infer type for CTE column %s; defaulting to interface{}", name),
-				Severity: SeverityWarning,
-			})
+			untyped = append(untyped, idx)
 		}
 
 		resolved = append(resolved, sc)
@@ -558,22 +701,42 @@ func (a *Analyzer) resolveCTE(cte parser.CTE, parent parser.Query, scope *queryS
 				Message:  fmt.Sprintf("recursive term of CTE %s projects %d columns; expected %d", cte.Name, projected, len(resolved)),
 				Severity: SeverityError,
 			})
+		} else if hasCatalog {
+			diags = append(diags, a.unifyRecursiveTerm(cte, recQuery, resolved, scope)...)
 		}
 
 	}
 
+	for _, idx := range untyped {
+		if resolved[idx].goType != "any" {
+			continue
+		}
+		col := anchorQuery.Columns[idx]
+		diags = append(diags, Diagnostic{
+			Path:     parent.Block.Path,
+			Line:     col.Line,
+			Column:   col.Column,
+			Message:  fmt.Sprintf("unable to infer type for CTE column %s; defaulting to interface{}", resolved[idx].name),
+			Severity: SeverityWarning,
+		})
+	}
+
+	return newCTEEntry(cte.Name, resolved), diags
+}
+
+// newCTEEntry builds the scope entry for a CTE with the given columns.
+func newCTEEntry(name string, cols []scopeColumn) *scopeEntry {
 	entry := &scopeEntry{
-		name:        cte.Name,
-		columns:     make([]scopeColumn, 0, len(resolved)),
-		columnIndex: make(map[string]int, len(resolved)),
+		name:        name,
+		columns:     make([]scopeColumn, 0, len(cols)),
+		columnIndex: make(map[string]int, len(cols)),
 	}
-	for _, col := range resolved {
+	for _, col := range cols {
 		idx := len(entry.columns)
 		entry.columns = append(entry.columns, col)
 		entry.columnIndex[normalizeIdent(col.name)] = idx
 	}
-
-	return entry, diags
+	return entry
 }
 
 func cteOutputNames(cte parser.CTE, q parser.Query) ([]string, []Diagnostic) {
@@ -628,14 +791,24 @@ func cteOutputNames(cte parser.CTE, q parser.Query) ([]string, []Diagnostic) {
 type cteColumnInfo struct {
 	goType          string
 	nullable        bool
+	storage         config.SQLiteStorage
 	suppressWarning bool
 }
 
 // resolveCTEColumn resolves a single CTE column's type information.
-func (a *Analyzer) resolveCTEColumn(col parser.Column, cte parser.CTE, workingScope *queryScope, path string) (cteColumnInfo, []Diagnostic) {
+func (a *Analyzer) resolveCTEColumn(col parser.Column, cte parser.CTE, workingScope, relations *queryScope, path string) (cteColumnInfo, []Diagnostic) {
 	info := cteColumnInfo{goType: "any", nullable: true}
 	var diags []Diagnostic
 
+	if _, _, simple := splitQualifiedIdentifier(col.Expr); !simple {
+		if typed, ok := a.typeExpr(col.Expr, workingScope, relations); ok {
+			info.goType = typed.goType
+			info.nullable = typed.nullable
+			info.storage = typed.storage
+			return info, diags
+		}
+	}
+
 	alias := col.Table
 	columnName := deriveColumnName(col)
 	agg, isAggregate := parseAggregateExpr(col.Expr)
@@ -675,6 +848,7 @@ func (a *Analyzer) resolveCTEColumn(col parser.Column, cte parser.CTE, workingSc
 		} else {
 			info.goType = lookup.goType
 			info.nullable = lookup.nullable
+			info.storage = lookup.storage
 		}
 	case scopeLookupAliasNotFound:
 		if inferred, ok := a.inferTypeFromExpr(col.Expr); ok {
@@ -714,7 +888,11 @@ type exprTypeInfo struct {
 // inferTypeFromExpr attempts to infer the Go type from a literal or simple expression.
 // Returns the inferred type and true if successful, or empty and false if unable to infer.
 func (a *Analyzer) inferTypeFromExpr(expr string) (exprTypeInfo, bool) {
-	return inferTypeFromExprWithResolver(expr, a.typeResolver, a.CustomTypes)
+	res, ok := a.typeExpr(expr, nil, nil)
+	if !ok {
+		return exprTypeInfo{}, false
+	}
+	return exprTypeInfo{goType: res.goType, nullable: res.nullable}, true
 }
 
 // InferTypeFromExprWithResolver is the public API for inferring type from an expression.
@@ -727,67 +905,15 @@ func InferTypeFromExprWithResolver(expr string, resolver TypeResolver, customTyp
 	return info.goType, info.nullable, true
 }
 
-// inferTypeFromExprWithResolver infers type with optional type resolver for CAST expressions.
+// inferTypeFromExprWithResolver infers the type of a standalone expression.
+// Column references are left untyped because no scope is available.
 func inferTypeFromExprWithResolver(expr string, resolver TypeResolver, customTypes map[string]config.CustomTypeMapping) (exprTypeInfo, bool) {
-	trimmed := strings.TrimSpace(expr)
-	if trimmed == "" {
-		return exprTypeInfo{}, false
-	}
-
-	tokens, err := tokenizer.Scan("", []byte(trimmed), false)
-	if err != nil || len(tokens) == 0 {
-		return exprTypeInfo{}, false
-	}
-
-	filtered := make([]tokenizer.Token, 0, len(tokens))
-	for _, tok := range tokens {
-		if tok.Kind == tokenizer.KindEOF || tok.Kind == tokenizer.KindDocComment {
-			continue
-		}
-		filtered = append(filtered, tok)
-	}
-	if len(filtered) == 0 {
+	t := &exprTyper{resolver: resolver, customTypes: customTypes}
+	res, ok := t.typeString(expr)
+	if !ok {
 		return exprTypeInfo{}, false
 	}
-
-	if len(filtered) == 1 {
-		return inferTypeFromSingleToken(filtered[0])
-	}
-
-	if castInfo, ok := inferTypeFromCast(filtered, resolver, customTypes); ok {
-		return castInfo, true
-	}
-
-	return exprTypeInfo{}, false
-}
-
-// inferTypeFromSingleToken infers type from a single token (literal).
-func inferTypeFromSingleToken(tok tokenizer.Token) (exprTypeInfo, bool) {
-	switch tok.Kind {
-	case tokenizer.KindString:
-		return exprTypeInfo{goType: "string", nullable: false}, true
-	case tokenizer.KindNumber:
-		return inferTypeFromNumber(tok.Text)
-	case tokenizer.KindBlob:
-		return exprTypeInfo{goType: "[]byte", nullable: false}, true
-	case tokenizer.KindKeyword:
-		upper := strings.ToUpper(tok.Text)
-		switch upper {
-		case "TRUE", "FALSE":
-			return exprTypeInfo{goType: "bool", nullable: false}, true
-		case "NULL":
-			return exprTypeInfo{goType: "any", nullable: true}, true
-		}
-	case tokenizer.KindIdentifier:
-		upper := strings.ToUpper(tok.Text)
-		switch upper {
-		case "TRUE", "FALSE":
-			return exprTypeInfo{goType: "bool", nullable: false}, true
-		case "NULL":
-			return exprTypeInfo{goType: "any", nullable: true}, true
-		}
-	}
-	return exprTypeInfo{}, false
+	return exprTypeInfo{goType: res.goType, nullable: res.nullable}, true
 }
 
 // inferTypeFromNumber infers type from a numeric literal.
@@ -798,55 +924,6 @@ func inferTypeFromNumber(text string) (exprTypeInfo, bool) {
 	return exprTypeInfo{goType: "int64", nullable: false}, true
 }
 
-// inferTypeFromCast infers type from a CAST expression.
-func inferTypeFromCast(tokens []tokenizer.Token, resolver TypeResolver, customTypes map[string]config.CustomTypeMapping) (exprTypeInfo, bool) {
-	if len(tokens) < 4 { //nolint:mnd // minimum tokens: CAST ( AS TYPE )
-		return exprTypeInfo{}, false
-	}
-
-	isCast := (tokens[0].Kind == tokenizer.KindKeyword || tokens[0].Kind == tokenizer.KindIdentifier) &&
-		strings.ToUpper(tokens[0].Text) == "CAST"
-	if !isCast {
-		return exprTypeInfo{}, false
-	}
-
-	var targetType string
-	var foundAS bool
-	depth := 0
-
-	for i := 1; i < len(tokens); i++ {
-		tok := tokens[i]
-		if tok.Kind == tokenizer.KindSymbol {
-			switch tok.Text {
-			case "(":
-				depth++
-			case ")":
-				depth--
-				if depth == 0 && foundAS && targetType != "" {
-					goType := sqlTypeToGo(targetType, resolver, customTypes)
-					if goType != "any" {
-						return exprTypeInfo{goType: goType, nullable: false}, true
-					}
-				}
-			}
-			continue
-		}
-		if (tok.Kind == tokenizer.KindKeyword || tok.Kind == tokenizer.KindIdentifier) && strings.ToUpper(tok.Text) == "AS" && depth == 1 {
-			foundAS = true
-			continue
-		}
-		if foundAS && depth == 1 && (tok.Kind == tokenizer.KindKeyword || tok.Kind == tokenizer.KindIdentifier) {
-			if targetType == "" {
-				targetType = tok.Text
-			} else {
-				targetType += " " + tok.Text
-			}
-		}
-	}
-
-	return exprTypeInfo{}, false
-}
-
 // sqlTypeToGo converts a SQL type to Go type, optionally using a resolver and custom types.
 func sqlTypeToGo(sqlType string, resolver TypeResolver, customTypes map[string]config.CustomTypeMapping) string {
 	if resolver != nil {
@@ -877,6 +954,9 @@ func (a *Analyzer) resolveAggregateType(agg aggregateExpr, lookup scopeColumn, _
 
 	info.goType = goType
 	info.nullable = nullable
+	if goType == lookup.goType {
+		info.storage = lookup.storage
+	}
 	info.suppressWarning = false
 	return info
 }
@@ -981,8 +1061,12 @@ func parseAggregateExpr(expr string) (aggregateExpr, bool) {
 	if closeIdx < 0 || closeIdx <= open {
 		return aggregateExpr{}, false
 	}
+	// An aggregate used as a window function keeps its aggregate semantics.
+	if end := matchingParen(trimmed, open); end > 0 && isOverClause(trimmed[end+1:]) {
+		closeIdx = end
+	}
 	after := strings.TrimSpace(trimmed[closeIdx+1:])
-	if after != "" {
+	if after != "" && !isOverClause(after) {
 		return aggregateExpr{}, false
 	}
 
@@ -1017,6 +1101,33 @@ func parseAggregateExpr(expr string) (aggregateExpr, bool) {
 	return agg, true
 }
 
+// matchingParen returns the index of the ")" closing the "(" at open in s, or -1.
+func matchingParen(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// isOverClause reports whether s starts with the OVER clause of a window function.
+func isOverClause(s string) bool {
+	s = strings.TrimSpace(s)
+	if len(s) < len("OVER") || !strings.EqualFold(s[:len("OVER")], "OVER") {
+		return false
+	}
+	rest := s[len("OVER"):]
+	return rest == "" || rest[0] == ' ' || rest[0] == '\t' || rest[0] == '\n' || rest[0] == '('
+}
+
 func splitQualifiedIdentifier(expr string) (string, string, bool) {
 	tokens, err := tokenizer.Scan("", []byte(expr), false)
 	if err != nil {
@@ -1171,9 +1282,24 @@ func expandStar(col parser.Column, scope *queryScope, blk block.Block, hasCatalo
 		return entryToResultColumns(entry), nil
 	}
 
-	// Expand all tables in scope
+	// Expand the FROM clause relations in order, or all tables in scope when
+	// the FROM clause is unknown.
 	var cols []ResultColumn
 	seen := make(map[*scopeEntry]struct{})
+	if len(scope.from) > 0 {
+		for _, key := range scope.from {
+			entry, ok := scope.entries[normalizeIdent(key)]
+			if !ok {
+				continue
+			}
+			if _, dup := seen[entry]; dup {
+				continue
+			}
+			seen[entry] = struct{}{}
+			cols = append(cols, entryToResultColumns(entry)...)
+		}
+		return cols, nil
+	}
 	for _, entry := range scope.entries {
 		if _, ok := seen[entry]; ok {
 			continue
@@ -1189,18 +1315,22 @@ func entryToResultColumns(entry *scopeEntry) []ResultColumn {
 	// Columns are stored in schema order to ensure deterministic output
 	for _, sc := range entry.columns {
 		cols = append(cols, ResultColumn{
-			Name:     sc.name,
-			Table:    entry.name,
-			GoType:   sc.goType,
-			Nullable: sc.nullable,
-			Import:   sc.importPath,
-			Package:  sc.packageName,
+			Name:      sc.name,
+			Table:     entry.name,
+			GoType:    sc.goType,
+			Nullable:  sc.nullable,
+			Import:    sc.importPath,
+			Package:   sc.packageName,
+			Enum:      sc.enum,
+			Domain:    sc.domain,
+			NullStyle: sc.nullStyle,
+			Storage:   sc.storage,
 		})
 	}
 	return cols
 }
 
-func discoverReturningColumns(tokens []tokenizer.Token, blk block.Block, scope *queryScope, hasCatalog bool) ([]ResultColumn, []Diagnostic) {
+func (a *Analyzer) discoverReturningColumns(tokens []tokenizer.Token, blk block.Block, scope *queryScope, hasCatalog bool) ([]ResultColumn, []Diagnostic) {
 	returningIdx := -1
 	for i := len(tokens) - 1; i >= 0; i-- {
 		if tokens[i].Kind == tokenizer.KindKeyword && strings.ToUpper(tokens[i].Text) == "RETURNING" {
@@ -1249,7 +1379,7 @@ func discoverReturningColumns(tokens []tokenizer.Token, blk block.Block, scope *
 				i = next
 			}
 
-			rc, rDiags := resolveResultColumn(parser.Column{Expr: name, Alias: alias, Line: tokens[i].Line, Column: tokens[i].Column}, scope, blk, hasCatalog)
+			rc, rDiags := a.resolveResultColumn(parser.Column{Expr: name, Alias: alias, Line: tokens[i].Line, Column: tokens[i].Column}, scope, scope, blk, hasCatalog)
 			cols = append(cols, rc)
 			diags = append(diags, rDiags...)
 		}
@@ -1259,7 +1389,7 @@ func discoverReturningColumns(tokens []tokenizer.Token, blk block.Block, scope *
 	return cols, diags
 }
 
-func resolveResultColumn(col parser.Column, scope *queryScope, blk block.Block, hasCatalog bool) (ResultColumn, []Diagnostic) {
+func (a *Analyzer) resolveResultColumn(col parser.Column, scope, relations *queryScope, blk block.Block, hasCatalog bool) (ResultColumn, []Diagnostic) {
 	rc := ResultColumn{
 		Name:     columnDisplayName(col),
 		Table:    col.Table,
@@ -1292,6 +1422,24 @@ func resolveResultColumn(col parser.Column, scope *queryScope, blk block.Block,
 			rc.Nullable = false
 			return rc, diags
 		}
+	}
+
+	// Anything other than a bare column reference goes through the expression typer;
+	// when it cannot type the expression the lookups below produce the diagnostics.
+	if _, _, simple := splitQualifiedIdentifier(col.Expr); !simple {
+		if info, ok := a.typeExpr(col.Expr, scope, relations); ok {
+			rc.GoType = info.goType
+			rc.Nullable = info.nullable
+			rc.Import = info.importPath
+			rc.Package = info.packageName
+			rc.Enum = info.enum
+			rc.Domain = info.domain
+			rc.Storage = info.storage
+			return rc, diags
+		}
+	}
+
+	if isAggregate {
 		if agg.argColumn == "" {
 			diags = append(diags, Diagnostic{
 				Path:     blk.Path,
@@ -1327,6 +1475,9 @@ func resolveResultColumn(col parser.Column, scope *queryScope, blk block.Block,
 			} else {
 				rc.GoType = goType
 				rc.Nullable = nullable
+				if goType == lookup.goType {
+					rc.Storage = lookup.storage
+				}
 			}
 			return rc, diags
 		}
@@ -1334,6 +1485,10 @@ func resolveResultColumn(col parser.Column, scope *queryScope, blk block.Block,
 		rc.Nullable = lookup.nullable
 		rc.Import = lookup.importPath
 		rc.Package = lookup.packageName
+		rc.Enum = lookup.enum
+		rc.Domain = lookup.domain
+		rc.NullStyle = lookup.nullStyle
+		rc.Storage = lookup.storage
 	case scopeLookupAliasNotFound:
 		if isAggregate {
 			msg := fmt.Sprintf("aggregate %s references unknown relation", aggregateKindString(agg.kind))
@@ -1357,9 +1512,9 @@ func resolveResultColumn(col parser.Column, scope *queryScope, blk block.Block,
 				Message:  fmt.Sprintf("result column %q references unknown table %q", rcOrExprName(rc, col), alias),
 				Severity: SeverityError,
 			})
-		} else if goType, nullable, ok := InferTypeFromExprWithResolver(col.Expr, nil, nil); ok {
-			rc.GoType = goType
-			rc.Nullable = nullable
+		} else if info, ok := a.inferTypeFromExpr(col.Expr); ok {
+			rc.GoType = info.goType
+			rc.Nullable = info.nullable
 		} else if col.Alias != "" {
 			diags = append(diags, Diagnostic{
 				Path:     blk.Path,
@@ -1394,9 +1549,9 @@ func resolveResultColumn(col parser.Column, scope *queryScope, blk block.Block,
 				Severity: SeverityError,
 			})
 		case col.Alias != "":
-			if goType, nullable, ok := InferTypeFromExprWithResolver(col.Expr, nil, nil); ok {
-				rc.GoType = goType
-				rc.Nullable = nullable
+			if info, ok := a.inferTypeFromExpr(col.Expr); ok {
+				rc.GoType = info.goType
+				rc.Nullable = info.nullable
 			} else {
 				diags = append(diags, Diagnostic{
 					Path:     blk.Path,
@@ -1458,6 +1613,8 @@ func (s *queryScope) clone() *queryScope {
 	}
 	clone := newQueryScope()
 	maps.Copy(clone.entries, s.entries)
+	clone.from = s.from
+	clone.outer = s.outer
 	return clone
 }
 
@@ -1488,6 +1645,9 @@ func (s *queryScope) get(name string) (*scopeEntry, bool) {
 		return nil, false
 	}
 	entry, ok := s.entries[normalizeIdent(name)]
+	if !ok && s.outer != nil {
+		return s.outer.get(name)
+	}
 	return entry, ok
 }
 
@@ -1498,6 +1658,9 @@ func (s *queryScope) lookup(alias, column string) (scopeColumn, *scopeEntry, sco
 	if alias != "" {
 		entry, ok := s.entries[normalizeIdent(alias)]
 		if !ok {
+			if s.outer != nil {
+				return s.outer.lookup(alias, column)
+			}
 			return scopeColumn{}, nil, scopeLookupAliasNotFound
 		}
 		idx, found := entry.columnIndex[normalizeIdent(column)]
@@ -1535,6 +1698,9 @@ func (s *queryScope) lookup(alias, column string) (scopeColumn, *scopeEntry, sco
 	if matches == 1 {
 		return foundCol, foundEntry, scopeLookupOK
 	}
+	if s.outer != nil {
+		return s.outer.lookup(alias, column)
+	}
 	return scopeColumn{}, nil, scopeLookupColumnNotFound
 }
 
@@ -1551,6 +1717,10 @@ func (a *Analyzer) scopeEntryFromTable(tbl *model.Table) *scopeEntry {
 			nullable:    typeInfo.nullable,
 			importPath:  typeInfo.importPath,
 			packageName: typeInfo.packageName,
+			enum:        typeInfo.enum,
+			domain:      typeInfo.domain,
+			nullStyle:   a.columnNullStyle(tbl.Name, col.Name),
+			storage:     typeInfo.storage,
 		})
 		colIndex[normalizeIdent(col.Name)] = idx
 	}
@@ -1563,6 +1733,9 @@ type columnTypeInfo struct {
 	nullable    bool
 	importPath  string
 	packageName string
+	enum        *model.Enum
+	domain      *model.Domain
+	storage     config.SQLiteStorage
 }
 
 // resolveColumnTypeFull resolves the Go type for a column with full type information.
@@ -1573,11 +1746,38 @@ func (a *Analyzer) resolveColumnTypeFull(tableName string, col *model.Column) co
 		return info
 	}
 
-	// Fall back to default type mapping
-	return columnTypeInfo{
-		goType:   a.SQLiteTypeToGo(col.Type),
-		nullable: !col.NotNull,
+	return a.schemaColumnType(a.Catalog, col)
+}
+
+// schemaColumnType resolves the Go type of a column from its schema
+// definition. Enum columns hold the enum's string values, domain columns
+// the values of the domain's base type and [sqlite_types] columns their
+// semantic type, unless a custom type mapping takes over the column's SQL
+// type.
+func (a *Analyzer) schemaColumnType(cat *model.Catalog, col *model.Column) columnTypeInfo {
+	info := columnTypeInfo{nullable: !col.NotNull}
+	_, custom := a.CustomTypes[normalizeSQLiteType(col.Type)]
+	if !custom {
+		info.enum = cat.ColumnEnum(col)
+		if info.enum == nil {
+			info.domain = cat.ColumnDomain(col)
+		}
+	}
+	switch {
+	case info.enum != nil:
+		info.goType = "string"
+	case info.domain != nil:
+		info.goType = a.SQLiteTypeToGo(info.domain.BaseType)
+		info.nullable = info.nullable && !info.domain.NotNull()
+	default:
+		if goType, storage, ok := SQLiteSemanticType(col, a.sqliteTypes); ok && !custom {
+			info.goType = goType
+			info.storage = storage
+			break
+		}
+		info.goType = a.SQLiteTypeToGo(col.Type)
 	}
+	return info
 }
 
 // lookupColumnOverrideFull checks for a column-specific type override with full type info.
@@ -1589,9 +1789,9 @@ func (a *Analyzer) lookupColumnOverrideFull(tableName, columnName string) (colum
 
 	// Try fully qualified name: table.column
 	qualifiedKey := strings.ToLower(tableName + "." + columnName)
-	if override, ok := a.ColumnOverrides[qualifiedKey]; ok {
+	if override, ok := a.ColumnOverrides[qualifiedKey]; ok && override.GoType.Type != "" {
 		return columnTypeInfo{
-			goType:      formatGoTypeWithPointer(override.GoType),
+			goType:      overrideGoType(override),
 			importPath:  override.GoType.Import,
 			packageName: override.GoType.Package,
 		}, true
@@ -1599,9 +1799,9 @@ func (a *Analyzer) lookupColumnOverrideFull(tableName, columnName string) (colum
 
 	// Try unqualified column name (for convenience)
 	unqualifiedKey := strings.ToLower(columnName)
-	if override, ok := a.ColumnOverrides[unqualifiedKey]; ok {
+	if override, ok := a.ColumnOverrides[unqualifiedKey]; ok && override.GoType.Type != "" {
 		return columnTypeInfo{
-			goType:      formatGoTypeWithPointer(override.GoType),
+			goType:      overrideGoType(override),
 			importPath:  override.GoType.Import,
 			packageName: override.GoType.Package,
 		}, true
@@ -1610,6 +1810,25 @@ func (a *Analyzer) lookupColumnOverrideFull(tableName, columnName string) (colum
 	return columnTypeInfo{}, false
 }
 
+// columnNullStyle returns the null_style a column override sets for the
+// column, or an empty style.
+func (a *Analyzer) columnNullStyle(tableName, columnName string) config.NullStyle {
+	if override, ok := a.ColumnOverrides[strings.ToLower(tableName+"."+columnName)]; ok && override.NullStyle != "" {
+		return override.NullStyle
+	}
+	return a.ColumnOverrides[strings.ToLower(columnName)].NullStyle
+}
+
+// overrideGoType returns the Go type for an override, wrapping JSON overrides
+// in the generated JSON type that decodes the column.
+func overrideGoType(override config.ColumnOverride) string {
+	goType := formatGoTypeWithPointer(override.GoType)
+	if override.JSON {
+		return "JSON[" + goType + "]"
+	}
+	return goType
+}
+
 // formatGoTypeWithPointer formats a Go type, adding pointer prefix if needed.
 func formatGoTypeWithPointer(details config.GoTypeDetails) string {
 	goType := details.Type
@@ -1632,14 +1851,16 @@ func shouldSkipIdentifier(isQualified bool, table, upperName, name string, scope
 		if strings.ToUpper(table) == "SQLC" {
 			return true
 		}
-		// Skip "excluded" pseudo-table in ON CONFLICT clauses (PostgreSQL/SQLite UPSERT)
+		// Skip the "excluded" pseudo-table unless an ON CONFLICT clause
+		// brought it into scope
 		if strings.ToUpper(table) == "EXCLUDED" {
-			return true
+			_, ok := scope.get(table)
+			return !ok
 		}
 		return false
 	}
-	// Skip common SQL functions and special identifiers for bare names
-	if isCommonFunction(upperName) || upperName == "SQLC" {
+	// Skip special identifiers for bare names
+	if upperName == "SQLC" {
 		return true
 	}
 
@@ -1694,6 +1915,11 @@ func (a *Analyzer) validateIdentifiers(tokens []tokenizer.Token, scope *querySco
 			}
 		}
 
+		// Function names are checked by validateFunctionCalls.
+		if !isQualified && i+1 < len(tokens) && isSymbol(tokens[i+1], "(") {
+			continue
+		}
+
 		if shouldSkipIdentifier(isQualified, table, upperName, name, scope, queryAliases) {
 			continue
 		}
@@ -1739,15 +1965,6 @@ func (a *Analyzer) validateIdentifiers(tokens []tokenizer.Token, scope *querySco
 	return diags
 }
 
-func isCommonFunction(name string) bool {
-	switch name {
-	case "COUNT", "SUM", "MIN", "MAX", "AVG", "COALESCE", "IFNULL", "NULLIF", "ABS", "LOWER", "UPPER", "TRIM", "LENGTH", "RANDOM", "ROUND", "REPLACE", "SUBSTR", "DATE", "TIME", "DATETIME", "STRFTIME", "UNIXEPOCH", "CAST", "EXISTS":
-		return true
-	default:
-		return false
-	}
-}
-
 func discoverReferencedRelations(tokens []tokenizer.Token) []string {
 	var referenced []string
 	for i := 0; i < len(tokens); {
@@ -1813,6 +2030,11 @@ func parseRelationName(tokens []tokenizer.Token, idx *int) (string, bool) {
 	for i < len(tokens) && tokens[i].Kind == tokenizer.KindDocComment {
 		i++
 	}
+	// Look through the parentheses of a nested join: JOIN (posts p JOIN ...).
+	for i+1 < len(tokens) && isSymbol(tokens[i], "(") &&
+		!isWord(tokens[i+1], "SELECT") && !isWord(tokens[i+1], "WITH") && !isWord(tokens[i+1], "VALUES") {
+		i++
+	}
 	if i >= len(tokens) {
 		*idx = i
 		return "", false
@@ -2060,6 +2282,25 @@ func (a *Analyzer) inferParamTypes(q parser.Query, scope *queryScope, baseScope
 			continue
 		}
 
+		if isRowCountParam(tokens, tokenIdx) {
+			infos[paramIdx] = paramInfo{GoType: "int64"}
+			continue
+		}
+
+		// Operands of || are strings; a concatenated LIKE pattern takes the
+		// nullability of the column it matches.
+		if table, column, concat := matchConcatOperand(tokens, tokenIdx); concat {
+			info := paramInfo{GoType: "string"}
+			if column != "" {
+				if target, found := a.lookupParamColumn(cat, scope, baseScope, table, column); found {
+					info.Nullable = target.Nullable
+					info.NullStyle = target.NullStyle
+				}
+			}
+			infos[paramIdx] = info
+			continue
+		}
+
 		isSlice := q.Params[paramIdx].IsVariadic
 
 		var table, column string
@@ -2070,7 +2311,11 @@ func (a *Analyzer) inferParamTypes(q parser.Query, scope *queryScope, baseScope
 		}
 
 		if !ok {
-			table, column, ok = matchEqualityReference(tokens, tokenIdx)
+			table, column, ok = matchComparisonReference(tokens, tokenIdx)
+		}
+
+		if !ok {
+			table, column, ok = matchBetweenReference(tokens, tokenIdx)
 		}
 
 		// Try to match arithmetic expressions like SET col = col + ?
@@ -2082,67 +2327,19 @@ func (a *Analyzer) inferParamTypes(q parser.Query, scope *queryScope, baseScope
 			continue
 		}
 
-		var typeName string
-		var nullable bool
-		var importPath string
-		var packageName string
-		found := false
-
-		if scope != nil {
-			if resolved, _, status := scope.lookup(table, column); status == scopeLookupOK && resolved.goType != "any" {
-				typeName = resolved.goType
-				nullable = resolved.nullable
-				importPath = resolved.importPath
-				packageName = resolved.packageName
-				found = true
-			}
-		}
-
-		if !found && baseScope != nil {
-			if resolved, _, status := baseScope.lookup(table, column); status == scopeLookupOK && resolved.goType != "any" {
-				typeName = resolved.goType
-				nullable = resolved.nullable
-				importPath = resolved.importPath
-				packageName = resolved.packageName
-				found = true
-			} else if (status == scopeLookupAliasNotFound || status == scopeLookupAmbiguous) && column != "" {
-				// Final fallback: try global lookup in baseScope if alias not found or ambiguous
-				if fallback, _, fbStatus := baseScope.lookup("", column); fbStatus == scopeLookupOK && fallback.goType != "any" {
-					typeName = fallback.goType
-					nullable = fallback.nullable
-					importPath = fallback.importPath
-					packageName = fallback.packageName
-					found = true
-				}
-			}
-		}
-
+		info, found := a.lookupParamColumn(cat, scope, baseScope, table, column)
 		if !found {
-			if info, schemaFound := a.schemaInfoForColumn(cat, table, column); schemaFound {
-				typeName = info.GoType
-				nullable = info.Nullable
-				importPath = info.Import
-				packageName = info.Package
-				found = true
-			}
+			continue
 		}
-
-		if found {
-			if isSlice {
-				// For slices, we want the base type wrapped in a slice, and typically not nullable elements
-				// unless the column is nullable. But usually input slices are []Type.
-				// If the column is nullable, sqlc usually generates []Type (and expects no nulls or handles them).
-				// We'll stick to non-pointer slice elements for now as that's typical for IN clauses.
-				typeName = "[]" + typeName
-				nullable = false // Slices themselves aren't nullable in this context usually
-			}
-			infos[paramIdx] = paramInfo{
-				GoType:   typeName,
-				Nullable: nullable,
-				Import:   importPath,
-				Package:  packageName,
-			}
+		if isSlice {
+			// For slices, we want the base type wrapped in a slice, and typically not nullable elements
+			// unless the column is nullable. But usually input slices are []Type.
+			// If the column is nullable, sqlc usually generates []Type (and expects no nulls or handles them).
+			// We'll stick to non-pointer slice elements for now as that's typical for IN clauses.
+			info.GoType = "[]" + info.GoType
+			info.Nullable = false // Slices themselves aren't nullable in this context usually
 		}
+		infos[paramIdx] = info
 	}
 
 	if q.Verb == parser.VerbInsert {
@@ -2155,6 +2352,42 @@ func (a *Analyzer) inferParamTypes(q parser.Query, scope *queryScope, baseScope
 	return infos
 }
 
+// lookupParamColumn resolves the type of the column a parameter is compared
+// with, trying the query scope first, then the base scope and finally the schema.
+func (a *Analyzer) lookupParamColumn(cat *model.Catalog, scope, baseScope *queryScope, table, column string) (paramInfo, bool) {
+	fromScope := func(resolved scopeColumn) paramInfo {
+		return paramInfo{
+			GoType:    resolved.goType,
+			Nullable:  resolved.nullable,
+			Import:    resolved.importPath,
+			Package:   resolved.packageName,
+			Enum:      resolved.enum,
+			Domain:    resolved.domain,
+			NullStyle: resolved.nullStyle,
+			Storage:   resolved.storage,
+		}
+	}
+
+	if scope != nil {
+		if resolved, _, status := scope.lookup(table, column); status == scopeLookupOK && resolved.goType != "any" {
+			return fromScope(resolved), true
+		}
+	}
+
+	if baseScope != nil {
+		if resolved, _, status := baseScope.lookup(table, column); status == scopeLookupOK && resolved.goType != "any" {
+			return fromScope(resolved), true
+		} else if (status == scopeLookupAliasNotFound || status == scopeLookupAmbiguous) && column != "" {
+			// Final fallback: try global lookup in baseScope if alias not found or ambiguous
+			if fallback, _, fbStatus := baseScope.lookup("", column); fbStatus == scopeLookupOK && fallback.goType != "any" {
+				return fromScope(fallback), true
+			}
+		}
+	}
+
+	return a.schemaInfoForColumn(cat, table, column)
+}
+
 func (a *Analyzer) schemaInfoForColumn(cat *model.Catalog, tableName, columnName string) (paramInfo, bool) {
 	table := lookupTable(cat, tableName)
 	if table == nil {
@@ -2168,16 +2401,22 @@ func (a *Analyzer) schemaInfoForColumn(cat *model.Catalog, tableName, columnName
 	// Check for column override first to get custom type info
 	if info, ok := a.lookupColumnOverrideFull(tableName, columnName); ok {
 		return paramInfo{
-			GoType:   info.goType,
-			Nullable: !column.NotNull,
-			Import:   info.importPath,
-			Package:  info.packageName,
+			GoType:    info.goType,
+			Nullable:  !column.NotNull,
+			Import:    info.importPath,
+			Package:   info.packageName,
+			NullStyle: a.columnNullStyle(tableName, columnName),
 		}, true
 	}
 
+	info := a.schemaColumnType(cat, column)
 	return paramInfo{
-		GoType:   a.SQLiteTypeToGo(column.Type),
-		Nullable: !column.NotNull,
+		GoType:    info.goType,
+		Nullable:  info.nullable,
+		Enum:      info.enum,
+		Domain:    info.domain,
+		NullStyle: a.columnNullStyle(tableName, columnName),
+		Storage:   info.storage,
 	}, true
 }
 
@@ -2207,17 +2446,94 @@ func matchInReference(tokens []tokenizer.Token, paramIdx int) (string, string, b
 	return parseColumnReferenceBackward(tokens, idx)
 }
 
-func matchEqualityReference(tokens []tokenizer.Token, paramIdx int) (string, string, bool) {
-	if table, column, ok := equalityLeftReference(tokens, paramIdx); ok {
+// isRowCountParam reports whether the parameter at idx is a LIMIT or OFFSET operand,
+// including the offset in MySQL's "LIMIT offset, count" form.
+func isRowCountParam(tokens []tokenizer.Token, idx int) bool {
+	if idx == 0 {
+		return false
+	}
+	prev := tokens[idx-1]
+	if prev.Kind == tokenizer.KindIdentifier || prev.Kind == tokenizer.KindKeyword {
+		word := strings.ToUpper(prev.Text)
+		return word == "LIMIT" || word == "OFFSET"
+	}
+	if prev.Kind != tokenizer.KindSymbol || prev.Text != "," || idx < 3 {
+		return false
+	}
+	limit := tokens[idx-3]
+	return (limit.Kind == tokenizer.KindIdentifier || limit.Kind == tokenizer.KindKeyword) && strings.EqualFold(limit.Text, "LIMIT")
+}
+
+// matchConcatOperand reports whether the parameter at paramIdx is an operand of
+// the || operator. When the concatenation is the pattern of a LIKE or GLOB, it
+// also returns the matched column, as in: col LIKE '%' || ? || '%'.
+func matchConcatOperand(tokens []tokenizer.Token, paramIdx int) (string, string, bool) {
+	end := paramIdx
+	if end+1 < len(tokens) {
+		next := tokens[end+1]
+		if (tokens[end].Text == "?" && next.Kind == tokenizer.KindNumber) || (tokens[end].Text == ":" && next.Kind == tokenizer.KindIdentifier) {
+			end++
+		}
+	}
+	if !isConcatOperator(tokens, paramIdx-2) && !isConcatOperator(tokens, end+1) {
+		return "", "", false
+	}
+
+	// Walk back over the operands in front of the parameter to the start of
+	// the concatenation.
+	start := paramIdx
+	for start >= 3 && isConcatOperator(tokens, start-2) {
+		start -= 3
+		if start > 0 {
+			prev := tokens[start-1]
+			switch {
+			case prev.Kind == tokenizer.KindSymbol && (prev.Text == "?" || prev.Text == ":"):
+				start--
+			case prev.Kind == tokenizer.KindSymbol && prev.Text == "." && start >= tableColumnLookupOffset:
+				start -= tableColumnLookupOffset
+			}
+		}
+	}
+
+	opIdx := start - 1
+	if opIdx < 1 || !isIdentifierToken(tokens[opIdx]) {
+		return "", "", true
+	}
+	switch strings.ToUpper(tokens[opIdx].Text) {
+	case "LIKE", "GLOB":
+	default:
+		return "", "", true
+	}
+	colIdx := opIdx - 1
+	if tokens[colIdx].Kind == tokenizer.KindKeyword && strings.EqualFold(tokens[colIdx].Text, "NOT") {
+		colIdx--
+	}
+	table, column, _ := parseColumnReferenceBackward(tokens, colIdx)
+	return table, column, true
+}
+
+// isConcatOperator reports whether the tokens at idx and idx+1 form ||.
+func isConcatOperator(tokens []tokenizer.Token, idx int) bool {
+	if idx < 0 || idx+1 >= len(tokens) {
+		return false
+	}
+	first, second := tokens[idx], tokens[idx+1]
+	return first.Kind == tokenizer.KindSymbol && first.Text == "|" &&
+		second.Kind == tokenizer.KindSymbol && second.Text == "|" &&
+		first.Line == second.Line && first.Column+1 == second.Column
+}
+
+func matchComparisonReference(tokens []tokenizer.Token, paramIdx int) (string, string, bool) {
+	if table, column, ok := comparisonLeftReference(tokens, paramIdx); ok {
 		return table, column, true
 	}
-	if table, column, ok := equalityRightReference(tokens, paramIdx); ok {
+	if table, column, ok := comparisonRightReference(tokens, paramIdx); ok {
 		return table, column, true
 	}
 	return "", "", false
 }
 
-func equalityLeftReference(tokens []tokenizer.Token, paramIdx int) (string, string, bool) {
+func comparisonLeftReference(tokens []tokenizer.Token, paramIdx int) (string, string, bool) {
 	if paramIdx == 0 {
 		return "", "", false
 	}
@@ -2225,14 +2541,13 @@ func equalityLeftReference(tokens []tokenizer.Token, paramIdx int) (string, stri
 	if eqIdx < 0 || eqIdx >= len(tokens) {
 		return "", "", false
 	}
-	eqTok := tokens[eqIdx]
-	if eqTok.Kind != tokenizer.KindSymbol || eqTok.Text != "=" {
+	if !isComparisonOperator(tokens[eqIdx]) {
 		return "", "", false
 	}
 	return parseColumnReferenceBackward(tokens, eqIdx-1)
 }
 
-func equalityRightReference(tokens []tokenizer.Token, paramIdx int) (string, string, bool) {
+func comparisonRightReference(tokens []tokenizer.Token, paramIdx int) (string, string, bool) {
 	nextIdx := paramIdx + 1
 	if nextIdx >= len(tokens) {
 		return "", "", false
@@ -2255,13 +2570,54 @@ func equalityRightReference(tokens []tokenizer.Token, paramIdx int) (string, str
 	if nextIdx >= len(tokens) {
 		return "", "", false
 	}
-	eqTok := tokens[nextIdx]
-	if eqTok.Kind != tokenizer.KindSymbol || eqTok.Text != "=" {
+	if !isComparisonOperator(tokens[nextIdx]) {
 		return "", "", false
 	}
 	return parseColumnReferenceForward(tokens, nextIdx+1)
 }
 
+// isComparisonOperator reports whether tok compares its operands, so that a
+// parameter on one side takes the type of the column on the other.
+func isComparisonOperator(tok tokenizer.Token) bool {
+	if tok.Kind != tokenizer.KindSymbol {
+		return false
+	}
+	switch tok.Text {
+	case "=", "==", "!=", "<>", "<", "<=", ">", ">=":
+		return true
+	}
+	return false
+}
+
+// matchBetweenReference matches parameters bounding a BETWEEN range, as in
+// col BETWEEN ? AND ?, and returns the column being tested.
+func matchBetweenReference(tokens []tokenizer.Token, paramIdx int) (string, string, bool) {
+	betweenIdx := paramIdx - 1
+	if betweenIdx >= 0 && isKeyword(tokens[betweenIdx], "AND") {
+		// Upper bound: skip back over the lower bound to BETWEEN.
+		lower := betweenIdx - 1
+		if lower >= 1 {
+			prev := tokens[lower-1]
+			if (tokens[lower].Kind == tokenizer.KindNumber && prev.Text == "?") || (tokens[lower].Kind == tokenizer.KindIdentifier && prev.Text == ":") {
+				lower--
+			}
+		}
+		betweenIdx = lower - 1
+	}
+	if betweenIdx < 1 || !isKeyword(tokens[betweenIdx], "BETWEEN") {
+		return "", "", false
+	}
+	colIdx := betweenIdx - 1
+	if isKeyword(tokens[colIdx], "NOT") {
+		colIdx--
+	}
+	return parseColumnReferenceBackward(tokens, colIdx)
+}
+
+func isKeyword(tok tokenizer.Token, word string) bool {
+	return isIdentifierToken(tok) && strings.EqualFold(tok.Text, word)
+}
+
 func parseColumnReferenceBackward(tokens []tokenizer.Token, idx int) (string, string, bool) {
 	if idx < 0 || idx >= len(tokens) {
 		return "", "", false
@@ -2400,10 +2756,14 @@ func (a *Analyzer) inferInsertParams(cat *model.Catalog, tokens []tokenizer.Toke
 		if schemaCol == nil {
 			continue
 		}
-		goType := a.SQLiteTypeToGo(schemaCol.Type)
+		info := a.schemaColumnType(cat, schemaCol)
 		infos[paramIdx] = paramInfo{
-			GoType:   goType,
-			Nullable: !schemaCol.NotNull,
+			GoType:    info.goType,
+			Nullable:  info.nullable,
+			Enum:      info.enum,
+			Domain:    info.domain,
+			NullStyle: a.columnNullStyle(tableName, schemaCol.Name),
+			Storage:   info.storage,
 		}
 	}
 }
@@ -2570,6 +2930,27 @@ func (a *Analyzer) SetTypeResolver(resolver TypeResolver) {
 	a.typeResolver = resolver
 }
 
+// SetFunctions sets the built-in function catalog used to type and validate calls.
+func (a *Analyzer) SetFunctions(functions *engine.FunctionCatalog) {
+	a.functions = functions
+}
+
+// AddFunctions registers user-declared functions on top of the current
+// catalog, replacing built-ins of the same name.
+func (a *Analyzer) AddFunctions(fns ...engine.Function) {
+	if len(fns) == 0 {
+		return
+	}
+	a.functions = a.functionCatalog().With(fns...)
+}
+
+func (a *Analyzer) functionCatalog() *engine.FunctionCatalog {
+	if a.functions != nil {
+		return a.functions
+	}
+	return defaultFunctions
+}
+
 // SQLiteTypeToGo is a convenience function that uses default type mapping
 // (kept for backward compatibility where no custom types are needed)
 func SQLiteTypeToGo(sqliteType string) string {
@@ -2,10 +2,14 @@
 package analyzer_test
 
 import (
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/electwix/db-catalyst/internal/config"
+	"github.com/electwix/db-catalyst/internal/engine"
+	"github.com/electwix/db-catalyst/internal/engine/mysql"
+	"github.com/electwix/db-catalyst/internal/engine/postgres"
 	"github.com/electwix/db-catalyst/internal/query/analyzer"
 	"github.com/electwix/db-catalyst/internal/query/block"
 	"github.com/electwix/db-catalyst/internal/query/parser"
@@ -40,8 +44,9 @@ WHERE users.id = :id AND users.email = :email;`,
 					t.Errorf("unexpected first column %+v", first)
 				}
 
+				// The WHERE equality rejects NULL emails.
 				second := res.Columns[1]
-				if second.Name != "email" || second.GoType != "string" || !second.Nullable {
+				if second.Name != "email" || second.GoType != "string" || second.Nullable {
 					t.Errorf("unexpected second column %+v", second)
 				}
 
@@ -1003,6 +1008,46 @@ func TestAnalyzer_ColumnOverride(t *testing.T) {
 		}
 	})
 
+	t.Run("json column override", func(t *testing.T) {
+		blk := block.Block{
+			Path:   "query/test.sql",
+			Line:   1,
+			Column: 1,
+			SQL:    "SELECT users.email FROM users;",
+		}
+		q, diags := parser.Parse(blk)
+		if len(diags) != 0 {
+			t.Fatalf("unexpected parser diagnostics: %+v", diags)
+		}
+
+		an := analyzer.New(catalog)
+		an.SetColumnOverrides(map[string]config.ColumnOverride{
+			"users.email": {
+				Column: "users.email",
+				GoType: config.GoTypeDetails{
+					Import:  "github.com/example/types",
+					Package: "types",
+					Type:    "types.Settings",
+				},
+				JSON: true,
+			},
+		})
+		res := an.Analyze(q)
+
+		if len(res.Diagnostics) != 0 {
+			t.Fatalf("unexpected diagnostics: %+v", res.Diagnostics)
+		}
+		if len(res.Columns) != 1 {
+			t.Fatalf("expected 1 column, got %d", len(res.Columns))
+		}
+		if res.Columns[0].GoType != "JSON[types.Settings]" {
+			t.Errorf("expected GoType JSON[types.Settings], got %q", res.Columns[0].GoType)
+		}
+		if res.Columns[0].Import != "github.com/example/types" {
+			t.Errorf("expected Import github.com/example/types, got %q", res.Columns[0].Import)
+		}
+	})
+
 	t.Run("pointer column override", func(t *testing.T) {
 		blk := block.Block{
 			Path:   "query/test.sql",
@@ -1128,6 +1173,7 @@ func buildTestCatalog() *model.Catalog {
 					{Name: "status", Type: "NUMERIC", NotNull: false},
 					{Name: "credits", Type: "INTEGER", NotNull: false},
 				},
+				PrimaryKey: &model.PrimaryKey{Columns: []string{"id"}},
 			},
 			"posts": {
 				Name: "posts",
@@ -1141,6 +1187,470 @@ func buildTestCatalog() *model.Catalog {
 	}
 }
 
+func TestOuterJoinNullability(t *testing.T) {
+	catalog := buildTestCatalog()
+
+	tests := []struct {
+		name     string
+		sql      string
+		wantNull []bool
+	}{
+		{"left join", "SELECT u.id, p.id AS post_id, p.user_id FROM users u LEFT JOIN posts p ON p.user_id = u.id", []bool{false, true, true}},
+		{"left outer join unqualified", "SELECT u.id, user_id FROM users u LEFT OUTER JOIN posts p ON p.user_id = u.id", []bool{false, true}},
+		{"left join without aliases", "SELECT users.id, posts.user_id FROM users LEFT JOIN posts ON posts.user_id = users.id", []bool{false, true}},
+		{"right join", "SELECT u.id, p.id AS post_id FROM users u RIGHT JOIN posts p ON p.user_id = u.id", []bool{true, false}},
+		{"full outer join", "SELECT u.id, p.id AS post_id FROM users u FULL OUTER JOIN posts p ON p.user_id = u.id", []bool{true, true}},
+		{"inner join", "SELECT u.id, p.id AS post_id FROM users u JOIN posts p ON p.user_id = u.id", []bool{false, false}},
+		{"right join after inner join", "SELECT u.id, p.id AS post_id, x.id AS other_id FROM users u JOIN posts p ON p.user_id = u.id RIGHT JOIN users x ON x.id = p.user_id", []bool{true, true, false}},
+		{"nested join", "SELECT u.id, p.id AS post_id, a.id AS author_id FROM users u LEFT JOIN (posts p JOIN users a ON a.id = p.user_id) ON p.user_id = u.id", []bool{false, true, true}},
+		{"join to cte", "WITH recent AS (SELECT id, user_id FROM posts) SELECT u.id, r.user_id FROM users u LEFT JOIN recent r ON r.user_id = u.id", []bool{false, true}},
+		{"left join inside cte", "WITH x AS (SELECT u.id, p.id AS post_id FROM users u LEFT JOIN posts p ON p.user_id = u.id) SELECT id, post_id FROM x", []bool{false, true}},
+		{"star from nullable side", "SELECT p.* FROM users u LEFT JOIN posts p ON p.user_id = u.id", []bool{true, true, true}},
+		{"self join", "SELECT u.id, m.id AS manager_id FROM users u LEFT JOIN users m ON m.id = u.credits", []bool{false, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blk := block.Block{Path: "query/test.sql", Line: 1, Column: 1, SQL: tt.sql}
+			q, diags := parser.Parse(blk)
+			if len(diags) != 0 {
+				t.Fatalf("unexpected parser diagnostics: %+v", diags)
+			}
+
+			res := analyzer.New(catalog).Analyze(q)
+			for _, d := range res.Diagnostics {
+				t.Errorf("unexpected diagnostic: %s", d.Message)
+			}
+			if len(res.Columns) != len(tt.wantNull) {
+				t.Fatalf("expected %d columns, got %d", len(tt.wantNull), len(res.Columns))
+			}
+			for i, col := range res.Columns {
+				if col.Nullable != tt.wantNull[i] {
+					t.Errorf("column %s nullable = %v, want %v", col.Name, col.Nullable, tt.wantNull[i])
+				}
+			}
+		})
+	}
+}
+
+func TestOuterJoinParamsKeepSchemaNullability(t *testing.T) {
+	sql := "SELECT u.id FROM users u LEFT JOIN posts p ON p.user_id = u.id WHERE p.user_id = ?"
+	blk := block.Block{Path: "query/test.sql", Line: 1, Column: 1, SQL: sql}
+	q, diags := parser.Parse(blk)
+	if len(diags) != 0 {
+		t.Fatalf("unexpected parser diagnostics: %+v", diags)
+	}
+
+	res := analyzer.New(buildTestCatalog()).Analyze(q)
+	if len(res.Params) != 1 {
+		t.Fatalf("expected 1 param, got %d", len(res.Params))
+	}
+	if p := res.Params[0]; p.GoType != "int64" || p.Nullable {
+		t.Errorf("param = %s (nullable %v), want int64 (nullable false)", p.GoType, p.Nullable)
+	}
+}
+
+func TestNullabilityNarrowing(t *testing.T) {
+	catalog := buildTestCatalog()
+	posts := catalog.Tables["posts"]
+	posts.Columns[1].References = &model.ForeignKeyRef{Table: "users", Columns: []string{"id"}}
+
+	tests := []struct {
+		name     string
+		sql      string
+		nonNull  []string
+		wantNull []bool
+	}{
+		{"is not null", "SELECT id, email FROM users WHERE email IS NOT NULL", nil, []bool{false, false}},
+		{"comparison", "SELECT email, credits FROM users WHERE credits > 10", nil, []bool{true, false}},
+		{"like and in", "SELECT email, status FROM users WHERE email LIKE '%@x' AND status IN (1, 2)", nil, []bool{false, false}},
+		{"or does not narrow", "SELECT email FROM users WHERE email IS NOT NULL OR id = 1", nil, []bool{true}},
+		{"is null does not narrow", "SELECT email FROM users WHERE email IS NULL", nil, []bool{true}},
+		{"parenthesized conjunct", "SELECT email FROM users WHERE (email IS NOT NULL AND id > 1)", nil, []bool{false}},
+		{"inner join equality", "SELECT u.credits, p.title FROM users u JOIN posts p ON p.title = u.email AND u.credits = p.id", nil, []bool{false, false}},
+		{"left join condition does not narrow", "SELECT u.email, p.title FROM users u LEFT JOIN posts p ON p.title = u.email", nil, []bool{true, true}},
+		{"where narrows outer joined column", "SELECT p.id AS post_id, p.title FROM users u LEFT JOIN posts p ON p.user_id = u.id WHERE p.title IS NOT NULL", nil, []bool{true, false}},
+		{"coalesce", "SELECT COALESCE(email, '') AS email FROM users", nil, []bool{false}},
+		{"left join through foreign key", "SELECT p.title, u.id AS author_id FROM posts p LEFT JOIN users u ON u.id = p.user_id", nil, []bool{true, true}},
+		{"left join through filtered foreign key", "SELECT u.id AS author_id, u.email FROM posts p LEFT JOIN users u ON u.id = p.user_id AND u.credits > 0", nil, []bool{true, true}},
+		{"inner join through foreign key", "SELECT p.title, u.email FROM posts p JOIN users u ON u.email = p.title", nil, []bool{false, false}},
+		{"nonnull annotation", "SELECT email, credits FROM users", []string{"EMAIL"}, []bool{false, true}},
+		{"nonnull outer join", "SELECT u.id, p.title FROM users u LEFT JOIN posts p ON p.user_id = u.id", []string{"title"}, []bool{false, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blk := block.Block{Path: "query/test.sql", Line: 1, Column: 1, SQL: tt.sql, NonNull: tt.nonNull}
+			q, diags := parser.Parse(blk)
+			if len(diags) != 0 {
+				t.Fatalf("unexpected parser diagnostics: %+v", diags)
+			}
+
+			res := analyzer.New(catalog).Analyze(q)
+			for _, d := range res.Diagnostics {
+				t.Errorf("unexpected diagnostic: %s", d.Message)
+			}
+			if len(res.Columns) != len(tt.wantNull) {
+				t.Fatalf("expected %d columns, got %d", len(tt.wantNull), len(res.Columns))
+			}
+			for i, col := range res.Columns {
+				if col.Nullable != tt.wantNull[i] {
+					t.Errorf("column %s nullable = %v, want %v", col.Name, col.Nullable, tt.wantNull[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNonNullAnnotationUnknownColumn(t *testing.T) {
+	blk := block.Block{Path: "query/test.sql", Line: 3, Column: 1, SQL: "SELECT email FROM users", NonNull: []string{"nickname"}}
+	q, diags := parser.Parse(blk)
+	if len(diags) != 0 {
+		t.Fatalf("unexpected parser diagnostics: %+v", diags)
+	}
+
+	res := analyzer.New(buildTestCatalog()).Analyze(q)
+	if len(res.Diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %+v", res.Diagnostics)
+	}
+	d := res.Diagnostics[0]
+	if d.Severity != analyzer.SeverityWarning || !strings.Contains(d.Message, `unknown result column "nickname"`) {
+		t.Errorf("unexpected diagnostic: %+v", d)
+	}
+	if !res.Columns[0].Nullable {
+		t.Errorf("expected email to stay nullable")
+	}
+}
+
+func TestCompoundSelect(t *testing.T) {
+	catalog := buildTestCatalog()
+
+	type col struct {
+		name     string
+		goType   string
+		nullable bool
+	}
+	tests := []struct {
+		name     string
+		sql      string
+		want     []col
+		wantDiag string
+	}{
+		{"names from first branch", "SELECT id AS uid FROM users UNION SELECT user_id FROM posts", []col{{"uid", "int64", false}}, ""},
+		{"nullability is ored", "SELECT id FROM users UNION ALL SELECT credits FROM users", []col{{"id", "int64", true}}, ""},
+		{"numeric widening", "SELECT id FROM users UNION SELECT 1.5", []col{{"id", "float64", false}}, ""},
+		{"null literal takes other type", "SELECT id, NULL AS note FROM users UNION SELECT id, title FROM posts", []col{{"id", "int64", false}, {"note", "string", true}}, ""},
+		{"branch aliases", "SELECT u.email FROM users u UNION SELECT u.title FROM posts u", []col{{"email", "string", true}}, ""},
+		{"branch predicates", "SELECT email FROM users WHERE email IS NOT NULL UNION SELECT title FROM posts WHERE title IS NOT NULL", []col{{"email", "string", false}}, ""},
+		{"without from", "SELECT 1 AS n UNION ALL SELECT 2 UNION ALL SELECT 3", []col{{"n", "int64", false}}, ""},
+		{"intersect and except", "SELECT id FROM users INTERSECT SELECT user_id FROM posts EXCEPT SELECT id FROM posts", []col{{"id", "int64", false}}, ""},
+		{"star branch", "SELECT id, user_id, title FROM posts UNION SELECT * FROM posts", []col{{"id", "int64", false}, {"user_id", "int64", false}, {"title", "string", true}}, ""},
+		{"column count mismatch", "SELECT id, email FROM users\nUNION\nSELECT id FROM posts", []col{{"id", "int64", false}, {"email", "string", true}}, "UNION branch 2 returns 1 columns; expected 2"},
+		{"type mismatch", "SELECT id FROM users UNION SELECT title FROM posts", []col{{"id", "any", true}}, "UNION branch 2 returns string for column id; expected int64"},
+		{"recursive cte null anchor", "WITH RECURSIVE t(id, parent) AS (SELECT id, NULL AS parent FROM users UNION ALL SELECT p.id, t.id FROM posts p JOIN t ON t.id = p.user_id) SELECT id, parent FROM t", []col{{"id", "int64", false}, {"parent", "int64", true}}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blk := block.Block{Path: "query/test.sql", Line: 1, Column: 1, SQL: tt.sql}
+			q, diags := parser.Parse(blk)
+			if len(diags) != 0 {
+				t.Fatalf("unexpected parser diagnostics: %+v", diags)
+			}
+
+			res := analyzer.New(catalog).Analyze(q)
+			if tt.wantDiag == "" {
+				for _, d := range res.Diagnostics {
+					t.Errorf("unexpected diagnostic: %s", d.Message)
+				}
+			} else if len(res.Diagnostics) != 1 || !strings.Contains(res.Diagnostics[0].Message, tt.wantDiag) {
+				t.Errorf("expected diagnostic %q, got %+v", tt.wantDiag, res.Diagnostics)
+			}
+			if len(res.Columns) != len(tt.want) {
+				t.Fatalf("expected %d columns, got %+v", len(tt.want), res.Columns)
+			}
+			for i, want := range tt.want {
+				got := res.Columns[i]
+				if got.Name != want.name || got.GoType != want.goType || got.Nullable != want.nullable {
+					t.Errorf("column %d = %s %s (nullable %v), want %s %s (nullable %v)", i, got.Name, got.GoType, got.Nullable, want.name, want.goType, want.nullable)
+				}
+			}
+		})
+	}
+}
+
+func TestCompoundSelectMismatchPosition(t *testing.T) {
+	blk := block.Block{Path: "query/test.sql", Line: 10, Column: 1, SQL: "SELECT id, email FROM users\nUNION ALL\n  SELECT id FROM posts"}
+	q, _ := parser.Parse(blk)
+	res := analyzer.New(buildTestCatalog()).Analyze(q)
+	if len(res.Diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %+v", res.Diagnostics)
+	}
+	d := res.Diagnostics[0]
+	if d.Severity != analyzer.SeverityError || d.Line != 13 || d.Column != 3 {
+		t.Errorf("expected error at 13:3, got %+v", d)
+	}
+}
+
+func TestDerivedTables(t *testing.T) {
+	catalog := buildTestCatalog()
+
+	type col struct {
+		name     string
+		table    string
+		goType   string
+		nullable bool
+	}
+	tests := []struct {
+		name     string
+		sql      string
+		want     []col
+		wantDiag string
+	}{
+		{
+			"qualified columns",
+			"SELECT t.id, t.n FROM (SELECT user_id AS id, COUNT(*) AS n FROM posts GROUP BY user_id) AS t",
+			[]col{{"id", "t", "int64", false}, {"n", "t", "int64", false}},
+			"",
+		},
+		{
+			"star",
+			"SELECT * FROM (SELECT id, email FROM users) t",
+			[]col{{"id", "t", "int64", false}, {"email", "t", "string", true}},
+			"",
+		},
+		{
+			"qualified star in join",
+			"SELECT u.id, t.* FROM users u JOIN (SELECT user_id, title FROM posts) t ON t.user_id = u.id",
+			[]col{{"id", "u", "int64", false}, {"user_id", "t", "int64", false}, {"title", "t", "string", true}},
+			"",
+		},
+		{
+			"column alias list",
+			"SELECT t.a FROM (SELECT id FROM users) AS t(a)",
+			[]col{{"a", "t", "int64", false}},
+			"",
+		},
+		{
+			"left joined derived table",
+			"SELECT u.id, t.n FROM users u LEFT JOIN (SELECT user_id, COUNT(*) AS n FROM posts GROUP BY user_id) t ON t.user_id = u.id",
+			[]col{{"id", "u", "int64", false}, {"n", "t", "int64", true}},
+			"",
+		},
+		{
+			"nested derived tables",
+			"SELECT x.id FROM (SELECT t.id FROM (SELECT id FROM users) t) x",
+			[]col{{"id", "x", "int64", false}},
+			"",
+		},
+		{
+			"derived table over cte",
+			"WITH c AS (SELECT id FROM users) SELECT d.id FROM (SELECT id FROM c) d",
+			[]col{{"id", "d", "int64", false}},
+			"",
+		},
+		{
+			"lateral",
+			"SELECT u.id, l.title FROM users u CROSS JOIN LATERAL (SELECT p.title FROM posts p WHERE p.user_id = u.id LIMIT 1) l",
+			[]col{{"id", "u", "int64", false}, {"title", "l", "string", true}},
+			"",
+		},
+		{
+			"lateral star excludes outer relations",
+			"SELECT l.* FROM users u, LATERAL (SELECT * FROM posts p WHERE p.user_id = u.id) l",
+			[]col{{"id", "l", "int64", false}, {"user_id", "l", "int64", false}, {"title", "l", "string", true}},
+			"",
+		},
+		{
+			"lateral outer column",
+			"SELECT l.total FROM users u CROSS JOIN LATERAL (SELECT u.credits + 1 AS total) l",
+			[]col{{"total", "l", "int64", true}},
+			"",
+		},
+		{
+			"unknown derived column",
+			"SELECT t.email FROM (SELECT id FROM users) t",
+			[]col{{"email", "t", "any", true}},
+			`unknown column "email"`,
+		},
+		{
+			"column list mismatch",
+			"SELECT t.a FROM (SELECT id, email FROM users) AS t(a)",
+			[]col{{"a", "t", "any", true}},
+			"derived table t lists 1 columns but its SELECT returns 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blk := block.Block{Path: "query/test.sql", Line: 1, Column: 1, SQL: tt.sql}
+			q, diags := parser.Parse(blk)
+			if len(diags) != 0 {
+				t.Fatalf("unexpected parser diagnostics: %+v", diags)
+			}
+
+			res := analyzer.New(catalog).Analyze(q)
+			if tt.wantDiag == "" {
+				for _, d := range res.Diagnostics {
+					t.Errorf("unexpected diagnostic: %s", d.Message)
+				}
+			} else {
+				found := false
+				for _, d := range res.Diagnostics {
+					found = found || strings.Contains(d.Message, tt.wantDiag)
+				}
+				if !found {
+					t.Errorf("expected diagnostic %q, got %+v", tt.wantDiag, res.Diagnostics)
+				}
+			}
+			if len(res.Columns) != len(tt.want) {
+				t.Fatalf("expected %d columns, got %+v", len(tt.want), res.Columns)
+			}
+			for i, want := range tt.want {
+				got := res.Columns[i]
+				if got.Name != want.name || got.Table != want.table || got.GoType != want.goType || got.Nullable != want.nullable {
+					t.Errorf("column %d = %s.%s %s (nullable %v), want %s.%s %s (nullable %v)", i, got.Table, got.Name, got.GoType, got.Nullable, want.table, want.name, want.goType, want.nullable)
+				}
+			}
+		})
+	}
+}
+
+func TestWindowFunctions(t *testing.T) {
+	catalog := buildTestCatalog()
+
+	type col struct {
+		name     string
+		goType   string
+		nullable bool
+	}
+	tests := []struct {
+		name     string
+		sql      string
+		want     []col
+		wantDiag string
+	}{
+		{
+			"ranking functions",
+			"SELECT ROW_NUMBER() OVER (ORDER BY credits DESC) AS rn, RANK() OVER (PARTITION BY status ORDER BY credits) AS r, DENSE_RANK() OVER (ORDER BY credits) AS dr, NTILE(4) OVER (ORDER BY id) AS bucket FROM users",
+			[]col{{"rn", "int64", false}, {"r", "int64", false}, {"dr", "int64", false}, {"bucket", "int64", false}},
+			"",
+		},
+		{
+			"value functions",
+			"SELECT LAG(email) OVER (ORDER BY id) AS prev, LEAD(id, 1, 0) OVER (ORDER BY id) AS next_id, FIRST_VALUE(id) OVER (ORDER BY id) AS first_id, LAST_VALUE(email) OVER (ORDER BY id) AS last_email, NTH_VALUE(id, 2) OVER (ORDER BY id) AS second_id FROM users",
+			[]col{{"prev", "string", true}, {"next_id", "int64", true}, {"first_id", "int64", true}, {"last_email", "string", true}, {"second_id", "int64", true}},
+			"",
+		},
+		{
+			"aggregates over windows",
+			"SELECT id, SUM(credits) OVER (PARTITION BY status) AS total, COUNT(*) OVER () AS n, AVG(credits) OVER (ORDER BY id ROWS BETWEEN 2 PRECEDING AND CURRENT ROW) AS moving FROM users",
+			[]col{{"id", "int64", false}, {"total", "int64", true}, {"n", "int64", false}, {"moving", "float64", true}},
+			"",
+		},
+		{
+			"named window",
+			"SELECT id, RANK() OVER w AS r, LAG(id) OVER (w ROWS UNBOUNDED PRECEDING) AS prev FROM users WINDOW w AS (PARTITION BY status ORDER BY credits DESC)",
+			[]col{{"id", "int64", false}, {"r", "int64", false}, {"prev", "int64", true}},
+			"",
+		},
+		{
+			"ranked join",
+			"SELECT u.id, p.title, ROW_NUMBER() OVER (PARTITION BY u.id ORDER BY p.id) AS pos FROM users u JOIN posts p ON p.user_id = u.id",
+			[]col{{"id", "int64", false}, {"title", "string", true}, {"pos", "int64", false}},
+			"",
+		},
+		{
+			"unknown partition column",
+			"SELECT RANK() OVER (PARTITION BY team ORDER BY credits) AS r FROM users",
+			[]col{{"r", "int64", false}},
+			`unknown column "team" in window specification`,
+		},
+		{
+			"unknown column in named window",
+			"SELECT RANK() OVER w AS r FROM users WINDOW w AS (ORDER BY score)",
+			[]col{{"r", "int64", false}},
+			`unknown column "score" in window specification`,
+		},
+		{
+			"unknown window",
+			"SELECT RANK() OVER w AS r FROM users",
+			[]col{{"r", "int64", false}},
+			`unknown window "w"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blk := block.Block{Path: "query/test.sql", Line: 1, Column: 1, SQL: tt.sql}
+			q, diags := parser.Parse(blk)
+			if len(diags) != 0 {
+				t.Fatalf("unexpected parser diagnostics: %+v", diags)
+			}
+
+			res := analyzer.New(catalog).Analyze(q)
+			if tt.wantDiag == "" {
+				for _, d := range res.Diagnostics {
+					t.Errorf("unexpected diagnostic: %s", d.Message)
+				}
+			} else {
+				found := false
+				for _, d := range res.Diagnostics {
+					found = found || (d.Severity == analyzer.SeverityError && strings.Contains(d.Message, tt.wantDiag))
+				}
+				if !found {
+					t.Errorf("expected error %q, got %+v", tt.wantDiag, res.Diagnostics)
+				}
+			}
+			if len(res.Columns) != len(tt.want) {
+				t.Fatalf("expected %d columns, got %+v", len(tt.want), res.Columns)
+			}
+			for i, want := range tt.want {
+				got := res.Columns[i]
+				if got.Name != want.name || got.GoType != want.goType || got.Nullable != want.nullable {
+					t.Errorf("column %d = %s %s (nullable %v), want %s %s (nullable %v)", i, got.Name, got.GoType, got.Nullable, want.name, want.goType, want.nullable)
+				}
+			}
+		})
+	}
+}
+
+func TestWindowDiagnosticPosition(t *testing.T) {
+	src := `-- name: ListUsers :many
+SELECT id FROM users;
+
+-- name: RankUsers :many
+SELECT id,
+  RANK() OVER (PARTITION BY nosuch ORDER BY credits) AS r,
+  ROW_NUMBER() OVER missing AS n
+FROM users;
+`
+	blocks, err := block.Slice("query/test.sql", []byte(src))
+	if err != nil {
+		t.Fatalf("Slice() error = %v", err)
+	}
+	q, _ := parser.Parse(blocks[1])
+	res := analyzer.New(buildTestCatalog()).Analyze(q)
+
+	want := map[string][2]int{
+		`unknown column "nosuch" in window specification`: {6, 29},
+		`unknown window "missing"`:                        {7, 21},
+	}
+	for _, d := range res.Diagnostics {
+		pos, ok := want[d.Message]
+		if !ok {
+			continue
+		}
+		if d.Line != pos[0] || d.Column != pos[1] {
+			t.Errorf("%s at %d:%d, want %d:%d", d.Message, d.Line, d.Column, pos[0], pos[1])
+		}
+		delete(want, d.Message)
+	}
+	for msg := range want {
+		t.Errorf("expected diagnostic %q, got %+v", msg, res.Diagnostics)
+	}
+}
+
 func TestInferTypeFromExpr(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -1151,7 +1661,7 @@ func TestInferTypeFromExpr(t *testing.T) {
 	}{
 		{"string literal", "'hello'", "string", false, true},
 		{"integer literal", "42", "int64", false, true},
-		{"negative integer", "-10", "", false, false}, // Not supported: unary minus is two tokens
+		{"negative integer", "-10", "int64", false, true},
 		{"float literal", "3.14", "float64", false, true},
 		{"scientific notation", "1e10", "float64", false, true},
 		{"boolean true", "TRUE", "bool", false, true},
@@ -1164,6 +1674,19 @@ func TestInferTypeFromExpr(t *testing.T) {
 		{"unknown expression", "complex_func()", "", false, false},
 		{"empty string", "", "", false, false},
 		{"column reference", "users.id", "", false, false},
+		{"integer arithmetic", "1 + 2 * 3", "int64", false, true},
+		{"mixed arithmetic", "1 + 2.5", "float64", false, true},
+		{"modulo", "7 % 2", "int64", false, true},
+		{"concatenation", "'a' || 'b'", "string", false, true},
+		{"comparison", "1 < 2", "bool", false, true},
+		{"logical", "1 = 1 AND 2 <> 3", "bool", false, true},
+		{"case with else", "CASE WHEN 1 = 1 THEN 'x' ELSE 'y' END", "string", false, true},
+		{"case without else", "CASE WHEN 1 = 1 THEN 1 END", "int64", true, true},
+		{"coalesce", "COALESCE(NULL, 5)", "int64", false, true},
+		{"nullif", "NULLIF(1, 2)", "int64", true, true},
+		{"null arithmetic", "1 + NULL", "int64", true, true},
+		{"parenthesized", "(1 + 2)", "int64", false, true},
+		{"trailing garbage", "1 2", "", false, false},
 	}
 
 	for _, tt := range tests {
@@ -1268,3 +1791,970 @@ func TestCTELiteralColumnInference(t *testing.T) {
 		})
 	}
 }
+
+func TestExpressionResultColumns(t *testing.T) {
+	catalog := buildTestCatalog()
+
+	tests := []struct {
+		name     string
+		sql      string
+		wantType string
+		wantNull bool
+	}{
+		{"arithmetic on non-null column", "SELECT id * 2 AS doubled FROM users", "int64", false},
+		{"arithmetic on nullable column", "SELECT credits + 1 AS next FROM users", "int64", true},
+		{"float division", "SELECT id / 2.0 AS half FROM users", "float64", false},
+		{"concatenation", "SELECT 'user:' || id AS label FROM users", "string", false},
+		{"comparison", "SELECT id > 10 AS big FROM users", "bool", false},
+		{"nullable comparison", "SELECT credits > 10 AS rich FROM users", "bool", true},
+		{"is null", "SELECT email IS NULL AS missing FROM users", "bool", false},
+		{"case", "SELECT CASE WHEN credits > 0 THEN 'paid' ELSE 'free' END AS tier FROM users", "string", false},
+		{"coalesce", "SELECT COALESCE(email, 'n/a') AS contact FROM users", "string", false},
+		{"ifnull", "SELECT IFNULL(credits, 0) AS credits FROM users", "int64", false},
+		{"nullif", "SELECT NULLIF(id, 0) AS maybe_id FROM users", "int64", true},
+		{"qualified column arithmetic", "SELECT u.id + p.id AS total FROM users u JOIN posts p ON p.user_id = u.id", "int64", false},
+		{"scalar subquery count", "SELECT (SELECT COUNT(*) FROM posts p WHERE p.user_id = u.id) AS post_count FROM users u", "int64", false},
+		{"scalar subquery column", "SELECT (SELECT p.title FROM posts p WHERE p.user_id = u.id LIMIT 1) AS first_title FROM users u", "string", true},
+		{"scalar subquery max", "SELECT (SELECT MAX(p.id) FROM posts p WHERE p.user_id = u.id) AS last_post FROM users u", "int64", true},
+		{"max over no rows", "SELECT MAX(id) AS latest FROM posts", "int64", true},
+		{"min over no rows", "SELECT MIN(id) AS first FROM posts", "int64", true},
+		{"scalar min", "SELECT MIN(id, 10) AS capped FROM users", "int64", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blk := block.Block{Path: "query/test.sql", Line: 1, Column: 1, SQL: tt.sql}
+			q, diags := parser.Parse(blk)
+			if len(diags) != 0 {
+				t.Fatalf("unexpected parser diagnostics: %+v", diags)
+			}
+
+			res := analyzer.New(catalog).Analyze(q)
+			for _, d := range res.Diagnostics {
+				t.Errorf("unexpected diagnostic: %s", d.Message)
+			}
+			if len(res.Columns) != 1 {
+				t.Fatalf("expected 1 column, got %d", len(res.Columns))
+			}
+			col := res.Columns[0]
+			if col.GoType != tt.wantType {
+				t.Errorf("GoType = %q, want %q", col.GoType, tt.wantType)
+			}
+			if col.Nullable != tt.wantNull {
+				t.Errorf("Nullable = %v, want %v", col.Nullable, tt.wantNull)
+			}
+		})
+	}
+}
+
+func TestRowCountParams(t *testing.T) {
+	catalog := buildTestCatalog()
+
+	tests := []struct {
+		name string
+		sql  string
+	}{
+		{"limit offset", "SELECT id FROM users LIMIT ? OFFSET ?"},
+		{"mysql limit", "SELECT id FROM users LIMIT ?, ?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blk := block.Block{Path: "query/test.sql", Line: 1, Column: 1, SQL: tt.sql}
+			q, diags := parser.Parse(blk)
+			if len(diags) != 0 {
+				t.Fatalf("unexpected parser diagnostics: %+v", diags)
+			}
+
+			res := analyzer.New(catalog).Analyze(q)
+			if len(res.Params) != 2 {
+				t.Fatalf("expected 2 params, got %d", len(res.Params))
+			}
+			for _, p := range res.Params {
+				if p.GoType != "int64" || p.Nullable {
+					t.Errorf("param %s = %q (nullable %v), want non-null int64", p.Name, p.GoType, p.Nullable)
+				}
+			}
+		})
+	}
+}
+
+func TestConcatParams(t *testing.T) {
+	catalog := buildTestCatalog()
+
+	tests := []struct {
+		name     string
+		sql      string
+		wantNull bool
+	}{
+		{"like pattern", "SELECT id FROM users WHERE email LIKE '%' || ? || '%'", true},
+		{"not like numbered", "SELECT p.id FROM posts p WHERE p.title NOT LIKE ?1 || '%'", true},
+		{"like non-null column", "SELECT id FROM users WHERE id LIKE ? || '%'", false},
+		{"plain concat", "SELECT ? || email AS label FROM users", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blk := block.Block{Path: "query/test.sql", Line: 1, Column: 1, SQL: tt.sql}
+			q, diags := parser.Parse(blk)
+			if len(diags) != 0 {
+				t.Fatalf("unexpected parser diagnostics: %+v", diags)
+			}
+
+			res := analyzer.New(catalog).Analyze(q)
+			if len(res.Params) != 1 {
+				t.Fatalf("expected 1 param, got %d", len(res.Params))
+			}
+			p := res.Params[0]
+			if p.GoType != "string" || p.Nullable != tt.wantNull {
+				t.Errorf("param %s = %q (nullable %v), want string (nullable %v)", p.Name, p.GoType, p.Nullable, tt.wantNull)
+			}
+		})
+	}
+}
+
+func TestFunctionCatalogTyping(t *testing.T) {
+	catalog := buildTestCatalog()
+	pg, err := postgres.New(engine.Options{})
+	if err != nil {
+		t.Fatalf("postgres.New() error = %v", err)
+	}
+	my, err := mysql.New(engine.Options{})
+	if err != nil {
+		t.Fatalf("mysql.New() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		functions *engine.FunctionCatalog
+		sql       string
+		wantType  string
+		wantNull  bool
+	}{
+		{"sqlite strftime", nil, "SELECT strftime('%Y', 'now') AS year FROM users", "string", false},
+		{"sqlite unixepoch", nil, "SELECT unixepoch() AS ts FROM users", "int64", false},
+		{"sqlite printf", nil, "SELECT printf('%d-%s', id, email) AS label FROM users", "string", true},
+		{"sqlite length", nil, "SELECT length(email) AS n FROM users", "int64", true},
+		{"sqlite group_concat", nil, "SELECT group_concat(email, ',') AS emails FROM users", "string", true},
+		{"sqlite total", nil, "SELECT total(credits) AS sum_credits FROM users", "float64", false},
+		{"postgres now", pg.Functions(), "SELECT now() AS ts FROM users", "time.Time", false},
+		{"postgres date_trunc", pg.Functions(), "SELECT date_trunc('day', now()) AS day FROM users", "time.Time", false},
+		{"postgres gen_random_uuid", pg.Functions(), "SELECT gen_random_uuid() AS id FROM users", "uuid.UUID", false},
+		{"postgres array_agg", pg.Functions(), "SELECT array_agg(id) AS ids FROM users", "[]int64", true},
+		{"postgres jsonb_build_object", pg.Functions(), "SELECT jsonb_build_object('id', id) AS doc FROM users", "json.RawMessage", false},
+		{"postgres json_agg", pg.Functions(), "SELECT json_agg(email) AS docs FROM users", "json.RawMessage", true},
+		{"mysql json_object", my.Functions(), "SELECT json_object('id', id) AS doc FROM users", "json.RawMessage", false},
+		{"mysql json_arrayagg", my.Functions(), "SELECT json_arrayagg(email) AS docs FROM users", "json.RawMessage", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blk := block.Block{Path: "query/test.sql", Line: 1, Column: 1, SQL: tt.sql}
+			q, diags := parser.Parse(blk)
+			if len(diags) != 0 {
+				t.Fatalf("unexpected parser diagnostics: %+v", diags)
+			}
+
+			an := analyzer.New(catalog)
+			an.SetFunctions(tt.functions)
+			res := an.Analyze(q)
+			for _, d := range res.Diagnostics {
+				t.Errorf("unexpected diagnostic: %s", d.Message)
+			}
+			if len(res.Columns) != 1 {
+				t.Fatalf("expected 1 column, got %d", len(res.Columns))
+			}
+			if col := res.Columns[0]; col.GoType != tt.wantType || col.Nullable != tt.wantNull {
+				t.Errorf("column = %s (nullable %v), want %s (nullable %v)", col.GoType, col.Nullable, tt.wantType, tt.wantNull)
+			}
+		})
+	}
+}
+
+func TestDeclaredFunctions(t *testing.T) {
+	catalog := buildTestCatalog()
+	declared := []engine.Function{
+		{Name: "regexp", Args: []string{"TEXT", "TEXT"}, Returns: "bool", Null: engine.NullNever},
+		{Name: "haversine", Args: []string{"REAL", "REAL", "REAL", "REAL"}, ReturnsSQL: "REAL"},
+		{Name: "st_centroid", Args: []string{"BLOB"}, Returns: "geo.Point", Import: "github.com/acme/geo", Package: "geo"},
+	}
+
+	tests := []struct {
+		name       string
+		sql        string
+		wantType   string
+		wantNull   bool
+		wantImport string
+		wantDiag   string
+	}{
+		{name: "bool result", sql: "SELECT regexp('^a', email) AS matched FROM users", wantType: "bool"},
+		{name: "strict nullability", sql: "SELECT haversine(credits, credits, 0, 0) AS dist FROM users", wantType: "float64", wantNull: true},
+		{name: "qualified go type", sql: "SELECT st_centroid(id) AS center FROM users", wantType: "geo.Point", wantImport: "github.com/acme/geo"},
+		{name: "arity checked", sql: "SELECT id FROM users WHERE regexp(email) = 1", wantType: "int64", wantDiag: "function regexp called with 1 arguments; expected 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blk := block.Block{Path: "query/test.sql", Line: 1, Column: 1, SQL: tt.sql}
+			q, diags := parser.Parse(blk)
+			if len(diags) != 0 {
+				t.Fatalf("unexpected parser diagnostics: %+v", diags)
+			}
+
+			an := analyzer.New(catalog)
+			an.AddFunctions(declared...)
+			res := an.Analyze(q)
+			var gotDiag string
+			for _, d := range res.Diagnostics {
+				gotDiag = d.Message
+			}
+			if gotDiag != tt.wantDiag {
+				t.Errorf("diagnostic = %q, want %q", gotDiag, tt.wantDiag)
+			}
+			if len(res.Columns) != 1 {
+				t.Fatalf("expected 1 column, got %d", len(res.Columns))
+			}
+			col := res.Columns[0]
+			if col.GoType != tt.wantType || col.Nullable != tt.wantNull || col.Import != tt.wantImport {
+				t.Errorf("column = %s (nullable %v, import %q), want %s (nullable %v, import %q)",
+					col.GoType, col.Nullable, col.Import, tt.wantType, tt.wantNull, tt.wantImport)
+			}
+		})
+	}
+}
+
+func TestFunctionCallValidation(t *testing.T) {
+	catalog := buildTestCatalog()
+
+	tests := []struct {
+		name         string
+		sql          string
+		wantMessage  string
+		wantSeverity analyzer.Severity
+	}{
+		{"unknown function", "SELECT id FROM users WHERE frobnicate(email) = 1", "unknown function frobnicate", analyzer.SeverityWarning},
+		{"too few arguments", "SELECT id FROM users WHERE replace(email, 'a') = 'b'", "function replace called with 2 arguments; expected 3", analyzer.SeverityError},
+		{"too many arguments", "SELECT id FROM users WHERE lower(email, 'x') = 'b'", "function lower called with 2 arguments; expected 1", analyzer.SeverityError},
+		{"valid calls", "SELECT id FROM users WHERE lower(email) = ? AND coalesce(credits, 0) > 0 AND id IN (1, 2)", "", 0},
+		{"insert column list", "INSERT INTO users (id, email) VALUES (?, lower(?))", "", 0},
+		{"aliased unknown function", "SELECT frobnicate(id) AS x, email FROM users", "unknown function frobnicate", analyzer.SeverityWarning},
+		{"aliased arity mismatch", "SELECT id AS x, upper(email, 3) AS y FROM users", "function upper called with 2 arguments; expected 1", analyzer.SeverityError},
+		{"cte column list", "WITH totals(n) AS (SELECT count(*) FROM users) SELECT n FROM totals", "", 0},
+		{"second cte column list", "WITH a(n) AS (SELECT 1), totals(n) AS (SELECT count(*) FROM users) SELECT n FROM totals", "", 0},
+		{"keyword arguments", "SELECT substring(email FROM 1 FOR 3) AS prefix FROM users", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blk := block.Block{Path: "query/test.sql", Line: 1, Column: 1, SQL: tt.sql}
+			q, diags := parser.Parse(blk)
+			if len(diags) != 0 {
+				t.Fatalf("unexpected parser diagnostics: %+v", diags)
+			}
+
+			res := analyzer.New(catalog).Analyze(q)
+			var found bool
+			for _, d := range res.Diagnostics {
+				if !strings.Contains(d.Message, "function") {
+					continue
+				}
+				if tt.wantMessage == "" {
+					t.Errorf("unexpected diagnostic: %s", d.Message)
+					continue
+				}
+				if d.Message == tt.wantMessage {
+					found = true
+					if d.Severity != tt.wantSeverity {
+						t.Errorf("severity = %v, want %v", d.Severity, tt.wantSeverity)
+					}
+				}
+			}
+			if tt.wantMessage != "" && !found {
+				t.Errorf("expected diagnostic %q, got %+v", tt.wantMessage, res.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestFunctionCallValidationPosition(t *testing.T) {
+	blk := block.Block{Path: "query/test.sql", Line: 20, Column: 1, SQL: "SELECT id,\n  frobnicate(email) AS x\nFROM users"}
+	q, _ := parser.Parse(blk)
+	res := analyzer.New(buildTestCatalog()).Analyze(q)
+	for _, d := range res.Diagnostics {
+		if d.Message != "unknown function frobnicate" {
+			continue
+		}
+		if d.Line != 22 || d.Column != 3 {
+			t.Errorf("expected unknown function at 22:3, got %+v", d)
+		}
+		return
+	}
+	t.Fatalf("expected unknown function diagnostic, got %+v", res.Diagnostics)
+}
+
+// jsonTypeResolver maps JSON columns like the PostgreSQL and MySQL engines.
+type jsonTypeResolver struct{}
+
+func (jsonTypeResolver) ResolveType(sqlType string, _ bool) analyzer.TypeInfo {
+	if strings.Contains(strings.ToUpper(sqlType), "JSON") {
+		return analyzer.TypeInfo{GoType: "json.RawMessage"}
+	}
+	return analyzer.TypeInfo{}
+}
+
+func TestJSONExtraction(t *testing.T) {
+	catalog := buildTestCatalog()
+	pg, err := postgres.New(engine.Options{})
+	if err != nil {
+		t.Fatalf("postgres.New() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		resolver analyzer.TypeResolver
+		sql      string
+		wantType string
+	}{
+		{"sqlite text extraction", nil, "SELECT email ->> '$.name' AS name FROM users", "string"},
+		{"sqlite json extraction", nil, "SELECT email -> '$.name' AS doc FROM users", "string"},
+		{"sqlite json_extract", nil, "SELECT json_extract(email, '$.name') AS name FROM users", "string"},
+		{"postgres text extraction", jsonTypeResolver{}, "SELECT email ->> 'name' AS name FROM users", "string"},
+		{"postgres json extraction", jsonTypeResolver{}, "SELECT email -> 'name' AS doc FROM users", "json.RawMessage"},
+		{"postgres path extraction", jsonTypeResolver{}, "SELECT email #> '{a,b}' AS doc FROM users", "json.RawMessage"},
+		{"postgres path text extraction", jsonTypeResolver{}, "SELECT email #>> '{a,b}' AS name FROM users", "string"},
+		{"postgres chained extraction", jsonTypeResolver{}, "SELECT email -> 'a' ->> 'b' AS name FROM users", "string"},
+		{"postgres jsonb_path_query", jsonTypeResolver{}, "SELECT jsonb_path_query(email, '$.a') AS doc FROM users", "json.RawMessage"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blk := block.Block{Path: "query/test.sql", Line: 1, Column: 1, SQL: tt.sql}
+			q, diags := parser.Parse(blk)
+			if len(diags) != 0 {
+				t.Fatalf("unexpected parser diagnostics: %+v", diags)
+			}
+
+			an := analyzer.New(catalog)
+			if tt.resolver != nil {
+				an.SetTypeResolver(tt.resolver)
+				an.SetFunctions(pg.Functions())
+			}
+			res := an.Analyze(q)
+			for _, d := range res.Diagnostics {
+				t.Errorf("unexpected diagnostic: %s", d.Message)
+			}
+			if len(res.Columns) != 1 {
+				t.Fatalf("expected 1 column, got %d", len(res.Columns))
+			}
+			if col := res.Columns[0]; col.GoType != tt.wantType || !col.Nullable {
+				t.Errorf("column = %s (nullable %v), want nullable %s", col.GoType, col.Nullable, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestUpsert(t *testing.T) {
+	catalog := &model.Catalog{
+		Tables: map[string]*model.Table{
+			"accounts": {
+				Name: "accounts",
+				Columns: []*model.Column{
+					{Name: "id", Type: "INTEGER", NotNull: true},
+					{Name: "tenant", Type: "TEXT", NotNull: true},
+					{Name: "email", Type: "TEXT", NotNull: true},
+					{Name: "credits", Type: "INTEGER", NotNull: false},
+				},
+				PrimaryKey: &model.PrimaryKey{Columns: []string{"id"}},
+				UniqueKeys: []*model.UniqueKey{{Name: "accounts_tenant_email", Columns: []string{"tenant", "email"}}},
+			},
+			"events": {
+				Name: "events",
+				Columns: []*model.Column{
+					{Name: "name", Type: "TEXT", NotNull: true},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		sql        string
+		wantParams []string
+		wantDiag   string
+	}{
+		{
+			name:       "primary key target",
+			sql:        "INSERT INTO accounts (id, email) VALUES (?, ?) ON CONFLICT (id) DO UPDATE SET email = excluded.email, credits = ?",
+			wantParams: []string{"id:int64", "email:string", "credits:int64"},
+		},
+		{
+			name:       "unique key target in any order",
+			sql:        "INSERT INTO accounts (id, tenant, email) VALUES ($1, $2, $3) ON CONFLICT (email, tenant) DO UPDATE SET credits = accounts.credits + $4",
+			wantParams: []string{"id:int64", "tenant:string", "email:string", "credits:int64"},
+		},
+		{
+			name:       "named constraint",
+			sql:        "INSERT INTO accounts (id, email) VALUES ($1, $2) ON CONFLICT ON CONSTRAINT accounts_pkey DO NOTHING",
+			wantParams: []string{"id:int64", "email:string"},
+		},
+		{
+			name:       "excluded in update condition",
+			sql:        "INSERT INTO accounts (id, credits) VALUES (?, ?) ON CONFLICT (id) DO UPDATE SET credits = excluded.credits WHERE excluded.credits > accounts.credits",
+			wantParams: []string{"id:int64", "credits:int64"},
+		},
+		{
+			name:       "on duplicate key update",
+			sql:        "INSERT INTO accounts (id, email) VALUES (?, ?) ON DUPLICATE KEY UPDATE email = VALUES(email), credits = ?",
+			wantParams: []string{"id:int64", "email:string", "credits:int64"},
+		},
+		{
+			name:     "target without key",
+			sql:      "INSERT INTO accounts (id, email) VALUES (?, ?) ON CONFLICT (email) DO NOTHING",
+			wantDiag: "ON CONFLICT target (email) does not match a primary key or unique key of table accounts",
+		},
+		{
+			name:     "unknown constraint",
+			sql:      "INSERT INTO accounts (id, email) VALUES ($1, $2) ON CONFLICT ON CONSTRAINT accounts_email_key DO NOTHING",
+			wantDiag: `ON CONFLICT constraint "accounts_email_key" is not a primary key or unique constraint of table accounts`,
+		},
+		{
+			name:     "duplicate key without key",
+			sql:      "INSERT INTO events (name) VALUES (?) ON DUPLICATE KEY UPDATE name = VALUES(name)",
+			wantDiag: "ON DUPLICATE KEY UPDATE on table events, which has no primary key or unique key",
+		},
+		{
+			name:     "unknown excluded column",
+			sql:      "INSERT INTO accounts (id, email) VALUES (?, ?) ON CONFLICT (id) DO UPDATE SET email = excluded.mail",
+			wantDiag: `unknown column "mail"`,
+		},
+		{
+			name:     "unknown assigned column",
+			sql:      "INSERT INTO accounts (id, email) VALUES (?, ?) ON CONFLICT (id) DO UPDATE SET mail = excluded.email",
+			wantDiag: `unknown column "mail" in table accounts`,
+		},
+		{
+			name:     "unknown VALUES() column",
+			sql:      "INSERT INTO accounts (id, email) VALUES (?, ?) ON DUPLICATE KEY UPDATE email = VALUES(mail)",
+			wantDiag: `unknown column "mail" in table accounts`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blk := block.Block{Path: "query/test.sql", Line: 1, Column: 1, SQL: tt.sql}
+			q, diags := parser.Parse(blk)
+			if len(diags) != 0 {
+				t.Fatalf("unexpected parser diagnostics: %+v", diags)
+			}
+
+			res := analyzer.New(catalog).Analyze(q)
+			var messages []string
+			for _, d := range res.Diagnostics {
+				messages = append(messages, d.Message)
+			}
+			if tt.wantDiag == "" {
+				if len(messages) != 0 {
+					t.Errorf("unexpected diagnostics: %v", messages)
+				}
+			} else if len(messages) != 1 || messages[0] != tt.wantDiag {
+				t.Errorf("diagnostics = %v, want [%s]", messages, tt.wantDiag)
+			}
+
+			if tt.wantParams == nil {
+				return
+			}
+			params := make([]string, 0, len(res.Params))
+			for _, p := range res.Params {
+				params = append(params, p.Name+":"+p.GoType)
+			}
+			if strings.Join(params, ",") != strings.Join(tt.wantParams, ",") {
+				t.Errorf("params = %v, want %v", params, tt.wantParams)
+			}
+		})
+	}
+
+	t.Run("absolute position", func(t *testing.T) {
+		blk := block.Block{Path: "query/test.sql", Line: 20, Column: 1, SQL: "INSERT INTO accounts (id, email)\nVALUES (?, ?)\nON CONFLICT (email)\nDO UPDATE SET mail = ?"}
+		q, _ := parser.Parse(blk)
+		res := analyzer.New(catalog).Analyze(q)
+
+		want := map[string][2]int{
+			"ON CONFLICT target (email) does not match a primary key or unique key of table accounts": {23, 1},
+			`unknown column "mail" in table accounts`:                                                 {24, 15},
+		}
+		for _, d := range res.Diagnostics {
+			pos, ok := want[d.Message]
+			if !ok {
+				t.Errorf("unexpected diagnostic: %+v", d)
+				continue
+			}
+			if d.Line != pos[0] || d.Column != pos[1] {
+				t.Errorf("%s at %d:%d, want %d:%d", d.Message, d.Line, d.Column, pos[0], pos[1])
+			}
+			delete(want, d.Message)
+		}
+		for msg := range want {
+			t.Errorf("expected diagnostic %q", msg)
+		}
+	})
+}
+
+func TestCopyFrom(t *testing.T) {
+	catalog := buildTestCatalog()
+
+	tests := []struct {
+		name        string
+		sql         string
+		wantTable   string
+		wantColumns []string
+		wantDiag    string
+	}{
+		{
+			name:        "column list",
+			sql:         "INSERT INTO posts (user_id, title) VALUES ($1, $2)",
+			wantTable:   "posts",
+			wantColumns: []string{"user_id", "title"},
+		},
+		{
+			name:        "all columns",
+			sql:         "INSERT INTO posts VALUES (?, ?, ?);",
+			wantTable:   "posts",
+			wantColumns: []string{"id", "user_id", "title"},
+		},
+		{
+			name:        "named params",
+			sql:         "INSERT INTO users (id, email) VALUES (:id, :email)",
+			wantTable:   "users",
+			wantColumns: []string{"id", "email"},
+		},
+		{
+			name:     "returning",
+			sql:      "INSERT INTO posts (user_id, title) VALUES ($1, $2) RETURNING id",
+			wantDiag: ":copyfrom supports a single VALUES row without further clauses, found RETURNING",
+		},
+		{
+			name:     "upsert",
+			sql:      "INSERT INTO users (id, email) VALUES (?, ?) ON CONFLICT (id) DO NOTHING",
+			wantDiag: ":copyfrom supports a single VALUES row without further clauses, found ON",
+		},
+		{
+			name:     "literal value",
+			sql:      "INSERT INTO posts (user_id, title) VALUES (?, 'draft')",
+			wantDiag: ":copyfrom values must be parameters in column order",
+		},
+		{
+			name:     "reordered params",
+			sql:      "INSERT INTO posts (user_id, title) VALUES ($2, $1)",
+			wantDiag: ":copyfrom values must be parameters in column order",
+		},
+		{
+			name:     "insert select",
+			sql:      "INSERT INTO posts (user_id, title) SELECT id, email FROM users",
+			wantDiag: ":copyfrom requires a VALUES list",
+		},
+		{
+			name:     "update",
+			sql:      "UPDATE users SET email = ? WHERE id = ?",
+			wantDiag: ":copyfrom requires a single INSERT statement",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blk := block.Block{Path: "query/test.sql", Line: 1, Column: 1, SQL: tt.sql, Command: block.CommandCopyFrom}
+			q, diags := parser.Parse(blk)
+			if len(diags) != 0 {
+				t.Fatalf("unexpected parser diagnostics: %+v", diags)
+			}
+
+			res := analyzer.New(catalog).Analyze(q)
+			var messages []string
+			for _, d := range res.Diagnostics {
+				messages = append(messages, d.Message)
+			}
+			if tt.wantDiag != "" {
+				if !slices.Contains(messages, tt.wantDiag) {
+					t.Errorf("diagnostics = %v, want %s", messages, tt.wantDiag)
+				}
+				if res.CopyFrom != nil {
+					t.Errorf("expected no copyfrom target, got %+v", res.CopyFrom)
+				}
+				return
+			}
+			if len(messages) != 0 {
+				t.Fatalf("unexpected diagnostics: %v", messages)
+			}
+			if res.CopyFrom == nil {
+				t.Fatal("expected copyfrom target")
+			}
+			if res.CopyFrom.Table != tt.wantTable || !slices.Equal(res.CopyFrom.Columns, tt.wantColumns) {
+				t.Errorf("target = %+v, want %s %v", res.CopyFrom, tt.wantTable, tt.wantColumns)
+			}
+		})
+	}
+}
+
+func TestBatchCommands(t *testing.T) {
+	catalog := buildTestCatalog()
+
+	tests := []struct {
+		name     string
+		sql      string
+		command  block.Command
+		wantDiag string
+	}{
+		{name: "batchexec", sql: "UPDATE users SET email = ? WHERE id = ?", command: block.CommandBatchExec},
+		{name: "batchone", sql: "SELECT id, email FROM users WHERE id = ?", command: block.CommandBatchOne},
+		{name: "batchmany", sql: "SELECT id, title FROM posts WHERE user_id = ?", command: block.CommandBatchMany},
+		{
+			name:     "no params",
+			sql:      "DELETE FROM posts",
+			command:  block.CommandBatchExec,
+			wantDiag: ":batchexec requires at least one parameter",
+		},
+		{
+			name:     "slice param",
+			sql:      "SELECT id FROM posts WHERE user_id IN (sqlc.slice('ids'))",
+			command:  block.CommandBatchMany,
+			wantDiag: `:batchmany does not support slice parameter "ids"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blk := block.Block{Path: "query/test.sql", Line: 1, Column: 1, SQL: tt.sql, Command: tt.command}
+			q, diags := parser.Parse(blk)
+			if len(diags) != 0 {
+				t.Fatalf("unexpected parser diagnostics: %+v", diags)
+			}
+
+			res := analyzer.New(catalog).Analyze(q)
+			var messages []string
+			for _, d := range res.Diagnostics {
+				messages = append(messages, d.Message)
+			}
+			if tt.wantDiag == "" {
+				if len(messages) != 0 {
+					t.Errorf("unexpected diagnostics: %v", messages)
+				}
+			} else if !slices.Contains(messages, tt.wantDiag) {
+				t.Errorf("diagnostics = %v, want %s", messages, tt.wantDiag)
+			}
+		})
+	}
+}
+
+func TestEmbed(t *testing.T) {
+	catalog := buildTestCatalog()
+	catalog.Tables["drafts"] = &model.Table{
+		Name:    "drafts",
+		Columns: []*model.Column{{Name: "body", Type: "TEXT"}},
+	}
+
+	type wantEmbed struct {
+		table    string
+		nullable bool
+	}
+	tests := []struct {
+		name     string
+		sql      string
+		want     []*wantEmbed // per result column; nil for plain columns
+		wantDiag string
+	}{
+		{
+			name: "inner join",
+			sql:  "SELECT sqlc.embed(u), p.title FROM users u JOIN posts p ON p.user_id = u.id",
+			want: []*wantEmbed{{"users", false}, {"users", false}, {"users", false}, {"users", false}, nil},
+		},
+		{
+			name: "left join",
+			sql:  "SELECT u.id, sqlc.embed(posts) FROM users u LEFT JOIN posts ON posts.user_id = u.id",
+			want: []*wantEmbed{nil, {"posts", true}, {"posts", true}, {"posts", true}},
+		},
+		{
+			name: "right join",
+			sql:  "SELECT sqlc.embed(u), p.id AS post_id FROM users u RIGHT JOIN posts p ON p.user_id = u.id",
+			want: []*wantEmbed{{"users", true}, {"users", true}, {"users", true}, {"users", true}, nil},
+		},
+		{
+			name: "table without not null columns",
+			sql:  "SELECT u.id, sqlc.embed(d) FROM users u LEFT JOIN drafts d ON d.body = u.email",
+			want: []*wantEmbed{nil, {"drafts", false}},
+		},
+		{
+			name:     "unknown table",
+			sql:      "SELECT sqlc.embed(x) FROM users",
+			wantDiag: `sqlc.embed references unknown table "x"`,
+		},
+		{
+			name:     "cte",
+			sql:      "WITH recent AS (SELECT id FROM posts) SELECT sqlc.embed(recent) FROM recent",
+			wantDiag: "sqlc.embed(recent) must name a table, not a CTE or subquery",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blk := block.Block{Path: "query/test.sql", Line: 1, Column: 1, SQL: tt.sql, Command: block.CommandMany}
+			q, diags := parser.Parse(blk)
+			if len(diags) != 0 {
+				t.Fatalf("unexpected parser diagnostics: %+v", diags)
+			}
+
+			res := analyzer.New(catalog).Analyze(q)
+			var messages []string
+			for _, d := range res.Diagnostics {
+				messages = append(messages, d.Message)
+			}
+			if tt.wantDiag != "" {
+				if !slices.Contains(messages, tt.wantDiag) {
+					t.Errorf("diagnostics = %v, want %s", messages, tt.wantDiag)
+				}
+				return
+			}
+			if len(messages) != 0 {
+				t.Fatalf("unexpected diagnostics: %v", messages)
+			}
+			if len(res.Columns) != len(tt.want) {
+				t.Fatalf("expected %d columns, got %d", len(tt.want), len(res.Columns))
+			}
+			for i, want := range tt.want {
+				got := res.Columns[i].Embed
+				switch {
+				case want == nil && got != nil:
+					t.Errorf("column %d (%s) unexpectedly embedded in %s", i, res.Columns[i].Name, got.Table)
+				case want != nil && got == nil:
+					t.Errorf("column %d (%s) not embedded, want %s", i, res.Columns[i].Name, want.table)
+				case want != nil && (got.Table != want.table || got.Nullable != want.nullable):
+					t.Errorf("column %d embed = %+v, want %+v", i, *got, *want)
+				}
+			}
+		})
+	}
+}
+
+func TestEnumColumns(t *testing.T) {
+	status := &model.Enum{Name: "ticket_status", Values: []string{"open", "closed"}}
+	priority := &model.Enum{Name: "tickets_priority", Values: []string{"low", "high"}}
+	catalog := &model.Catalog{
+		Tables: map[string]*model.Table{
+			"tickets": {
+				Name: "tickets",
+				Columns: []*model.Column{
+					{Name: "id", Type: "INTEGER", NotNull: true},
+					{Name: "status", Type: "ticket_status", NotNull: true},
+					{Name: "priority", Type: "TEXT", Enum: "tickets_priority"},
+					{Name: "title", Type: "TEXT", NotNull: true},
+				},
+			},
+		},
+		Enums: map[string]*model.Enum{
+			"ticket_status":    status,
+			"tickets_priority": priority,
+		},
+	}
+
+	tests := []struct {
+		name       string
+		sql        string
+		wantCols   []*model.Enum
+		wantParams []*model.Enum
+	}{
+		{"select", "SELECT id, status, priority, title FROM tickets", []*model.Enum{nil, status, priority, nil}, nil},
+		{"where params", "SELECT id FROM tickets WHERE status = ? AND priority IN (sqlc.slice('priorities'))", []*model.Enum{nil}, []*model.Enum{status, priority}},
+		{"insert params", "INSERT INTO tickets (status, priority, title) VALUES (?, ?, ?)", nil, []*model.Enum{status, priority, nil}},
+		{"case of one enum", "SELECT CASE WHEN id > 1 THEN status ELSE status END AS s FROM tickets", []*model.Enum{status}, nil},
+		{"case mixing enums", "SELECT CASE WHEN id > 1 THEN status ELSE priority END AS s FROM tickets", []*model.Enum{nil}, nil},
+		{"union with text", "SELECT status FROM tickets UNION SELECT title FROM tickets", []*model.Enum{nil}, nil},
+		{"union of one enum", "SELECT status FROM tickets UNION SELECT status FROM tickets", []*model.Enum{status}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blk := block.Block{Path: "query/test.sql", Line: 1, Column: 1, SQL: tt.sql}
+			q, diags := parser.Parse(blk)
+			if len(diags) != 0 {
+				t.Fatalf("unexpected parser diagnostics: %+v", diags)
+			}
+			res := analyzer.New(catalog).Analyze(q)
+			for _, d := range res.Diagnostics {
+				t.Errorf("unexpected diagnostic: %s", d.Message)
+			}
+			if len(res.Columns) != len(tt.wantCols) {
+				t.Fatalf("expected %d columns, got %+v", len(tt.wantCols), res.Columns)
+			}
+			for i, want := range tt.wantCols {
+				if got := res.Columns[i]; got.Enum != want {
+					t.Errorf("column %s enum = %v, want %v", got.Name, got.Enum, want)
+				}
+				if want != nil && res.Columns[i].GoType != "string" {
+					t.Errorf("column %s type = %s, want string", res.Columns[i].Name, res.Columns[i].GoType)
+				}
+			}
+			if len(res.Params) != len(tt.wantParams) {
+				t.Fatalf("expected %d params, got %+v", len(tt.wantParams), res.Params)
+			}
+			for i, want := range tt.wantParams {
+				if got := res.Params[i]; got.Enum != want {
+					t.Errorf("param %s enum = %v, want %v", got.Name, got.Enum, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDomainColumns(t *testing.T) {
+	email := &model.Domain{Name: "email_address", BaseType: "TEXT", Constraints: []*model.DomainConstraint{{Type: "not_null"}}}
+	quantity := &model.Domain{Name: "quantity", BaseType: "INTEGER"}
+	catalog := &model.Catalog{
+		Tables: map[string]*model.Table{
+			"orders": {
+				Name: "orders",
+				Columns: []*model.Column{
+					{Name: "id", Type: "INTEGER", NotNull: true},
+					{Name: "email", Type: "email_address"},
+					{Name: "qty", Type: "public.quantity"},
+				},
+			},
+		},
+		Domains: map[string]*model.Domain{
+			"email_address": email,
+			"quantity":      quantity,
+		},
+	}
+
+	analyze := func(sql string) analyzer.Result {
+		q, diags := parser.Parse(block.Block{Path: "query/test.sql", Line: 1, Column: 1, SQL: sql})
+		if len(diags) != 0 {
+			t.Fatalf("unexpected parser diagnostics: %+v", diags)
+		}
+		res := analyzer.New(catalog).Analyze(q)
+		for _, d := range res.Diagnostics {
+			t.Errorf("unexpected diagnostic: %s", d.Message)
+		}
+		return res
+	}
+	res := analyze("SELECT email, qty FROM orders")
+	params := analyze("INSERT INTO orders (email, qty) VALUES (?, ?)").Params
+
+	wants := []struct {
+		domain   *model.Domain
+		goType   string
+		nullable bool
+	}{
+		{email, "string", false}, // NOT NULL on the domain
+		{quantity, "int64", true},
+	}
+	if len(res.Columns) != len(wants) || len(params) != len(wants) {
+		t.Fatalf("expected %d columns and params, got %+v and %+v", len(wants), res.Columns, params)
+	}
+	for i, want := range wants {
+		got := res.Columns[i]
+		if got.Domain != want.domain || got.GoType != want.goType || got.Nullable != want.nullable {
+			t.Errorf("column %s = %v %s (nullable %v), want %v %s (nullable %v)", got.Name, got.Domain, got.GoType, got.Nullable, want.domain, want.goType, want.nullable)
+		}
+	}
+	for i, want := range wants {
+		got := params[i]
+		if got.Domain != want.domain || got.GoType != want.goType || got.Nullable != want.nullable {
+			t.Errorf("param %s = %v %s (nullable %v), want %v %s (nullable %v)", got.Name, got.Domain, got.GoType, got.Nullable, want.domain, want.goType, want.nullable)
+		}
+	}
+}
+
+func TestSQLiteTypes(t *testing.T) {
+	catalog := &model.Catalog{
+		Tables: map[string]*model.Table{
+			"events": {
+				Name: "events",
+				Columns: []*model.Column{
+					{Name: "id", Type: "INTEGER", NotNull: true},
+					{Name: "done", Type: "BOOLEAN", NotNull: true},
+					{Name: "payload", Type: "JSON"},
+					{Name: "created_at", Type: "DATETIME", NotNull: true},
+					{Name: "at", Type: "INTEGER", NotNull: true, Default: &model.Value{Text: "(unixepoch())"}},
+					{Name: "at_ms", Type: "INTEGER", Default: &model.Value{Text: "(unixepoch('subsec') * 1000)"}},
+					{Name: "jd", Type: "REAL", Default: &model.Value{Text: "(julianday('now'))"}},
+					{Name: "n", Type: "INTEGER", Default: &model.Value{Text: "0"}},
+				},
+			},
+		},
+	}
+
+	analyze := func(opts config.SQLiteTypes, sql string) analyzer.Result {
+		q, diags := parser.Parse(block.Block{Path: "query/test.sql", Line: 1, Column: 1, SQL: sql})
+		if len(diags) != 0 {
+			t.Fatalf("unexpected parser diagnostics: %+v", diags)
+		}
+		an := analyzer.New(catalog)
+		an.SetSQLiteTypes(opts)
+		res := an.Analyze(q)
+		for _, d := range res.Diagnostics {
+			t.Errorf("unexpected diagnostic: %s", d.Message)
+		}
+		return res
+	}
+
+	opts := config.SQLiteTypes{Enabled: true, TimeEncoding: config.StorageUnix}
+	res := analyze(opts, "SELECT done, payload, created_at, at, at_ms, jd, n FROM events")
+	params := analyze(opts, "INSERT INTO events (done, payload, created_at, at, at_ms, jd, n) VALUES (?, ?, ?, ?, ?, ?, ?)").Params
+
+	wants := []struct {
+		goType  string
+		storage config.SQLiteStorage
+	}{
+		{"bool", ""},
+		{"json.RawMessage", config.StorageJSON},
+		{"time.Time", config.StorageUnix},
+		{"time.Time", config.StorageUnix},
+		{"time.Time", config.StorageUnixMillis},
+		{"time.Time", config.StorageJulianDay},
+		{"int64", ""},
+	}
+	if len(res.Columns) != len(wants) || len(params) != len(wants) {
+		t.Fatalf("expected %d columns and params, got %+v and %+v", len(wants), res.Columns, params)
+	}
+	for i, want := range wants {
+		if got := res.Columns[i]; got.GoType != want.goType || got.Storage != want.storage {
+			t.Errorf("column %s = %s (%q), want %s (%q)", got.Name, got.GoType, got.Storage, want.goType, want.storage)
+		}
+		if got := params[i]; got.GoType != want.goType || got.Storage != want.storage {
+			t.Errorf("param %s = %s (%q), want %s (%q)", got.Name, got.GoType, got.Storage, want.goType, want.storage)
+		}
+	}
+
+	ranges := analyze(opts, "SELECT id FROM events WHERE created_at > ? AND at <= ? AND at_ms NOT BETWEEN ? AND ? AND ? < jd").Params
+	rangeWants := []struct {
+		goType  string
+		storage config.SQLiteStorage
+	}{
+		{"time.Time", config.StorageUnix},
+		{"time.Time", config.StorageUnix},
+		{"time.Time", config.StorageUnixMillis},
+		{"time.Time", config.StorageUnixMillis},
+		{"time.Time", config.StorageJulianDay},
+	}
+	if len(ranges) != len(rangeWants) {
+		t.Fatalf("expected %d range params, got %+v", len(rangeWants), ranges)
+	}
+	for i, want := range rangeWants {
+		if got := ranges[i]; got.GoType != want.goType || got.Storage != want.storage {
+			t.Errorf("range param %s = %s (%q), want %s (%q)", got.Name, got.GoType, got.Storage, want.goType, want.storage)
+		}
+	}
+
+	union := analyzer.New(catalog)
+	union.SetSQLiteTypes(opts)
+	q, _ := parser.Parse(block.Block{Path: "query/test.sql", Line: 1, Column: 1, SQL: "SELECT created_at FROM events UNION ALL SELECT at_ms FROM events"})
+	unionRes := union.Analyze(q)
+	if got := unionRes.Columns[0]; got.GoType != "any" || got.Storage != "" {
+		t.Errorf("union of storages = %s (%q), want any", got.GoType, got.Storage)
+	}
+	wantDiag := "returns time.Time stored as unix_millis for column created_at; expected time.Time stored as unix"
+	if len(unionRes.Diagnostics) != 1 || !strings.Contains(unionRes.Diagnostics[0].Message, wantDiag) {
+		t.Errorf("union diagnostics = %+v, want %q", unionRes.Diagnostics, wantDiag)
+	}
+	if got := analyze(config.SQLiteTypes{}, "SELECT created_at FROM events").Columns[0]; got.GoType == "time.Time" || got.Storage != "" {
+		t.Errorf("disabled sqlite_types = %s (%q), want affinity type", got.GoType, got.Storage)
+	}
+}
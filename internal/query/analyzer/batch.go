@@ -0,0 +1,36 @@
+package analyzer
+
+import (
+	"fmt"
+
+	"github.com/electwix/db-catalyst/internal/query/parser"
+)
+
+// checkBatch checks that a batch query can be queued once per item: every
+// item needs its own arguments, and the statement text must be the same for
+// all items, which rules out slice parameters.
+func checkBatch(q parser.Query) []Diagnostic {
+	blk := q.Block
+	if len(q.Params) == 0 {
+		return []Diagnostic{{
+			Path:     blk.Path,
+			Line:     blk.Line,
+			Column:   blk.Column,
+			Message:  fmt.Sprintf("%s requires at least one parameter", blk.Command),
+			Severity: SeverityError,
+		}}
+	}
+	var diags []Diagnostic
+	for _, p := range q.Params {
+		if p.IsVariadic {
+			diags = append(diags, Diagnostic{
+				Path:     blk.Path,
+				Line:     p.Line,
+				Column:   p.Column,
+				Message:  fmt.Sprintf("%s does not support slice parameter %q", blk.Command, p.Name),
+				Severity: SeverityError,
+			})
+		}
+	}
+	return diags
+}
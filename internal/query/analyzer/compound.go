@@ -0,0 +1,213 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/electwix/db-catalyst/internal/query/block"
+	"github.com/electwix/db-catalyst/internal/query/parser"
+	"github.com/electwix/db-catalyst/internal/schema/tokenizer"
+)
+
+// numericRanks orders the Go numeric types by width for widening the columns
+// of compound query branches.
+var numericRanks = map[string]int{
+	"int8": 1, "int16": 2, "int32": 3, "int64": 4, "float32": 5, "float64": 6,
+}
+
+// resolveCompoundBranches resolves the result columns of each branch after the
+// first and unifies them into cols. Each branch is resolved against the
+// relations of its own FROM clause.
+func (a *Analyzer) resolveCompoundBranches(q parser.Query, cols []ResultColumn, branchTokens [][]tokenizer.Token, baseScope *queryScope, hasCatalog bool) []Diagnostic {
+	var diags []Diagnostic
+	for i, branch := range q.Compound {
+		scope := baseScope
+		if branchTokens != nil {
+			scope = relationScope(baseScope, branchTokens[i+1], q.CTEs)
+			a.applyJoinNullability(scope, branchTokens[i+1])
+		}
+
+		branchCols, branchDiags := a.resolveSelectColumns(namedLike(branch.Columns, cols), scope, baseScope, q.Block, hasCatalog)
+		diags = append(diags, branchDiags...)
+		diags = append(diags, unifyCompoundColumns(cols, branchCols, branch, i+2, q.Block)...)
+	}
+	return diags
+}
+
+// namedLike gives the unaliased columns of a branch the names of the first
+// branch's columns, which name the result. Branches with star expansions are
+// left alone since their positions are only known after expansion.
+func namedLike(branchCols []parser.Column, cols []ResultColumn) []parser.Column {
+	named := make([]parser.Column, len(branchCols))
+	copy(named, branchCols)
+	for i, col := range named {
+		if col.Expr == "*" || strings.HasSuffix(col.Expr, ".*") {
+			return branchCols
+		}
+		if col.Alias == "" && i < len(cols) {
+			named[i].Alias = cols[i].Name
+		}
+	}
+	return named
+}
+
+// unifyCompoundColumns merges the result columns of compound branch number
+// index into cols. Names come from the first branch, numeric types widen,
+// and a column is nullable when it is nullable in any branch.
+func unifyCompoundColumns(cols, branchCols []ResultColumn, branch parser.CompoundBranch, index int, blk block.Block) []Diagnostic {
+	if len(branchCols) != len(cols) {
+		return []Diagnostic{{
+			Path:     blk.Path,
+			Line:     branch.Line,
+			Column:   branch.Column,
+			Message:  fmt.Sprintf("%s branch %d returns %d columns; expected %d", branch.Operator, index, len(branchCols), len(cols)),
+			Severity: SeverityError,
+		}}
+	}
+
+	var diags []Diagnostic
+	for i := range cols {
+		unified, ok := unifyColumnTypes(cols[i], branchCols[i])
+		if !ok {
+			diags = append(diags, Diagnostic{
+				Path:     blk.Path,
+				Line:     branch.Line,
+				Column:   branch.Column,
+				Message:  fmt.Sprintf("%s branch %d returns %s for column %s; expected %s, defaulting to interface{}", branch.Operator, index, storedType(branchCols[i].GoType, branchCols[i].Storage), cols[i].Name, storedType(cols[i].GoType, cols[i].Storage)),
+				Severity: SeverityWarning,
+			})
+		}
+		cols[i] = unified
+	}
+	return diags
+}
+
+// unifyColumnTypes combines the types of a column in two branches. Untyped
+// columns such as NULL literals take the other branch's type; it reports false
+// when the types cannot be combined, in which case the column becomes any.
+func unifyColumnTypes(col, other ResultColumn) (ResultColumn, bool) {
+	col.Nullable = col.Nullable || other.Nullable
+	switch {
+	case other.GoType == "any" || other.GoType == "":
+		return col, true
+	case col.GoType == "any" || col.GoType == "":
+		col.GoType = other.GoType
+		col.Import = other.Import
+		col.Package = other.Package
+		col.Enum = other.Enum
+		col.Domain = other.Domain
+		col.Storage = other.Storage
+		return col, true
+	case col.GoType == other.GoType && col.Import == other.Import && col.Storage == other.Storage:
+		if col.Enum != other.Enum || col.Domain != other.Domain {
+			// Values of different enums or domains (or of plain columns)
+			// only share the base type.
+			col.Enum = nil
+			col.Domain = nil
+		}
+		return col, true
+	}
+	col.Enum = nil
+	col.Domain = nil
+	col.Storage = ""
+
+	rank, ok := numericRanks[col.GoType]
+	otherRank, otherOK := numericRanks[other.GoType]
+	if !ok || !otherOK {
+		col.GoType = "any"
+		col.Import = ""
+		col.Package = ""
+		return col, false
+	}
+	isFloat := strings.HasPrefix(col.GoType, "float")
+	if isFloat != strings.HasPrefix(other.GoType, "float") {
+		// Mixing integers and floats needs a float wide enough for both.
+		col.GoType = "float64"
+	} else if otherRank > rank {
+		col.GoType = other.GoType
+	}
+	return col, true
+}
+
+// splitCompoundTokens splits the tokens of a compound SELECT into its branches
+// at the top-level UNION, INTERSECT and EXCEPT operators. It returns nil when
+// the number of branches differs from want.
+func splitCompoundTokens(tokens []tokenizer.Token, want int) [][]tokenizer.Token {
+	var branches [][]tokenizer.Token
+	depth := 0
+	start := 0
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch {
+		case isSymbol(tok, "("):
+			depth++
+		case isSymbol(tok, ")"):
+			if depth > 0 {
+				depth--
+			}
+		case depth == 0 && (isWord(tok, "UNION") || isWord(tok, "INTERSECT") || isWord(tok, "EXCEPT")):
+			branches = append(branches, tokens[start:i])
+			if i+1 < len(tokens) && (isWord(tokens[i+1], "ALL") || isWord(tokens[i+1], "DISTINCT")) {
+				i++
+			}
+			start = i + 1
+		}
+	}
+	branches = append(branches, tokens[start:])
+	if len(branches) != want {
+		return nil
+	}
+	return branches
+}
+
+// unifyRecursiveTerm types the columns of the term after UNION in a CTE, which
+// may refer to the CTE itself with the anchor's columns, and unifies them into
+// the anchor's columns.
+func (a *Analyzer) unifyRecursiveTerm(cte parser.CTE, recQuery parser.Query, cols []scopeColumn, scope *queryScope) []Diagnostic {
+	tokens, err := tokenizer.Scan(recQuery.Block.Path, []byte(recQuery.Block.SQL), false)
+	if err != nil || len(recQuery.Columns) == 0 {
+		return nil
+	}
+	withSelf := scope.clone()
+	withSelf.addEntry(cte.Name, newCTEEntry(cte.Name, cols))
+	recScope := relationScope(withSelf, tokens, nil)
+	a.applyJoinNullability(recScope, tokens)
+
+	// The recursive term only refines the anchor's types; problems resolving
+	// its columns are not reported.
+	recCols, _ := a.resolveSelectColumns(recQuery.Columns, recScope, withSelf, recQuery.Block, true)
+	if len(recCols) != len(cols) {
+		return nil
+	}
+
+	var diags []Diagnostic
+	for i, col := range cols {
+		unified, ok := unifyColumnTypes(ResultColumn{
+			Name:     col.name,
+			GoType:   col.goType,
+			Nullable: col.nullable,
+			Import:   col.importPath,
+			Package:  col.packageName,
+			Enum:     col.enum,
+			Domain:   col.domain,
+			Storage:  col.storage,
+		}, recCols[i])
+		if !ok {
+			diags = append(diags, Diagnostic{
+				Path:     recQuery.Block.Path,
+				Line:     cte.Line,
+				Column:   cte.Column,
+				Message:  fmt.Sprintf("recursive term of CTE %s returns %s for column %s; expected %s, defaulting to interface{}", cte.Name, storedType(recCols[i].GoType, recCols[i].Storage), col.name, storedType(col.goType, col.storage)),
+				Severity: SeverityWarning,
+			})
+		}
+		cols[i].goType = unified.GoType
+		cols[i].nullable = unified.Nullable
+		cols[i].importPath = unified.Import
+		cols[i].packageName = unified.Package
+		cols[i].enum = unified.Enum
+		cols[i].domain = unified.Domain
+		cols[i].storage = unified.Storage
+	}
+	return diags
+}
@@ -0,0 +1,162 @@
+package analyzer
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/electwix/db-catalyst/internal/query/parser"
+	"github.com/electwix/db-catalyst/internal/schema/model"
+	"github.com/electwix/db-catalyst/internal/schema/tokenizer"
+)
+
+// CopyFromTarget describes the table and columns a :copyfrom query inserts
+// into. Columns are in the order of the query parameters.
+type CopyFromTarget struct {
+	Table   string
+	Columns []string
+}
+
+// resolveCopyFrom checks that a :copyfrom query is a plain single-table
+// INSERT with one VALUES row made of parameters, one per column, and returns
+// its target. Bulk inserts send the rows through COPY or multi-row VALUES
+// lists, so anything beyond the column values has nowhere to go.
+func (a *Analyzer) resolveCopyFrom(q parser.Query, tokens []tokenizer.Token, hasCatalog bool) (*CopyFromTarget, []Diagnostic) {
+	blk := q.Block
+	fail := func(line, column int, format string, args ...any) (*CopyFromTarget, []Diagnostic) {
+		return nil, []Diagnostic{{
+			Path:     blk.Path,
+			Line:     line,
+			Column:   column,
+			Message:  fmt.Sprintf(":copyfrom "+format, args...),
+			Severity: SeverityError,
+		}}
+	}
+
+	if q.Verb != parser.VerbInsert || len(q.CTEs) > 0 {
+		return fail(blk.Line, blk.Column, "requires a single INSERT statement")
+	}
+	for _, p := range q.Params {
+		if p.IsVariadic {
+			return fail(p.Line, p.Column, "does not support slice parameter %q", p.Name)
+		}
+	}
+
+	i := 0
+	for i < len(tokens) && !isWord(tokens[i], "INTO") {
+		i++
+	}
+	if i+1 >= len(tokens) || tokens[i+1].Kind != tokenizer.KindIdentifier {
+		return fail(blk.Line, blk.Column, "requires INSERT INTO <table>")
+	}
+	i++
+	target := &CopyFromTarget{Table: tokenizer.NormalizeIdentifier(tokens[i].Text)}
+	if i+2 < len(tokens) && isSymbol(tokens[i+1], ".") && tokens[i+2].Kind == tokenizer.KindIdentifier {
+		target.Table += "." + tokenizer.NormalizeIdentifier(tokens[i+2].Text)
+		i += 2
+	}
+	i++
+
+	if i < len(tokens) && isSymbol(tokens[i], "(") {
+		end := skipParens(tokens, i)
+		for _, tok := range tokens[i+1 : max(end-1, i+1)] {
+			if isSymbol(tok, ",") {
+				continue
+			}
+			target.Columns = append(target.Columns, tokenizer.NormalizeIdentifier(tok.Text))
+		}
+		i = end
+	}
+
+	if i >= len(tokens) || !isWord(tokens[i], "VALUES") {
+		tok := tokens[min(i, len(tokens)-1)]
+		return fail(tok.Line, tok.Column, "requires a VALUES list")
+	}
+	i++
+	if i >= len(tokens) || !isSymbol(tokens[i], "(") {
+		tok := tokens[min(i, len(tokens)-1)]
+		return fail(tok.Line, tok.Column, "requires a VALUES list")
+	}
+	end := skipParens(tokens, i)
+	values := splitCopyFromValues(tokens[i+1 : max(end-1, i+1)])
+	for idx, value := range values {
+		if !isCopyFromParam(value, idx+1) {
+			return fail(value[0].Line, value[0].Column, "values must be parameters in column order")
+		}
+	}
+	for end < len(tokens) && isSymbol(tokens[end], ";") {
+		end++
+	}
+	if end < len(tokens) && tokens[end].Kind != tokenizer.KindEOF {
+		return fail(tokens[end].Line, tokens[end].Column, "supports a single VALUES row without further clauses, found %s", tokens[end].Text)
+	}
+
+	var table *model.Table
+	if hasCatalog {
+		table = lookupTable(a.Catalog, target.Table)
+		if table == nil {
+			return fail(blk.Line, blk.Column, "target table %s not found", target.Table)
+		}
+		target.Table = table.Name
+	}
+	if len(target.Columns) == 0 {
+		if table == nil {
+			return fail(blk.Line, blk.Column, "requires a column list without a schema catalog")
+		}
+		for _, col := range table.Columns {
+			target.Columns = append(target.Columns, col.Name)
+		}
+	}
+	if len(values) != len(target.Columns) {
+		return fail(blk.Line, blk.Column, "has %d values for %d columns", len(values), len(target.Columns))
+	}
+	return target, nil
+}
+
+// splitCopyFromValues splits the tokens of a VALUES row at top-level commas.
+func splitCopyFromValues(tokens []tokenizer.Token) [][]tokenizer.Token {
+	var values [][]tokenizer.Token
+	start := 0
+	for i := 0; i < len(tokens); i++ {
+		switch {
+		case isSymbol(tokens[i], "("):
+			i = skipParens(tokens, i) - 1
+		case isSymbol(tokens[i], ","):
+			values = append(values, tokens[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(tokens) {
+		values = append(values, tokens[start:])
+	}
+	return values
+}
+
+// isCopyFromParam reports whether value is a single parameter that may be
+// the pos-th value of the row. Numbered parameters must match pos so that
+// parameters and columns line up.
+func isCopyFromParam(value []tokenizer.Token, pos int) bool {
+	switch len(value) {
+	case 1:
+		tok := value[0]
+		switch {
+		case tok.Kind == tokenizer.KindParam:
+			return tok.Text == "$"+strconv.Itoa(pos)
+		case isSymbol(tok, "?"):
+			return true
+		case tok.Kind == tokenizer.KindIdentifier && len(tok.Text) > 1 && tok.Text[0] == '@':
+			return true
+		}
+	case 2:
+		switch {
+		case isSymbol(value[0], "?") && value[1].Kind == tokenizer.KindNumber:
+			return value[1].Text == strconv.Itoa(pos)
+		case isSymbol(value[0], ":") && value[1].Kind == tokenizer.KindIdentifier:
+			return true
+		}
+	default:
+		// sqlc.arg(name) and sqlc.narg(name)
+		return len(value) >= 4 && isWord(value[0], "sqlc") && isSymbol(value[1], ".") &&
+			(isWord(value[2], "arg") || isWord(value[2], "narg")) && isSymbol(value[3], "(")
+	}
+	return false
+}
@@ -0,0 +1,203 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/electwix/db-catalyst/internal/query/parser"
+	"github.com/electwix/db-catalyst/internal/schema/tokenizer"
+)
+
+// addDerivedTables resolves the subqueries in the FROM clauses of tokens and
+// adds them to scope under their aliases. They are resolved in FROM order so
+// that a LATERAL subquery can refer to the relations before it.
+func (a *Analyzer) addDerivedTables(scope *queryScope, q parser.Query, tokens []tokenizer.Token, hasCatalog bool) []Diagnostic {
+	if !hasCatalog {
+		return nil
+	}
+	var diags []Diagnostic
+	relations := parseFromClauses(tokens).relations
+	for i, rel := range relations {
+		if rel.subquery == nil {
+			continue
+		}
+		var outer *queryScope
+		if rel.lateral {
+			outer = newQueryScope()
+			for _, prev := range relations[:i] {
+				name := prev.name
+				if name == "" {
+					name = prev.alias
+				}
+				if entry, ok := scope.get(name); ok {
+					outer.addEntry(prev.key(), entry)
+				}
+			}
+		}
+		entry, entryDiags := a.resolveDerivedTable(rel, q, scope, outer)
+		diags = append(diags, entryDiags...)
+		if entry != nil {
+			scope.addEntry(rel.alias, entry)
+		}
+	}
+	return diags
+}
+
+// resolveDerivedTable analyzes the subquery of a derived table like a query of
+// its own and returns a scope entry for its result columns.
+func (a *Analyzer) resolveDerivedTable(rel joinRelation, parent parser.Query, scope, outer *queryScope) (*scopeEntry, []Diagnostic) {
+	first := rel.subquery[0]
+	last := rel.subquery[len(rel.subquery)-1]
+	idx := newTextIndex(parent.Block.SQL)
+	start := idx.offset(first)
+	end := min(idx.offset(last)+len(last.Text), len(parent.Block.SQL))
+
+	// Blank out the rest of the statement so token positions in the
+	// subquery match the enclosing block.
+	blk := parent.Block
+	blk.SQL = maskOutside(parent.Block.SQL, start, end)
+	q, parseDiags := parser.Parse(blk)
+	diags := make([]Diagnostic, 0, len(parseDiags))
+	for _, pd := range parseDiags {
+		diags = append(diags, Diagnostic{
+			Path:     pd.Path,
+			Line:     pd.Line,
+			Column:   pd.Column,
+			Message:  pd.Message,
+			Severity: convertSeverity(pd.Severity),
+		})
+	}
+	if q.Verb != parser.VerbSelect {
+		return nil, append(diags, Diagnostic{
+			Path:     parent.Block.Path,
+			Line:     actualTokenLine(parent, first),
+			Column:   first.Column,
+			Message:  fmt.Sprintf("derived table %s is not a SELECT; its columns are not typed", rel.alias),
+			Severity: SeverityWarning,
+		})
+	}
+
+	tokens, err := tokenizer.Scan(blk.Path, []byte(blk.SQL), false)
+	if err != nil {
+		return nil, diags
+	}
+	mainTokens := tokens
+	if mainIdx := findMainStatementStart(tokens); mainIdx >= 0 {
+		mainTokens = tokens[mainIdx:]
+	}
+
+	local := scope.clone()
+	for _, cte := range q.CTEs {
+		entry, cteDiags := a.resolveCTE(cte, q, local, true)
+		diags = append(diags, cteDiags...)
+		if entry != nil {
+			local.addEntry(cte.Name, entry)
+		}
+	}
+	diags = append(diags, a.addDerivedTables(local, q, mainTokens, true)...)
+
+	columnTokens := mainTokens
+	var branchTokens [][]tokenizer.Token
+	if len(q.Compound) > 0 {
+		if branchTokens = splitCompoundTokens(mainTokens, len(q.Compound)+1); branchTokens != nil {
+			columnTokens = branchTokens[0]
+		}
+	}
+	columnScope := relationScope(local, columnTokens, q.CTEs)
+	columnScope.outer = outer
+	validationScope := columnScope.clone()
+	a.applyJoinNullability(columnScope, columnTokens)
+
+	cols, colDiags := a.resolveSelectColumns(q.Columns, columnScope, local, q.Block, true)
+	diags = append(diags, colDiags...)
+	diags = append(diags, a.resolveCompoundBranches(q, cols, branchTokens, local, true)...)
+	if branchTokens == nil {
+		diags = append(diags, a.validateIdentifiers(withoutDerivedTables(mainTokens), validationScope, q.Block)...)
+	}
+
+	if len(rel.columns) > 0 {
+		if len(rel.columns) != len(cols) {
+			return nil, append(diags, Diagnostic{
+				Path:     parent.Block.Path,
+				Line:     actualTokenLine(parent, first),
+				Column:   first.Column,
+				Message:  fmt.Sprintf("derived table %s lists %d columns but its SELECT returns %d", rel.alias, len(rel.columns), len(cols)),
+				Severity: SeverityError,
+			})
+		}
+		for i := range cols {
+			cols[i].Name = rel.columns[i]
+		}
+	}
+
+	scopeCols := make([]scopeColumn, 0, len(cols))
+	for _, col := range cols {
+		if col.Name == "" {
+			continue
+		}
+		scopeCols = append(scopeCols, scopeColumn{
+			name:        col.Name,
+			owner:       rel.alias,
+			goType:      col.GoType,
+			nullable:    col.Nullable,
+			importPath:  col.Import,
+			packageName: col.Package,
+			enum:        col.Enum,
+			domain:      col.Domain,
+			nullStyle:   col.NullStyle,
+			storage:     col.Storage,
+		})
+	}
+	return newCTEEntry(rel.alias, scopeCols), diags
+}
+
+// withoutDerivedTables returns tokens without the subqueries of the derived
+// tables in their FROM clauses, which are analyzed in scopes of their own.
+func withoutDerivedTables(tokens []tokenizer.Token) []tokenizer.Token {
+	type position struct{ line, column int }
+	skip := make(map[position]position)
+	for _, rel := range parseFromClauses(tokens).relations {
+		if rel.subquery == nil {
+			continue
+		}
+		first := rel.subquery[0]
+		last := rel.subquery[len(rel.subquery)-1]
+		skip[position{first.Line, first.Column}] = position{last.Line, last.Column}
+	}
+	if len(skip) == 0 {
+		return tokens
+	}
+
+	out := make([]tokenizer.Token, 0, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		end, ok := skip[position{tok.Line, tok.Column}]
+		if !ok {
+			out = append(out, tok)
+			continue
+		}
+		for i < len(tokens) && (tokens[i].Line != end.line || tokens[i].Column != end.column) {
+			i++
+		}
+	}
+	return out
+}
+
+// maskOutside replaces each rune of sql outside [start, end) with a space,
+// keeping line breaks so that line and column positions are unchanged.
+func maskOutside(sql string, start, end int) string {
+	var b strings.Builder
+	b.Grow(len(sql))
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		if (i < start || i >= end) && c != '\n' && c != '\r' {
+			if c >= 0x80 && c < 0xC0 {
+				// Continuation byte of a rune already replaced.
+				continue
+			}
+			c = ' '
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
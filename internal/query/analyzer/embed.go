@@ -0,0 +1,61 @@
+package analyzer
+
+import (
+	"fmt"
+
+	"github.com/electwix/db-catalyst/internal/query/block"
+	"github.com/electwix/db-catalyst/internal/query/parser"
+)
+
+// Embed describes a table whose columns a sqlc.embed(name) result groups
+// into one nested model. The columns of an embed share the same *Embed.
+type Embed struct {
+	Table    string // Schema table the nested model is generated from
+	Nullable bool   // Whether an outer join can leave every column NULL
+}
+
+// resolveEmbed expands sqlc.embed(name) to the columns of the named table.
+// The embed is nullable when a column the schema declares NOT NULL became
+// nullable in scope, which happens on the null-extended side of outer joins.
+func (a *Analyzer) resolveEmbed(col parser.Column, scope, relations *queryScope, blk block.Block, hasCatalog bool) ([]ResultColumn, []Diagnostic) {
+	fail := func(format string, args ...any) ([]ResultColumn, []Diagnostic) {
+		return nil, []Diagnostic{{
+			Path:     blk.Path,
+			Line:     col.Line,
+			Column:   col.Column,
+			Message:  fmt.Sprintf(format, args...),
+			Severity: SeverityError,
+		}}
+	}
+
+	if !hasCatalog {
+		return fail("sqlc.embed(%s) requires a schema catalog", col.Embed)
+	}
+	entry, ok := scope.get(col.Embed)
+	if !ok {
+		return fail("sqlc.embed references unknown table %q", col.Embed)
+	}
+	table := lookupTable(a.Catalog, entry.name)
+	if table == nil {
+		return fail("sqlc.embed(%s) must name a table, not a CTE or subquery", col.Embed)
+	}
+
+	embed := &Embed{Table: table.Name}
+	base, _ := relations.get(col.Embed)
+	for i, sc := range entry.columns {
+		notNull := i < len(table.Columns) && table.Columns[i].NotNull
+		if base != nil && i < len(base.columns) {
+			notNull = !base.columns[i].nullable
+		}
+		if notNull && sc.nullable {
+			embed.Nullable = true
+			break
+		}
+	}
+
+	cols := entryToResultColumns(entry)
+	for i := range cols {
+		cols[i].Embed = embed
+	}
+	return cols, nil
+}
@@ -0,0 +1,1090 @@
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/electwix/db-catalyst/internal/config"
+	"github.com/electwix/db-catalyst/internal/engine"
+	"github.com/electwix/db-catalyst/internal/schema/model"
+	"github.com/electwix/db-catalyst/internal/schema/tokenizer"
+)
+
+// exprType is the inferred type of a SQL expression. An empty goType means the
+// type is unknown (for example a bare parameter), which lets the other operand
+// of a binary operator decide the result type.
+type exprType struct {
+	goType   string
+	nullable bool
+	null     bool // the NULL literal: no type of its own, always null
+	// nonNullAggregate marks the result of an aggregate call outside of a
+	// window that is never NULL, such as COUNT.
+	nonNullAggregate bool
+	importPath       string
+	packageName      string
+	enum             *model.Enum
+	domain           *model.Domain
+	storage          config.SQLiteStorage
+}
+
+func (e exprType) known() bool {
+	return e.goType != "" && e.goType != "any"
+}
+
+// exprTyper infers Go types for SQL expressions by parsing them with SQL
+// operator precedence. Column references resolve against scope; scalar
+// subqueries additionally see the relations in relations.
+type exprTyper struct {
+	scope       *queryScope
+	relations   *queryScope
+	resolver    TypeResolver
+	customTypes map[string]config.CustomTypeMapping
+	functions   *engine.FunctionCatalog
+
+	tokens []tokenizer.Token
+	pos    int
+	failed bool
+}
+
+// typeExpr infers the type of expr using the analyzer's resolver and custom types.
+func (a *Analyzer) typeExpr(expr string, scope, relations *queryScope) (exprType, bool) {
+	t := &exprTyper{
+		scope:       scope,
+		relations:   relations,
+		resolver:    a.typeResolver,
+		customTypes: a.CustomTypes,
+		functions:   a.functionCatalog(),
+	}
+	return t.typeString(expr)
+}
+
+func (t *exprTyper) typeString(expr string) (exprType, bool) {
+	trimmed := strings.TrimSpace(expr)
+	if trimmed == "" {
+		return exprType{}, false
+	}
+	tokens, err := tokenizer.Scan("", []byte(trimmed), false)
+	if err != nil {
+		return exprType{}, false
+	}
+	return t.typeTokens(tokens)
+}
+
+// typeTokens types a complete expression. It fails if the tokens do not form a
+// single expression, reference an unresolvable column, or have no known type.
+func (t *exprTyper) typeTokens(tokens []tokenizer.Token) (exprType, bool) {
+	child := &exprTyper{
+		scope:       t.scope,
+		relations:   t.relations,
+		resolver:    t.resolver,
+		customTypes: t.customTypes,
+		functions:   t.functions,
+		tokens:      filterExprTokens(tokens),
+	}
+	if len(child.tokens) == 0 {
+		return exprType{}, false
+	}
+	res := child.parseExpr()
+	if child.failed || child.pos != len(child.tokens) {
+		return exprType{}, false
+	}
+	if res.null {
+		return exprType{goType: "any", nullable: true, null: true}, true
+	}
+	if !res.known() {
+		return exprType{}, false
+	}
+	return res, true
+}
+
+func filterExprTokens(tokens []tokenizer.Token) []tokenizer.Token {
+	filtered := make([]tokenizer.Token, 0, len(tokens))
+	for _, tok := range tokens {
+		if tok.Kind == tokenizer.KindEOF || tok.Kind == tokenizer.KindDocComment {
+			continue
+		}
+		filtered = append(filtered, tok)
+	}
+	return filtered
+}
+
+func (t *exprTyper) peek() (tokenizer.Token, bool) {
+	if t.pos >= len(t.tokens) {
+		return tokenizer.Token{}, false
+	}
+	return t.tokens[t.pos], true
+}
+
+func (t *exprTyper) peekAt(offset int) (tokenizer.Token, bool) {
+	idx := t.pos + offset
+	if idx < 0 || idx >= len(t.tokens) {
+		return tokenizer.Token{}, false
+	}
+	return t.tokens[idx], true
+}
+
+// isWord reports whether tok is the bare keyword or identifier word.
+func isWord(tok tokenizer.Token, word string) bool {
+	if tok.Kind != tokenizer.KindKeyword && tok.Kind != tokenizer.KindIdentifier {
+		return false
+	}
+	// Quoted identifiers are never keywords.
+	if tok.Text != "" && (tok.Text[0] == '"' || tok.Text[0] == '`' || tok.Text[0] == '[') {
+		return false
+	}
+	return strings.EqualFold(tok.Text, word)
+}
+
+func isSymbol(tok tokenizer.Token, sym string) bool {
+	return tok.Kind == tokenizer.KindSymbol && tok.Text == sym
+}
+
+func (t *exprTyper) acceptWord(word string) bool {
+	if tok, ok := t.peek(); ok && isWord(tok, word) {
+		t.pos++
+		return true
+	}
+	return false
+}
+
+func (t *exprTyper) acceptSymbol(sym string) bool {
+	if tok, ok := t.peek(); ok && isSymbol(tok, sym) {
+		t.pos++
+		return true
+	}
+	return false
+}
+
+// acceptOperator consumes a possibly multi-character operator that the
+// tokenizer split into adjacent single-character symbols (||, <<, >>, ->, ->>).
+func (t *exprTyper) acceptOperator(op string) bool {
+	if len(op) == 1 || op == "<=" || op == ">=" || op == "<>" || op == "!=" || op == "==" || op == "::" {
+		return t.acceptSymbol(op)
+	}
+	for i := range len(op) {
+		tok, ok := t.peekAt(i)
+		if !ok || !isSymbol(tok, string(op[i])) {
+			return false
+		}
+		if i > 0 {
+			prev := t.tokens[t.pos+i-1]
+			if tok.Line != prev.Line || tok.Column != prev.Column+1 {
+				return false
+			}
+		}
+	}
+	// Do not split a longer operator: "->" must not match the start of "->>".
+	if next, ok := t.peekAt(len(op)); ok && next.Kind == tokenizer.KindSymbol &&
+		(next.Text == ">" || next.Text == "|") {
+		last := t.tokens[t.pos+len(op)-1]
+		if next.Line == last.Line && next.Column == last.Column+1 {
+			return false
+		}
+	}
+	t.pos += len(op)
+	return true
+}
+
+func (t *exprTyper) fail() exprType {
+	t.failed = true
+	return exprType{}
+}
+
+func (t *exprTyper) parseExpr() exprType {
+	return t.parseOr()
+}
+
+func (t *exprTyper) parseOr() exprType {
+	left := t.parseAnd()
+	for !t.failed && t.acceptWord("OR") {
+		right := t.parseAnd()
+		left = boolResult(left.nullable || right.nullable)
+	}
+	return left
+}
+
+func (t *exprTyper) parseAnd() exprType {
+	left := t.parseNot()
+	for !t.failed && t.acceptWord("AND") {
+		right := t.parseNot()
+		left = boolResult(left.nullable || right.nullable)
+	}
+	return left
+}
+
+func (t *exprTyper) parseNot() exprType {
+	if t.acceptWord("NOT") {
+		operand := t.parseNot()
+		return boolResult(operand.nullable)
+	}
+	return t.parseComparison()
+}
+
+func boolResult(nullable bool) exprType {
+	return exprType{goType: "bool", nullable: nullable}
+}
+
+func (t *exprTyper) parseComparison() exprType {
+	left := t.parseBitwise()
+	for !t.failed {
+		tok, ok := t.peek()
+		if !ok {
+			return left
+		}
+		switch {
+		case isSymbol(tok, "="), isSymbol(tok, "=="), isSymbol(tok, "!="), isSymbol(tok, "<>"),
+			isSymbol(tok, "<"), isSymbol(tok, "<="), isSymbol(tok, ">"), isSymbol(tok, ">="):
+			t.pos++
+			right := t.parseBitwise()
+			left = boolResult(left.nullable || right.nullable)
+		case isWord(tok, "IS"):
+			t.pos++
+			t.acceptWord("NOT")
+			if t.acceptWord("DISTINCT") && !t.acceptWord("FROM") {
+				return t.fail()
+			}
+			t.parseBitwise()
+			left = boolResult(false)
+		case isWord(tok, "ISNULL"), isWord(tok, "NOTNULL"):
+			t.pos++
+			left = boolResult(false)
+		case isWord(tok, "NOT"):
+			next, ok := t.peekAt(1)
+			if !ok {
+				return left
+			}
+			if isWord(next, "NULL") {
+				t.pos += 2
+				left = boolResult(false)
+				continue
+			}
+			if !isWord(next, "IN") && !isWord(next, "BETWEEN") && !isPatternWord(next) {
+				return left
+			}
+			t.pos++
+		case isWord(tok, "IN"):
+			t.pos++
+			right := t.parseInList()
+			left = boolResult(left.nullable || right.nullable)
+		case isWord(tok, "BETWEEN"):
+			t.pos++
+			low := t.parseBitwise()
+			if !t.acceptWord("AND") {
+				return t.fail()
+			}
+			high := t.parseBitwise()
+			left = boolResult(left.nullable || low.nullable || high.nullable)
+		case isPatternWord(tok):
+			t.pos++
+			right := t.parseBitwise()
+			nullable := left.nullable || right.nullable
+			if t.acceptWord("ESCAPE") {
+				esc := t.parseBitwise()
+				nullable = nullable || esc.nullable
+			}
+			left = boolResult(nullable)
+		default:
+			return left
+		}
+	}
+	return left
+}
+
+func isPatternWord(tok tokenizer.Token) bool {
+	return isWord(tok, "LIKE") || isWord(tok, "ILIKE") || isWord(tok, "GLOB") ||
+		isWord(tok, "REGEXP") || isWord(tok, "MATCH")
+}
+
+// parseInList consumes the parenthesised list or subquery following IN.
+func (t *exprTyper) parseInList() exprType {
+	if !t.acceptSymbol("(") {
+		// SQLite allows IN table-name; PostgreSQL allows IN with a parameter array.
+		if tok, ok := t.peek(); ok && (tok.Kind == tokenizer.KindIdentifier || tok.Kind == tokenizer.KindParam) {
+			t.pos++
+			return exprType{}
+		}
+		return t.fail()
+	}
+	if tok, ok := t.peek(); ok && (isWord(tok, "SELECT") || isWord(tok, "WITH") || isWord(tok, "VALUES")) {
+		t.skipToClose()
+		return exprType{}
+	}
+	var res exprType
+	if t.acceptSymbol(")") {
+		return res
+	}
+	for !t.failed {
+		item := t.parseExpr()
+		res.nullable = res.nullable || item.nullable
+		if t.acceptSymbol(",") {
+			continue
+		}
+		if !t.acceptSymbol(")") {
+			return t.fail()
+		}
+		break
+	}
+	return res
+}
+
+// skipToClose advances past the ")" matching an already consumed "(".
+func (t *exprTyper) skipToClose() {
+	depth := 1
+	for t.pos < len(t.tokens) {
+		tok := t.tokens[t.pos]
+		t.pos++
+		if tok.Kind != tokenizer.KindSymbol {
+			continue
+		}
+		switch tok.Text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return
+			}
+		}
+	}
+	t.failed = true
+}
+
+func (t *exprTyper) parseBitwise() exprType {
+	left := t.parseAdditive()
+	for !t.failed {
+		switch {
+		case t.acceptOperator("<<"), t.acceptOperator(">>"), t.acceptSymbol("&"):
+		case t.isSingleBar() && t.acceptSymbol("|"):
+		default:
+			return left
+		}
+		right := t.parseAdditive()
+		left = exprType{goType: "int64", nullable: left.nullable || right.nullable}
+	}
+	return left
+}
+
+// isSingleBar reports whether the next token is a lone "|" rather than the start of "||".
+func (t *exprTyper) isSingleBar() bool {
+	tok, ok := t.peek()
+	if !ok || !isSymbol(tok, "|") {
+		return false
+	}
+	next, ok := t.peekAt(1)
+	return !ok || !isSymbol(next, "|") || next.Line != tok.Line || next.Column != tok.Column+1
+}
+
+func (t *exprTyper) parseAdditive() exprType {
+	left := t.parseMultiplicative()
+	for !t.failed {
+		tok, ok := t.peek()
+		if !ok || (!isSymbol(tok, "+") && !isSymbol(tok, "-")) {
+			return left
+		}
+		if isSymbol(tok, "-") {
+			if next, ok := t.peekAt(1); ok && isSymbol(next, ">") && next.Line == tok.Line && next.Column == tok.Column+1 {
+				return left
+			}
+		}
+		t.pos++
+		right := t.parseMultiplicative()
+		left = arithmeticResult(left, right)
+	}
+	return left
+}
+
+func (t *exprTyper) parseMultiplicative() exprType {
+	left := t.parseConcat()
+	for !t.failed {
+		tok, ok := t.peek()
+		if !ok {
+			return left
+		}
+		switch {
+		case isSymbol(tok, "*"), isSymbol(tok, "/"):
+			t.pos++
+			right := t.parseConcat()
+			left = arithmeticResult(left, right)
+		case isSymbol(tok, "%"):
+			t.pos++
+			right := t.parseConcat()
+			res := arithmeticResult(left, right)
+			if isIntegerGoType(res.goType) || isFloatGoType(res.goType) {
+				res.goType = "int64"
+			}
+			left = res
+		default:
+			return left
+		}
+	}
+	return left
+}
+
+// parseConcat parses string concatenation and the JSON extraction operators,
+// which share SQLite's highest binary precedence. ->> and #>> extract text;
+// -> and #> extract JSON, which may be missing from the document.
+func (t *exprTyper) parseConcat() exprType {
+	left := t.parseUnary()
+	for !t.failed {
+		switch {
+		case t.acceptOperator("||"):
+			right := t.parseUnary()
+			left = exprType{goType: "string", nullable: left.nullable || right.nullable || left.null || right.null}
+		case t.acceptOperator("->>"), t.acceptOperator("#>>"):
+			t.parseUnary()
+			left = exprType{goType: "string", nullable: true}
+		case t.acceptOperator("->"), t.acceptOperator("#>"):
+			t.parseUnary()
+			left = exprType{goType: t.jsonGoType(), nullable: true}
+		default:
+			return left
+		}
+	}
+	return left
+}
+
+// jsonGoType returns the Go type of JSON values, which is the type of a JSON
+// column in the current dialect. SQLite returns JSON as text.
+func (t *exprTyper) jsonGoType() string {
+	if goType := sqlTypeToGo("JSON", t.resolver, t.customTypes); goType != "any" {
+		return goType
+	}
+	return "string"
+}
+
+func (t *exprTyper) parseUnary() exprType {
+	switch {
+	case t.acceptSymbol("-"), t.acceptSymbol("+"):
+		return t.parseUnary()
+	case t.acceptSymbol("~"):
+		operand := t.parseUnary()
+		return exprType{goType: "int64", nullable: operand.nullable || operand.null}
+	}
+	return t.parsePostfix()
+}
+
+func (t *exprTyper) parsePostfix() exprType {
+	res := t.parsePrimary()
+	for !t.failed {
+		switch {
+		case t.acceptSymbol("::"):
+			typeName := t.parseTypeName()
+			if typeName == "" {
+				return t.fail()
+			}
+			res = t.castTo(res, typeName)
+		case t.acceptWord("COLLATE"):
+			if _, ok := t.peek(); !ok {
+				return t.fail()
+			}
+			t.pos++
+		default:
+			return res
+		}
+	}
+	return res
+}
+
+// parseTypeName consumes a SQL type such as "VARCHAR(20)", "DOUBLE PRECISION" or "INT[]".
+func (t *exprTyper) parseTypeName() string {
+	var words []string
+	for {
+		tok, ok := t.peek()
+		if !ok || (tok.Kind != tokenizer.KindIdentifier && tok.Kind != tokenizer.KindKeyword) || isWord(tok, "AS") {
+			break
+		}
+		words = append(words, tokenizer.NormalizeIdentifier(tok.Text))
+		t.pos++
+	}
+	if len(words) == 0 {
+		return ""
+	}
+	if t.acceptSymbol("(") {
+		t.skipToClose()
+	}
+	name := strings.Join(words, " ")
+	for t.acceptSymbol("[") {
+		if !t.acceptSymbol("]") {
+			t.failed = true
+			return ""
+		}
+		name += "[]"
+	}
+	return name
+}
+
+func (t *exprTyper) castTo(operand exprType, typeName string) exprType {
+	goType := sqlTypeToGo(typeName, t.resolver, t.customTypes)
+	if goType == "any" {
+		return exprType{nullable: operand.nullable || operand.null}
+	}
+	return exprType{goType: goType, nullable: operand.nullable || operand.null}
+}
+
+func (t *exprTyper) parsePrimary() exprType {
+	tok, ok := t.peek()
+	if !ok {
+		return t.fail()
+	}
+
+	switch tok.Kind {
+	case tokenizer.KindNumber:
+		t.pos++
+		info, _ := inferTypeFromNumber(tok.Text)
+		return exprType{goType: info.goType}
+	case tokenizer.KindString:
+		t.pos++
+		return exprType{goType: "string"}
+	case tokenizer.KindBlob:
+		t.pos++
+		return exprType{goType: "[]byte"}
+	case tokenizer.KindParam:
+		t.pos++
+		return exprType{}
+	case tokenizer.KindSymbol:
+		return t.parseSymbolPrimary(tok)
+	case tokenizer.KindKeyword, tokenizer.KindIdentifier:
+		return t.parseWordPrimary(tok)
+	default:
+		return t.fail()
+	}
+}
+
+func (t *exprTyper) parseSymbolPrimary(tok tokenizer.Token) exprType {
+	switch tok.Text {
+	case "?":
+		t.pos++
+		if next, ok := t.peek(); ok && next.Kind == tokenizer.KindNumber && next.Column == tok.Column+1 {
+			t.pos++
+		}
+		return exprType{}
+	case ":", "@", "$":
+		t.pos++
+		if next, ok := t.peek(); ok && next.Kind == tokenizer.KindIdentifier {
+			t.pos++
+			return exprType{}
+		}
+		return t.fail()
+	case "(":
+		t.pos++
+		if next, ok := t.peek(); ok && (isWord(next, "SELECT") || isWord(next, "WITH") || isWord(next, "VALUES")) {
+			return t.parseSubquery()
+		}
+		inner := t.parseExpr()
+		if t.acceptSymbol(",") {
+			// Row value such as (a, b): usable in comparisons but has no scalar type.
+			for !t.failed {
+				t.parseExpr()
+				if !t.acceptSymbol(",") {
+					break
+				}
+			}
+			inner = exprType{nullable: inner.nullable}
+		}
+		if !t.acceptSymbol(")") {
+			return t.fail()
+		}
+		return inner
+	default:
+		return t.fail()
+	}
+}
+
+func (t *exprTyper) parseWordPrimary(tok tokenizer.Token) exprType {
+	switch {
+	case isWord(tok, "NULL"):
+		t.pos++
+		return exprType{nullable: true, null: true}
+	case isWord(tok, "TRUE"), isWord(tok, "FALSE"):
+		t.pos++
+		return exprType{goType: "bool"}
+	case isWord(tok, "CURRENT_TIMESTAMP"), isWord(tok, "CURRENT_DATE"), isWord(tok, "CURRENT_TIME"):
+		t.pos++
+		return exprType{goType: "string"}
+	case isWord(tok, "CASE"):
+		t.pos++
+		return t.parseCase()
+	case isWord(tok, "CAST"):
+		if next, ok := t.peekAt(1); ok && isSymbol(next, "(") {
+			t.pos += 2
+			return t.parseCast()
+		}
+	case isWord(tok, "EXISTS"):
+		if next, ok := t.peekAt(1); ok && isSymbol(next, "(") {
+			t.pos += 2
+			t.skipToClose()
+			return boolResult(false)
+		}
+	}
+
+	if isWord(tok, "SQLC") {
+		if res, ok := t.parseSQLCMacro(); ok {
+			return res
+		}
+	}
+
+	if next, ok := t.peekAt(1); ok && isSymbol(next, "(") {
+		return t.parseFunctionCall()
+	}
+	if isReservedExprWord(tok) {
+		return t.fail()
+	}
+	return t.parseColumnRef()
+}
+
+// isReservedExprWord reports words that terminate or structure expressions and
+// therefore can never be column references.
+func isReservedExprWord(tok tokenizer.Token) bool {
+	for _, w := range []string{
+		"SELECT", "FROM", "WHERE", "AND", "OR", "NOT", "WHEN", "THEN", "ELSE", "END",
+		"AS", "IS", "IN", "BETWEEN", "LIKE", "ON", "JOIN", "GROUP", "ORDER", "BY", "LIMIT", "OFFSET",
+	} {
+		if isWord(tok, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSQLCMacro types sqlc.arg(x), sqlc.narg(x) and sqlc.slice(x) as parameters.
+func (t *exprTyper) parseSQLCMacro() (exprType, bool) {
+	dot, ok1 := t.peekAt(1)
+	name, ok2 := t.peekAt(2)
+	open, ok3 := t.peekAt(3)
+	if !ok1 || !ok2 || !ok3 || !isSymbol(dot, ".") || !isSymbol(open, "(") {
+		return exprType{}, false
+	}
+	var res exprType
+	switch {
+	case isWord(name, "ARG"), isWord(name, "SLICE"):
+	case isWord(name, "NARG"):
+		res.nullable = true
+	default:
+		return exprType{}, false
+	}
+	t.pos += 4
+	t.skipToClose()
+	return res, true
+}
+
+func (t *exprTyper) parseColumnRef() exprType {
+	parts := []string{tokenizer.NormalizeIdentifier(t.tokens[t.pos].Text)}
+	t.pos++
+	for {
+		dot, ok := t.peek()
+		if !ok || !isSymbol(dot, ".") {
+			break
+		}
+		next, ok := t.peekAt(1)
+		if !ok || (next.Kind != tokenizer.KindIdentifier && next.Kind != tokenizer.KindKeyword) {
+			break
+		}
+		parts = append(parts, tokenizer.NormalizeIdentifier(next.Text))
+		t.pos += 2
+	}
+
+	alias := ""
+	column := parts[len(parts)-1]
+	if len(parts) > 1 {
+		alias = parts[len(parts)-2]
+	}
+
+	// Without a scope (type-only inference) column references are simply untyped.
+	if t.scope == nil {
+		return exprType{}
+	}
+	col, _, res := t.scope.lookup(alias, column)
+	if res != scopeLookupOK {
+		return t.fail()
+	}
+	if col.goType == "any" {
+		return exprType{nullable: col.nullable}
+	}
+	return exprType{
+		goType:      col.goType,
+		nullable:    col.nullable,
+		importPath:  col.importPath,
+		packageName: col.packageName,
+		enum:        col.enum,
+		domain:      col.domain,
+		storage:     col.storage,
+	}
+}
+
+func (t *exprTyper) parseCase() exprType {
+	// Simple CASE: the operand is compared against each WHEN value.
+	if tok, ok := t.peek(); ok && !isWord(tok, "WHEN") {
+		t.parseExpr()
+	}
+	var branches []exprType
+	sawWhen := false
+	for !t.failed && t.acceptWord("WHEN") {
+		sawWhen = true
+		t.parseExpr()
+		if !t.acceptWord("THEN") {
+			return t.fail()
+		}
+		branches = append(branches, t.parseExpr())
+	}
+	if !sawWhen {
+		return t.fail()
+	}
+	hasElse := false
+	if t.acceptWord("ELSE") {
+		hasElse = true
+		branches = append(branches, t.parseExpr())
+	}
+	if !t.acceptWord("END") {
+		return t.fail()
+	}
+	res := unifyTypes(branches)
+	if !hasElse {
+		res.nullable = true
+	}
+	return res
+}
+
+func (t *exprTyper) parseCast() exprType {
+	operand := t.parseExpr()
+	if t.failed || !t.acceptWord("AS") {
+		return t.fail()
+	}
+	typeName := t.parseTypeName()
+	if typeName == "" || !t.acceptSymbol(")") {
+		return t.fail()
+	}
+	return t.castTo(operand, typeName)
+}
+
+// parseSubquery types a scalar subquery whose opening "(" was already consumed.
+func (t *exprTyper) parseSubquery() exprType {
+	start := t.pos
+	t.skipToClose()
+	if t.failed {
+		return exprType{}
+	}
+	inner := t.tokens[start : t.pos-1]
+	res, ok := t.typeScalarSubquery(inner)
+	if !ok {
+		return exprType{nullable: true}
+	}
+	return res
+}
+
+// typeScalarSubquery types the first projected column of a SELECT. The result
+// is nullable because the subquery may produce no row or a NULL aggregate,
+// except for ungrouped aggregates such as COUNT(*) that always yield exactly
+// one non-NULL value.
+func (t *exprTyper) typeScalarSubquery(tokens []tokenizer.Token) (exprType, bool) {
+	selectIdx := -1
+	for i, tok := range tokens {
+		if isWord(tok, "SELECT") {
+			selectIdx = i
+			break
+		}
+	}
+	if selectIdx < 0 {
+		return exprType{}, false
+	}
+	exprStart := selectIdx + 1
+	if exprStart < len(tokens) && (isWord(tokens[exprStart], "DISTINCT") || isWord(tokens[exprStart], "ALL")) {
+		exprStart++
+	}
+
+	exprEnd := len(tokens)
+	grouped := false
+	depth := 0
+	for i := exprStart; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.Kind == tokenizer.KindSymbol {
+			switch tok.Text {
+			case "(":
+				depth++
+			case ")":
+				depth--
+			case ",":
+				if depth == 0 && exprEnd == len(tokens) {
+					exprEnd = i
+				}
+			}
+			continue
+		}
+		if depth != 0 {
+			continue
+		}
+		switch {
+		case isWord(tok, "FROM"), isWord(tok, "WHERE"), isWord(tok, "UNION"), isWord(tok, "LIMIT"), isWord(tok, "ORDER"):
+			if exprEnd == len(tokens) {
+				exprEnd = i
+			}
+		case isWord(tok, "AS") && exprEnd == len(tokens):
+			exprEnd = i
+		case isWord(tok, "GROUP"):
+			grouped = true
+		}
+	}
+	if exprEnd <= exprStart {
+		return exprType{}, false
+	}
+
+	scope := t.scope.clone()
+	if t.relations != nil {
+		for _, name := range discoverReferencedRelations(tokens) {
+			if entry, ok := t.relations.get(name); ok {
+				scope.addEntry(name, entry)
+			}
+		}
+		addAliasesFromTokens(scope, tokens)
+	}
+
+	child := &exprTyper{
+		scope:       scope,
+		relations:   t.relations,
+		resolver:    t.resolver,
+		customTypes: t.customTypes,
+		functions:   t.functions,
+	}
+	res, ok := child.typeTokens(tokens[exprStart:exprEnd])
+	if !ok {
+		return exprType{}, false
+	}
+	if !res.nonNullAggregate || grouped {
+		res.nullable = true
+	}
+	res.nonNullAggregate = false
+	return res, true
+}
+
+func (t *exprTyper) parseFunctionCall() exprType {
+	name := strings.ToUpper(tokenizer.NormalizeIdentifier(t.tokens[t.pos].Text))
+	t.pos += 2 // name and "("
+
+	call := functionCall{name: name}
+	if t.acceptSymbol(")") {
+		return t.finishCall(call)
+	}
+	if t.acceptSymbol("*") {
+		call.star = true
+		if !t.acceptSymbol(")") {
+			return t.fail()
+		}
+		return t.finishCall(call)
+	}
+	if t.acceptWord("DISTINCT") || t.acceptWord("ALL") {
+		call.distinct = true
+	}
+	for !t.failed {
+		call.args = append(call.args, t.parseExpr())
+		if t.acceptSymbol(",") {
+			continue
+		}
+		if t.acceptSymbol(")") {
+			break
+		}
+		// Trailing clauses inside the call, e.g. ORDER BY in string_agg or SEPARATOR in GROUP_CONCAT.
+		if tok, ok := t.peek(); ok && (isWord(tok, "ORDER") || isWord(tok, "SEPARATOR")) {
+			t.skipToClose()
+			break
+		}
+		return t.fail()
+	}
+	if t.failed {
+		return exprType{}
+	}
+	return t.finishCall(call)
+}
+
+// finishCall consumes FILTER and OVER clauses following a call and types it.
+func (t *exprTyper) finishCall(call functionCall) exprType {
+	if t.acceptWord("FILTER") {
+		if !t.acceptSymbol("(") {
+			return t.fail()
+		}
+		t.skipToClose()
+	}
+	if t.acceptWord("OVER") {
+		call.window = true
+		if t.acceptSymbol("(") {
+			t.skipToClose()
+		} else if tok, ok := t.peek(); ok && tok.Kind == tokenizer.KindIdentifier {
+			t.pos++
+		} else {
+			return t.fail()
+		}
+	}
+	res, ok := t.typeFunction(call)
+	if !ok {
+		return exprType{}
+	}
+	if call.window {
+		res.nonNullAggregate = false
+	}
+	return res
+}
+
+// functionCall captures the typed arguments of a call expression.
+type functionCall struct {
+	name     string
+	args     []exprType
+	star     bool
+	distinct bool
+	window   bool
+}
+
+// typeFunction types a call using the signature from the function catalog.
+// Unknown functions, bad arity and untyped results leave the call untyped.
+func (t *exprTyper) typeFunction(call functionCall) (exprType, bool) {
+	catalog := t.functions
+	if catalog == nil {
+		catalog = defaultFunctions
+	}
+	fn, ok := catalog.Lookup(call.name)
+	if !ok {
+		return exprType{}, false
+	}
+	argCount := len(call.args)
+	if call.star {
+		argCount = 1
+	}
+	if !fn.Accepts(argCount) {
+		return exprType{}, false
+	}
+
+	var args []exprType
+	if fn.ArgOffset < len(call.args) {
+		args = call.args[fn.ArgOffset:]
+	}
+
+	var res exprType
+	switch fn.Result {
+	case engine.ResultFixed:
+		res.goType = fn.Returns
+		res.importPath = fn.Import
+		res.packageName = fn.Package
+		if res.goType == "" && fn.ReturnsSQL != "" {
+			res.goType = sqlTypeToGo(fn.ReturnsSQL, t.resolver, t.customTypes)
+		}
+	case engine.ResultArg, engine.ResultNumericArg, engine.ResultArrayOfArg:
+		if len(args) == 0 {
+			return exprType{}, false
+		}
+		res = args[0]
+		res.null = false
+		if fn.Result == engine.ResultNumericArg &&
+			!isIntegerGoType(res.goType) && !isFloatGoType(res.goType) && !isDecimalGoType(res.goType) {
+			return exprType{}, false
+		}
+		if fn.Result == engine.ResultArrayOfArg && res.known() {
+			res.goType = "[]" + res.goType
+		}
+	case engine.ResultCommonArgs:
+		res = unifyTypes(args)
+	}
+
+	null := fn.Null
+	if fn.Aggregate && fn.Variadic && argCount > 1 && null == engine.NullAlways {
+		// A scalar call of a variadic aggregate, such as SQLite's MIN(a, b),
+		// is NULL only when an argument is; the aggregate is NULL over no rows.
+		null = engine.NullStrict
+	}
+	switch null {
+	case engine.NullStrict:
+		res.nullable = anyNullable(args)
+	case engine.NullNever:
+		res.nullable = false
+	case engine.NullAlways:
+		res.nullable = true
+	case engine.NullAllArgs:
+		res.nullable = len(args) > 0
+		for _, arg := range args {
+			if !arg.nullable && !arg.null {
+				res.nullable = false
+				break
+			}
+		}
+	}
+
+	res.nonNullAggregate = fn.Aggregate && fn.Null == engine.NullNever && !call.window && (!fn.Variadic || argCount <= 1)
+	return res, res.known()
+}
+
+// anyNullable reports whether any of args may be NULL.
+func anyNullable(args []exprType) bool {
+	for _, arg := range args {
+		if arg.nullable || arg.null {
+			return true
+		}
+	}
+	return false
+}
+
+// unifyTypes combines the branch types of CASE-like expressions, widening
+// integers to floats. NULL branches make the result nullable without
+// contributing a type.
+func unifyTypes(types []exprType) exprType {
+	var res exprType
+	for _, typ := range types {
+		res.nullable = res.nullable || typ.nullable || typ.null
+		if typ.null || typ.goType == "" {
+			continue
+		}
+		if res.goType == "" {
+			res.goType = typ.goType
+			res.importPath = typ.importPath
+			res.packageName = typ.packageName
+			res.enum = typ.enum
+			res.domain = typ.domain
+			res.storage = typ.storage
+			continue
+		}
+		if res.enum != typ.enum || res.domain != typ.domain {
+			// Only branches of a single enum or domain keep its type.
+			res.enum = nil
+			res.domain = nil
+		}
+		if res.storage != typ.storage {
+			// Values stored differently cannot be scanned alike.
+			res.storage = ""
+			res.goType = "any"
+		}
+		res.goType = widenGoType(res.goType, typ.goType)
+		if res.goType != typ.goType {
+			continue
+		}
+		res.importPath = typ.importPath
+		res.packageName = typ.packageName
+	}
+	return res
+}
+
+// arithmeticResult types a binary arithmetic operation.
+func arithmeticResult(left, right exprType) exprType {
+	res := unifyTypes([]exprType{left, right})
+	if res.goType == "bool" {
+		res.goType = "int64"
+	}
+	return res
+}
+
+// widenGoType returns the common type of a and b, or "any" when they are incompatible.
+func widenGoType(a, b string) string {
+	switch {
+	case a == b:
+		return a
+	case a == "any" || b == "any":
+		return "any"
+	case isIntegerGoType(a) && isIntegerGoType(b):
+		return "int64"
+	case (isIntegerGoType(a) || isFloatGoType(a)) && (isIntegerGoType(b) || isFloatGoType(b)):
+		return "float64"
+	case isDecimalGoType(a) && (isIntegerGoType(b) || isFloatGoType(b)):
+		return a
+	case isDecimalGoType(b) && (isIntegerGoType(a) || isFloatGoType(a)):
+		return b
+	default:
+		return "any"
+	}
+}
+
+func isDecimalGoType(goType string) bool {
+	return strings.HasSuffix(goType, "Decimal") || strings.HasSuffix(goType, "Numeric")
+}
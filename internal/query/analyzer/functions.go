@@ -0,0 +1,153 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/electwix/db-catalyst/internal/query/parser"
+	"github.com/electwix/db-catalyst/internal/schema/tokenizer"
+)
+
+// callSyntaxWords are words that may be followed by "(" without being a function call.
+var callSyntaxWords = map[string]struct{}{
+	"ALL": {}, "AND": {}, "ANY": {}, "AS": {}, "CAST": {}, "CHECK": {}, "CONFLICT": {},
+	"EXISTS": {}, "FILTER": {}, "IN": {}, "JOIN": {}, "KEY": {}, "NOT": {}, "ON": {},
+	"OR": {}, "OVER": {}, "REFERENCES": {}, "RETURNING": {}, "ROW": {}, "SELECT": {},
+	"SOME": {}, "UNIQUE": {}, "USING": {}, "VALUES": {}, "WHERE": {}, "WITHIN": {},
+	"FROM": {}, "SET": {}, "THEN": {}, "ELSE": {}, "WHEN": {}, "LATERAL": {},
+}
+
+// callSpecialArgWords mark the SQL-standard keyword argument forms such as
+// SUBSTRING(s FROM 1 FOR 2) or EXTRACT(YEAR FROM ts), whose arity cannot be
+// read from the comma count.
+var callSpecialArgWords = map[string]struct{}{
+	"FROM": {}, "FOR": {}, "IN": {}, "AS": {}, "PLACING": {}, "BOTH": {}, "LEADING": {}, "TRAILING": {},
+}
+
+// validateFunctionCalls reports calls to functions missing from the function
+// catalog and calls with the wrong number of arguments.
+func (a *Analyzer) validateFunctionCalls(tokens []tokenizer.Token, scope *queryScope, q parser.Query) []Diagnostic {
+	catalog := a.functionCatalog()
+	var diags []Diagnostic
+
+	for i := 0; i+1 < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.Kind != tokenizer.KindIdentifier && tok.Kind != tokenizer.KindKeyword {
+			continue
+		}
+		if !isSymbol(tokens[i+1], "(") || !isCallPosition(tokens, i, scope) {
+			continue
+		}
+
+		name := tokenizer.NormalizeIdentifier(tok.Text)
+		argCount, special, closeIdx := countCallArgs(tokens, i+1)
+		if isCTEColumnList(tokens, i, closeIdx) {
+			continue
+		}
+
+		fn, ok := catalog.Lookup(name)
+		if !ok {
+			diags = append(diags, Diagnostic{
+				Path:     q.Block.Path,
+				Line:     actualTokenLine(q, tok),
+				Column:   tok.Column,
+				Message:  fmt.Sprintf("unknown function %s", name),
+				Severity: SeverityWarning,
+			})
+			continue
+		}
+		if special || fn.Accepts(argCount) {
+			continue
+		}
+		diags = append(diags, Diagnostic{
+			Path:     q.Block.Path,
+			Line:     actualTokenLine(q, tok),
+			Column:   tok.Column,
+			Message:  fmt.Sprintf("function %s called with %d arguments; expected %s", fn.Name, argCount, fn.Arity()),
+			Severity: SeverityError,
+		})
+	}
+	return diags
+}
+
+// isCallPosition reports whether the word at idx, which is followed by "(", names a function.
+func isCallPosition(tokens []tokenizer.Token, idx int, scope *queryScope) bool {
+	tok := tokens[idx]
+	if _, ok := callSyntaxWords[strings.ToUpper(tok.Text)]; ok && isWord(tok, tok.Text) {
+		return false
+	}
+	if idx == 0 {
+		return true
+	}
+	prev := tokens[idx-1]
+	switch {
+	case isSymbol(prev, "."), isSymbol(prev, "::"):
+		// Qualified calls (sqlc.arg, schema.fn) and sized type names.
+		return false
+	case isWord(prev, "AS"), isWord(prev, "INTO"), isWord(prev, "TABLE"), isWord(prev, "UPDATE"):
+		return false
+	}
+	// INSERT INTO t (cols) is covered above; relation names elsewhere are not calls either.
+	if _, ok := scope.get(tokenizer.NormalizeIdentifier(tok.Text)); ok {
+		return false
+	}
+	return true
+}
+
+// isCTEColumnList reports whether the word at idx, whose parenthesized list
+// closes at closeIdx, is a CTE name with a column list:
+// WITH name(a, b) AS [NOT] [MATERIALIZED] (...).
+func isCTEColumnList(tokens []tokenizer.Token, idx, closeIdx int) bool {
+	if idx == 0 || closeIdx+2 >= len(tokens) || !isWord(tokens[closeIdx+1], "AS") {
+		return false
+	}
+	prev := tokens[idx-1]
+	if !isWord(prev, "WITH") && !isWord(prev, "RECURSIVE") && !isSymbol(prev, ",") {
+		return false
+	}
+	next := tokens[closeIdx+2]
+	return isSymbol(next, "(") || isWord(next, "MATERIALIZED") || isWord(next, "NOT")
+}
+
+// countCallArgs counts the top-level arguments of the call whose "(" is at
+// openIdx. It also reports whether keyword argument syntax was used and the
+// index of the closing parenthesis.
+func countCallArgs(tokens []tokenizer.Token, openIdx int) (count int, special bool, closeIdx int) {
+	depth := 0
+	empty := true
+	for i := openIdx; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.Kind == tokenizer.KindSymbol {
+			switch tok.Text {
+			case "(":
+				depth++
+				if depth == 1 {
+					continue
+				}
+			case ")":
+				depth--
+				if depth == 0 {
+					if !empty {
+						count++
+					}
+					return count, special, i
+				}
+			case ",":
+				if depth == 1 {
+					count++
+					continue
+				}
+			}
+		}
+		if tok.Kind == tokenizer.KindEOF {
+			break
+		}
+		empty = false
+		if depth == 1 {
+			if _, ok := callSpecialArgWords[strings.ToUpper(tok.Text)]; ok && isWord(tok, tok.Text) {
+				special = true
+			}
+		}
+	}
+	return count, true, len(tokens) - 1
+}
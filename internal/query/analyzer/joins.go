@@ -0,0 +1,433 @@
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/electwix/db-catalyst/internal/schema/tokenizer"
+)
+
+// joinRelation is one relation of a FROM clause and whether an outer join can
+// null-extend its rows.
+type joinRelation struct {
+	name     string
+	alias    string
+	nullable bool
+	// subquery holds the tokens inside the parentheses of a derived table,
+	// with its column alias list and whether it is LATERAL.
+	subquery []tokenizer.Token
+	columns  []string
+	lateral  bool
+}
+
+// key returns the name the relation is referenced by in the query.
+func (r joinRelation) key() string {
+	if r.alias != "" {
+		return r.alias
+	}
+	return r.name
+}
+
+// fromClause collects the relations of a FROM clause and the conditions that
+// filter its rows: inner join ON conditions and the WHERE clause.
+type fromClause struct {
+	relations   []joinRelation
+	joinFilters [][]tokenizer.Token
+	where       [][]tokenizer.Token
+}
+
+// joinKind classifies the join operator in front of a relation.
+type joinKind int
+
+const (
+	joinInner joinKind = iota
+	joinLeft
+	joinRight
+	joinFull
+)
+
+// fromClauseEndWords end a FROM clause at its own nesting level.
+var fromClauseEndWords = map[string]struct{}{
+	"WHERE": {}, "GROUP": {}, "HAVING": {}, "WINDOW": {}, "ORDER": {}, "LIMIT": {},
+	"OFFSET": {}, "FETCH": {}, "FOR": {}, "UNION": {}, "INTERSECT": {}, "EXCEPT": {},
+	"RETURNING": {}, "SET": {}, "VALUES": {},
+}
+
+// joinWords introduce a join operator.
+var joinWords = map[string]struct{}{
+	"JOIN": {}, "LEFT": {}, "RIGHT": {}, "FULL": {}, "INNER": {}, "CROSS": {},
+	"NATURAL": {}, "OUTER": {}, "STRAIGHT_JOIN": {},
+}
+
+// applyJoinNullability adjusts column nullability in scope for the FROM and
+// WHERE clauses in tokens. Relations on the nullable side of an outer join
+// get all-nullable copies, then columns that the join conditions or WHERE
+// clause prove non-null are narrowed back.
+func (a *Analyzer) applyJoinNullability(scope *queryScope, tokens []tokenizer.Token) {
+	if scope == nil {
+		return
+	}
+	clause := parseFromClauses(tokens)
+
+	// A table referenced by name stays non-null if any occurrence is.
+	nameNullable := make(map[string]bool)
+	for _, rel := range clause.relations {
+		if rel.name == "" {
+			continue
+		}
+		name := normalizeIdent(rel.name)
+		if prev, seen := nameNullable[name]; seen {
+			nameNullable[name] = prev && rel.nullable
+		} else {
+			nameNullable[name] = rel.nullable
+		}
+	}
+
+	extended := make(map[*scopeEntry]*scopeEntry)
+	for _, rel := range clause.relations {
+		if !rel.nullable {
+			continue
+		}
+		key := rel.key()
+		entry, ok := scope.get(key)
+		if !ok {
+			continue
+		}
+		c, ok := extended[entry]
+		if !ok {
+			c = entry.withNullability(func(scopeColumn) bool { return true })
+			extended[entry] = c
+		}
+		scope.addEntry(key, c)
+		if rel.alias != "" && nameNullable[normalizeIdent(rel.name)] {
+			if named, ok := scope.get(rel.name); ok && named == entry {
+				scope.addEntry(rel.name, c)
+			}
+		}
+	}
+	nullExtended := make(map[*scopeEntry]struct{}, len(extended))
+	for _, c := range extended {
+		nullExtended[c] = struct{}{}
+	}
+
+	// Inner join conditions hold for every joined row, but a later outer
+	// join can still null-extend the relations they constrain.
+	for _, cond := range clause.joinFilters {
+		for _, ref := range nonNullRefs(cond) {
+			narrowColumn(scope, ref, nullExtended)
+		}
+	}
+	for _, cond := range clause.where {
+		for _, ref := range nonNullRefs(cond) {
+			narrowColumn(scope, ref, nil)
+		}
+	}
+}
+
+// withNullability returns a copy of the entry with each column's nullability
+// set by fn.
+func (e *scopeEntry) withNullability(fn func(scopeColumn) bool) *scopeEntry {
+	c := &scopeEntry{name: e.name, columnIndex: e.columnIndex}
+	c.columns = make([]scopeColumn, len(e.columns))
+	for i, col := range e.columns {
+		col.nullable = fn(col)
+		c.columns[i] = col
+	}
+	return c
+}
+
+// replaceEntry points every name bound to old at replacement.
+func (s *queryScope) replaceEntry(old, replacement *scopeEntry) {
+	for key, entry := range s.entries {
+		if entry == old {
+			s.entries[key] = replacement
+		}
+	}
+}
+
+// narrowColumn marks the referenced column non-null unless it belongs to one
+// of the skipped entries.
+func narrowColumn(scope *queryScope, ref columnRef, skip map[*scopeEntry]struct{}) {
+	col, entry, status := scope.lookup(ref.table, ref.column)
+	if status != scopeLookupOK || !col.nullable {
+		return
+	}
+	if _, ok := skip[entry]; ok {
+		return
+	}
+	target := normalizeIdent(col.name)
+	scope.replaceEntry(entry, entry.withNullability(func(c scopeColumn) bool {
+		return c.nullable && normalizeIdent(c.name) != target
+	}))
+}
+
+// parseFromClauses parses every top-level FROM and WHERE clause in tokens.
+func parseFromClauses(tokens []tokenizer.Token) fromClause {
+	var clause fromClause
+	depth := 0
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch {
+		case isSymbol(tok, "("):
+			depth++
+		case isSymbol(tok, ")"):
+			if depth > 0 {
+				depth--
+			}
+		case depth == 0 && isWord(tok, "FROM"):
+			found, next := parseJoinSequence(tokens, i+1)
+			clause.relations = append(clause.relations, found.relations...)
+			clause.joinFilters = append(clause.joinFilters, found.joinFilters...)
+			i = next - 1
+		case depth == 0 && isWord(tok, "WHERE"):
+			cond, next := scanCondition(tokens, i+1)
+			clause.where = append(clause.where, cond)
+			i = next - 1
+		}
+	}
+	return clause
+}
+
+// parseJoinSequence parses relations joined by commas and JOIN operators
+// starting at idx. It stops at the end of the FROM clause or at a closing
+// parenthesis, returning the index of that token.
+func parseJoinSequence(tokens []tokenizer.Token, idx int) (fromClause, int) {
+	var clause fromClause
+	kind := joinInner
+	i := idx
+	for i < len(tokens) {
+		item, next := parseJoinItem(tokens, i)
+
+		var cond []tokenizer.Token
+		i = next
+		if i < len(tokens) && isWord(tokens[i], "ON") {
+			cond, i = scanCondition(tokens, i+1)
+		}
+
+		switch kind {
+		case joinLeft:
+			markNullable(item.relations)
+		case joinRight:
+			markNullable(clause.relations)
+		case joinFull:
+			markNullable(item.relations)
+			markNullable(clause.relations)
+		case joinInner:
+			if cond != nil {
+				clause.joinFilters = append(clause.joinFilters, cond)
+			}
+		}
+		clause.relations = append(clause.relations, item.relations...)
+		clause.joinFilters = append(clause.joinFilters, item.joinFilters...)
+
+		// Skip USING lists and anything else up to the next join operator.
+		kind = joinInner
+		more := false
+		depth := 0
+		for ; i < len(tokens); i++ {
+			tok := tokens[i]
+			if tok.Kind == tokenizer.KindEOF || isSymbol(tok, ";") {
+				return clause, i
+			}
+			if isSymbol(tok, "(") {
+				depth++
+				continue
+			}
+			if isSymbol(tok, ")") {
+				if depth == 0 {
+					return clause, i
+				}
+				depth--
+				continue
+			}
+			if depth > 0 {
+				continue
+			}
+			if isSymbol(tok, ",") {
+				i++
+				more = true
+				break
+			}
+			if isClauseEnd(tok) {
+				return clause, i
+			}
+			if isJoinWord(tok) {
+				kind, i = parseJoinOperator(tokens, i)
+				more = true
+				break
+			}
+		}
+		if !more {
+			break
+		}
+	}
+	return clause, i
+}
+
+// scanCondition returns the tokens of a condition starting at idx, ending
+// before the next join operator or clause at the same nesting level.
+func scanCondition(tokens []tokenizer.Token, idx int) ([]tokenizer.Token, int) {
+	depth := 0
+	i := idx
+	for ; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.Kind == tokenizer.KindEOF || isSymbol(tok, ";") {
+			break
+		}
+		if isSymbol(tok, "(") {
+			depth++
+			continue
+		}
+		if isSymbol(tok, ")") {
+			if depth == 0 {
+				break
+			}
+			depth--
+			continue
+		}
+		if depth == 0 && (isSymbol(tok, ",") || isClauseEnd(tok) || isJoinWord(tok)) {
+			break
+		}
+	}
+	return tokens[idx:i], i
+}
+
+// parseJoinOperator reads a join operator such as LEFT OUTER JOIN starting at
+// idx and returns its kind and the index of the relation that follows.
+func parseJoinOperator(tokens []tokenizer.Token, idx int) (joinKind, int) {
+	kind := joinInner
+	i := idx
+	for ; i < len(tokens) && isJoinWord(tokens[i]); i++ {
+		switch strings.ToUpper(tokens[i].Text) {
+		case "LEFT":
+			kind = joinLeft
+		case "RIGHT":
+			kind = joinRight
+		case "FULL":
+			kind = joinFull
+		case "JOIN", "STRAIGHT_JOIN":
+			return kind, i + 1
+		}
+	}
+	return kind, i
+}
+
+// parseJoinItem parses a table, function, subquery or parenthesized join at
+// idx and returns its relations and the index following it.
+func parseJoinItem(tokens []tokenizer.Token, idx int) (fromClause, int) {
+	i := idx
+	lateral := false
+	for i < len(tokens) && (isWord(tokens[i], "LATERAL") || isWord(tokens[i], "ONLY")) {
+		lateral = lateral || isWord(tokens[i], "LATERAL")
+		i++
+	}
+	if i >= len(tokens) {
+		return fromClause{}, i
+	}
+
+	if isSymbol(tokens[i], "(") {
+		if i+1 < len(tokens) && (isWord(tokens[i+1], "SELECT") || isWord(tokens[i+1], "WITH") || isWord(tokens[i+1], "VALUES")) {
+			open := i
+			i = skipParens(tokens, i)
+			alias, columns, next := parseJoinAlias(tokens, i)
+			if alias == "" {
+				return fromClause{}, next
+			}
+			rel := joinRelation{alias: alias, columns: columns, lateral: lateral}
+			if i-1 > open+1 {
+				rel.subquery = tokens[open+1 : i-1]
+			}
+			return fromClause{relations: []joinRelation{rel}}, next
+		}
+		nested, closeIdx := parseJoinSequence(tokens, i+1)
+		i = closeIdx
+		if i < len(tokens) && isSymbol(tokens[i], ")") {
+			i++
+		}
+		_, _, i = parseJoinAlias(tokens, i)
+		return nested, i
+	}
+
+	tok := tokens[i]
+	if tok.Kind != tokenizer.KindIdentifier && tok.Kind != tokenizer.KindKeyword {
+		return fromClause{}, i
+	}
+	name := tokenizer.NormalizeIdentifier(tok.Text)
+	i++
+	for i+1 < len(tokens) && isSymbol(tokens[i], ".") {
+		name = tokenizer.NormalizeIdentifier(tokens[i+1].Text)
+		i += 2
+	}
+	if i < len(tokens) && isSymbol(tokens[i], "(") {
+		// Table-valued function such as json_each(...) or generate_series(...).
+		i = skipParens(tokens, i)
+		name = ""
+	}
+	alias, _, i := parseJoinAlias(tokens, i)
+	if name == "" && alias == "" {
+		return fromClause{}, i
+	}
+	return fromClause{relations: []joinRelation{{name: name, alias: alias}}}, i
+}
+
+// parseJoinAlias reads an optional [AS] alias at idx, ignoring clause words,
+// and the column alias list that may follow it.
+func parseJoinAlias(tokens []tokenizer.Token, idx int) (string, []string, int) {
+	i := idx
+	if i < len(tokens) && isWord(tokens[i], "AS") {
+		i++
+	}
+	if i >= len(tokens) {
+		return "", nil, i
+	}
+	tok := tokens[i]
+	if tok.Kind != tokenizer.KindIdentifier || isClauseEnd(tok) || isJoinWord(tok) ||
+		isWord(tok, "ON") || isWord(tok, "USING") {
+		return "", nil, i
+	}
+	i++
+	var columns []string
+	if i < len(tokens) && isSymbol(tokens[i], "(") {
+		// Column alias list: AS t(a, b).
+		end := skipParens(tokens, i)
+		for _, col := range tokens[i+1 : max(end-1, i+1)] {
+			if col.Kind == tokenizer.KindIdentifier || col.Kind == tokenizer.KindKeyword {
+				columns = append(columns, tokenizer.NormalizeIdentifier(col.Text))
+			}
+		}
+		i = end
+	}
+	return tokenizer.NormalizeIdentifier(tok.Text), columns, i
+}
+
+// skipParens returns the index after the parenthesis matching the one at idx.
+func skipParens(tokens []tokenizer.Token, idx int) int {
+	depth := 0
+	for i := idx; i < len(tokens); i++ {
+		switch {
+		case isSymbol(tokens[i], "("):
+			depth++
+		case isSymbol(tokens[i], ")"):
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(tokens)
+}
+
+func markNullable(rels []joinRelation) {
+	for i := range rels {
+		rels[i].nullable = true
+	}
+}
+
+func isClauseEnd(tok tokenizer.Token) bool {
+	_, ok := fromClauseEndWords[strings.ToUpper(tok.Text)]
+	return ok && isWord(tok, tok.Text)
+}
+
+func isJoinWord(tok tokenizer.Token) bool {
+	_, ok := joinWords[strings.ToUpper(tok.Text)]
+	return ok && isWord(tok, tok.Text)
+}
@@ -0,0 +1,201 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/electwix/db-catalyst/internal/query/block"
+	"github.com/electwix/db-catalyst/internal/schema/tokenizer"
+)
+
+// columnRef is a possibly qualified column reference.
+type columnRef struct {
+	table  string
+	column string
+}
+
+// comparisonOperators are the operators that are never true for NULL operands.
+var comparisonOperators = map[string]struct{}{
+	"=": {}, "==": {}, "<": {}, ">": {}, "<=": {}, ">=": {}, "<>": {}, "!=": {},
+}
+
+// nullRejectingWords follow a column in predicates that are never true when
+// the column is NULL.
+var nullRejectingWords = map[string]struct{}{
+	"LIKE": {}, "ILIKE": {}, "GLOB": {}, "REGEXP": {}, "MATCH": {}, "IN": {}, "BETWEEN": {},
+}
+
+// nonNullRefs returns the columns that cond guarantees are not NULL for every
+// row it accepts: operands of comparisons, IS NOT NULL tests, and LIKE, IN or
+// BETWEEN predicates in its top-level AND conjuncts.
+func nonNullRefs(cond []tokenizer.Token) []columnRef {
+	var refs []columnRef
+	for _, conj := range splitConjuncts(cond) {
+		if len(conj) > 1 && isSymbol(conj[0], "(") && skipParens(conj, 0) == len(conj) {
+			refs = append(refs, nonNullRefs(conj[1:len(conj)-1])...)
+			continue
+		}
+
+		if ref, next, ok := parseColumnRef(conj, 0); ok && next < len(conj) {
+			rest := conj[next:]
+			switch {
+			case len(rest) == 3 && isWord(rest[0], "IS") && isWord(rest[1], "NOT") && isWord(rest[2], "NULL"):
+				refs = append(refs, ref)
+				continue
+			case isNullRejectingWord(rest[0]),
+				len(rest) > 1 && isWord(rest[0], "NOT") && isNullRejectingWord(rest[1]):
+				refs = append(refs, ref)
+				continue
+			}
+		}
+
+		opIdx := comparisonIndex(conj)
+		if opIdx < 0 {
+			continue
+		}
+		if ref, ok := exactColumnRef(conj[:opIdx]); ok {
+			refs = append(refs, ref)
+		}
+		if ref, ok := exactColumnRef(conj[opIdx+1:]); ok {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// equalityRefs reports whether conj is an equality between two column references.
+func equalityRefs(conj []tokenizer.Token) (columnRef, columnRef, bool) {
+	opIdx := comparisonIndex(conj)
+	if opIdx < 0 || (conj[opIdx].Text != "=" && conj[opIdx].Text != "==") {
+		return columnRef{}, columnRef{}, false
+	}
+	left, ok := exactColumnRef(conj[:opIdx])
+	if !ok {
+		return columnRef{}, columnRef{}, false
+	}
+	right, ok := exactColumnRef(conj[opIdx+1:])
+	if !ok {
+		return columnRef{}, columnRef{}, false
+	}
+	return left, right, true
+}
+
+// splitConjuncts splits cond at its top-level AND operators, leaving the AND
+// of BETWEEN ... AND ... in place.
+func splitConjuncts(cond []tokenizer.Token) [][]tokenizer.Token {
+	var out [][]tokenizer.Token
+	depth := 0
+	start := 0
+	between := false
+	for i, tok := range cond {
+		switch {
+		case isSymbol(tok, "("):
+			depth++
+		case isSymbol(tok, ")"):
+			depth--
+		case depth != 0:
+		case isWord(tok, "BETWEEN"):
+			between = true
+		case isWord(tok, "AND"):
+			if between {
+				between = false
+				continue
+			}
+			if i > start {
+				out = append(out, cond[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if start < len(cond) {
+		out = append(out, cond[start:])
+	}
+	return out
+}
+
+// comparisonIndex returns the index of the first top-level comparison
+// operator in conj, or -1. MySQL's NULL-safe <=> is not a comparison here.
+func comparisonIndex(conj []tokenizer.Token) int {
+	depth := 0
+	for i, tok := range conj {
+		switch {
+		case isSymbol(tok, "("):
+			depth++
+		case isSymbol(tok, ")"):
+			depth--
+		case depth == 0 && tok.Kind == tokenizer.KindSymbol:
+			if _, ok := comparisonOperators[tok.Text]; !ok {
+				continue
+			}
+			if tok.Text == "<=" && i+1 < len(conj) && isSymbol(conj[i+1], ">") {
+				return -1
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// exactColumnRef reports whether tokens consist of a single column reference.
+func exactColumnRef(tokens []tokenizer.Token) (columnRef, bool) {
+	ref, next, ok := parseColumnRef(tokens, 0)
+	if !ok || next != len(tokens) {
+		return columnRef{}, false
+	}
+	return ref, true
+}
+
+// parseColumnRef parses column or table.column at idx.
+func parseColumnRef(tokens []tokenizer.Token, idx int) (columnRef, int, bool) {
+	if idx >= len(tokens) || tokens[idx].Kind != tokenizer.KindIdentifier {
+		return columnRef{}, idx, false
+	}
+	first := tokenizer.NormalizeIdentifier(tokens[idx].Text)
+	if idx+2 < len(tokens) && isSymbol(tokens[idx+1], ".") &&
+		(tokens[idx+2].Kind == tokenizer.KindIdentifier || tokens[idx+2].Kind == tokenizer.KindKeyword) {
+		return columnRef{table: first, column: tokenizer.NormalizeIdentifier(tokens[idx+2].Text)}, idx + 3, true
+	}
+	if idx+1 < len(tokens) && isSymbol(tokens[idx+1], "(") {
+		// Function call, not a column.
+		return columnRef{}, idx, false
+	}
+	return columnRef{column: first}, idx + 1, true
+}
+
+func isNullRejectingWord(tok tokenizer.Token) bool {
+	_, ok := nullRejectingWords[upperWord(tok)]
+	return ok
+}
+
+// upperWord returns the upper-cased text of a bare word token, or "".
+func upperWord(tok tokenizer.Token) string {
+	if !isWord(tok, tok.Text) {
+		return ""
+	}
+	return strings.ToUpper(tok.Text)
+}
+
+// applyNonNullAnnotations marks the result columns named by @nonnull comments
+// as non-null, for guarantees the analyzer cannot prove on its own.
+func applyNonNullAnnotations(cols []ResultColumn, blk block.Block) []Diagnostic {
+	var diags []Diagnostic
+	for _, name := range blk.NonNull {
+		found := false
+		for i := range cols {
+			if strings.EqualFold(cols[i].Name, name) {
+				cols[i].Nullable = false
+				found = true
+			}
+		}
+		if !found {
+			diags = append(diags, Diagnostic{
+				Path:     blk.Path,
+				Line:     blk.Line,
+				Column:   blk.Column,
+				Message:  fmt.Sprintf("@nonnull references unknown result column %q", name),
+				Severity: SeverityWarning,
+			})
+		}
+	}
+	return diags
+}
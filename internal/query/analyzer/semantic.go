@@ -0,0 +1,77 @@
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/electwix/db-catalyst/internal/config"
+	"github.com/electwix/db-catalyst/internal/schema/model"
+)
+
+// SetSQLiteTypes enables the SQLite semantic type mapping of [sqlite_types].
+func (a *Analyzer) SetSQLiteTypes(opts config.SQLiteTypes) {
+	a.sqliteTypes = opts
+}
+
+// SQLiteSemanticType returns the Go type of a SQLite column under
+// [sqlite_types] and how the column stores it. BOOLEAN columns map to bool,
+// JSON columns to json.RawMessage, and DATE, DATETIME and TIMESTAMP columns
+// to time.Time. INTEGER and REAL columns defaulting to unixepoch(),
+// strftime('%s') or julianday() hold times too. A time column whose default
+// reveals its storage uses that storage instead of the configured
+// time_encoding. ok is false for columns that keep their affinity type.
+func SQLiteSemanticType(col *model.Column, opts config.SQLiteTypes) (goType string, storage config.SQLiteStorage, ok bool) {
+	if !opts.Enabled || col == nil {
+		return "", "", false
+	}
+	declared := strings.ToUpper(col.Type)
+	defaultStorage, hasDefault := timeDefaultStorage(col.Default)
+	switch {
+	case strings.Contains(declared, "BOOL"):
+		return "bool", "", true
+	case strings.Contains(declared, "JSON"):
+		return "json.RawMessage", config.StorageJSON, true
+	case strings.Contains(declared, "DATE"), strings.Contains(declared, "TIMESTAMP"):
+		if hasDefault {
+			return "time.Time", defaultStorage, true
+		}
+		return "time.Time", opts.TimeEncoding, true
+	case !hasDefault:
+		return "", "", false
+	case strings.Contains(declared, "INT"):
+		if defaultStorage == config.StorageUnix || defaultStorage == config.StorageUnixMillis {
+			return "time.Time", defaultStorage, true
+		}
+	case strings.Contains(declared, "REAL"), strings.Contains(declared, "FLOA"), strings.Contains(declared, "DOUB"):
+		if defaultStorage == config.StorageJulianDay {
+			return "time.Time", defaultStorage, true
+		}
+	}
+	return "", "", false
+}
+
+// timeDefaultStorage recognizes DEFAULT expressions producing the current
+// time as a number, such as (unixepoch()) or (unixepoch('subsec') * 1000).
+func timeDefaultStorage(def *model.Value) (config.SQLiteStorage, bool) {
+	if def == nil {
+		return "", false
+	}
+	expr := strings.ToLower(strings.Join(strings.Fields(def.Text), ""))
+	switch {
+	case strings.Contains(expr, "unixepoch(") && strings.Contains(expr, "*1000"):
+		return config.StorageUnixMillis, true
+	case strings.Contains(expr, "unixepoch("), strings.Contains(expr, "strftime('%s'"):
+		return config.StorageUnix, true
+	case strings.Contains(expr, "julianday("):
+		return config.StorageJulianDay, true
+	}
+	return "", false
+}
+
+// storedType describes goType for diagnostics, naming the storage of
+// [sqlite_types] values that differ only in how they are stored.
+func storedType(goType string, storage config.SQLiteStorage) string {
+	if storage == "" {
+		return goType
+	}
+	return goType + " stored as " + string(storage)
+}
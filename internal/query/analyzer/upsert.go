@@ -0,0 +1,295 @@
+package analyzer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/electwix/db-catalyst/internal/query/parser"
+	"github.com/electwix/db-catalyst/internal/schema/model"
+	"github.com/electwix/db-catalyst/internal/schema/tokenizer"
+)
+
+// upsertClause is the ON CONFLICT or ON DUPLICATE KEY UPDATE clause of an
+// INSERT statement.
+type upsertClause struct {
+	// on is the ON token that starts the clause.
+	on tokenizer.Token
+	// target holds the conflict target columns, nil when the clause has no
+	// target or names a constraint instead.
+	target     []string
+	constraint string
+	// complexTarget marks targets with expressions, which are not checked.
+	complexTarget bool
+	duplicateKey  bool
+	// update holds the tokens of the assignment list, empty for DO NOTHING.
+	update []tokenizer.Token
+}
+
+// upsertEndWords end the assignment list of an upsert clause.
+var upsertEndWords = map[string]struct{}{
+	"WHERE": {}, "RETURNING": {}, "ON": {},
+}
+
+// resolveUpsert analyzes the upsert clauses of an INSERT statement in tokens.
+// ON CONFLICT makes the excluded pseudo-table available in scopes as an alias
+// of the target table. Conflict targets must match a primary key or unique
+// key of the table, assignments must name its columns and MySQL VALUES()
+// references must name its columns.
+func (a *Analyzer) resolveUpsert(tokens []tokenizer.Token, q parser.Query, hasCatalog bool, scopes ...*queryScope) []Diagnostic {
+	clauses := parseUpsertClauses(tokens)
+	if len(clauses) == 0 || !hasCatalog {
+		return nil
+	}
+	tableName := insertTarget(tokens)
+	table := lookupTable(a.Catalog, tableName)
+	if table == nil {
+		return nil
+	}
+
+	var diags []Diagnostic
+	for _, clause := range clauses {
+		if !clause.duplicateKey {
+			for _, scope := range scopes {
+				if entry, ok := scope.get(table.Name); ok {
+					scope.addAlias("excluded", entry)
+				}
+			}
+		}
+		if msg := checkConflictTarget(table, clause); msg != "" {
+			// MySQL accepts such a clause, but it can never fire.
+			severity := SeverityError
+			if clause.duplicateKey {
+				severity = SeverityWarning
+			}
+			diags = append(diags, Diagnostic{
+				Path:     q.Block.Path,
+				Line:     actualTokenLine(q, clause.on),
+				Column:   clause.on.Column,
+				Message:  msg,
+				Severity: severity,
+			})
+		}
+		for _, tok := range upsertColumnRefs(clause.update) {
+			if lookupColumn(table, tokenizer.NormalizeIdentifier(tok.Text)) != nil {
+				continue
+			}
+			diags = append(diags, Diagnostic{
+				Path:     q.Block.Path,
+				Line:     actualTokenLine(q, tok),
+				Column:   tok.Column,
+				Message:  fmt.Sprintf("unknown column %q in table %s", tokenizer.NormalizeIdentifier(tok.Text), table.Name),
+				Severity: SeverityError,
+			})
+		}
+	}
+	return diags
+}
+
+// parseUpsertClauses returns the upsert clauses at the top level of tokens.
+// SQLite allows several ON CONFLICT clauses on one INSERT.
+func parseUpsertClauses(tokens []tokenizer.Token) []upsertClause {
+	var clauses []upsertClause
+	for i := 0; i+1 < len(tokens); i++ {
+		if isSymbol(tokens[i], "(") {
+			i = skipParens(tokens, i) - 1
+			continue
+		}
+		if !isWord(tokens[i], "ON") {
+			continue
+		}
+		clause := upsertClause{on: tokens[i]}
+		j := i + 1
+		switch {
+		case isWord(tokens[j], "CONFLICT"):
+			j = parseConflictTarget(tokens, j+1, &clause)
+			for j < len(tokens) && !isWord(tokens[j], "DO") {
+				j++
+			}
+			if j+1 < len(tokens) && isWord(tokens[j+1], "UPDATE") {
+				j += 2
+				if j < len(tokens) && isWord(tokens[j], "SET") {
+					j++
+				}
+				clause.update, j = upsertAssignments(tokens, j)
+			}
+		case isWord(tokens[j], "DUPLICATE") && j+2 < len(tokens) && isWord(tokens[j+1], "KEY") && isWord(tokens[j+2], "UPDATE"):
+			clause.duplicateKey = true
+			clause.update, j = upsertAssignments(tokens, j+3)
+		default:
+			continue
+		}
+		clauses = append(clauses, clause)
+		i = j - 1
+	}
+	return clauses
+}
+
+// parseConflictTarget reads the conflict target starting at idx into clause
+// and returns the index after it, including any index predicate.
+func parseConflictTarget(tokens []tokenizer.Token, idx int, clause *upsertClause) int {
+	if idx+2 < len(tokens) && isWord(tokens[idx], "ON") && isWord(tokens[idx+1], "CONSTRAINT") {
+		clause.constraint = normalizeIdent(tokens[idx+2].Text)
+		return idx + 3
+	}
+	if idx >= len(tokens) || !isSymbol(tokens[idx], "(") {
+		return idx
+	}
+	end := skipParens(tokens, idx)
+	expectColumn := true
+	for _, tok := range tokens[idx+1 : max(end-1, idx+1)] {
+		switch {
+		case isSymbol(tok, ","):
+			expectColumn = true
+		case expectColumn && (tok.Kind == tokenizer.KindIdentifier || tok.Kind == tokenizer.KindKeyword):
+			clause.target = append(clause.target, tokenizer.NormalizeIdentifier(tok.Text))
+			expectColumn = false
+		case isWord(tok, "COLLATE"), isWord(tok, "ASC"), isWord(tok, "DESC"):
+			// Index options do not change which key the target names.
+		case tok.Kind == tokenizer.KindIdentifier && !expectColumn:
+			// Collation name following COLLATE.
+		default:
+			clause.complexTarget = true
+		}
+	}
+	return end
+}
+
+// upsertAssignments returns the tokens of the assignment list starting at idx
+// and the index after it.
+func upsertAssignments(tokens []tokenizer.Token, idx int) ([]tokenizer.Token, int) {
+	end := idx
+	for end < len(tokens) {
+		tok := tokens[end]
+		if isSymbol(tok, "(") {
+			end = skipParens(tokens, end)
+			continue
+		}
+		if isSymbol(tok, ";") || tok.Kind == tokenizer.KindEOF {
+			break
+		}
+		if _, ok := upsertEndWords[strings.ToUpper(tok.Text)]; ok && isWord(tok, tok.Text) {
+			break
+		}
+		end++
+	}
+	return tokens[idx:end], end
+}
+
+// upsertColumnRefs returns the tokens in an assignment list that must name
+// columns of the target table: the assigned columns and the arguments of
+// MySQL's VALUES() function.
+func upsertColumnRefs(update []tokenizer.Token) []tokenizer.Token {
+	var refs []tokenizer.Token
+	depth := 0
+	for i, tok := range update {
+		switch {
+		case isSymbol(tok, "("):
+			depth++
+		case isSymbol(tok, ")"):
+			depth--
+		case isSymbol(tok, "=") && depth == 0 && i > 0 && isUpsertColumn(update[i-1]):
+			if i < 2 || !isSymbol(update[i-2], ".") {
+				refs = append(refs, update[i-1])
+			}
+		case isWord(tok, "VALUES") && i+3 < len(update) && isSymbol(update[i+1], "(") &&
+			isUpsertColumn(update[i+2]) && isSymbol(update[i+3], ")"):
+			refs = append(refs, update[i+2])
+		}
+	}
+	return refs
+}
+
+func isUpsertColumn(tok tokenizer.Token) bool {
+	return tok.Kind == tokenizer.KindIdentifier || (tok.Kind == tokenizer.KindKeyword && !isWord(tok, "SET"))
+}
+
+// checkConflictTarget returns a diagnostic message when the conflict target of
+// clause matches no primary key or unique key of table.
+func checkConflictTarget(table *model.Table, clause upsertClause) string {
+	switch {
+	case clause.constraint != "":
+		if !slices.Contains(uniqueKeyNames(table), clause.constraint) {
+			return fmt.Sprintf("ON CONFLICT constraint %q is not a primary key or unique constraint of table %s", clause.constraint, table.Name)
+		}
+	case clause.duplicateKey:
+		if len(uniqueKeyColumns(table)) == 0 {
+			return fmt.Sprintf("ON DUPLICATE KEY UPDATE on table %s, which has no primary key or unique key", table.Name)
+		}
+	case len(clause.target) > 0 && !clause.complexTarget:
+		for _, key := range uniqueKeyColumns(table) {
+			if sameColumnSet(key, clause.target) {
+				return ""
+			}
+		}
+		return fmt.Sprintf("ON CONFLICT target (%s) does not match a primary key or unique key of table %s", strings.Join(clause.target, ", "), table.Name)
+	}
+	return ""
+}
+
+// uniqueKeyColumns returns the column sets of the primary key, unique
+// constraints and unique indexes of table.
+func uniqueKeyColumns(table *model.Table) [][]string {
+	var keys [][]string
+	if table.PrimaryKey != nil {
+		keys = append(keys, table.PrimaryKey.Columns)
+	}
+	for _, uk := range table.UniqueKeys {
+		keys = append(keys, uk.Columns)
+	}
+	for _, idx := range table.Indexes {
+		if idx.Unique {
+			keys = append(keys, idx.Columns)
+		}
+	}
+	return keys
+}
+
+// uniqueKeyNames returns the normalized names of the primary key, unique
+// constraints and unique indexes of table. Unnamed constraints get the names
+// PostgreSQL generates for them.
+func uniqueKeyNames(table *model.Table) []string {
+	var names []string
+	if pk := table.PrimaryKey; pk != nil {
+		if pk.Name != "" {
+			names = append(names, normalizeIdent(pk.Name))
+		} else {
+			names = append(names, normalizeIdent(table.Name+"_pkey"))
+		}
+	}
+	for _, uk := range table.UniqueKeys {
+		if uk.Name != "" {
+			names = append(names, normalizeIdent(uk.Name))
+		} else {
+			names = append(names, normalizeIdent(table.Name+"_"+strings.Join(uk.Columns, "_")+"_key"))
+		}
+	}
+	for _, idx := range table.Indexes {
+		if idx.Unique && idx.Name != "" {
+			names = append(names, normalizeIdent(idx.Name))
+		}
+	}
+	return names
+}
+
+func sameColumnSet(key, target []string) bool {
+	if len(key) != len(target) {
+		return false
+	}
+	for _, col := range key {
+		if !slices.ContainsFunc(target, func(t string) bool { return strings.EqualFold(t, col) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// insertTarget returns the table named after INTO in tokens.
+func insertTarget(tokens []tokenizer.Token) string {
+	for i := 0; i+1 < len(tokens); i++ {
+		if isWord(tokens[i], "INTO") && tokens[i+1].Kind == tokenizer.KindIdentifier {
+			return tokenizer.NormalizeIdentifier(tokens[i+1].Text)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,169 @@
+package analyzer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/electwix/db-catalyst/internal/query/parser"
+	"github.com/electwix/db-catalyst/internal/schema/tokenizer"
+)
+
+// windowSpecWords are the words of window specifications and their frame
+// clauses, which are never column references.
+var windowSpecWords = map[string]struct{}{
+	"PARTITION": {}, "BY": {}, "ORDER": {}, "ASC": {}, "DESC": {}, "NULLS": {}, "FIRST": {},
+	"LAST": {}, "ROWS": {}, "RANGE": {}, "GROUPS": {}, "BETWEEN": {}, "AND": {}, "UNBOUNDED": {},
+	"PRECEDING": {}, "FOLLOWING": {}, "CURRENT": {}, "ROW": {}, "EXCLUDE": {}, "NO": {},
+	"OTHERS": {}, "TIES": {}, "GROUP": {}, "INTERVAL": {}, "COLLATE": {}, "CASE": {}, "WHEN": {},
+	"THEN": {}, "ELSE": {}, "END": {}, "IS": {}, "IN": {}, "LIKE": {}, "TRUE": {}, "FALSE": {},
+}
+
+// validateWindows checks the OVER clauses of window function calls and the
+// named windows of the WINDOW clause in tokens. Columns in PARTITION BY and
+// ORDER BY must exist in scope and named windows must be defined. Qualified
+// references are left to validateIdentifiers.
+func validateWindows(tokens []tokenizer.Token, scope *queryScope, q parser.Query) []Diagnostic {
+	windows := windowDefinitions(tokens)
+	var diags []Diagnostic
+	for _, w := range windows {
+		diags = append(diags, validateWindowSpec(w.spec, windows, scope, q)...)
+	}
+
+	// Window calls inside subqueries belong to the subquery's relations.
+	var subquery []bool
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch {
+		case isSymbol(tok, "("):
+			subquery = append(subquery, i+1 < len(tokens) && (isWord(tokens[i+1], "SELECT") || isWord(tokens[i+1], "WITH")))
+			continue
+		case isSymbol(tok, ")"):
+			if len(subquery) > 0 {
+				subquery = subquery[:len(subquery)-1]
+			}
+			continue
+		case !isWord(tok, "OVER") || i+1 >= len(tokens) || slices.Contains(subquery, true):
+			continue
+		}
+
+		next := tokens[i+1]
+		if isSymbol(next, "(") {
+			end := skipParens(tokens, i+1)
+			diags = append(diags, validateWindowSpec(tokens[i+2:max(end-1, i+2)], windows, scope, q)...)
+			i = end - 1
+			continue
+		}
+		if next.Kind != tokenizer.KindIdentifier {
+			continue
+		}
+		if !hasWindow(windows, next.Text) {
+			diags = append(diags, Diagnostic{
+				Path:     q.Block.Path,
+				Line:     actualTokenLine(q, next),
+				Column:   next.Column,
+				Message:  fmt.Sprintf("unknown window %q", tokenizer.NormalizeIdentifier(next.Text)),
+				Severity: SeverityError,
+			})
+		}
+		i++
+	}
+	return diags
+}
+
+// namedWindow is a window defined by the WINDOW clause.
+type namedWindow struct {
+	name string
+	spec []tokenizer.Token
+}
+
+// windowDefinitions returns the windows defined by the top-level WINDOW
+// clause of tokens, in order.
+func windowDefinitions(tokens []tokenizer.Token) []namedWindow {
+	var windows []namedWindow
+	depth := 0
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch {
+		case isSymbol(tok, "("):
+			depth++
+			continue
+		case isSymbol(tok, ")"):
+			depth--
+			continue
+		case depth != 0 || !isWord(tok, "WINDOW"):
+			continue
+		}
+
+		// WINDOW name AS (spec) [, name AS (spec)]...
+		for j := i + 1; j+2 < len(tokens); {
+			if tokens[j].Kind != tokenizer.KindIdentifier || !isWord(tokens[j+1], "AS") || !isSymbol(tokens[j+2], "(") {
+				break
+			}
+			end := skipParens(tokens, j+2)
+			windows = append(windows, namedWindow{
+				name: normalizeIdent(tokens[j].Text),
+				spec: tokens[j+3 : max(end-1, j+3)],
+			})
+			i = end - 1
+			if end >= len(tokens) || !isSymbol(tokens[end], ",") {
+				break
+			}
+			j = end + 1
+		}
+	}
+	return windows
+}
+
+// hasWindow reports whether windows defines the window named name.
+func hasWindow(windows []namedWindow, name string) bool {
+	name = normalizeIdent(name)
+	return slices.ContainsFunc(windows, func(w namedWindow) bool { return w.name == name })
+}
+
+// validateWindowSpec reports the unknown unqualified columns in the
+// PARTITION BY, ORDER BY and frame clauses of spec. A leading window name
+// refers to a window of the WINDOW clause that spec extends.
+func validateWindowSpec(spec []tokenizer.Token, windows []namedWindow, scope *queryScope, q parser.Query) []Diagnostic {
+	var diags []Diagnostic
+	for i := 0; i < len(spec); i++ {
+		tok := spec[i]
+		if tok.Kind != tokenizer.KindIdentifier {
+			continue
+		}
+		if i+1 < len(spec) && isSymbol(spec[i+1], ".") {
+			i += 2
+			continue
+		}
+		if i+1 < len(spec) && isSymbol(spec[i+1], "(") {
+			continue
+		}
+		if i > 0 && (isWord(spec[i-1], "AS") || isWord(spec[i-1], "COLLATE")) {
+			// Type name of a CAST or a collation name.
+			continue
+		}
+		name := tokenizer.NormalizeIdentifier(tok.Text)
+		if _, ok := windowSpecWords[strings.ToUpper(name)]; ok && isWord(tok, name) {
+			continue
+		}
+		if isWord(tok, name) && tokenizer.IsKeyword(name) {
+			continue
+		}
+		if i == 0 && hasWindow(windows, name) {
+			continue
+		}
+		if strings.EqualFold(name, "SQLC") {
+			continue
+		}
+		if _, _, res := scope.lookup("", name); res == scopeLookupColumnNotFound {
+			diags = append(diags, Diagnostic{
+				Path:     q.Block.Path,
+				Line:     actualTokenLine(q, tok),
+				Column:   tok.Column,
+				Message:  fmt.Sprintf("unknown column %q in window specification", name),
+				Severity: SeverityError,
+			})
+		}
+	}
+	return diags
+}
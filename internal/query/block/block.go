@@ -29,6 +29,18 @@ const (
 	CommandExecRows
 	// CommandExecLastID indicates a query that returns the last insert ID.
 	CommandExecLastID
+	// CommandCopyFrom indicates a bulk insert of many rows into a single table.
+	CommandCopyFrom
+	// CommandBatchExec indicates a query executed once per item of a batch.
+	CommandBatchExec
+	// CommandBatchOne indicates a query returning a single row per item of a batch.
+	CommandBatchOne
+	// CommandBatchMany indicates a query returning multiple rows per item of a batch.
+	CommandBatchMany
+	// CommandIter indicates a query whose rows are streamed through an iterator.
+	CommandIter
+	// CommandOpt indicates a query returning at most one row, or nil when none matches.
+	CommandOpt
 )
 
 // ParamTypeOverride represents an explicit type override for a parameter.
@@ -50,7 +62,10 @@ type Block struct {
 	EndOffset   int
 	Suffix      string
 	ParamTypes  []ParamTypeOverride // Explicit type overrides from @param comments
+	NonNull     []string            // Result columns declared non-null by @nonnull comments
 	Cache       *cache.Annotation   // Cache annotation if present
+	ResultType  string              // Result type name from a @returns comment
+	ParamsType  string              // Params type name from a @params comment
 }
 
 func (c Command) String() string {
@@ -67,6 +82,18 @@ func (c Command) String() string {
 		return ":execrows"
 	case CommandExecLastID:
 		return ":execlastid"
+	case CommandCopyFrom:
+		return ":copyfrom"
+	case CommandBatchExec:
+		return ":batchexec"
+	case CommandBatchOne:
+		return ":batchone"
+	case CommandBatchMany:
+		return ":batchmany"
+	case CommandIter:
+		return ":iter"
+	case CommandOpt:
+		return ":opt"
 	default:
 		return ":unknown"
 	}
@@ -87,11 +114,28 @@ func ParseCommand(tag string) (Command, bool) {
 		return CommandExecRows, true
 	case ":execlastid":
 		return CommandExecLastID, true
+	case ":copyfrom":
+		return CommandCopyFrom, true
+	case ":batchexec":
+		return CommandBatchExec, true
+	case ":batchone":
+		return CommandBatchOne, true
+	case ":batchmany":
+		return CommandBatchMany, true
+	case ":iter":
+		return CommandIter, true
+	case ":opt":
+		return CommandOpt, true
 	default:
 		return CommandUnknown, false
 	}
 }
 
+// IsBatch reports whether c queues one execution per item of a batch.
+func (c Command) IsBatch() bool {
+	return c == CommandBatchExec || c == CommandBatchOne || c == CommandBatchMany
+}
+
 // Slice extracts query blocks from a SQL file.
 func Slice(path string, src []byte) ([]Block, error) {
 	if !utf8.Valid(src) {
@@ -108,8 +152,7 @@ func Slice(path string, src []byte) ([]Block, error) {
 		docStart     int
 		contentStart int
 		lineIndex    int
-		paramTypes   []ParamTypeOverride
-		cacheAnn     *cache.Annotation
+		annotations  docAnnotations
 	}
 	markers := make([]markerInfo, 0, len(lines))
 	for idx, ln := range lines {
@@ -143,7 +186,7 @@ func Slice(path string, src []byte) ([]Block, error) {
 		if !ok {
 			return nil, fmt.Errorf("%s:%d:%d: unknown command %s", path, ln.line, column, cmdTag)
 		}
-		docLines, docStart, paramTypes, cacheAnn := collectDocLines(lines, idx)
+		docLines, docStart, annotations := collectDocLines(lines, idx)
 		markers = append(markers, markerInfo{
 			name:         name,
 			command:      cmd,
@@ -153,8 +196,7 @@ func Slice(path string, src []byte) ([]Block, error) {
 			docStart:     docStart,
 			contentStart: ln.next,
 			lineIndex:    idx,
-			paramTypes:   paramTypes,
-			cacheAnn:     cacheAnn,
+			annotations:  annotations,
 		})
 	}
 	if len(markers) == 0 {
@@ -219,8 +261,11 @@ func Slice(path string, src []byte) ([]Block, error) {
 			StartOffset: sqlStart,
 			EndOffset:   sqlEnd,
 			Suffix:      suffix,
-			ParamTypes:  m.paramTypes,
-			Cache:       m.cacheAnn,
+			ParamTypes:  m.annotations.paramTypes,
+			NonNull:     m.annotations.nonNull,
+			Cache:       m.annotations.cache,
+			ResultType:  m.annotations.resultType,
+			ParamsType:  m.annotations.paramsType,
 		})
 	}
 	return blocks, nil
@@ -272,13 +317,21 @@ func splitLines(text string) []lineInfo {
 	return lines
 }
 
-func collectDocLines(lines []lineInfo, markerIdx int) ([]string, int, []ParamTypeOverride, *cache.Annotation) {
+// docAnnotations holds the annotations found in a query's doc comment.
+type docAnnotations struct {
+	paramTypes []ParamTypeOverride
+	nonNull    []string
+	cache      *cache.Annotation
+	resultType string
+	paramsType string
+}
+
+func collectDocLines(lines []lineInfo, markerIdx int) ([]string, int, docAnnotations) {
+	var ann docAnnotations
 	if markerIdx == 0 {
-		return nil, lines[markerIdx].start, nil, nil
+		return nil, lines[markerIdx].start, ann
 	}
 	doc := make([]string, 0)
-	paramTypes := make([]ParamTypeOverride, 0)
-	var cacheAnn *cache.Annotation
 	docStart := lines[markerIdx].start
 	for i := markerIdx - 1; i >= 0; i-- {
 		text := lines[i].text
@@ -295,11 +348,17 @@ func collectDocLines(lines []lineInfo, markerIdx int) ([]string, int, []ParamTyp
 		if strings.HasPrefix(lowerContent, "name:") {
 			break
 		}
-		// Check for @param annotation
+		// Check for @param, @nonnull, @returns and @params annotations
 		if pt := parseParamType(content); pt != nil {
-			paramTypes = append(paramTypes, *pt)
-		} else if ann := cache.ParseAnnotation(content); ann != nil {
-			cacheAnn = ann
+			ann.paramTypes = append(ann.paramTypes, *pt)
+		} else if cols := parseNonNull(content); cols != nil {
+			ann.nonNull = append(cols, ann.nonNull...)
+		} else if cacheAnn := cache.ParseAnnotation(content); cacheAnn != nil {
+			ann.cache = cacheAnn
+		} else if name := parseTypeName(content, "@returns"); name != "" {
+			ann.resultType = name
+		} else if name := parseTypeName(content, "@params"); name != "" {
+			ann.paramsType = name
 		} else {
 			doc = append(doc, content)
 		}
@@ -308,12 +367,9 @@ func collectDocLines(lines []lineInfo, markerIdx int) ([]string, int, []ParamTyp
 	if len(doc) == 0 {
 		doc = nil
 	}
-	if len(paramTypes) == 0 {
-		paramTypes = nil
-	}
 	slices.Reverse(doc)
-	slices.Reverse(paramTypes)
-	return doc, docStart, paramTypes, cacheAnn
+	slices.Reverse(ann.paramTypes)
+	return doc, docStart, ann
 }
 
 // parseParamType parses a @param annotation like "@param userID: uuid".
@@ -339,6 +395,37 @@ func parseParamType(content string) *ParamTypeOverride {
 	}
 }
 
+// parseTypeName parses an annotation naming a Go type, like
+// "@returns UserDTO" or "@params github.com/acme/api/dto.CreateUser".
+// Returns "" if the content is not the given annotation.
+func parseTypeName(content, annotation string) string {
+	rest, ok := strings.CutPrefix(content, annotation+" ")
+	if !ok {
+		return ""
+	}
+	fields := strings.Fields(rest)
+	if len(fields) != 1 {
+		return ""
+	}
+	return fields[0]
+}
+
+// parseNonNull parses a @nonnull annotation listing result columns separated
+// by commas or spaces, like "@nonnull name, email".
+// Returns nil if the content is not a @nonnull annotation.
+func parseNonNull(content string) []string {
+	if !strings.HasPrefix(content, "@nonnull ") {
+		return nil
+	}
+	cols := strings.FieldsFunc(content[len("@nonnull "):], func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+	if len(cols) == 0 {
+		return nil
+	}
+	return cols
+}
+
 func trimSQL(sql string) string {
 	// Trim trailing whitespace
 	sql = strings.TrimRightFunc(sql, unicode.IsSpace)
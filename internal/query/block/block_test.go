@@ -103,6 +103,12 @@ func TestParseCommand(t *testing.T) {
 		":execresult": CommandExecResult,
 		":execrows":   CommandExecRows,
 		":execlastid": CommandExecLastID,
+		":copyfrom":   CommandCopyFrom,
+		":batchexec":  CommandBatchExec,
+		":batchone":   CommandBatchOne,
+		":batchmany":  CommandBatchMany,
+		":iter":       CommandIter,
+		":opt":        CommandOpt,
 	}
 	for tag, want := range cases {
 		got, ok := ParseCommand(tag)
@@ -126,6 +132,12 @@ func TestCommandString(t *testing.T) {
 		CommandExecResult: ":execresult",
 		CommandExecRows:   ":execrows",
 		CommandExecLastID: ":execlastid",
+		CommandCopyFrom:   ":copyfrom",
+		CommandBatchExec:  ":batchexec",
+		CommandBatchOne:   ":batchone",
+		CommandBatchMany:  ":batchmany",
+		CommandIter:       ":iter",
+		CommandOpt:        ":opt",
 		CommandUnknown:    ":unknown",
 	}
 	for cmd, want := range cases {
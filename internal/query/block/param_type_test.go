@@ -1,6 +1,7 @@
 package block
 
 import (
+	"slices"
 	"testing"
 )
 
@@ -64,3 +65,70 @@ SELECT * FROM users WHERE id = :userID;
 		t.Errorf("expected doc 'Get a user by ID', got %q", blk.Doc)
 	}
 }
+
+func TestNonNullParsing(t *testing.T) {
+	src := []byte(`-- List display names
+-- @nonnull display_name, email
+-- @param id: int64
+-- @nonnull nickname
+-- name: ListNames :many
+SELECT display_name, email, nickname FROM users WHERE id = :id;
+`)
+
+	blocks, err := Slice("test.sql", src)
+	if err != nil {
+		t.Fatalf("Slice failed: %v", err)
+	}
+
+	if len(blocks) != 1 {
+		t.Fatalf("expected 1 block, got %d", len(blocks))
+	}
+
+	blk := blocks[0]
+	want := []string{"display_name", "email", "nickname"}
+	if !slices.Equal(blk.NonNull, want) {
+		t.Errorf("expected non-null columns %v, got %v", want, blk.NonNull)
+	}
+	if blk.Doc != "List display names" {
+		t.Errorf("expected annotations to be excluded from doc, got %q", blk.Doc)
+	}
+	if len(blk.ParamTypes) != 1 {
+		t.Errorf("expected 1 param type, got %+v", blk.ParamTypes)
+	}
+}
+
+func TestNamedTypeParsing(t *testing.T) {
+	src := []byte(`-- Create a user
+-- @returns UserDTO
+-- @params github.com/acme/api/dto.CreateUser
+-- name: CreateUser :one
+INSERT INTO users (name, email) VALUES (:name, :email) RETURNING id, name;
+
+-- @returns two words
+-- name: ListUsers :many
+SELECT id, name FROM users;
+`)
+
+	blocks, err := Slice("test.sql", src)
+	if err != nil {
+		t.Fatalf("Slice failed: %v", err)
+	}
+
+	if len(blocks) != 2 {
+		t.Fatalf("expected 2 blocks, got %d", len(blocks))
+	}
+
+	blk := blocks[0]
+	if blk.ResultType != "UserDTO" {
+		t.Errorf("expected result type UserDTO, got %q", blk.ResultType)
+	}
+	if blk.ParamsType != "github.com/acme/api/dto.CreateUser" {
+		t.Errorf("expected params type github.com/acme/api/dto.CreateUser, got %q", blk.ParamsType)
+	}
+	if blk.Doc != "Create a user" {
+		t.Errorf("expected annotations to be excluded from doc, got %q", blk.Doc)
+	}
+	if blocks[1].ResultType != "" {
+		t.Errorf("expected malformed @returns to be ignored, got %q", blocks[1].ResultType)
+	}
+}
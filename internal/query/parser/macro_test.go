@@ -91,3 +91,23 @@ func TestParseSQLCMacros(t *testing.T) {
 		})
 	}
 }
+
+func TestParseEmbedColumn(t *testing.T) {
+	sql := "SELECT sqlc.embed(u), sqlc.embed(posts), posts.id AS post_id FROM users u JOIN posts ON posts.user_id = u.id"
+	got, diags := Parse(block.Block{Name: "TestQuery", Command: block.CommandMany, SQL: sql})
+	if len(diags) > 0 {
+		t.Fatalf("Parse() unexpected diagnostics: %v", diags)
+	}
+	if len(got.Columns) != 3 {
+		t.Fatalf("Columns length = %d, want 3", len(got.Columns))
+	}
+	for i, want := range []string{"u", "posts", ""} {
+		if got.Columns[i].Embed != want {
+			t.Errorf("Columns[%d].Embed = %q, want %q", i, got.Columns[i].Embed, want)
+		}
+	}
+	first := got.Columns[0]
+	if span := sql[first.StartOffset:first.EndOffset]; span != "sqlc.embed(u)" {
+		t.Errorf("Columns[0] spans %q, want %q", span, "sqlc.embed(u)")
+	}
+}
@@ -24,6 +24,7 @@ type Query struct {
 	Columns     []Column
 	Params      []Param
 	CTEs        []CTE
+	Compound    []CompoundBranch
 	Diagnostics []Diagnostic
 }
 
@@ -48,6 +49,7 @@ type Column struct {
 	Expr        string
 	Alias       string
 	Table       string
+	Embed       string // Relation named by sqlc.embed(name), if any
 	Line        int
 	Column      int
 	StartOffset int
@@ -63,6 +65,16 @@ type CTE struct {
 	Column    int
 }
 
+// CompoundBranch represents a SELECT combined with the preceding branches of
+// a compound query by UNION, INTERSECT or EXCEPT. The first branch supplies
+// Query.Columns.
+type CompoundBranch struct {
+	Operator string // e.g. "UNION ALL"
+	Columns  []Column
+	Line     int
+	Column   int
+}
+
 // Param represents a query parameter.
 type Param struct {
 	Name          string
@@ -177,6 +189,10 @@ func Parse(blk block.Block) (Query, []Diagnostic) {
 		columns, columnDiags := parseSelectColumns(tokens, verbIdx, blk, posIdx)
 		q.Columns = columns
 		diags = append(diags, columnDiags...)
+
+		compound, compoundDiags := parseCompoundBranches(tokens, verbIdx, blk, posIdx)
+		q.Compound = compound
+		diags = append(diags, compoundDiags...)
 	}
 
 	return finalizeQuery(q, diags)
@@ -1126,7 +1142,7 @@ func parseSelectColumns(tokens []tokenizer.Token, selectIdx int, blk block.Block
 		if tok.Kind == tokenizer.KindEOF {
 			break
 		}
-		if depth == 0 && tok.Kind == tokenizer.KindKeyword && tok.Text == "FROM" {
+		if depth == 0 && (tok.Kind == tokenizer.KindKeyword && tok.Text == "FROM" || isCompoundOperator(tok)) {
 			break
 		}
 		if depth == 0 && tok.Kind == tokenizer.KindKeyword && len(columns) == 0 && (tok.Text == "DISTINCT" || tok.Text == "ALL") {
@@ -1166,6 +1182,79 @@ func parseSelectColumns(tokens []tokenizer.Token, selectIdx int, blk block.Block
 	return columns, diags
 }
 
+// parseCompoundBranches collects the SELECT branches that follow the first one
+// at the top level of a compound query.
+func parseCompoundBranches(tokens []tokenizer.Token, selectIdx int, blk block.Block, pos positionIndex) ([]CompoundBranch, []Diagnostic) {
+	var branches []CompoundBranch
+	var diags []Diagnostic
+	depth := 0
+	for i := selectIdx + 1; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.Kind == tokenizer.KindEOF {
+			break
+		}
+		if tok.Kind == tokenizer.KindSymbol {
+			switch tok.Text {
+			case "(":
+				depth++
+			case ")":
+				if depth > 0 {
+					depth--
+				}
+			}
+			continue
+		}
+		if depth != 0 || !isCompoundOperator(tok) {
+			continue
+		}
+
+		operator := strings.ToUpper(tok.Text)
+		next := i + 1
+		for next < len(tokens) && tokens[next].Kind == tokenizer.KindDocComment {
+			next++
+		}
+		if next < len(tokens) && (isWordToken(tokens[next], "ALL") || isWordToken(tokens[next], "DISTINCT")) {
+			operator += " " + strings.ToUpper(tokens[next].Text)
+			next++
+		}
+		if next >= len(tokens) || !isWordToken(tokens[next], "SELECT") {
+			diags = append(diags, makeDiag(blk, tok.Line, tok.Column, SeverityWarning, "%s branch is not a SELECT; its columns are not checked", operator))
+			continue
+		}
+
+		columns, columnDiags := parseSelectColumns(tokens, next, blk, pos)
+		// Only the first branch names the result columns, so later branches
+		// need no aliases.
+		for _, d := range columnDiags {
+			if d.Severity == SeverityError {
+				diags = append(diags, d)
+			}
+		}
+		line, column := actualPosition(blk, tokens[next].Line, tokens[next].Column)
+		branches = append(branches, CompoundBranch{
+			Operator: operator,
+			Columns:  columns,
+			Line:     line,
+			Column:   column,
+		})
+		i = next
+	}
+	return branches, diags
+}
+
+// isCompoundOperator reports whether tok is UNION, INTERSECT or EXCEPT.
+func isCompoundOperator(tok tokenizer.Token) bool {
+	return isWordToken(tok, "UNION") || isWordToken(tok, "INTERSECT") || isWordToken(tok, "EXCEPT")
+}
+
+// isWordToken reports whether tok is an unquoted keyword or identifier spelled text.
+func isWordToken(tok tokenizer.Token, text string) bool {
+	if tok.Kind != tokenizer.KindKeyword && tok.Kind != tokenizer.KindIdentifier {
+		return false
+	}
+	return strings.EqualFold(tok.Text, text)
+}
+
 func trimTokens(tokens []tokenizer.Token) []tokenizer.Token {
 	start := 0
 	for start < len(tokens) && tokens[start].Kind == tokenizer.KindSymbol && tokens[start].Text == "," {
@@ -1179,6 +1268,21 @@ func trimTokens(tokens []tokenizer.Token) []tokenizer.Token {
 }
 
 func buildColumn(tokens []tokenizer.Token, blk block.Block, pos positionIndex) (Column, []Diagnostic) {
+	if name, ok := embedMacro(tokens); ok {
+		startOffset := pos.offset(tokens[0])
+		endOffset := min(pos.offset(tokens[len(tokens)-1])+1, len(pos.sql))
+		line, column := actualPosition(blk, tokens[0].Line, tokens[0].Column)
+		return Column{
+			Expr:        strings.TrimSpace(pos.sql[startOffset:endOffset]),
+			Table:       name,
+			Embed:       name,
+			Line:        line,
+			Column:      column,
+			StartOffset: startOffset,
+			EndOffset:   endOffset,
+		}, nil
+	}
+
 	var diags []Diagnostic
 	exprTokens, aliasTok, alias, hasAlias := extractAlias(tokens)
 	table, columnName, simple := analyzeSimpleColumn(exprTokens)
@@ -1222,6 +1326,21 @@ func buildColumn(tokens []tokenizer.Token, blk block.Block, pos positionIndex) (
 	return col, diags
 }
 
+// embedMacro reports whether tokens are exactly sqlc.embed(name) and returns
+// the normalized name.
+func embedMacro(tokens []tokenizer.Token) (string, bool) {
+	//nolint:mnd // sqlc . embed ( name ) is six tokens
+	if len(tokens) != 6 {
+		return "", false
+	}
+	if !isWordToken(tokens[0], "sqlc") || tokens[1].Kind != tokenizer.KindSymbol || tokens[1].Text != "." ||
+		!isWordToken(tokens[2], "embed") || tokens[3].Kind != tokenizer.KindSymbol || tokens[3].Text != "(" ||
+		tokens[4].Kind != tokenizer.KindIdentifier || tokens[5].Kind != tokenizer.KindSymbol || tokens[5].Text != ")" {
+		return "", false
+	}
+	return tokenizer.NormalizeIdentifier(tokens[4].Text), true
+}
+
 func extractAlias(tokens []tokenizer.Token) ([]tokenizer.Token, *tokenizer.Token, string, bool) {
 	if len(tokens) == 0 {
 		return tokens, nil, "", false
@@ -1245,6 +1364,10 @@ func extractAlias(tokens []tokenizer.Token) ([]tokenizer.Token, *tokenizer.Token
 			continue
 		}
 		if tok.Kind == tokenizer.KindIdentifier {
+			// OVER and the window name after it belong to a window function call.
+			if isWordToken(tok, "OVER") || (i > 0 && isWordToken(tokens[i-1], "OVER")) {
+				break
+			}
 			aliasTok := tok
 			alias := tokenizer.NormalizeIdentifier(tok.Text)
 			if i > 0 && tokens[i-1].Kind == tokenizer.KindKeyword && tokens[i-1].Text == "AS" {
@@ -1370,6 +1493,12 @@ func inferParamName(tokens []tokenizer.Token, paramIdx int, usedOrders map[int]i
 		return ""
 	}
 
+	// Parameters in the update clause of an upsert are named after the column
+	// they are assigned to, before the INSERT column list is considered
+	if name := inferUpsertParamName(tokens, paramIdx); name != "" {
+		return name
+	}
+
 	// First, try to infer from INSERT statement column list
 	// This is done before the ambiguity check because INSERT has explicit column mapping
 	if name := inferInsertParamName(tokens, paramIdx); name != "" {
@@ -1736,10 +1865,71 @@ func countParamInValues(tokens []tokenizer.Token, paramIdx int) int {
 				}
 			}
 		}
+		if tok.Kind == tokenizer.KindParam && parenDepth > 0 {
+			count++
+		}
 	}
 	return count
 }
 
+// inferUpsertParamName names a parameter in the update clause of an upsert,
+// ON CONFLICT ... DO UPDATE SET or MySQL's ON DUPLICATE KEY UPDATE, after the
+// column it is assigned to. Parameters nested in parentheses or in the
+// clause's WHERE condition are left to the other rules.
+func inferUpsertParamName(tokens []tokenizer.Token, paramIdx int) string {
+	column := ""
+	depth := 0
+	for i := paramIdx - 1; i >= 0; i-- {
+		tok := tokens[i]
+		if tok.Kind == tokenizer.KindSymbol {
+			switch tok.Text {
+			case ")":
+				depth++
+			case "(":
+				if depth == 0 {
+					return ""
+				}
+				depth--
+			case "=":
+				if depth == 0 && column == "" && i > 0 && isUpsertColumnToken(tokens[i-1]) {
+					column = tokenizer.NormalizeIdentifier(tokens[i-1].Text)
+				}
+			case ",":
+				if depth == 0 && column == "" {
+					return ""
+				}
+			}
+			continue
+		}
+		if depth > 0 || (tok.Kind != tokenizer.KindKeyword && tok.Kind != tokenizer.KindIdentifier) {
+			continue
+		}
+		switch strings.ToUpper(tok.Text) {
+		case "UPDATE":
+			if column == "" || i == 0 {
+				return ""
+			}
+			prev := strings.ToUpper(tokens[i-1].Text)
+			if prev != "DO" && prev != "KEY" {
+				return ""
+			}
+			return camelCaseParam(column)
+		case "WHERE", "INTO", "SELECT", "RETURNING":
+			return ""
+		}
+	}
+	return ""
+}
+
+// isUpsertColumnToken reports whether tok can be the column of an upsert
+// assignment.
+func isUpsertColumnToken(tok tokenizer.Token) bool {
+	if tok.Kind == tokenizer.KindIdentifier {
+		return true
+	}
+	return tok.Kind == tokenizer.KindKeyword && !strings.EqualFold(tok.Text, "SET") && !strings.EqualFold(tok.Text, "UPDATE")
+}
+
 // inferUpdateParamName attempts to infer a parameter name from an UPDATE SET clause.
 // It handles patterns like "SET col = ?" by looking for the column before the = sign.
 func inferUpdateParamName(tokens []tokenizer.Token, paramIdx int) string {
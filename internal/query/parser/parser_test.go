@@ -66,6 +66,55 @@ WHERE u.status = :status AND u.score > ? AND u.id = ?1;`,
 	}
 }
 
+func TestParseCompoundSelect(t *testing.T) {
+	blk := block.Block{
+		Path:   "query/users.sql",
+		Line:   4,
+		Column: 1,
+		SQL: `SELECT id, 'user' AS kind FROM users
+UNION ALL
+SELECT id, 'admin' FROM admins
+except select 1, 'x'`,
+	}
+
+	q, diags := Parse(blk)
+	if len(diags) != 0 {
+		t.Fatalf("unexpected diagnostics: %+v", diags)
+	}
+	if len(q.Columns) != 2 {
+		t.Fatalf("expected 2 columns, got %+v", q.Columns)
+	}
+	if len(q.Compound) != 2 {
+		t.Fatalf("expected 2 compound branches, got %+v", q.Compound)
+	}
+
+	first := q.Compound[0]
+	if first.Operator != "UNION ALL" || first.Line != 7 || first.Column != 1 {
+		t.Errorf("unexpected first branch: %+v", first)
+	}
+	if len(first.Columns) != 2 || first.Columns[1].Expr != "'admin'" {
+		t.Errorf("unexpected first branch columns: %+v", first.Columns)
+	}
+
+	second := q.Compound[1]
+	if second.Operator != "EXCEPT" || len(second.Columns) != 2 || second.Columns[0].Expr != "1" {
+		t.Errorf("unexpected second branch: %+v", second)
+	}
+}
+
+func TestParseCompoundSelectWithoutFrom(t *testing.T) {
+	q, diags := Parse(block.Block{Path: "q.sql", Line: 1, Column: 1, SQL: "SELECT 1 AS n UNION SELECT 2"})
+	if len(diags) != 0 {
+		t.Fatalf("unexpected diagnostics: %+v", diags)
+	}
+	if len(q.Columns) != 1 || q.Columns[0].Expr != "1" {
+		t.Errorf("expected first branch column 1, got %+v", q.Columns)
+	}
+	if len(q.Compound) != 1 || q.Compound[0].Operator != "UNION" {
+		t.Errorf("unexpected branches: %+v", q.Compound)
+	}
+}
+
 func TestParseSelectMissingAlias(t *testing.T) {
 	blk := block.Block{
 		Path:   "query/users.sql",
@@ -89,6 +138,31 @@ func TestParseSelectMissingAlias(t *testing.T) {
 	}
 }
 
+func TestParseWindowFunctionAlias(t *testing.T) {
+	tests := []struct {
+		sql       string
+		wantAlias string
+		wantDiags int
+	}{
+		{"SELECT RANK() OVER (ORDER BY id) AS r FROM users", "r", 0},
+		{"SELECT RANK() OVER w r FROM users WINDOW w AS (ORDER BY id)", "r", 0},
+		{"SELECT RANK() OVER (ORDER BY id) FROM users", "", 1},
+		{"SELECT RANK() OVER w FROM users WINDOW w AS (ORDER BY id)", "", 1},
+	}
+	for _, tt := range tests {
+		q, diags := Parse(block.Block{Path: "q.sql", Line: 1, Column: 1, SQL: tt.sql})
+		if len(q.Columns) != 1 {
+			t.Fatalf("%s: expected 1 column, got %+v", tt.sql, q.Columns)
+		}
+		if q.Columns[0].Alias != tt.wantAlias {
+			t.Errorf("%s: alias = %q, want %q", tt.sql, q.Columns[0].Alias, tt.wantAlias)
+		}
+		if len(diags) != tt.wantDiags {
+			t.Errorf("%s: expected %d diagnostics, got %+v", tt.sql, tt.wantDiags, diags)
+		}
+	}
+}
+
 func TestParseParametersNumbered(t *testing.T) {
 	blk := block.Block{
 		Path:   "query/books.sql",
@@ -498,6 +572,31 @@ func TestInferParamName(t *testing.T) {
 			paramIdx: 0,
 			wantName: "arg1",
 		},
+		{
+			name:     "INSERT with numbered params",
+			sql:      "INSERT INTO users (id, name) VALUES ($1, $2)",
+			paramIdx: 1,
+			wantName: "name",
+		},
+		// Upserts
+		{
+			name:     "ON CONFLICT DO UPDATE param",
+			sql:      "INSERT INTO users (id, name) VALUES (?, ?) ON CONFLICT (id) DO UPDATE SET name = excluded.name, score = ?",
+			paramIdx: 2,
+			wantName: "score",
+		},
+		{
+			name:     "ON CONFLICT DO UPDATE arithmetic param",
+			sql:      "INSERT INTO users (id, score) VALUES ($1, $2) ON CONFLICT (id) DO UPDATE SET score = users.score + $3",
+			paramIdx: 2,
+			wantName: "score",
+		},
+		{
+			name:     "ON DUPLICATE KEY UPDATE param after VALUES()",
+			sql:      "INSERT INTO users (id, name) VALUES (?, ?) ON DUPLICATE KEY UPDATE name = VALUES(name), score = ?",
+			paramIdx: 2,
+			wantName: "score",
+		},
 		// LIMIT and OFFSET
 		{
 			name:     "LIMIT parameter",
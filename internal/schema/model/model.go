@@ -11,19 +11,21 @@ import (
 
 // Catalog represents the collection of tables and views discovered in DDL files.
 type Catalog struct {
-	Tables  map[string]*Table
-	Views   map[string]*View
-	Enums   map[string]*Enum
-	Domains map[string]*Domain
+	Tables    map[string]*Table
+	Views     map[string]*View
+	Enums     map[string]*Enum
+	Domains   map[string]*Domain
+	Functions map[string]*Function
 }
 
 // NewCatalog constructs a catalog with initialized maps.
 func NewCatalog() *Catalog {
 	return &Catalog{
-		Tables:  make(map[string]*Table),
-		Views:   make(map[string]*View),
-		Enums:   make(map[string]*Enum),
-		Domains: make(map[string]*Domain),
+		Tables:    make(map[string]*Table),
+		Views:     make(map[string]*View),
+		Enums:     make(map[string]*Enum),
+		Domains:   make(map[string]*Domain),
+		Functions: make(map[string]*Function),
 	}
 }
 
@@ -48,7 +50,10 @@ type Column struct {
 	NotNull    bool
 	Default    *Value
 	References *ForeignKeyRef
-	Span       tokenizer.Span
+	// Enum names the catalog enum of an inline enum, such as a MySQL
+	// ENUM('a','b') column or a SQLite CHECK (col IN ('a','b')) constraint.
+	Enum string
+	Span tokenizer.Span
 }
 
 // PrimaryKey captures a table's primary key declaration.
@@ -96,7 +101,8 @@ type View struct {
 	Span tokenizer.Span
 }
 
-// Enum represents a CREATE TYPE ... AS ENUM definition.
+// Enum represents a CREATE TYPE ... AS ENUM definition, or the inline enum
+// of a column. Values are unquoted.
 type Enum struct {
 	Name   string
 	Values []string
@@ -111,14 +117,76 @@ type Domain struct {
 	Span        tokenizer.Span
 }
 
+// Function represents a CREATE FUNCTION definition.
+type Function struct {
+	Name string
+	// Args lists the SQL types of the input arguments.
+	Args []string
+	// Optional is the number of trailing arguments declared with a DEFAULT.
+	Optional int
+	// Variadic reports whether the last argument is declared VARIADIC.
+	Variadic bool
+	// Returns is the SQL result type; it is empty for RETURNS TABLE and void.
+	Returns    string
+	ReturnsSet bool
+	Span       tokenizer.Span
+}
+
 // DomainConstraint represents a constraint on a domain.
 type DomainConstraint struct {
 	Name string
 	Type string // "check", "not_null", "default"
-	Expr string
+	Expr string // SQL text of a CHECK condition or DEFAULT value
 	Span tokenizer.Span
 }
 
+// ColumnEnum returns the enum restricting the values of col: its inline enum,
+// or the enum its type names. It returns nil for other columns.
+func (c *Catalog) ColumnEnum(col *Column) *Enum {
+	if c == nil || col == nil {
+		return nil
+	}
+	name := col.Enum
+	if name == "" {
+		name = col.Type
+	}
+	return lookupType(c.Enums, name)
+}
+
+// ColumnDomain returns the domain col is declared with, or nil.
+func (c *Catalog) ColumnDomain(col *Column) *Domain {
+	if c == nil || col == nil {
+		return nil
+	}
+	return lookupType(c.Domains, col.Type)
+}
+
+// lookupType finds a user-defined type by name, ignoring case and any schema
+// qualifier such as public.status.
+func lookupType[T any](types map[string]*T, name string) *T {
+	if len(types) == 0 || name == "" {
+		return nil
+	}
+	name = strings.ToLower(name)
+	if typ, ok := types[name]; ok {
+		return typ
+	}
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		return types[name[idx+1:]]
+	}
+	return nil
+}
+
+// NotNull reports whether the domain has a NOT NULL constraint.
+func (d *Domain) NotNull() bool {
+	for _, con := range d.Constraints {
+		if con.Type == "not_null" {
+			return true
+		}
+	}
+	return false
+}
+
 // ValueKind identifies the literal kind stored in a Value.
 type ValueKind int
 
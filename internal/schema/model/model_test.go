@@ -232,3 +232,56 @@ func TestIndex(t *testing.T) {
 		t.Errorf("Columns = %v, want [email]", idx.Columns)
 	}
 }
+
+func TestCatalogColumnEnum(t *testing.T) {
+	c := NewCatalog()
+	mood := &Enum{Name: "mood", Values: []string{"sad", "ok"}}
+	status := &Enum{Name: "users_status", Values: []string{"active"}}
+	c.Enums["mood"] = mood
+	c.Enums["users_status"] = status
+
+	tests := []struct {
+		name string
+		col  *Column
+		want *Enum
+	}{
+		{"type names enum", &Column{Name: "feeling", Type: "Mood"}, mood},
+		{"schema-qualified type", &Column{Name: "feeling", Type: "public.mood"}, mood},
+		{"inline enum", &Column{Name: "status", Type: "TEXT", Enum: "users_status"}, status},
+		{"plain column", &Column{Name: "name", Type: "TEXT"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.ColumnEnum(tt.col); got != tt.want {
+				t.Errorf("ColumnEnum() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	var nilCatalog *Catalog
+	if got := nilCatalog.ColumnEnum(&Column{Type: "mood"}); got != nil {
+		t.Errorf("nil catalog ColumnEnum() = %v, want nil", got)
+	}
+}
+
+func TestCatalogColumnDomain(t *testing.T) {
+	c := NewCatalog()
+	email := &Domain{Name: "email_address", BaseType: "TEXT", Constraints: []*DomainConstraint{
+		{Type: "not_null"},
+		{Type: "check", Expr: "VALUE ~ '@'"},
+	}}
+	c.Domains["email_address"] = email
+
+	if got := c.ColumnDomain(&Column{Name: "email", Type: "public.Email_Address"}); got != email {
+		t.Errorf("ColumnDomain() = %v, want %v", got, email)
+	}
+	if got := c.ColumnDomain(&Column{Name: "name", Type: "TEXT"}); got != nil {
+		t.Errorf("ColumnDomain() = %v, want nil", got)
+	}
+	if !email.NotNull() {
+		t.Error("NotNull() = false, want true")
+	}
+	if (&Domain{Name: "positive_money", BaseType: "NUMERIC"}).NotNull() {
+		t.Error("NotNull() = true for a domain without NOT NULL")
+	}
+}
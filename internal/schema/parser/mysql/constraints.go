@@ -53,16 +53,17 @@ func (ps *parserState) parseTableConstraint(table *model.Table) {
 		if ps.matchKeyword(KeywordIndex) || ps.matchKeyword(KeywordKey) {
 			ps.advance()
 		}
-		// Optional index name
+		// Optional index name, which names the key over the constraint name
+		keyName := constraintName
 		if ps.current().Kind == tokenizer.KindIdentifier {
-			ps.advance()
+			keyName, _, _ = ps.parseIdentifier(true)
 		}
 		cols, last, ok := ps.parseColumnNameList()
 		if !ok {
 			return
 		}
 		table.UniqueKeys = append(table.UniqueKeys, &model.UniqueKey{
-			Name:    constraintName,
+			Name:    keyName,
 			Columns: cols,
 			Span:    tokenizer.SpanBetween(start, last),
 		})
@@ -2,7 +2,10 @@ package mysql
 
 import (
 	"context"
+	"slices"
 	"testing"
+
+	"github.com/electwix/db-catalyst/internal/schema/model"
 )
 
 func TestParser_Parse(t *testing.T) {
@@ -200,3 +203,93 @@ func TestParser_AutoIncrement(t *testing.T) {
 		t.Error("Expected primary key for AUTO_INCREMENT column")
 	}
 }
+
+func TestParser_UniqueKeyNames(t *testing.T) {
+	parser := New()
+	ctx := context.Background()
+
+	ddl := `CREATE TABLE users (
+		id INT PRIMARY KEY,
+		email VARCHAR(255) NOT NULL,
+		handle VARCHAR(64) NOT NULL,
+		org_id INT NOT NULL,
+		UNIQUE KEY uq_email (email),
+		CONSTRAINT uq_handle UNIQUE (handle),
+		UNIQUE (org_id, handle)
+	);`
+
+	catalog, _, err := parser.Parse(ctx, "test.sql", []byte(ddl))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	table := catalog.Tables["users"]
+	if table == nil {
+		t.Fatal("Table 'users' not found")
+	}
+	var names []string
+	for _, uk := range table.UniqueKeys {
+		names = append(names, uk.Name)
+	}
+	if want := []string{"uq_email", "uq_handle", ""}; !slices.Equal(names, want) {
+		t.Errorf("unique key names = %q, want %q", names, want)
+	}
+}
+
+func TestParser_EnumColumns(t *testing.T) {
+	parser := New()
+	ctx := context.Background()
+
+	ddl := `CREATE TABLE items (
+		id INT AUTO_INCREMENT PRIMARY KEY,
+		status ENUM('active', 'inactive', 'it''s pending') NOT NULL DEFAULT 'active',
+		name VARCHAR(255) NOT NULL
+	);
+	ALTER TABLE items ADD COLUMN size ENUM('s', 'm', 'l');`
+
+	catalog, diags, err := parser.Parse(ctx, "test.sql", []byte(ddl))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(diags) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	table := catalog.Tables["items"]
+	if table == nil {
+		t.Fatal("Table 'items' not found")
+	}
+	if len(table.Columns) != 4 {
+		t.Fatalf("Expected 4 columns, got %d", len(table.Columns))
+	}
+
+	status := table.Columns[1]
+	if status.Type != "ENUM('active','inactive','it''s pending')" || !status.NotNull {
+		t.Errorf("status column = %q (not null %v)", status.Type, status.NotNull)
+	}
+	if name := table.Columns[2]; name.Type != "VARCHAR(255)" || !name.NotNull {
+		t.Errorf("name column = %q (not null %v)", name.Type, name.NotNull)
+	}
+
+	tests := []struct {
+		column *model.Column
+		name   string
+		values []string
+	}{
+		{table.Columns[1], "items_status", []string{"active", "inactive", "it's pending"}},
+		{table.Columns[3], "items_size", []string{"s", "m", "l"}},
+	}
+	for _, tt := range tests {
+		enum := catalog.ColumnEnum(tt.column)
+		if enum == nil {
+			t.Errorf("column %s: expected enum", tt.column.Name)
+			continue
+		}
+		if enum.Name != tt.name || !slices.Equal(enum.Values, tt.values) {
+			t.Errorf("column %s: enum = %s %v, want %s %v", tt.column.Name, enum.Name, enum.Values, tt.name, tt.values)
+		}
+	}
+	if enum := catalog.ColumnEnum(table.Columns[2]); enum != nil {
+		t.Errorf("name column should not be an enum, got %+v", enum)
+	}
+}
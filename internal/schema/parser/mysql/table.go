@@ -61,6 +61,7 @@ func (ps *parserState) parseAlter() {
 	}
 
 	table.Columns = append(table.Columns, res.column)
+	ps.registerColumnEnum(table, res.column, res.enumValues)
 
 	if res.pk != nil {
 		if table.PrimaryKey != nil {
@@ -152,6 +153,7 @@ func (ps *parserState) parseCreateTable() {
 		} else {
 			table.Columns = append(table.Columns, res.column)
 			colSeen[canon] = res.column.Span
+			ps.registerColumnEnum(table, res.column, res.enumValues)
 		}
 
 		if res.pk != nil {
@@ -202,11 +204,12 @@ func (ps *parserState) parseCreateTable() {
 
 // columnResult holds the result of parsing a column definition.
 type columnResult struct {
-	column  *model.Column
-	pk      *model.PrimaryKey
-	unique  *model.UniqueKey
-	foreign *model.ForeignKey
-	lastTok tokenizer.Token
+	column     *model.Column
+	pk         *model.PrimaryKey
+	unique     *model.UniqueKey
+	foreign    *model.ForeignKey
+	enumValues []string
+	lastTok    tokenizer.Token
 }
 
 // parseColumnDefinition parses a column definition with MySQL-specific types.
@@ -225,9 +228,10 @@ func (ps *parserState) parseColumnDefinition() (*columnResult, bool) {
 	}
 
 	// Parse column type (MySQL types can be complex with attributes)
-	typeStr, lastTypeTok, ok := ps.parseColumnType()
+	typeStr, enumValues, lastTypeTok, ok := ps.parseColumnType()
 	if ok {
 		res.column.Type = typeStr
+		res.enumValues = enumValues
 		res.lastTok = lastTypeTok
 	}
 
@@ -359,15 +363,18 @@ func (ps *parserState) parseColumnDefinition() (*columnResult, bool) {
 	return res, true
 }
 
-// parseColumnType parses a MySQL column type including ENUM/SET.
-func (ps *parserState) parseColumnType() (string, tokenizer.Token, bool) {
+// parseColumnType parses a MySQL column type including ENUM/SET. For ENUM
+// types the unquoted values are returned as well.
+func (ps *parserState) parseColumnType() (string, []string, tokenizer.Token, bool) {
 	tok := ps.current()
 	if tok.Kind != tokenizer.KindIdentifier && tok.Kind != tokenizer.KindKeyword {
-		return "", tok, false
+		return "", nil, tok, false
 	}
 
 	typeParts := []string{tok.Text}
 	lastTok := tok
+	isEnum := strings.EqualFold(tok.Text, "ENUM")
+	var enumValues []string
 	ps.advance()
 
 	// Handle type modifiers like VARCHAR(255), DECIMAL(10,2), ENUM('a','b')
@@ -375,27 +382,26 @@ func (ps *parserState) parseColumnType() (string, tokenizer.Token, bool) {
 		depth := 0
 		for !ps.isEOF() {
 			t := ps.current()
-			if t.Kind == tokenizer.KindSymbol {
-				switch t.Text {
-				case "(":
-					depth++
-				case ")":
-					depth--
-					if depth < 0 {
-						// End of type modifier
-						lastTok = t
-						ps.advance()
-						goto checkAttributes
-					}
-				}
-			}
 			typeParts = append(typeParts, t.Text)
 			lastTok = t
 			ps.advance()
+			if t.Kind == tokenizer.KindString && isEnum {
+				enumValues = append(enumValues, tokenizer.StringValue(t.Text))
+			}
+			if t.Kind != tokenizer.KindSymbol {
+				continue
+			}
+			if t.Text == "(" {
+				depth++
+			} else if t.Text == ")" {
+				depth--
+				if depth == 0 {
+					break
+				}
+			}
 		}
 	}
 
-checkAttributes:
 	// Check for UNSIGNED, SIGNED, ZEROFILL
 	for ps.matchKeyword("UNSIGNED") || ps.matchKeyword("SIGNED") || ps.matchKeyword("ZEROFILL") {
 		attrTok := ps.advance()
@@ -403,7 +409,18 @@ checkAttributes:
 		lastTok = attrTok
 	}
 
-	return strings.Join(typeParts, ""), lastTok, true
+	return strings.Join(typeParts, ""), enumValues, lastTok, true
+}
+
+// registerColumnEnum records an inline ENUM column type as a catalog enum
+// named after its table and column.
+func (ps *parserState) registerColumnEnum(table *model.Table, col *model.Column, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	name := table.Name + "_" + col.Name
+	ps.catalog.Enums[canonicalName(name)] = &model.Enum{Name: name, Values: values, Span: col.Span}
+	col.Enum = name
 }
 
 // parseTableOptions parses MySQL table options like ENGINE, CHARSET, etc.
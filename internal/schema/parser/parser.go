@@ -237,6 +237,7 @@ func (p *Parser) parseCreateTable(createTok tokenizer.Token) {
 		} else {
 			table.Columns = append(table.Columns, res.column)
 			colSeen[canon] = res.column.Span
+			p.registerColumnEnum(table, res.column, res.enumValues)
 		}
 		if res.pk != nil {
 			if table.PrimaryKey != nil {
@@ -370,6 +371,13 @@ func (p *Parser) parseTableConstraint(table *model.Table) {
 		table.ForeignKeys = append(table.ForeignKeys, fk)
 	case "CHECK":
 		p.advance()
+		if colName, values, ok := p.checkInValues(); ok {
+			for _, col := range table.Columns {
+				if canonicalName(col.Name) == canonicalName(colName) {
+					p.registerColumnEnum(table, col, values)
+				}
+			}
+		}
 		p.skipCheckConstraint()
 	default:
 		p.addDiagToken(tok, SeverityError, "unsupported table constraint %s", tok.Text)
@@ -569,6 +577,7 @@ func (p *Parser) parseAlter() {
 		return
 	}
 	table.Columns = append(table.Columns, res.column)
+	p.registerColumnEnum(table, res.column, res.enumValues)
 	if res.pk != nil {
 		if table.PrimaryKey != nil {
 			p.addDiagSpan(res.pk.Span, SeverityError, "table %s already has a primary key", table.Name)
@@ -780,6 +789,8 @@ type columnResult struct {
 	unique  *model.UniqueKey
 	foreign *model.ForeignKey
 	lastTok tokenizer.Token
+	// enumValues holds the values a CHECK (col IN (...)) constraint allows
+	enumValues []string
 }
 
 func (p *Parser) parseColumnDefinition() (*columnResult, bool) {
@@ -883,6 +894,9 @@ func (p *Parser) parseColumnDefinition() (*columnResult, bool) {
 			res.lastTok = uniqTok
 		case "CHECK":
 			checkTok := p.advance()
+			if colName, values, ok := p.checkInValues(); ok && canonicalName(colName) == canonicalName(res.column.Name) {
+				res.enumValues = values
+			}
 			if last := p.skipCheckConstraint(); last.Line != 0 {
 				res.lastTok = last
 			} else {
@@ -1117,6 +1131,52 @@ func (p *Parser) skipCheckConstraint() tokenizer.Token {
 	return last
 }
 
+// checkInValues recognizes a CHECK constraint expression of the form
+// (col IN ('a', 'b')) at the current position without consuming it, and
+// returns the column and the unquoted values it allows.
+func (p *Parser) checkInValues() (string, []string, bool) {
+	toks := p.tokens[p.pos:]
+	isSymbol := func(i int, text string) bool {
+		return i < len(toks) && toks[i].Kind == tokenizer.KindSymbol && toks[i].Text == text
+	}
+	if !isSymbol(0, "(") || len(toks) < 4 ||
+		(toks[1].Kind != tokenizer.KindIdentifier && toks[1].Kind != tokenizer.KindKeyword) ||
+		!strings.EqualFold(toks[2].Text, "IN") || !isSymbol(3, "(") {
+		return "", nil, false
+	}
+	var values []string
+	i := 4
+	for {
+		if i >= len(toks) || toks[i].Kind != tokenizer.KindString {
+			return "", nil, false
+		}
+		values = append(values, tokenizer.StringValue(toks[i].Text))
+		i++
+		if isSymbol(i, ")") {
+			break
+		}
+		if !isSymbol(i, ",") {
+			return "", nil, false
+		}
+		i++
+	}
+	if !isSymbol(i+1, ")") {
+		return "", nil, false
+	}
+	return tokenizer.NormalizeIdentifier(toks[1].Text), values, true
+}
+
+// registerColumnEnum records values as the inline enum of col, named after
+// its table and column.
+func (p *Parser) registerColumnEnum(table *model.Table, col *model.Column, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	name := table.Name + "_" + col.Name
+	p.catalog.Enums[canonicalName(name)] = &model.Enum{Name: name, Values: values, Span: col.Span}
+	col.Enum = name
+}
+
 func (p *Parser) skipForeignKeyActions() tokenizer.Token {
 	var last tokenizer.Token
 	depth := 0
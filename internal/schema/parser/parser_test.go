@@ -91,6 +91,43 @@ func TestAlterTableAddColumn(t *testing.T) {
 	}
 }
 
+func TestCheckInEnums(t *testing.T) {
+	catalog, diags := parseFixture(t, "check_enums.sql")
+	if hasErrors(diags) {
+		t.Fatalf("unexpected diagnostics: %s", formatDiagnostics(diags))
+	}
+	table := lookupTable(t, catalog, "tickets")
+	want := map[string][]string{
+		"status":   {"open", "in_progress", "closed"},
+		"priority": {"low", "high"},
+		"title":    nil,
+		"kind":     {"bug", "it's a feature"},
+	}
+	for _, col := range table.Columns {
+		values, ok := want[col.Name]
+		if !ok {
+			continue
+		}
+		enum := catalog.ColumnEnum(col)
+		if values == nil {
+			if enum != nil {
+				t.Errorf("column %s should not be an enum, got %+v", col.Name, enum)
+			}
+			continue
+		}
+		if enum == nil {
+			t.Errorf("column %s should be an enum", col.Name)
+			continue
+		}
+		if enum.Name != "tickets_"+col.Name || !slices.Equal(enum.Values, values) {
+			t.Errorf("column %s enum = %s %v, want tickets_%s %v", col.Name, enum.Name, enum.Values, col.Name, values)
+		}
+	}
+	if !table.Columns[1].NotNull || table.Columns[1].Type != "TEXT" {
+		t.Errorf("status column should keep its type and NOT NULL, got %+v", table.Columns[1])
+	}
+}
+
 func TestIndexUnknownTableDiagnostic(t *testing.T) {
 	_, diags := parseFixture(t, "bad_index.sql")
 	if !containsMessage(diags, "index") {
@@ -220,7 +220,7 @@ func (ps *parserState) skipCheckConstraint() tokenizer.Token {
 
 	for !ps.isEOF() {
 		tok := ps.current()
-		if tok.Kind == tokenizer.KindSymbol && (tok.Text == "," || tok.Text == ")") {
+		if tok.Kind == tokenizer.KindSymbol && (tok.Text == "," || tok.Text == ")" || tok.Text == ";") {
 			break
 		}
 		if tok.Kind == tokenizer.KindKeyword && isClauseBoundaryKeyword(tok.Text) {
@@ -0,0 +1,230 @@
+package postgres
+
+import (
+	"strings"
+
+	"github.com/electwix/db-catalyst/internal/schema/model"
+	"github.com/electwix/db-catalyst/internal/schema/tokenizer"
+)
+
+// functionClauseWords end the RETURNS type of a CREATE FUNCTION statement.
+var functionClauseWords = map[string]struct{}{
+	"AS": {}, "BEGIN": {}, "CALLED": {}, "COST": {}, "EXTERNAL": {}, "IMMUTABLE": {},
+	"LANGUAGE": {}, "LEAKPROOF": {}, "NOT": {}, "PARALLEL": {}, "RETURN": {}, "RETURNS": {},
+	"ROWS": {}, "SECURITY": {}, "SET": {}, "STABLE": {}, "STRICT": {}, "SUPPORT": {},
+	"TRANSFORM": {}, "VOLATILE": {}, "WINDOW": {},
+}
+
+// typePrefixWords start multi-word type names such as DOUBLE PRECISION, so an
+// argument beginning with one of them has no parameter name.
+var typePrefixWords = map[string]struct{}{
+	"BIT": {}, "CHAR": {}, "CHARACTER": {}, "DOUBLE": {}, "INTERVAL": {},
+	"NATIONAL": {}, "TIME": {}, "TIMESTAMP": {},
+}
+
+// parseCreateFunction handles CREATE FUNCTION statements. Only the signature
+// is recorded; the body is skipped. Overloads replace earlier definitions of
+// the same name.
+func (ps *parserState) parseCreateFunction() {
+	createTok := ps.previous()
+
+	name, _, ok := ps.parseObjectName()
+	if !ok {
+		ps.sync()
+		return
+	}
+	if !ps.expectSymbol("(") {
+		ps.sync()
+		return
+	}
+
+	fn := &model.Function{Name: name}
+	ps.parseFunctionArgs(fn)
+
+	if ps.matchWord("RETURNS") {
+		ps.advance()
+		ps.parseFunctionReturns(fn)
+	}
+
+	last := ps.skipFunctionBody()
+	if ps.matchSymbol(";") {
+		last = ps.advance()
+	}
+	if last.Line == 0 {
+		last = createTok
+	}
+	fn.Span = tokenizer.SpanBetween(createTok, last)
+
+	ps.catalog.Functions[canonicalName(name)] = fn
+}
+
+// parseFunctionArgs parses the argument list after the opening parenthesis,
+// recording the types of the input arguments.
+func (ps *parserState) parseFunctionArgs(fn *model.Function) {
+	var arg []tokenizer.Token
+	depth := 0
+	for !ps.isEOF() {
+		tok := ps.advance()
+		if tok.Kind == tokenizer.KindSymbol {
+			switch tok.Text {
+			case "(":
+				depth++
+			case ")":
+				if depth == 0 {
+					addFunctionArg(fn, arg)
+					return
+				}
+				depth--
+			case ",":
+				if depth == 0 {
+					addFunctionArg(fn, arg)
+					arg = arg[:0]
+					continue
+				}
+			}
+		}
+		arg = append(arg, tok)
+	}
+}
+
+// addFunctionArg records one argument declaration of the form
+// [mode] [name] type [DEFAULT expr].
+func addFunctionArg(fn *model.Function, tokens []tokenizer.Token) {
+	hasDefault := false
+	for i, tok := range tokens {
+		if isWordToken(tok, "DEFAULT") || (tok.Kind == tokenizer.KindSymbol && tok.Text == "=") {
+			tokens = tokens[:i]
+			hasDefault = true
+			break
+		}
+	}
+	if len(tokens) == 0 {
+		return
+	}
+
+	switch strings.ToUpper(tokens[0].Text) {
+	case "OUT":
+		// Output arguments are not passed by the caller.
+		return
+	case "VARIADIC":
+		fn.Variadic = true
+		tokens = tokens[1:]
+	case "IN", "INOUT":
+		tokens = tokens[1:]
+	}
+	if len(tokens) > 1 && isWordToken(tokens[0], tokens[0].Text) && isWordToken(tokens[1], tokens[1].Text) {
+		if _, ok := typePrefixWords[strings.ToUpper(tokens[0].Text)]; !ok {
+			tokens = tokens[1:]
+		}
+	}
+
+	fn.Args = append(fn.Args, joinTypeTokens(tokens))
+	if hasDefault {
+		fn.Optional++
+	} else {
+		// Only trailing arguments may be omitted.
+		fn.Optional = 0
+	}
+}
+
+// parseFunctionReturns parses the type following RETURNS.
+func (ps *parserState) parseFunctionReturns(fn *model.Function) {
+	if ps.matchWord("SETOF") {
+		ps.advance()
+		fn.ReturnsSet = true
+	}
+	if ps.matchWord("TABLE") {
+		ps.advance()
+		fn.ReturnsSet = true
+		if ps.matchSymbol("(") {
+			ps.skipBalancedParentheses()
+		}
+		return
+	}
+
+	var typ []tokenizer.Token
+	depth := 0
+	for !ps.isEOF() {
+		tok := ps.current()
+		if tok.Kind == tokenizer.KindSymbol {
+			switch tok.Text {
+			case "(":
+				depth++
+			case ")":
+				depth--
+			case ";":
+				if depth <= 0 {
+					fn.Returns = joinTypeTokens(typ)
+					return
+				}
+			}
+		}
+		if depth == 0 && len(typ) > 0 {
+			if _, ok := functionClauseWords[strings.ToUpper(tok.Text)]; ok && isWordToken(tok, tok.Text) {
+				break
+			}
+		}
+		typ = append(typ, tok)
+		ps.advance()
+	}
+	fn.Returns = joinTypeTokens(typ)
+}
+
+// skipFunctionBody skips the remaining function options, including
+// dollar-quoted bodies that may contain semicolons, up to the terminating
+// semicolon.
+func (ps *parserState) skipFunctionBody() tokenizer.Token {
+	var last tokenizer.Token
+	for !ps.isEOF() {
+		tok := ps.current()
+		if tok.Kind == tokenizer.KindSymbol && tok.Text == ";" {
+			return last
+		}
+		last = ps.advance()
+		if !isDollarQuote(tok) {
+			continue
+		}
+		for !ps.isEOF() {
+			last = ps.advance()
+			if last.Kind == tok.Kind && last.Text == tok.Text {
+				break
+			}
+		}
+	}
+	return last
+}
+
+// matchWord reports whether the current token is the given word, whether it
+// was scanned as a keyword or an identifier.
+func (ps *parserState) matchWord(text string) bool {
+	return isWordToken(ps.current(), text)
+}
+
+// isWordToken reports whether tok is a keyword or identifier spelled text.
+func isWordToken(tok tokenizer.Token, text string) bool {
+	if tok.Kind != tokenizer.KindKeyword && tok.Kind != tokenizer.KindIdentifier {
+		return false
+	}
+	return strings.EqualFold(tok.Text, text)
+}
+
+// isDollarQuote reports whether tok opens a dollar-quoted string such as $$ or $body$.
+func isDollarQuote(tok tokenizer.Token) bool {
+	return tok.Kind == tokenizer.KindIdentifier && len(tok.Text) >= 2 &&
+		strings.HasPrefix(tok.Text, "$") && strings.HasSuffix(tok.Text, "$")
+}
+
+// joinTypeTokens rebuilds a type name, separating adjacent words with a space.
+func joinTypeTokens(tokens []tokenizer.Token) string {
+	var b strings.Builder
+	prevWord := false
+	for _, tok := range tokens {
+		word := tok.Kind == tokenizer.KindKeyword || tok.Kind == tokenizer.KindIdentifier
+		if word && prevWord {
+			b.WriteByte(' ')
+		}
+		b.WriteString(tok.Text)
+		prevWord = word
+	}
+	return b.String()
+}
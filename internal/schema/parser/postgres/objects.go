@@ -212,7 +212,7 @@ func (ps *parserState) parseEnumType(name string) {
 		}
 
 		if tok.Kind == tokenizer.KindString {
-			values = append(values, tok.Text)
+			values = append(values, tokenizer.StringValue(tok.Text))
 			ps.advance()
 		} else {
 			ps.addDiagToken(tok, diagnostic.SeverityError, "expected string literal for enum value")
@@ -272,7 +272,8 @@ func (ps *parserState) parseCreateDomain() {
 		BaseType: baseType,
 	}
 
-	// Parse constraints
+	// Parse constraints, each optionally named by a preceding CONSTRAINT
+	var constraintName string
 	for !ps.isEOF() {
 		tok := ps.current()
 		if tok.Kind == tokenizer.KindSymbol && (tok.Text == ";" || tok.Text == ")") {
@@ -282,29 +283,48 @@ func (ps *parserState) parseCreateDomain() {
 		if tok.Kind == tokenizer.KindKeyword {
 			switch tok.Text {
 			case "DEFAULT":
-				ps.advance()
-				_, _ = ps.parseDefaultValue()
+				defaultTok := ps.advance()
+				start := ps.pos
+				if _, last := ps.parseDefaultValue(); last.Line != 0 {
+					domain.Constraints = append(domain.Constraints, &model.DomainConstraint{
+						Name: constraintName,
+						Type: "default",
+						Expr: tokenizer.Join(ps.tokens[start:ps.pos]),
+						Span: tokenizer.SpanBetween(defaultTok, last),
+					})
+				}
+				constraintName = ""
 			case "NOT":
-				ps.advance()
+				notTok := ps.advance()
 				if ps.matchKeyword("NULL") {
-					ps.advance()
+					nullTok := ps.advance()
 					domain.Constraints = append(domain.Constraints, &model.DomainConstraint{
+						Name: constraintName,
 						Type: "not_null",
+						Span: tokenizer.SpanBetween(notTok, nullTok),
 					})
 				}
+				constraintName = ""
 			case KeywordCheck:
 				checkTok := ps.advance()
+				start := ps.pos
 				if last := ps.skipCheckConstraint(); last.Line != 0 {
+					expr := ps.tokens[start:ps.pos]
+					// Drop the parentheses around the condition
+					if len(expr) >= 2 && expr[0].Text == "(" && expr[len(expr)-1].Text == ")" {
+						expr = expr[1 : len(expr)-1]
+					}
 					domain.Constraints = append(domain.Constraints, &model.DomainConstraint{
+						Name: constraintName,
 						Type: "check",
-						Expr: "check constraint", // Simplified
+						Expr: tokenizer.Join(expr),
 						Span: tokenizer.SpanBetween(checkTok, last),
 					})
 				}
+				constraintName = ""
 			case "CONSTRAINT":
 				ps.advance()
-				// Skip constraint name
-				ps.parseIdentifier(true)
+				constraintName, _, _ = ps.parseIdentifier(true)
 			default:
 				ps.advance()
 			}
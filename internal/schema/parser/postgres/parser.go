@@ -7,6 +7,7 @@
 //   - UUID type
 //   - CREATE TYPE for enums
 //   - Domain constraints
+//   - CREATE FUNCTION signatures and return types
 //   - PostgreSQL-specific syntax variations
 package postgres
 
@@ -147,7 +148,7 @@ func (ps *parserState) parseCreate() {
 	// Accept keywords or identifiers that match expected CREATE targets
 	// (PostgreSQL-specific keywords like TYPE, DOMAIN may be tokenized as identifiers)
 	if tok.Kind != tokenizer.KindKeyword && tok.Kind != tokenizer.KindIdentifier {
-		ps.addDiagToken(tok, diagnostic.SeverityError, "expected TABLE, INDEX, VIEW, TYPE, or DOMAIN after CREATE")
+		ps.addDiagToken(tok, diagnostic.SeverityError, "expected TABLE, INDEX, VIEW, TYPE, DOMAIN, or FUNCTION after CREATE")
 		ps.sync()
 		return
 	}
@@ -168,6 +169,9 @@ func (ps *parserState) parseCreate() {
 	case "DOMAIN":
 		ps.advance()
 		ps.parseCreateDomain()
+	case "FUNCTION":
+		ps.advance()
+		ps.parseCreateFunction()
 	default:
 		ps.addDiagToken(tok, diagnostic.SeverityError, "unsupported CREATE target %s", tok.Text)
 		ps.sync()
@@ -2,7 +2,10 @@ package postgres
 
 import (
 	"context"
+	"slices"
 	"testing"
+
+	"github.com/electwix/db-catalyst/internal/schema/model"
 )
 
 func TestParser_Parse(t *testing.T) {
@@ -264,8 +267,8 @@ func TestParser_Enums(t *testing.T) {
 	if userStatus == nil {
 		t.Fatal("Enum 'user_status' not found")
 	}
-	if len(userStatus.Values) != 3 {
-		t.Errorf("Expected 3 values in user_status, got %d", len(userStatus.Values))
+	if want := []string{"pending", "active", "completed"}; !slices.Equal(userStatus.Values, want) {
+		t.Errorf("Expected user_status values %v, got %v", want, userStatus.Values)
 	}
 
 	// Verify priority enum
@@ -316,6 +319,123 @@ func TestParser_Domains(t *testing.T) {
 	}
 }
 
+func TestParser_DomainConstraints(t *testing.T) {
+	parser := New()
+	ctx := context.Background()
+
+	ddl := `CREATE DOMAIN email_address AS TEXT
+		CONSTRAINT email_not_null NOT NULL
+		CONSTRAINT email_format CHECK (VALUE ~* '^[^@\s]+@[^@\s]+$');
+
+	CREATE DOMAIN positive_money AS NUMERIC(12, 2) DEFAULT 0 CHECK (value > 0 and value<=  1000000);`
+
+	catalog, diags, err := parser.Parse(ctx, "test.sql", []byte(ddl))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(diags) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	tests := []struct {
+		domain string
+		want   []model.DomainConstraint
+	}{
+		{"email_address", []model.DomainConstraint{
+			{Name: "email_not_null", Type: "not_null"},
+			{Name: "email_format", Type: "check", Expr: `VALUE ~* '^[^@\s]+@[^@\s]+$'`},
+		}},
+		{"positive_money", []model.DomainConstraint{
+			{Type: "default", Expr: "0"},
+			{Type: "check", Expr: "value > 0 AND value<= 1000000"},
+		}},
+	}
+	for _, tt := range tests {
+		domain := catalog.Domains[tt.domain]
+		if domain == nil {
+			t.Fatalf("Domain %q not found", tt.domain)
+		}
+		if len(domain.Constraints) != len(tt.want) {
+			t.Fatalf("domain %s: expected %d constraints, got %d", tt.domain, len(tt.want), len(domain.Constraints))
+		}
+		for i, want := range tt.want {
+			got := domain.Constraints[i]
+			if got.Name != want.Name || got.Type != want.Type || got.Expr != want.Expr {
+				t.Errorf("domain %s constraint %d = {%q %q %q}, want {%q %q %q}", tt.domain, i, got.Name, got.Type, got.Expr, want.Name, want.Type, want.Expr)
+			}
+		}
+	}
+	if !catalog.Domains["email_address"].NotNull() {
+		t.Error("email_address should be NOT NULL")
+	}
+}
+
+func TestParser_Functions(t *testing.T) {
+	parser := New()
+	ctx := context.Background()
+
+	ddl := `CREATE OR REPLACE FUNCTION haversine(lat1 DOUBLE PRECISION, lon1 DOUBLE PRECISION, lat2 float8, lon2 float8)
+	RETURNS DOUBLE PRECISION
+	LANGUAGE sql IMMUTABLE
+	AS $$ SELECT 6371 * acos(1); $$;
+
+	CREATE FUNCTION slugify(input TEXT, sep TEXT DEFAULT '-') RETURNS TEXT AS $body$
+	BEGIN
+		RETURN lower(replace(input, ' ', sep));
+	END;
+	$body$ LANGUAGE plpgsql;
+
+	CREATE FUNCTION recent_ids(OUT id BIGINT, IN since TIMESTAMP WITH TIME ZONE) RETURNS SETOF BIGINT AS $$ SELECT 1 $$ LANGUAGE sql;
+
+	CREATE FUNCTION user_rows() RETURNS TABLE (id INT, name TEXT) AS $$ SELECT 1, 'a' $$ LANGUAGE sql;
+
+	CREATE TABLE users (id SERIAL PRIMARY KEY);`
+
+	catalog, diags, err := parser.Parse(ctx, "test.sql", []byte(ddl))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(diags) != 0 {
+		t.Fatalf("unexpected diagnostics: %+v", diags)
+	}
+	if catalog.Tables["users"] == nil {
+		t.Fatal("table after function bodies was not parsed")
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		optional   int
+		returns    string
+		returnsSet bool
+	}{
+		{name: "haversine", args: []string{"DOUBLE PRECISION", "DOUBLE PRECISION", "float8", "float8"}, returns: "DOUBLE PRECISION"},
+		{name: "slugify", args: []string{"TEXT", "TEXT"}, optional: 1, returns: "TEXT"},
+		{name: "recent_ids", args: []string{"TIMESTAMP WITH TIME ZONE"}, returns: "BIGINT", returnsSet: true},
+		{name: "user_rows", returnsSet: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn := catalog.Functions[tt.name]
+			if fn == nil {
+				t.Fatalf("function %q not found", tt.name)
+			}
+			if !slices.Equal(fn.Args, tt.args) {
+				t.Errorf("Args = %q, want %q", fn.Args, tt.args)
+			}
+			if fn.Optional != tt.optional {
+				t.Errorf("Optional = %d, want %d", fn.Optional, tt.optional)
+			}
+			if fn.Returns != tt.returns {
+				t.Errorf("Returns = %q, want %q", fn.Returns, tt.returns)
+			}
+			if fn.ReturnsSet != tt.returnsSet {
+				t.Errorf("ReturnsSet = %v, want %v", fn.ReturnsSet, tt.returnsSet)
+			}
+		})
+	}
+}
+
 func TestParser_ConstraintValidation(t *testing.T) {
 	parser := New()
 	ctx := context.Background()
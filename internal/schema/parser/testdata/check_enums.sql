@@ -0,0 +1,9 @@
+CREATE TABLE tickets (
+    id INTEGER PRIMARY KEY,
+    status TEXT NOT NULL CHECK (status IN ('open', 'in_progress', 'closed')),
+    priority TEXT,
+    title TEXT CHECK (length(title) > 0),
+    CHECK (priority IN ('low', 'high'))
+);
+
+ALTER TABLE tickets ADD COLUMN kind TEXT CHECK (kind IN ('bug', 'it''s a feature'));
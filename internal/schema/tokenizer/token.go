@@ -164,6 +164,31 @@ func NormalizeIdentifier(text string) string {
 	}
 }
 
+// StringValue removes the quotes of a string literal while unescaping
+// doubled quotes. Other text is returned unchanged.
+func StringValue(text string) string {
+	if len(text) < minQuotedIdentLen || text[0] != '\'' || text[len(text)-1] != '\'' {
+		return text
+	}
+	return strings.ReplaceAll(text[1:len(text)-1], "''", "'")
+}
+
+// Join rebuilds the source text of consecutive tokens, separating two tokens
+// by a single space wherever the source had whitespace between them.
+func Join(tokens []Token) string {
+	var b strings.Builder
+	for i, tok := range tokens {
+		if i > 0 {
+			prev := tokens[i-1]
+			if tok.Line != prev.Line || tok.Column != prev.Column+utf8.RuneCountInString(prev.Text) {
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(tok.Text)
+	}
+	return b.String()
+}
+
 var keywords = map[string]struct{}{
 	"ABORT":         {},
 	"ACTION":        {},
@@ -206,6 +206,32 @@ func TestNormalizeIdentifier(t *testing.T) {
 	}
 }
 
+func TestStringValue(t *testing.T) {
+	cases := map[string]string{
+		"'active'":  "active",
+		"'it''s'":   "it's",
+		"''":        "",
+		"plain":     "plain",
+		"'unclosed": "'unclosed",
+	}
+	for input, want := range cases {
+		if got := StringValue(input); got != want {
+			t.Fatalf("StringValue(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestJoin(t *testing.T) {
+	tokens, err := Scan("join.sql", []byte("length( VALUE )<=10 AND\n  VALUE ~* 'a b'"), false)
+	if err != nil {
+		t.Fatalf("Scan error: %v", err)
+	}
+	tokens = tokens[:len(tokens)-1] // drop EOF
+	if got, want := Join(tokens), "length( VALUE )<=10 AND VALUE ~* 'a b'"; got != want {
+		t.Fatalf("Join() = %q, want %q", got, want)
+	}
+}
+
 func BenchmarkScan(b *testing.B) {
 	schema := []byte(`CREATE TABLE authors (
     id INTEGER PRIMARY KEY,
@@ -2,6 +2,7 @@ package sqlfix
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/electwix/db-catalyst/internal/schema/model"
@@ -128,3 +129,88 @@ func TestRunner_UnresolvedAliasStar(t *testing.T) {
 		t.Fatal("expected warning for unresolved alias")
 	}
 }
+
+func TestRunner_ExpandDerivedTableStar(t *testing.T) {
+	catalog := model.NewCatalog()
+	catalog.Tables["users"] = &model.Table{
+		Name:    "users",
+		Columns: []*model.Column{{Name: "id"}, {Name: "email"}},
+	}
+	catalog.Tables["posts"] = &model.Table{
+		Name:    "posts",
+		Columns: []*model.Column{{Name: "user_id"}, {Name: "title"}},
+	}
+
+	tests := []struct {
+		name     string
+		sql      string
+		expected string
+	}{
+		{
+			"unqualified star",
+			"SELECT *\nFROM (SELECT id, email AS contact FROM users) AS t;",
+			"SELECT id, contact\nFROM (SELECT id, email AS contact FROM users) AS t;",
+		},
+		{
+			"qualified star with nested star",
+			"SELECT u.id, t.*\nFROM users u\nJOIN (SELECT * FROM posts) t ON t.user_id = u.id;",
+			"SELECT u.id, t.user_id, t.title\nFROM users u\nJOIN (SELECT * FROM posts) t ON t.user_id = u.id;",
+		},
+		{
+			"column alias list",
+			"SELECT t.*\nFROM (SELECT id, email FROM users) AS t(a, b);",
+			"SELECT t.a, t.b\nFROM (SELECT id, email FROM users) AS t(a, b);",
+		},
+		{
+			"lateral",
+			"SELECT l.*\nFROM users u\nCROSS JOIN LATERAL (SELECT p.title FROM posts p WHERE p.user_id = u.id) l;",
+			"SELECT l.title\nFROM users u\nCROSS JOIN LATERAL (SELECT p.title FROM posts p WHERE p.user_id = u.id) l;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRunner()
+			r.readFile = func(string) ([]byte, error) {
+				return []byte("-- name: Derived :many\n" + tt.sql + "\n"), nil
+			}
+			r.writeFile = func(string, []byte) error { return nil }
+			r.SetCatalog(catalog, nil)
+
+			report, out, err := r.rewriteFile(context.Background(), "queries.sql")
+			if err != nil {
+				t.Fatalf("rewriteFile: %v", err)
+			}
+
+			expected := "-- name: Derived :many\n" + tt.expected + "\n"
+			if string(out) != expected {
+				t.Fatalf("unexpected output:\nwant %q\n got %q\nwarnings: %v", expected, string(out), report.Warnings)
+			}
+			if report.ExpandedStars != 1 {
+				t.Fatalf("expected 1 expanded star, got %d", report.ExpandedStars)
+			}
+		})
+	}
+}
+
+func TestRunner_DerivedTableUnnamedColumn(t *testing.T) {
+	r := NewRunner()
+	r.readFile = func(string) ([]byte, error) {
+		return []byte("-- name: Counts :many\nSELECT *\nFROM (SELECT COUNT(*) FROM users) AS t;\n"), nil
+	}
+	r.writeFile = func(string, []byte) error { return nil }
+	catalog := model.NewCatalog()
+	catalog.Tables["users"] = &model.Table{Name: "users", Columns: []*model.Column{{Name: "id"}}}
+	r.SetCatalog(catalog, nil)
+
+	report, _, err := r.rewriteFile(context.Background(), "queries.sql")
+	if err != nil {
+		t.Fatalf("rewriteFile: %v", err)
+	}
+	if report.ExpandedStars != 0 {
+		t.Fatalf("expected no expanded stars, got %d", report.ExpandedStars)
+	}
+	if len(report.Warnings) == 0 || !strings.Contains(report.Warnings[0], "has no name") {
+		t.Fatalf("expected unnamed column warning, got %v", report.Warnings)
+	}
+}
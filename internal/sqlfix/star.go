@@ -21,6 +21,10 @@ type relationRef struct {
 	tableCanonical  string
 	tableNormalized string
 	baseTable       bool
+	// subquery holds the tokens of a derived table's SELECT, and columnList
+	// its column alias list, if any.
+	subquery   []schematokenizer.Token
+	columnList []string
 }
 
 type starExpression struct {
@@ -254,7 +258,7 @@ func parseSingleRelation(tokens []schematokenizer.Token, idx int) (*relationRef,
 	}
 
 	tok := tokens[i]
-	if tok.Kind == schematokenizer.KindKeyword {
+	if tok.Kind == schematokenizer.KindKeyword || tok.Kind == schematokenizer.KindIdentifier {
 		if strings.ToUpper(tok.Text) == "LATERAL" {
 			i++
 			for i < len(tokens) && tokens[i].Kind == schematokenizer.KindDocComment {
@@ -268,6 +272,7 @@ func parseSingleRelation(tokens []schematokenizer.Token, idx int) (*relationRef,
 	}
 
 	if tok.Kind == schematokenizer.KindSymbol && tok.Text == "(" {
+		open := i
 		depth := 1
 		i++
 		for i < len(tokens) && depth > 0 {
@@ -282,15 +287,22 @@ func parseSingleRelation(tokens []schematokenizer.Token, idx int) (*relationRef,
 			}
 			i++
 		}
+		var subquery []schematokenizer.Token
+		if open+1 < i-1 && isSubqueryStart(tokens[open+1]) {
+			subquery = tokens[open+1 : i-1]
+		}
 		aliasToken, next := parseAliasToken(tokens, i)
 		if aliasToken == "" {
-			return &relationRef{baseTable: false}, next
+			return &relationRef{baseTable: false, subquery: subquery}, next
 		}
+		columnList, next := parseColumnList(tokens, next)
 		aliasNorm := schematokenizer.NormalizeIdentifier(aliasToken)
 		return &relationRef{
 			aliasCanonical:  canonicalIdent(aliasToken),
 			aliasNormalized: aliasNorm,
 			baseTable:       false,
+			subquery:        subquery,
+			columnList:      columnList,
 		}, next
 	}
 
@@ -356,6 +368,33 @@ func parseAliasToken(tokens []schematokenizer.Token, idx int) (string, int) {
 	return "", idx
 }
 
+// parseColumnList reads the column alias list of a derived table, as in
+// AS t(a, b), at idx.
+func parseColumnList(tokens []schematokenizer.Token, idx int) ([]string, int) {
+	if idx >= len(tokens) || tokens[idx].Kind != schematokenizer.KindSymbol || tokens[idx].Text != "(" {
+		return nil, idx
+	}
+	var names []string
+	for i := idx + 1; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch {
+		case tok.Kind == schematokenizer.KindSymbol && tok.Text == ")":
+			return names, i + 1
+		case tok.Kind == schematokenizer.KindIdentifier || tok.Kind == schematokenizer.KindKeyword:
+			names = append(names, schematokenizer.NormalizeIdentifier(tok.Text))
+		}
+	}
+	return nil, idx
+}
+
+func isSubqueryStart(tok schematokenizer.Token) bool {
+	if tok.Kind != schematokenizer.KindKeyword && tok.Kind != schematokenizer.KindIdentifier {
+		return false
+	}
+	upper := strings.ToUpper(tok.Text)
+	return upper == "SELECT" || upper == "WITH"
+}
+
 func buildRelationIndex(refs []*relationRef) map[string]*relationRef {
 	index := make(map[string]*relationRef, len(refs)*relationIndexMultiplier)
 	for _, ref := range refs {
@@ -412,6 +451,9 @@ func (r *Runner) columnsForRelation(ref *relationRef) ([]string, string, bool) {
 	if ref == nil {
 		return nil, "relation not resolved", false
 	}
+	if !ref.baseTable && ref.subquery != nil {
+		return r.columnsForSubquery(ref)
+	}
 	if !ref.baseTable {
 		name := ref.aliasNormalized
 		if name == "" {
@@ -442,3 +484,52 @@ func (r *Runner) columnsForRelation(ref *relationRef) ([]string, string, bool) {
 	}
 	return cols, "", true
 }
+
+// columnsForSubquery returns the result column names of a derived table,
+// expanding the stars inside its subquery first.
+func (r *Runner) columnsForSubquery(ref *relationRef) ([]string, string, bool) {
+	name := ref.aliasNormalized
+	if name == "" {
+		name = "subquery"
+	}
+	if len(ref.columnList) > 0 {
+		return append([]string(nil), ref.columnList...), "", true
+	}
+
+	parts := make([]string, 0, len(ref.subquery))
+	for _, tok := range ref.subquery {
+		if tok.Kind != schematokenizer.KindDocComment {
+			parts = append(parts, tok.Text)
+		}
+	}
+	sql := strings.Join(parts, " ")
+	blk := block.Block{Name: name, SQL: sql}
+	query, diags := queryparser.Parse(blk)
+	if hasParseErrors(diags) || query.Verb != queryparser.VerbSelect {
+		return nil, fmt.Sprintf("derived table %q could not be parsed", name), false
+	}
+
+	expanded, _, replaced, err := r.expandStars(blk, sql, query)
+	if err != nil {
+		return nil, fmt.Sprintf("derived table %q: %v", name, err), false
+	}
+	if replaced > 0 {
+		blk.SQL = expanded
+		query, diags = queryparser.Parse(blk)
+		if hasParseErrors(diags) {
+			return nil, fmt.Sprintf("derived table %q could not be parsed after star expansion", name), false
+		}
+	}
+
+	cols := make([]string, 0, len(query.Columns))
+	for _, col := range query.Columns {
+		if col.Expr == "*" || strings.HasSuffix(col.Expr, ".*") {
+			return nil, fmt.Sprintf("cannot expand %q in derived table %q", col.Expr, name), false
+		}
+		if col.Alias == "" {
+			return nil, fmt.Sprintf("column %q of derived table %q has no name", col.Expr, name), false
+		}
+		cols = append(cols, col.Alias)
+	}
+	return cols, "", true
+}
@@ -0,0 +1,76 @@
+package types
+
+import (
+	"strings"
+
+	"github.com/electwix/db-catalyst/internal/config"
+)
+
+const pgtypeImport = "github.com/jackc/pgx/v5/pgtype"
+
+// NullableType describes the Go type holding a nullable value.
+type NullableType struct {
+	GoType  string
+	Import  string
+	Package string
+	// UsesSQLNull is set for the database/sql null types.
+	UsesSQLNull bool
+	// IsPointer is set when NULL is a nil pointer.
+	IsPointer bool
+}
+
+// sqlNullTypes are the sql.Null* types of NullStyleSQLNull.
+var sqlNullTypes = map[string]string{
+	"int64":     "sql.NullInt64",
+	"int32":     "sql.NullInt32",
+	"int16":     "sql.NullInt16",
+	"uint8":     "sql.NullByte",
+	"byte":      "sql.NullByte",
+	"float64":   "sql.NullFloat64",
+	"string":    "sql.NullString",
+	"bool":      "sql.NullBool",
+	"time.Time": "sql.NullTime",
+}
+
+// pgtypeTypes are the pgtype types of NullStylePgtype.
+var pgtypeTypes = map[string]string{
+	"int32":     "pgtype.Int4",
+	"int64":     "pgtype.Int8",
+	"float64":   "pgtype.Float8",
+	"string":    "pgtype.Text",
+	"bool":      "pgtype.Bool",
+	"time.Time": "pgtype.Timestamptz",
+}
+
+// Nullable returns the Go type representing a nullable value of the Go type
+// base in style. option is the generic type of config.NullStyleOption. An
+// empty style means config.NullStyleSQLNull.
+//
+// Base types without a sql.Null* or pgtype counterpart, such as []byte or
+// uuid.UUID, fall back to pointers in those styles. Pointers and any already
+// hold NULL and are returned unchanged.
+func Nullable(base string, style config.NullStyle, option config.GoTypeDetails) NullableType {
+	if base == "any" || strings.HasPrefix(base, "*") {
+		return NullableType{GoType: base, IsPointer: strings.HasPrefix(base, "*")}
+	}
+	switch style {
+	case config.NullStyleGeneric:
+		return NullableType{GoType: "sql.Null[" + base + "]", Import: "database/sql", Package: "sql", UsesSQLNull: true}
+	case config.NullStyleOption:
+		goType := option.Type + "[" + base + "]"
+		if option.Package != "" {
+			goType = option.Package + "." + goType
+		}
+		return NullableType{GoType: goType, Import: option.Import, Package: option.Package}
+	case config.NullStylePgtype:
+		if goType, ok := pgtypeTypes[base]; ok {
+			return NullableType{GoType: goType, Import: pgtypeImport, Package: "pgtype"}
+		}
+	case config.NullStylePointer:
+	default:
+		if goType, ok := sqlNullTypes[base]; ok {
+			return NullableType{GoType: goType, Import: "database/sql", Package: "sql", UsesSQLNull: true}
+		}
+	}
+	return NullableType{GoType: "*" + base, IsPointer: true}
+}
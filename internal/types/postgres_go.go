@@ -118,9 +118,9 @@ func (m *GoPostgresMapper) Map(semantic SemanticType) LanguageType {
 
 	// JSON types
 	case CategoryJSON, CategoryJSONB:
-		// Use interface{} or json.RawMessage, or a custom type
+		// Column overrides can decode into a struct instead.
 		return LanguageType{
-			Name:    "[]byte",
+			Name:    "json.RawMessage",
 			Import:  "encoding/json",
 			Package: "json",
 		}
@@ -363,7 +363,7 @@ func TestGoPostgresMapper(t *testing.T) {
 		{
 			name:        "json",
 			semantic:    SemanticType{Category: CategoryJSON},
-			wantType:    "[]byte",
+			wantType:    "json.RawMessage",
 			wantImport:  "encoding/json",
 			wantPackage: "json",
 		},
@@ -20,6 +20,41 @@ func NewSQLiteMapper() *SQLiteMapper {
 	return &SQLiteMapper{}
 }
 
+// SQLiteGoType returns the Go type of a value of the declared SQLite type
+// sqlType. INTEGER is int64, the width SQLite stores integers with, so that
+// table models and query results agree.
+//
+//nolint:goconst // Type names are naturally repeated and don't need constants
+func SQLiteGoType(sqlType string) string {
+	upperType := strings.ToUpper(sqlType)
+
+	switch {
+	case strings.Contains(upperType, "INT"):
+		switch {
+		case strings.Contains(upperType, "SMALLINT"):
+			return "int16"
+		case strings.Contains(upperType, "TINYINT"):
+			return "int8"
+		default:
+			return "int64"
+		}
+	case strings.Contains(upperType, "TEXT"), strings.Contains(upperType, "CHAR"), strings.Contains(upperType, "CLOB"):
+		return "string"
+	case strings.Contains(upperType, "BLOB"):
+		return "[]byte"
+	case strings.Contains(upperType, "REAL"), strings.Contains(upperType, "FLOAT"), strings.Contains(upperType, "DOUBLE"):
+		return "float64"
+	case strings.Contains(upperType, "NUMERIC"), strings.Contains(upperType, "DECIMAL"):
+		return "float64"
+	case strings.Contains(upperType, "BOOL"):
+		return "bool"
+	case strings.Contains(upperType, "DATE"), strings.Contains(upperType, "TIMESTAMP"):
+		return "time.Time"
+	default:
+		return "any"
+	}
+}
+
 // Map converts a SQLite type declaration to a semantic type.
 // SQLite types are case-insensitive and may include length constraints.
 func (m *SQLiteMapper) Map(sqlType string, nullable bool) SemanticType {
@@ -2,6 +2,8 @@ package types
 
 import (
 	"testing"
+
+	"github.com/electwix/db-catalyst/internal/config"
 )
 
 func TestSemanticTypeCategoryString(t *testing.T) {
@@ -309,3 +311,32 @@ func TestExtractPackageName(t *testing.T) {
 		})
 	}
 }
+
+func TestNullable(t *testing.T) {
+	option := config.GoTypeDetails{Import: "github.com/acme/opt", Package: "opt", Type: "Option"}
+	tests := []struct {
+		base  string
+		style config.NullStyle
+		want  string
+	}{
+		{"string", "", "sql.NullString"},
+		{"string", config.NullStyleSQLNull, "sql.NullString"},
+		{"[]byte", config.NullStyleSQLNull, "*[]byte"},
+		{"int64", config.NullStylePointer, "*int64"},
+		{"int64", config.NullStyleGeneric, "sql.Null[int64]"},
+		{"int32", config.NullStylePgtype, "pgtype.Int4"},
+		{"time.Time", config.NullStylePgtype, "pgtype.Timestamptz"},
+		{"uuid.UUID", config.NullStylePgtype, "*uuid.UUID"},
+		{"string", config.NullStyleOption, "opt.Option[string]"},
+		{"any", config.NullStyleGeneric, "any"},
+		{"*string", config.NullStyleSQLNull, "*string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.base+"/"+string(tt.style), func(t *testing.T) {
+			got := Nullable(tt.base, tt.style, option)
+			if got.GoType != tt.want {
+				t.Errorf("Nullable(%q, %q) = %q, want %q", tt.base, tt.style, got.GoType, tt.want)
+			}
+		})
+	}
+}